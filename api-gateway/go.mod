@@ -2,4 +2,9 @@ module api-gateway
 
 go 1.21
 
-require github.com/gorilla/mux v1.8.1
+require (
+	github.com/gorilla/mux v1.8.1
+	pkg v0.0.0
+)
+
+replace pkg => ../pkg
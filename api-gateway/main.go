@@ -2,25 +2,24 @@ package main
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/http/httputil"
+	"net/url"
 	"os"
+	"pkg/health"
+	"strings"
 
 	"github.com/gorilla/mux"
 )
 
-type Employee struct {
-	Name     string `json:"name"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
-}
-
 type APIGateway struct {
 	employeeServiceURL string
 	authServiceURL     string
+	loggerServiceURL   string
+	loggerStreamProxy  *httputil.ReverseProxy
 }
 
 func NewAPIGateway() *APIGateway {
@@ -34,44 +33,19 @@ func NewAPIGateway() *APIGateway {
 		authServiceURL = "http://localhost:8082"
 	}
 
+	loggerServiceURL := os.Getenv("LOGGER_SERVICE_URL")
+	if loggerServiceURL == "" {
+		loggerServiceURL = "http://localhost:8084"
+	}
+
 	return &APIGateway{
 		employeeServiceURL: employeeServiceURL,
 		authServiceURL:     authServiceURL,
+		loggerServiceURL:   loggerServiceURL,
+		loggerStreamProxy:  newStreamProxy(loggerServiceURL),
 	}
 }
 
-func (gw *APIGateway) CreateEmployeeHandler(w http.ResponseWriter, r *http.Request) {
-	var employee Employee
-	if err := json.NewDecoder(r.Body).Decode(&employee); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	// Forward request to employee service (delegar validación al servicio)
-	jsonData, err := json.Marshal(employee)
-	if err != nil {
-		http.Error(w, "Error processing request", http.StatusInternalServerError)
-		return
-	}
-
-	resp, err := http.Post(
-		fmt.Sprintf("%s/employees", gw.employeeServiceURL),
-		"application/json",
-		bytes.NewBuffer(jsonData),
-	)
-	if err != nil {
-		log.Printf("Error calling employee service: %v", err)
-		http.Error(w, "Error communicating with employee service", http.StatusInternalServerError)
-		return
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(resp.StatusCode)
-	w.Write(body)
-}
-
 func (gw *APIGateway) GetEmployeesHandler(w http.ResponseWriter, r *http.Request) {
 	resp, err := http.Get(fmt.Sprintf("%s/employees", gw.employeeServiceURL))
 	if err != nil {
@@ -116,13 +90,95 @@ func (gw *APIGateway) LoginHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(responseBody)
 }
 
+// Headers que se propagan entre el cliente y los servicios internos
+var (
+	forwardedRequestHeaders  = []string{"Content-Type", "Accept", "Authorization", "Idempotency-Key"}
+	forwardedResponseHeaders = []string{"Content-Type", "Content-Disposition", "Idempotent-Replayed"}
+)
+
+// ProxyToEmployeeService reenvía la petición al employee service conservando
+// método, ruta (sin el prefijo /api), query string y cuerpo
+func (gw *APIGateway) ProxyToEmployeeService(w http.ResponseWriter, r *http.Request) {
+	gw.forward(w, r, gw.employeeServiceURL)
+}
+
+// ProxyToLoggerService reenvía las consultas del registro de auditoría y de
+// las estadísticas de actividad al logger service
+func (gw *APIGateway) ProxyToLoggerService(w http.ResponseWriter, r *http.Request) {
+	gw.forward(w, r, gw.loggerServiceURL)
+}
+
+// ProxyLogStream reenvía el stream en vivo del registro de auditoría (SSE y WebSocket)
+func (gw *APIGateway) ProxyLogStream(w http.ResponseWriter, r *http.Request) {
+	gw.loggerStreamProxy.ServeHTTP(w, r)
+}
+
+// newStreamProxy crea un reverse proxy para conexiones de larga duración: a
+// diferencia de forward hace flush de cada evento y admite el upgrade a WebSocket
+func newStreamProxy(serviceURL string) *httputil.ReverseProxy {
+	target, err := url.Parse(serviceURL)
+	if err != nil {
+		log.Fatalf("Invalid service URL %s: %v", serviceURL, err)
+	}
+
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	director := proxy.Director
+	proxy.Director = func(r *http.Request) {
+		r.URL.Path = strings.TrimPrefix(r.URL.Path, "/api")
+		r.URL.RawPath = ""
+		director(r)
+	}
+	proxy.FlushInterval = -1
+	return proxy
+}
+
+// forward reenvía la petición a un servicio interno y copia su respuesta
+func (gw *APIGateway) forward(w http.ResponseWriter, r *http.Request, serviceURL string) {
+	targetURL := serviceURL + strings.TrimPrefix(r.URL.Path, "/api")
+	if r.URL.RawQuery != "" {
+		targetURL += "?" + r.URL.RawQuery
+	}
+
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, targetURL, r.Body)
+	if err != nil {
+		http.Error(w, "Error processing request", http.StatusInternalServerError)
+		return
+	}
+	for _, header := range forwardedRequestHeaders {
+		if value := r.Header.Get(header); value != "" {
+			req.Header.Set(header, value)
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Printf("Error calling %s: %v", serviceURL, err)
+		http.Error(w, "Error communicating with upstream service", http.StatusInternalServerError)
+		return
+	}
+	defer resp.Body.Close()
+
+	for _, header := range forwardedResponseHeaders {
+		if value := resp.Header.Get(header); value != "" {
+			w.Header().Set(header, value)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+
+	// Copiar en streaming para no cargar respuestas grandes (p.ej. exportaciones) en memoria
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		log.Printf("Error copying response from %s: %v", serviceURL, err)
+	}
+}
+
 // CORSMiddleware agrega headers CORS a las respuestas
 func CORSMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Permitir origen específico o todos los orígenes en desarrollo
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Idempotency-Key, Last-Event-ID")
+		w.Header().Set("Access-Control-Expose-Headers", "Content-Disposition, Idempotent-Replayed")
 		w.Header().Set("Access-Control-Max-Age", "3600")
 
 		// Manejar preflight requests
@@ -139,8 +195,33 @@ func main() {
 	gateway := NewAPIGateway()
 
 	router := mux.NewRouter()
-	router.HandleFunc("/api/employees", gateway.CreateEmployeeHandler).Methods("POST", "OPTIONS")
+
+	// Endpoints de salud: listo cuando los servicios internos responden
+	healthHandler := health.New("api-gateway")
+	healthHandler.AddCheck("employee-service", health.HTTPCheck(gateway.employeeServiceURL+"/health"))
+	healthHandler.AddCheck("auth-service", health.HTTPCheck(gateway.authServiceURL+"/health"))
+	healthHandler.AddCheck("logger-service", health.HTTPCheck(gateway.loggerServiceURL+"/health"))
+	router.HandleFunc("/health", healthHandler.Live).Methods("GET")
+	router.HandleFunc("/health/ready", healthHandler.Ready).Methods("GET")
+
+	router.HandleFunc("/api/employees", gateway.ProxyToEmployeeService).Methods("POST", "OPTIONS")
 	router.HandleFunc("/api/employees", gateway.GetEmployeesHandler).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/employees/import", gateway.ProxyToEmployeeService).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/employees/export", gateway.ProxyToEmployeeService).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/employees/{id}", gateway.ProxyToEmployeeService).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/employees/{id}/manager", gateway.ProxyToEmployeeService).Methods("PUT", "OPTIONS")
+	router.HandleFunc("/api/employees/{id}/department", gateway.ProxyToEmployeeService).Methods("PUT", "OPTIONS")
+	router.HandleFunc("/api/employees/{id}/reports", gateway.ProxyToEmployeeService).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/employees/{id}/management-chain", gateway.ProxyToEmployeeService).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/employees/{id}/status", gateway.ProxyToEmployeeService).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/employees/{id}/status-history", gateway.ProxyToEmployeeService).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/departments", gateway.ProxyToEmployeeService).Methods("GET", "POST", "OPTIONS")
+	router.HandleFunc("/api/departments/{id}", gateway.ProxyToEmployeeService).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/departments/{id}/org-chart", gateway.ProxyToEmployeeService).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/logs", gateway.ProxyToLoggerService).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/logs/stream", gateway.ProxyLogStream).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/logs/ws", gateway.ProxyLogStream).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/stats/activity", gateway.ProxyToLoggerService).Methods("GET", "OPTIONS")
 	router.HandleFunc("/api/auth/login", gateway.LoginHandler).Methods("POST", "OPTIONS")
 
 	// Aplicar middleware CORS
@@ -3,46 +3,30 @@ package main
 import (
 	"auth-service/internal/application"
 	"auth-service/internal/infrastructure"
+	"auth-service/internal/ports"
 	"context"
 	"log"
 	"net/http"
 	"os"
+	"pkg/awsclient"
+	"pkg/eventschema"
+	"pkg/health"
+	"pkg/password"
+	"pkg/sqsqueue"
 	"strconv"
-
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials"
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 )
 
 func main() {
 	ctx := context.Background()
 
-	// Configurar AWS SDK
-	awsEndpoint := os.Getenv("AWS_ENDPOINT")
-	awsRegion := os.Getenv("AWS_REGION")
-	if awsRegion == "" {
-		awsRegion = "us-east-1"
-	}
-
-	cfg, err := config.LoadDefaultConfig(ctx,
-		config.WithRegion(awsRegion),
-		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
-			os.Getenv("AWS_ACCESS_KEY_ID"),
-			os.Getenv("AWS_SECRET_ACCESS_KEY"),
-			"",
-		)),
-	)
+	// Configurar AWS SDK y crear clientes (endpoint de LocalStack vía AWS_ENDPOINT)
+	clients, err := awsclient.NewFactoryFromEnv(ctx)
 	if err != nil {
 		log.Fatalf("Error loading AWS config: %v", err)
 	}
 
-	// Crear cliente de DynamoDB
-	dynamoClient := dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
-		if awsEndpoint != "" {
-			o.BaseEndpoint = aws.String(awsEndpoint)
-		}
-	})
+	dynamoClient := clients.DynamoDB()
+	sqsClient := clients.SQS()
 
 	// Obtener variables de entorno
 	tableName := os.Getenv("DYNAMODB_TABLE")
@@ -66,16 +50,39 @@ func main() {
 
 	// Crear instancias de infraestructura (adaptadores)
 	repository := infrastructure.NewDynamoDBUserRepository(dynamoClient, tableName)
-	passwordHasher := infrastructure.NewBcryptPasswordHasher()
+	passwordHasher := password.NewBcryptHasher()
 	tokenGenerator := infrastructure.NewJWTTokenGenerator(jwtSecret, jwtExpiration)
 
+	// Los inicios de sesión (auth.login y auth.login_failed) se publican en la cola del
+	// logger-service (opcional: sin LOG_QUEUE_URL no se publican)
+	logQueueURL := os.Getenv("LOG_QUEUE_URL")
+	var publisher ports.EventPublisher
+	if logQueueURL != "" {
+		registry, err := eventschema.Default()
+		if err != nil {
+			log.Fatalf("Error loading event schemas: %v", err)
+		}
+		publisher = sqsqueue.NewPublisher(sqsClient, logQueueURL, registry)
+	} else {
+		log.Println("LOG_QUEUE_URL not set: login events will not be published")
+	}
+
 	// Crear servicio de aplicación con inyección de dependencias
-	service := application.NewAuthService(repository, passwordHasher, tokenGenerator)
+	service := application.NewAuthService(repository, passwordHasher, tokenGenerator, publisher)
 
 	// Crear manejador HTTP
 	handler := infrastructure.NewHTTPHandler(service)
 	router := handler.SetupRoutes()
 
+	// Endpoints de salud (liveness y readiness)
+	healthHandler := health.New("auth-service")
+	healthHandler.AddCheck("users-table", awsclient.TableCheck(dynamoClient, tableName))
+	if logQueueURL != "" {
+		healthHandler.AddCheck("log-queue", awsclient.QueueCheck(sqsClient, logQueueURL))
+	}
+	router.HandleFunc("/health", healthHandler.Live).Methods("GET")
+	router.HandleFunc("/health/ready", healthHandler.Ready).Methods("GET")
+
 	// Iniciar servidor
 	port := os.Getenv("PORT")
 	if port == "" {
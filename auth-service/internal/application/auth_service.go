@@ -5,6 +5,8 @@ import (
 	"auth-service/internal/ports"
 	"context"
 	"log"
+	"pkg/events"
+	"time"
 )
 
 // AuthService implementa la lógica de negocio para autenticación
@@ -12,18 +14,22 @@ type AuthService struct {
 	repository     ports.UserRepository
 	passwordHasher ports.PasswordHasher
 	tokenGenerator ports.TokenGenerator
+	publisher      ports.EventPublisher
 }
 
-// NewAuthService crea una nueva instancia del servicio de autenticación
+// NewAuthService crea una nueva instancia del servicio de autenticación.
+// publisher puede ser nil: entonces no se publican los eventos auth.login.
 func NewAuthService(
 	repo ports.UserRepository,
 	hasher ports.PasswordHasher,
 	tokenGen ports.TokenGenerator,
+	publisher ports.EventPublisher,
 ) *AuthService {
 	return &AuthService{
 		repository:     repo,
 		passwordHasher: hasher,
 		tokenGenerator: tokenGen,
+		publisher:      publisher,
 	}
 }
 
@@ -38,6 +44,7 @@ func (s *AuthService) Login(ctx context.Context, credentials *domain.LoginCreden
 	user, err := s.repository.FindByEmail(ctx, credentials.Email)
 	if err != nil {
 		log.Printf("User not found: %s", credentials.Email)
+		s.publishLoginFailed(ctx, credentials.Email, nil, events.LoginFailureUnknownUser)
 		return nil, domain.ErrInvalidCredentials
 	}
 
@@ -45,9 +52,17 @@ func (s *AuthService) Login(ctx context.Context, credentials *domain.LoginCreden
 	err = s.passwordHasher.Compare(user.Password, credentials.Password)
 	if err != nil {
 		log.Printf("Invalid password for user: %s", credentials.Email)
+		s.publishLoginFailed(ctx, credentials.Email, user, events.LoginFailureInvalidPassword)
 		return nil, domain.ErrInvalidCredentials
 	}
 
+	// Denegar el acceso a empleados cuya baja ya es efectiva
+	if user.IsTerminated(time.Now()) {
+		log.Printf("Login denied for terminated account: %s", credentials.Email)
+		s.publishLoginFailed(ctx, credentials.Email, user, events.LoginFailureAccountTerminated)
+		return nil, domain.ErrAccountTerminated
+	}
+
 	// Generar token JWT (usando el puerto TokenGenerator)
 	token, err := s.tokenGenerator.GenerateToken(user.ID)
 	if err != nil {
@@ -56,9 +71,46 @@ func (s *AuthService) Login(ctx context.Context, credentials *domain.LoginCreden
 	}
 
 	log.Printf("User authenticated successfully: %s (ID: %s)", user.Email, user.ID)
+	s.publishLogin(ctx, user)
 	return token, nil
 }
 
+// publishLogin publica el evento auth.login. Es solo informativo (registro,
+// estadísticas de actividad y alertas), así que un error no invalida el login.
+func (s *AuthService) publishLogin(ctx context.Context, user *domain.User) {
+	if s.publisher == nil {
+		return
+	}
+
+	event, err := domain.NewLoginEvent(user, time.Now())
+	if err != nil {
+		log.Printf("Error creating login event: %v", err)
+		return
+	}
+	s.publish(ctx, event)
+}
+
+// publishLoginFailed publica el evento auth.login_failed de un intento rechazado
+func (s *AuthService) publishLoginFailed(ctx context.Context, email string, user *domain.User, reason string) {
+	if s.publisher == nil {
+		return
+	}
+
+	event, err := domain.NewLoginFailedEvent(email, user, reason, time.Now())
+	if err != nil {
+		log.Printf("Error creating login failed event: %v", err)
+		return
+	}
+	s.publish(ctx, event)
+}
+
+// publish publica un evento de inicio de sesión; los errores solo se registran
+func (s *AuthService) publish(ctx context.Context, event *events.CloudEvent) {
+	if err := s.publisher.Publish(ctx, event); err != nil {
+		log.Printf("Error publishing %s event: %v", event.Type, err)
+	}
+}
+
 // ValidateToken valida un token JWT y retorna el ID del usuario
 func (s *AuthService) ValidateToken(ctx context.Context, token string) (string, error) {
 	return s.tokenGenerator.ValidateToken(token)
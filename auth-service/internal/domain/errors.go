@@ -8,4 +8,5 @@ var (
 	ErrUserNotFound       = errors.New("user not found")
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrTokenGeneration    = errors.New("error generating token")
+	ErrAccountTerminated  = errors.New("account terminated")
 )
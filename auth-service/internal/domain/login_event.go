@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"pkg/events"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NewLoginEvent crea el evento auth.login de un inicio de sesión correcto
+func NewLoginEvent(user *User, at time.Time) (*events.CloudEvent, error) {
+	at = at.UTC()
+	return events.New(uuid.New().String(), events.SourceAuthService, events.TypeAuthLogin, user.ID, at, events.AuthLoginPayload{
+		UserID:     user.ID,
+		Email:      user.Email,
+		LoggedInAt: at,
+	})
+}
+
+// NewLoginFailedEvent crea el evento auth.login_failed de un intento de inicio
+// de sesión rechazado. user es nil cuando el email no corresponde a ningún usuario.
+func NewLoginFailedEvent(email string, user *User, reason string, at time.Time) (*events.CloudEvent, error) {
+	at = at.UTC()
+	payload := events.AuthLoginFailedPayload{
+		Email:    email,
+		Reason:   reason,
+		FailedAt: at,
+	}
+	if user != nil {
+		payload.UserID = user.ID
+	}
+	return events.New(uuid.New().String(), events.SourceAuthService, events.TypeAuthLoginFailed, payload.UserID, at, payload)
+}
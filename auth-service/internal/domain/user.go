@@ -4,11 +4,21 @@ import "time"
 
 // User representa un usuario en el sistema de autenticación
 type User struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	Password  string    `json:"-"` // Hash del password (nunca se serializa)
-	CreatedAt time.Time `json:"created_at"`
+	ID                string    `json:"id"`
+	Name              string    `json:"name"`
+	Email             string    `json:"email"`
+	Password          string    `json:"-"` // Hash del password (nunca se serializa)
+	Status            string    `json:"status"`
+	StatusEffectiveAt time.Time `json:"status_effective_at"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// StatusTerminated es el estado laboral de una cuenta dada de baja (gestionado por employee-service)
+const StatusTerminated = "terminated"
+
+// IsTerminated indica si la baja del usuario ya es efectiva en el instante indicado
+func (u *User) IsTerminated(at time.Time) bool {
+	return u.Status == StatusTerminated && !u.StatusEffectiveAt.After(at)
 }
 
 // Validate valida los datos básicos del usuario
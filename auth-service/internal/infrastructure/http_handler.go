@@ -64,6 +64,10 @@ func (h *HTTPHandler) Login(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "Invalid email or password", http.StatusUnauthorized)
 			return
 		}
+		if err == domain.ErrAccountTerminated {
+			http.Error(w, "Account is no longer active", http.StatusForbidden)
+			return
+		}
 
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
@@ -81,17 +85,9 @@ func (h *HTTPHandler) Login(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
-// HealthCheck endpoint para verificar el estado del servicio
-func (h *HTTPHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
-}
-
 // SetupRoutes configura las rutas del servidor
 func (h *HTTPHandler) SetupRoutes() *mux.Router {
 	router := mux.NewRouter()
 	router.HandleFunc("/auth/login", h.Login).Methods("POST")
-	router.HandleFunc("/health", h.HealthCheck).Methods("GET")
 	return router
 }
@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+	"pkg/events"
+)
+
+// EventPublisher define el puerto para publicar eventos
+type EventPublisher interface {
+	Publish(ctx context.Context, event *events.CloudEvent) error
+}
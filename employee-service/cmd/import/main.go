@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"employee-service/internal/application"
+	"employee-service/internal/infrastructure"
+	"employee-service/internal/ports"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"pkg/awsclient"
+	"pkg/password"
+	"strings"
+)
+
+// Comando de importación masiva de empleados.
+//
+// Uso:
+//
+//	go run ./cmd/import -file empleados.csv [-format csv|ndjson] [-dry-run]
+func main() {
+	filePath := flag.String("file", "", "Ruta del archivo a importar (CSV o NDJSON)")
+	format := flag.String("format", "", "Formato del archivo: csv o ndjson (por defecto se deduce de la extensión)")
+	dryRun := flag.Bool("dry-run", false, "Solo validar las filas, sin escribir ni publicar eventos")
+	flag.Parse()
+
+	if *filePath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *format == "" {
+		switch strings.ToLower(filepath.Ext(*filePath)) {
+		case ".csv":
+			*format = infrastructure.FormatCSV
+		case ".ndjson", ".jsonl":
+			*format = infrastructure.FormatNDJSON
+		default:
+			log.Fatalf("Cannot infer format from %s, use -format", *filePath)
+		}
+	}
+
+	ctx := context.Background()
+
+	// Configurar AWS SDK
+	clients, err := awsclient.NewFactoryFromEnv(ctx)
+	if err != nil {
+		log.Fatalf("Error loading AWS config: %v", err)
+	}
+	dynamoClient := clients.DynamoDB()
+
+	tableName := os.Getenv("DYNAMODB_TABLE")
+	if tableName == "" {
+		tableName = "employees"
+	}
+
+	departmentsTableName := os.Getenv("DEPARTMENTS_TABLE")
+	if departmentsTableName == "" {
+		departmentsTableName = "departments"
+	}
+
+	outboxTableName := os.Getenv("OUTBOX_TABLE")
+	if outboxTableName == "" {
+		outboxTableName = "employee-outbox"
+	}
+
+	// Mismo modo de publicación que el servicio: en modo stream los eventos se derivan
+	// del stream de la tabla y la importación no escribe en el outbox
+	publishingMode := os.Getenv("EVENT_PUBLISHING_MODE")
+	if publishingMode == "" {
+		publishingMode = "outbox"
+	}
+	if publishingMode != "outbox" && publishingMode != "stream" {
+		log.Fatalf("Invalid EVENT_PUBLISHING_MODE %q (expected outbox or stream)", publishingMode)
+	}
+
+	file, err := os.Open(*filePath)
+	if err != nil {
+		log.Fatalf("Error opening %s: %v", *filePath, err)
+	}
+	defer file.Close()
+
+	reader, err := infrastructure.NewRecordReader(*format, file)
+	if err != nil {
+		log.Fatalf("Error reading %s: %v", *filePath, err)
+	}
+
+	repository := infrastructure.NewDynamoDBRepository(dynamoClient, tableName, outboxTableName)
+	departmentRepository := infrastructure.NewDynamoDBDepartmentRepository(dynamoClient, departmentsTableName)
+	passwordHasher := password.NewBcryptHasher()
+
+	var outboxStore ports.OutboxStore
+	if publishingMode == "outbox" {
+		outboxStore = infrastructure.NewDynamoDBOutboxStore(dynamoClient, outboxTableName)
+	}
+
+	service := application.NewEmployeeService(repository, departmentRepository, outboxStore, passwordHasher)
+
+	report, err := service.ImportEmployees(ctx, reader, *dryRun)
+	if err != nil {
+		log.Fatalf("Import aborted: %v", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(report)
+
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
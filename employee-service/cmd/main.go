@@ -3,52 +3,34 @@ package main
 import (
 	"context"
 	"employee-service/internal/application"
+	"employee-service/internal/domain"
 	"employee-service/internal/infrastructure"
+	"employee-service/internal/ports"
 	"log"
 	"net/http"
 	"os"
-
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials"
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
-	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"pkg/awsclient"
+	"pkg/eventschema"
+	"pkg/health"
+	"pkg/password"
+	"pkg/sqsqueue"
+	"strconv"
+	"time"
 )
 
 func main() {
 	ctx := context.Background()
 
-	// Configurar AWS SDK
-	awsEndpoint := os.Getenv("AWS_ENDPOINT")
-	awsRegion := os.Getenv("AWS_REGION")
-	if awsRegion == "" {
-		awsRegion = "us-east-1"
-	}
-
-	cfg, err := config.LoadDefaultConfig(ctx,
-		config.WithRegion(awsRegion),
-		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
-			os.Getenv("AWS_ACCESS_KEY_ID"),
-			os.Getenv("AWS_SECRET_ACCESS_KEY"),
-			"",
-		)),
-	)
+	// Configurar AWS SDK y crear clientes (endpoint de LocalStack vía AWS_ENDPOINT)
+	clients, err := awsclient.NewFactoryFromEnv(ctx)
 	if err != nil {
 		log.Fatalf("Error loading AWS config: %v", err)
 	}
 
-	// Crear clientes de AWS
-	dynamoClient := dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
-		if awsEndpoint != "" {
-			o.BaseEndpoint = aws.String(awsEndpoint)
-		}
-	})
-
-	sqsClient := sqs.NewFromConfig(cfg, func(o *sqs.Options) {
-		if awsEndpoint != "" {
-			o.BaseEndpoint = aws.String(awsEndpoint)
-		}
-	})
+	dynamoClient := clients.DynamoDB()
+	streamsClient := clients.DynamoDBStreams()
+	sqsClient := clients.SQS()
+	snsClient := clients.SNS()
 
 	// Obtener variables de entorno
 	tableName := os.Getenv("DYNAMODB_TABLE")
@@ -56,23 +38,145 @@ func main() {
 		tableName = "employees"
 	}
 
+	departmentsTableName := os.Getenv("DEPARTMENTS_TABLE")
+	if departmentsTableName == "" {
+		departmentsTableName = "departments"
+	}
+
+	idempotencyTableName := os.Getenv("IDEMPOTENCY_TABLE")
+	if idempotencyTableName == "" {
+		idempotencyTableName = "idempotency-keys"
+	}
+
+	idempotencyWindowHours := 24 // Default: 24 horas
+	if value := os.Getenv("IDEMPOTENCY_TTL_HOURS"); value != "" {
+		if hours, err := strconv.Atoi(value); err == nil {
+			idempotencyWindowHours = hours
+		}
+	}
+
+	outboxTableName := os.Getenv("OUTBOX_TABLE")
+	if outboxTableName == "" {
+		outboxTableName = "employee-outbox"
+	}
+
+	// Modo de publicación de eventos: "outbox" (por defecto) o "stream" (DynamoDB Streams)
+	publishingMode := os.Getenv("EVENT_PUBLISHING_MODE")
+	if publishingMode == "" {
+		publishingMode = "outbox"
+	}
+	if publishingMode != "outbox" && publishingMode != "stream" {
+		log.Fatalf("Invalid EVENT_PUBLISHING_MODE %q (expected outbox or stream)", publishingMode)
+	}
+
+	checkpointsTableName := os.Getenv("STREAM_CHECKPOINTS_TABLE")
+	if checkpointsTableName == "" {
+		checkpointsTableName = "stream-checkpoints"
+	}
+
+	// Los eventos se publican en el topic SNS (fan-out a las colas de cada consumidor);
+	// sin EVENT_TOPIC_ARN se publican directamente en la cola SQS_QUEUE_URL
+	topicARN := os.Getenv("EVENT_TOPIC_ARN")
 	queueURL := os.Getenv("SQS_QUEUE_URL")
-	if queueURL == "" {
-		log.Fatal("SQS_QUEUE_URL environment variable is required")
+	if topicARN == "" && queueURL == "" {
+		log.Fatal("EVENT_TOPIC_ARN or SQS_QUEUE_URL environment variable is required")
+	}
+
+	// Suscripciones del bus (EVENT_SUBSCRIPTIONS="nombre=ARN de la cola|tipo,tipo;..."):
+	// se declaran en el topic al arrancar, creándolas o actualizando su filter policy
+	subscriptions, err := domain.ParseSubscriptions(os.Getenv("EVENT_SUBSCRIPTIONS"))
+	if err != nil {
+		log.Fatalf("Error parsing EVENT_SUBSCRIPTIONS: %v", err)
+	}
+	if len(subscriptions) > 0 && topicARN == "" {
+		log.Fatal("EVENT_SUBSCRIPTIONS requires EVENT_TOPIC_ARN")
 	}
 
 	// Crear instancias de infraestructura
-	repository := infrastructure.NewDynamoDBRepository(dynamoClient, tableName)
-	publisher := infrastructure.NewSQSEventPublisher(sqsClient, queueURL)
-	passwordHasher := infrastructure.NewBcryptPasswordHasher()
+	repository := infrastructure.NewDynamoDBRepository(dynamoClient, tableName, outboxTableName)
+	departmentRepository := infrastructure.NewDynamoDBDepartmentRepository(dynamoClient, departmentsTableName)
+	// Registro de esquemas: los eventos se validan antes de publicarse
+	registry, err := eventschema.Default()
+	if err != nil {
+		log.Fatalf("Error loading event schemas: %v", err)
+	}
+
+	var publisher ports.EventPublisher
+	if topicARN != "" {
+		var bus ports.EventBus = infrastructure.NewSNSEventBus(snsClient, topicARN, registry)
+		for _, subscription := range subscriptions {
+			if err := bus.Subscribe(ctx, subscription); err != nil {
+				log.Fatalf("Error declaring subscription %s: %v", subscription.Name, err)
+			}
+		}
+		publisher = bus
+	} else {
+		publisher = infrastructure.NewSQSEventPublisher(sqsqueue.NewPublisher(sqsClient, queueURL, registry))
+	}
+	outboxStore := infrastructure.NewDynamoDBOutboxStore(dynamoClient, outboxTableName)
+	passwordHasher := password.NewBcryptHasher()
+	idempotencyStore := infrastructure.NewDynamoDBIdempotencyStore(dynamoClient, idempotencyTableName)
 
 	// Crear servicio de aplicación (con inyección de dependencias)
-	service := application.NewEmployeeService(repository, publisher, passwordHasher)
+	var serviceOutbox ports.OutboxStore = outboxStore
+	if publishingMode == "stream" {
+		// Los eventos se derivan del stream de la tabla: no se escribe en el outbox
+		serviceOutbox = nil
+	}
+	service := application.NewEmployeeService(repository, departmentRepository, serviceOutbox, passwordHasher)
+	departmentService := application.NewDepartmentService(departmentRepository, repository)
+
+	// Intervalo de publicación (relay del outbox o lectura del stream)
+	relayInterval := 2 * time.Second
+	if value := os.Getenv("OUTBOX_RELAY_INTERVAL_SECONDS"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+			relayInterval = time.Duration(seconds) * time.Second
+		}
+	}
+
+	// Intentos de publicación de una entrada del outbox antes de marcarla como failed
+	outboxMaxAttempts := 10
+	if value := os.Getenv("OUTBOX_MAX_ATTEMPTS"); value != "" {
+		if attempts, err := strconv.Atoi(value); err == nil && attempts > 0 {
+			outboxMaxAttempts = attempts
+		}
+	}
+
+	switch publishingMode {
+	case "stream":
+		// CDC: sigue el stream de la tabla de empleados y publica los cambios en SQS
+		streamARN := os.Getenv("EMPLOYEES_STREAM_ARN")
+		if streamARN == "" {
+			if streamARN, err = infrastructure.LatestStreamARN(ctx, dynamoClient, tableName); err != nil {
+				log.Fatalf("Error resolving stream of table %s: %v", tableName, err)
+			}
+		}
+		stream := infrastructure.NewDynamoDBChangeStream(streamsClient, streamARN)
+		checkpoints := infrastructure.NewDynamoDBCheckpointStore(dynamoClient, checkpointsTableName)
+		streamPublisher := application.NewChangeStreamPublisher(stream, checkpoints, publisher, relayInterval)
+		go streamPublisher.Run(ctx)
+
+	default:
+		// Relay del outbox: publica en SQS los eventos guardados junto con cada cambio
+		relay := application.NewOutboxRelay(outboxStore, publisher, relayInterval, outboxMaxAttempts)
+		go relay.Run(ctx)
+	}
 
 	// Crear manejador HTTP
-	handler := infrastructure.NewHTTPHandler(service)
+	idempotency := infrastructure.NewIdempotencyMiddleware(idempotencyStore, time.Duration(idempotencyWindowHours)*time.Hour)
+	handler := infrastructure.NewHTTPHandler(service, departmentService, idempotency)
 	router := handler.SetupRoutes()
 
+	// Endpoints de salud (liveness y readiness)
+	healthHandler := health.New("employee-service")
+	healthHandler.AddCheck("employees-table", awsclient.TableCheck(dynamoClient, tableName))
+	healthHandler.AddCheck("departments-table", awsclient.TableCheck(dynamoClient, departmentsTableName))
+	if publishingMode == "outbox" {
+		healthHandler.AddCheck("outbox-table", awsclient.TableCheck(dynamoClient, outboxTableName))
+	}
+	router.HandleFunc("/health", healthHandler.Live).Methods("GET")
+	router.HandleFunc("/health/ready", healthHandler.Ready).Methods("GET")
+
 	// Iniciar servidor
 	log.Println("Employee service starting on port 8081...")
 	if err := http.ListenAndServe(":8081", router); err != nil {
@@ -0,0 +1,130 @@
+package application
+
+import (
+	"context"
+	"employee-service/internal/domain"
+	"employee-service/internal/ports"
+	"log"
+	"time"
+)
+
+// ChangeStreamPublisher publica eventos a partir del stream de cambios de la tabla
+// de empleados (change data capture). Es la alternativa al OutboxRelay: los eventos
+// se derivan de los cambios ya confirmados, sin escribir en el outbox.
+// La entrega es at-least-once: el checkpoint se guarda después de publicar.
+type ChangeStreamPublisher struct {
+	stream      ports.ChangeStream
+	checkpoints ports.CheckpointStore
+	publisher   ports.EventPublisher
+	interval    time.Duration
+}
+
+// NewChangeStreamPublisher crea una nueva instancia del publicador
+func NewChangeStreamPublisher(stream ports.ChangeStream, checkpoints ports.CheckpointStore, publisher ports.EventPublisher, interval time.Duration) *ChangeStreamPublisher {
+	return &ChangeStreamPublisher{
+		stream:      stream,
+		checkpoints: checkpoints,
+		publisher:   publisher,
+		interval:    interval,
+	}
+}
+
+// Run sigue el stream periódicamente hasta que se cancele el contexto
+func (p *ChangeStreamPublisher) Run(ctx context.Context) {
+	log.Printf("Change stream publisher started (interval %s)", p.interval)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.PublishPending(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Error publishing change stream records: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("Change stream publisher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishPending procesa los registros nuevos de todos los shards.
+// Un shard hijo solo se procesa cuando su padre está completo, para conservar
+// el orden de los cambios de cada empleado.
+func (p *ChangeStreamPublisher) PublishPending(ctx context.Context) error {
+	shards, err := p.stream.Shards(ctx)
+	if err != nil {
+		return err
+	}
+
+	known := make(map[string]bool, len(shards))
+	for _, shard := range shards {
+		known[shard.ID] = true
+	}
+
+	for _, shard := range shards {
+		if shard.ParentID != "" && known[shard.ParentID] {
+			parentPosition, err := p.checkpoints.Load(ctx, shard.ParentID)
+			if err != nil {
+				return err
+			}
+			if parentPosition != domain.ShardEndCheckpoint {
+				continue
+			}
+		}
+
+		if err := p.publishShard(ctx, shard.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// publishShard publica los registros pendientes de un shard desde su checkpoint
+func (p *ChangeStreamPublisher) publishShard(ctx context.Context, shardID string) error {
+	position, err := p.checkpoints.Load(ctx, shardID)
+	if err != nil {
+		return err
+	}
+	if position == domain.ShardEndCheckpoint {
+		return nil
+	}
+
+	for {
+		records, closed, err := p.stream.Read(ctx, shardID, position)
+		if err != nil {
+			return err
+		}
+
+		for _, record := range records {
+			events, err := record.ToEvents()
+			if err != nil {
+				// El checkpoint no avanza: el shard queda detenido en este registro
+				// hasta corregir la configuración del stream
+				return err
+			}
+
+			for _, event := range events {
+				if err := p.publisher.Publish(ctx, event); err != nil {
+					// Se reintenta desde el último checkpoint en la siguiente pasada
+					return err
+				}
+			}
+
+			if err := p.checkpoints.Save(ctx, shardID, record.SequenceNumber); err != nil {
+				return err
+			}
+			position = record.SequenceNumber
+		}
+
+		if closed {
+			return p.checkpoints.Save(ctx, shardID, domain.ShardEndCheckpoint)
+		}
+		if len(records) == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package application_test
+
+import (
+	"context"
+	"employee-service/internal/application"
+	"employee-service/internal/domain"
+	"employee-service/internal/infrastructure"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// El test usa el stream y los checkpoints en memoria de infrastructure, que importa
+// application; por eso está en el paquete externo application_test.
+
+// recordingPublisher registra los eventos publicados y falla con los de failing
+type recordingPublisher struct {
+	published []string
+	failing   map[string]bool
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event *domain.EmployeeEvent) error {
+	if p.failing[event.EventID] {
+		return errors.New("topic unavailable")
+	}
+	p.published = append(p.published, event.EventID+" "+event.EventType)
+	return nil
+}
+
+func (p *recordingPublisher) PublishBatch(ctx context.Context, events []*domain.EmployeeEvent) []error {
+	results := make([]error, len(events))
+	for i, event := range events {
+		results[i] = p.Publish(ctx, event)
+	}
+	return results
+}
+
+func testEmployee(t *testing.T) (*domain.Employee, *domain.Employee) {
+	t.Helper()
+	hiredAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	onboarding := &domain.Employee{ID: "emp-1", Name: "Ana", Email: "ana@example.com", CreatedAt: hiredAt}
+	onboarding.StartOnboarding(hiredAt)
+
+	active := *onboarding
+	active.StatusHistory = append([]domain.StatusTransition(nil), onboarding.StatusHistory...)
+	if _, err := active.TransitionTo(domain.StatusActive, domain.ReasonOnboardingCompleted, hiredAt.AddDate(0, 1, 0)); err != nil {
+		t.Fatalf("TransitionTo() = %v", err)
+	}
+	return onboarding, &active
+}
+
+func shardID(t *testing.T, stream *infrastructure.InMemoryChangeStream) string {
+	t.Helper()
+	shards, err := stream.Shards(context.Background())
+	if err != nil || len(shards) != 1 {
+		t.Fatalf("Shards() = %v, %v", shards, err)
+	}
+	return shards[0].ID
+}
+
+func TestChangeStreamPublisherResumesFromCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	stream := infrastructure.NewInMemoryChangeStream()
+	checkpoints := infrastructure.NewInMemoryCheckpointStore()
+	onboarding, active := testEmployee(t)
+
+	first := stream.Append(domain.ChangeInsert, nil, onboarding)
+	second := stream.Append(domain.ChangeModify, onboarding, active)
+
+	publisher := &recordingPublisher{}
+	if err := application.NewChangeStreamPublisher(stream, checkpoints, publisher, time.Second).PublishPending(ctx); err != nil {
+		t.Fatalf("PublishPending() = %v", err)
+	}
+	want := []string{
+		"stream-" + first.SequenceNumber + " employee.created",
+		"stream-" + second.SequenceNumber + " employee.updated",
+		"stream-" + second.SequenceNumber + "-status employee.status_changed",
+	}
+	if !reflect.DeepEqual(publisher.published, want) {
+		t.Fatalf("published %v, want %v", publisher.published, want)
+	}
+
+	// Otro publicador con los mismos checkpoints (p.ej. tras un reinicio) solo publica lo nuevo
+	third := stream.Append(domain.ChangeRemove, active, nil)
+	resumed := &recordingPublisher{}
+	if err := application.NewChangeStreamPublisher(stream, checkpoints, resumed, time.Second).PublishPending(ctx); err != nil {
+		t.Fatalf("PublishPending() = %v", err)
+	}
+	if want := []string{"stream-" + third.SequenceNumber + " employee.deleted"}; !reflect.DeepEqual(resumed.published, want) {
+		t.Fatalf("published after resume %v, want %v", resumed.published, want)
+	}
+
+	position, _ := checkpoints.Load(ctx, shardID(t, stream))
+	if position != third.SequenceNumber {
+		t.Errorf("checkpoint = %q, want %q", position, third.SequenceNumber)
+	}
+
+	// Al cerrarse el shard queda marcado como completo
+	stream.Close()
+	if err := application.NewChangeStreamPublisher(stream, checkpoints, resumed, time.Second).PublishPending(ctx); err != nil {
+		t.Fatalf("PublishPending() = %v", err)
+	}
+	if position, _ := checkpoints.Load(ctx, shardID(t, stream)); position != domain.ShardEndCheckpoint {
+		t.Errorf("checkpoint = %q, want %q", position, domain.ShardEndCheckpoint)
+	}
+}
+
+func TestChangeStreamPublisherStopsAtFailedRecord(t *testing.T) {
+	ctx := context.Background()
+	onboarding, active := testEmployee(t)
+
+	tests := []struct {
+		name    string
+		failing func(second *domain.ChangeRecord) map[string]bool
+		second  func(stream *infrastructure.InMemoryChangeStream) *domain.ChangeRecord
+	}{
+		{
+			name: "falla la publicación del cambio de estado",
+			failing: func(second *domain.ChangeRecord) map[string]bool {
+				return map[string]bool{"stream-" + second.SequenceNumber + "-status": true}
+			},
+			second: func(stream *infrastructure.InMemoryChangeStream) *domain.ChangeRecord {
+				return stream.Append(domain.ChangeModify, onboarding, active)
+			},
+		},
+		{
+			name: "registro sin imagen nueva",
+			second: func(stream *infrastructure.InMemoryChangeStream) *domain.ChangeRecord {
+				return stream.Append(domain.ChangeModify, onboarding, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := infrastructure.NewInMemoryChangeStream()
+			checkpoints := infrastructure.NewInMemoryCheckpointStore()
+
+			first := stream.Append(domain.ChangeInsert, nil, onboarding)
+			second := tt.second(stream)
+			third := stream.Append(domain.ChangeRemove, active, nil)
+
+			publisher := &recordingPublisher{}
+			if tt.failing != nil {
+				publisher.failing = tt.failing(second)
+			}
+			if err := application.NewChangeStreamPublisher(stream, checkpoints, publisher, time.Second).PublishPending(ctx); err == nil {
+				t.Fatal("PublishPending() = nil, want error")
+			}
+
+			// El checkpoint se queda en el último registro publicado completo y el
+			// siguiente registro no se publica antes que el que falló
+			if position, _ := checkpoints.Load(ctx, shardID(t, stream)); position != first.SequenceNumber {
+				t.Errorf("checkpoint = %q, want %q", position, first.SequenceNumber)
+			}
+			for _, published := range publisher.published {
+				if published == "stream-"+third.SequenceNumber+" employee.deleted" {
+					t.Errorf("published %q past the failed record", published)
+				}
+			}
+		})
+	}
+}
+
+func TestChangeStreamPublisherRetriesFromCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	stream := infrastructure.NewInMemoryChangeStream()
+	checkpoints := infrastructure.NewInMemoryCheckpointStore()
+	onboarding, active := testEmployee(t)
+
+	stream.Append(domain.ChangeInsert, nil, onboarding)
+	second := stream.Append(domain.ChangeModify, onboarding, active)
+
+	statusEventID := "stream-" + second.SequenceNumber + "-status"
+	publisher := &recordingPublisher{failing: map[string]bool{statusEventID: true}}
+	relay := application.NewChangeStreamPublisher(stream, checkpoints, publisher, time.Second)
+	if err := relay.PublishPending(ctx); err == nil {
+		t.Fatal("PublishPending() = nil, want error")
+	}
+
+	// La siguiente pasada reprocesa el registro completo: at-least-once, con los mismos IDs
+	publisher.failing = nil
+	publisher.published = nil
+	if err := relay.PublishPending(ctx); err != nil {
+		t.Fatalf("PublishPending() = %v", err)
+	}
+	want := []string{
+		"stream-" + second.SequenceNumber + " employee.updated",
+		statusEventID + " employee.status_changed",
+	}
+	if !reflect.DeepEqual(publisher.published, want) {
+		t.Errorf("published %v, want %v", publisher.published, want)
+	}
+}
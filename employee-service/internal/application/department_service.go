@@ -0,0 +1,64 @@
+package application
+
+import (
+	"context"
+	"employee-service/internal/domain"
+	"employee-service/internal/ports"
+
+	"github.com/google/uuid"
+)
+
+// DepartmentService implementa la lógica de negocio para departamentos
+type DepartmentService struct {
+	repository ports.DepartmentRepository
+	employees  ports.EmployeeRepository
+}
+
+// NewDepartmentService crea una nueva instancia del servicio
+func NewDepartmentService(repo ports.DepartmentRepository, employees ports.EmployeeRepository) *DepartmentService {
+	return &DepartmentService{
+		repository: repo,
+		employees:  employees,
+	}
+}
+
+// CreateDepartment crea un nuevo departamento
+func (s *DepartmentService) CreateDepartment(ctx context.Context, name, description string) (*domain.Department, error) {
+	department := domain.NewDepartment(name, description)
+
+	if err := department.Validate(); err != nil {
+		return nil, err
+	}
+
+	department.ID = uuid.New().String()
+
+	if err := s.repository.Save(ctx, department); err != nil {
+		return nil, err
+	}
+
+	return department, nil
+}
+
+// GetAllDepartments obtiene todos los departamentos
+func (s *DepartmentService) GetAllDepartments(ctx context.Context) ([]*domain.Department, error) {
+	return s.repository.FindAll(ctx)
+}
+
+// GetDepartmentByID obtiene un departamento por su ID
+func (s *DepartmentService) GetDepartmentByID(ctx context.Context, id string) (*domain.Department, error) {
+	return s.repository.FindByID(ctx, id)
+}
+
+// GetOrgChart construye el organigrama de un departamento
+func (s *DepartmentService) GetOrgChart(ctx context.Context, departmentID string) ([]*domain.OrgChartNode, error) {
+	if _, err := s.repository.FindByID(ctx, departmentID); err != nil {
+		return nil, err
+	}
+
+	employees, err := s.employees.FindByDepartmentID(ctx, departmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.BuildOrgChart(employees), nil
+}
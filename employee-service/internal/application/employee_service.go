@@ -4,73 +4,329 @@ import (
 	"context"
 	"employee-service/internal/domain"
 	"employee-service/internal/ports"
+	"log"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// assignManagerMaxAttempts es el máximo de intentos de una asignación de jefe cuando
+// la jerarquía cambia entre la validación y la escritura
+const assignManagerMaxAttempts = 3
+
 // EmployeeService implementa la lógica de negocio para empleados
+// Los eventos no se publican directamente: se escriben en el outbox junto con el
+// cambio que los origina y el OutboxRelay se encarga de publicarlos.
+// Si outbox es nil los eventos se obtienen del stream de la tabla (ChangeStreamPublisher).
 type EmployeeService struct {
 	repository     ports.EmployeeRepository
-	publisher      ports.EventPublisher
+	departments    ports.DepartmentRepository
+	outbox         ports.OutboxStore
 	passwordHasher ports.PasswordHasher
 }
 
 // NewEmployeeService crea una nueva instancia del servicio
-func NewEmployeeService(repo ports.EmployeeRepository, pub ports.EventPublisher, hasher ports.PasswordHasher) *EmployeeService {
+func NewEmployeeService(repo ports.EmployeeRepository, departments ports.DepartmentRepository, outbox ports.OutboxStore, hasher ports.PasswordHasher) *EmployeeService {
 	return &EmployeeService{
 		repository:     repo,
-		publisher:      pub,
+		departments:    departments,
+		outbox:         outbox,
 		passwordHasher: hasher,
 	}
 }
 
+// CreateEmployeeInput contiene los datos necesarios para crear un empleado
+type CreateEmployeeInput struct {
+	Name         string
+	Email        string
+	Password     string
+	DepartmentID string
+	ManagerID    string
+}
+
 // CreateEmployee crea un nuevo empleado
-func (s *EmployeeService) CreateEmployee(ctx context.Context, name, email, password string) (*domain.Employee, error) {
-	employee := domain.NewEmployee(name, email, password)
+func (s *EmployeeService) CreateEmployee(ctx context.Context, input CreateEmployeeInput) (*domain.Employee, error) {
+	employee, err := s.newValidatedEmployee(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkReferences(ctx, employee.DepartmentID, employee.ManagerID); err != nil {
+		return nil, err
+	}
+
+	if err := s.assignIdentity(employee, input.Password); err != nil {
+		return nil, err
+	}
+
+	entry, err := domain.NewOutboxEntry(newEmployeeEvent("employee.created", employee))
+	if err != nil {
+		return nil, err
+	}
+
+	// El empleado y su evento employee.created se guardan en la misma transacción
+	if err := s.persist(ctx, employee, entry); err != nil {
+		return nil, err
+	}
+
+	return employee, nil
+}
+
+// newValidatedEmployee construye el empleado y valida sus datos básicos
+func (s *EmployeeService) newValidatedEmployee(input CreateEmployeeInput) (*domain.Employee, error) {
+	employee := domain.NewEmployee(input.Name, input.Email, input.Password)
+	employee.DepartmentID = input.DepartmentID
+	employee.ManagerID = input.ManagerID
 
 	if err := employee.Validate(); err != nil {
 		return nil, err
 	}
+	return employee, nil
+}
+
+// checkReferences verifica que el departamento y el jefe indicados existan.
+// Un empleado nuevo no puede cerrar un ciclo: basta con verificar que el jefe exista
+func (s *EmployeeService) checkReferences(ctx context.Context, departmentID, managerID string) error {
+	if err := s.ensureDepartmentExists(ctx, departmentID); err != nil {
+		return err
+	}
+	return s.ensureManagerExists(ctx, managerID)
+}
 
+// assignIdentity hashea el password y asigna un ID nuevo al empleado
+func (s *EmployeeService) assignIdentity(employee *domain.Employee, password string) error {
 	// Hashear el password usando el puerto (Strategy Pattern)
 	hashedPassword, err := s.passwordHasher.Hash(password)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	employee.Password = hashedPassword
-
 	employee.ID = uuid.New().String()
+	return nil
+}
+
+// newEmployeeEvent construye un evento del empleado (employee.created,
+// employee.updated) sin información sensible
+func newEmployeeEvent(eventType string, employee *domain.Employee) *domain.EmployeeEvent {
+	return &domain.EmployeeEvent{
+		EventID:   uuid.New().String(),
+		EventType: eventType,
+		Employee:  employee.EventData(),
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+}
+
+// GetAllEmployees obtiene todos los empleados
+func (s *EmployeeService) GetAllEmployees(ctx context.Context) ([]*domain.Employee, error) {
+	return s.repository.FindAll(ctx)
+}
+
+// GetEmployeeByID obtiene un empleado por su ID
+func (s *EmployeeService) GetEmployeeByID(ctx context.Context, id string) (*domain.Employee, error) {
+	return s.repository.FindByID(ctx, id)
+}
+
+// AssignManager asigna (o elimina, si managerID es "") el jefe directo de un empleado.
+// La escritura se condiciona a la cadena de mando leída al validar que no hay ciclos;
+// si otra asignación la cambió mientras tanto, se vuelve a leer y validar.
+func (s *EmployeeService) AssignManager(ctx context.Context, employeeID, managerID string) (*domain.Employee, error) {
+	for attempt := 1; ; attempt++ {
+		employee, err := s.assignManager(ctx, employeeID, managerID)
+		if err != domain.ErrHierarchyChanged || attempt == assignManagerMaxAttempts {
+			return employee, err
+		}
+		log.Printf("Hierarchy changed while assigning manager of %s, retrying (attempt %d)", employeeID, attempt)
+	}
+}
+
+// assignManager lee el empleado y la cadena de mando del nuevo jefe, valida la
+// asignación y la guarda condicionada a que nada de lo leído haya cambiado
+func (s *EmployeeService) assignManager(ctx context.Context, employeeID, managerID string) (*domain.Employee, error) {
+	employee, err := s.repository.FindByID(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureManagerExists(ctx, managerID); err != nil {
+		return nil, err
+	}
+
+	var chain []domain.ManagerLink
+	if err := domain.ValidateManagerAssignment(employeeID, managerID, func(id string) (string, error) {
+		emp, err := s.repository.FindByID(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		chain = append(chain, domain.ManagerLink{EmployeeID: id, ManagerID: emp.ManagerID})
+		return emp.ManagerID, nil
+	}); err != nil {
+		return nil, err
+	}
+
+	previousManagerID := employee.ManagerID
+	employee.ManagerID = managerID
+
+	var entry *domain.OutboxEntry
+	if s.outbox != nil {
+		entry, err = domain.NewOutboxEntry(newEmployeeEvent("employee.updated", employee))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repository.SaveManagerAssignment(ctx, employee, previousManagerID, chain, entry); err != nil {
+		return nil, err
+	}
+
+	return employee, nil
+}
+
+// AssignDepartment asigna (o elimina, si departmentID es "") el departamento de un empleado
+func (s *EmployeeService) AssignDepartment(ctx context.Context, employeeID, departmentID string) (*domain.Employee, error) {
+	employee, err := s.repository.FindByID(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureDepartmentExists(ctx, departmentID); err != nil {
+		return nil, err
+	}
+
+	employee.DepartmentID = departmentID
+	if err := s.persistUpdate(ctx, employee); err != nil {
+		return nil, err
+	}
+
+	return employee, nil
+}
+
+// GetDirectReports obtiene los empleados que reportan directamente a un jefe
+func (s *EmployeeService) GetDirectReports(ctx context.Context, managerID string) ([]*domain.Employee, error) {
+	if _, err := s.repository.FindByID(ctx, managerID); err != nil {
+		return nil, err
+	}
+	return s.repository.FindByManagerID(ctx, managerID)
+}
+
+// GetManagementChain obtiene la cadena de mando de un empleado,
+// desde su jefe directo hasta la cima de la jerarquía
+func (s *EmployeeService) GetManagementChain(ctx context.Context, employeeID string) ([]*domain.Employee, error) {
+	employee, err := s.repository.FindByID(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := []*domain.Employee{}
+	visited := map[string]bool{employee.ID: true}
+	for managerID := employee.ManagerID; managerID != ""; {
+		if visited[managerID] {
+			return nil, domain.ErrManagerCycle
+		}
+		visited[managerID] = true
+
+		manager, err := s.repository.FindByID(ctx, managerID)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, manager)
+		managerID = manager.ManagerID
+	}
 
-	if err := s.repository.Save(ctx, employee); err != nil {
+	return chain, nil
+}
+
+// ChangeStatus aplica una transición del ciclo de vida laboral y registra un evento por cada transición.
+// El evento de una transición con fecha efectiva futura se publica cuando entra en vigor.
+func (s *EmployeeService) ChangeStatus(ctx context.Context, employeeID string, status domain.EmploymentStatus, reason domain.ReasonCode, effectiveDate time.Time) (*domain.Employee, error) {
+	employee, err := s.repository.FindByID(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	transition, err := employee.TransitionTo(status, reason, effectiveDate)
+	if err != nil {
 		return nil, err
 	}
 
-	// Publicar evento (sin información sensible)
 	event := &domain.EmployeeEvent{
-		EventType: "employee.created",
-		Employee: &domain.EmployeeEventData{
-			ID:        employee.ID,
-			Name:      employee.Name,
-			Email:     employee.Email,
-			CreatedAt: employee.CreatedAt.Format(time.RFC3339),
+		EventID:   uuid.New().String(),
+		EventType: "employee.status_changed",
+		Employee:  employee.EventData(),
+		Transition: &domain.StatusTransitionData{
+			From:          string(transition.From),
+			To:            string(transition.To),
+			ReasonCode:    string(transition.ReasonCode),
+			EffectiveDate: transition.EffectiveDate.Format(time.RFC3339),
 		},
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
+	if transition.EffectiveDate.After(time.Now()) {
+		event.Timestamp = transition.EffectiveDate.Format(time.RFC3339)
+	}
+
+	entry, err := domain.NewOutboxEntry(event)
+	if err != nil {
+		return nil, err
+	}
+	entry.ScheduleAt(transition.EffectiveDate)
 
-	if err := s.publisher.PublishEmployeeCreated(ctx, event); err != nil {
+	if err := s.persist(ctx, employee, entry); err != nil {
 		return nil, err
 	}
 
 	return employee, nil
 }
 
-// GetAllEmployees obtiene todos los empleados
-func (s *EmployeeService) GetAllEmployees(ctx context.Context) ([]*domain.Employee, error) {
-	return s.repository.FindAll(ctx)
+// GetStatusHistory obtiene el historial de transiciones de estado de un empleado
+func (s *EmployeeService) GetStatusHistory(ctx context.Context, employeeID string) ([]domain.StatusTransition, error) {
+	employee, err := s.repository.FindByID(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if employee.StatusHistory == nil {
+		return []domain.StatusTransition{}, nil
+	}
+	return employee.StatusHistory, nil
 }
 
-// GetEmployeeByID obtiene un empleado por su ID
-func (s *EmployeeService) GetEmployeeByID(ctx context.Context, id string) (*domain.Employee, error) {
-	return s.repository.FindByID(ctx, id)
+// persist guarda el empleado junto con la entrada del outbox, o solo el empleado
+// cuando los eventos se capturan desde el stream de la tabla
+func (s *EmployeeService) persist(ctx context.Context, employee *domain.Employee, entry *domain.OutboxEntry) error {
+	if s.outbox == nil {
+		return s.repository.Save(ctx, employee)
+	}
+	return s.repository.SaveWithOutbox(ctx, employee, entry)
+}
+
+// persistUpdate guarda un cambio del empleado junto con su evento employee.updated
+func (s *EmployeeService) persistUpdate(ctx context.Context, employee *domain.Employee) error {
+	entry, err := domain.NewOutboxEntry(newEmployeeEvent("employee.updated", employee))
+	if err != nil {
+		return err
+	}
+	return s.persist(ctx, employee, entry)
+}
+
+// ensureDepartmentExists verifica que el departamento exista (si se indicó uno)
+func (s *EmployeeService) ensureDepartmentExists(ctx context.Context, departmentID string) error {
+	if departmentID == "" {
+		return nil
+	}
+	_, err := s.departments.FindByID(ctx, departmentID)
+	return err
+}
+
+// ensureManagerExists verifica que el jefe exista (si se indicó uno)
+func (s *EmployeeService) ensureManagerExists(ctx context.Context, managerID string) error {
+	if managerID == "" {
+		return nil
+	}
+	if _, err := s.repository.FindByID(ctx, managerID); err != nil {
+		if err == domain.ErrNotFound {
+			return domain.ErrManagerNotFound
+		}
+		return err
+	}
+	return nil
 }
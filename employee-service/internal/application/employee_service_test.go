@@ -0,0 +1,193 @@
+package application
+
+import (
+	"context"
+	"employee-service/internal/domain"
+	"errors"
+	"testing"
+)
+
+// fakeEmployeeRepository es un repositorio en memoria. beforeSave, si está definido,
+// se ejecuta antes de cada SaveManagerAssignment para simular una escritura concurrente.
+type fakeEmployeeRepository struct {
+	employees  map[string]*domain.Employee
+	beforeSave func(attempt int)
+	saves      int
+}
+
+func newFakeEmployeeRepository(employees ...*domain.Employee) *fakeEmployeeRepository {
+	repository := &fakeEmployeeRepository{employees: make(map[string]*domain.Employee)}
+	for _, employee := range employees {
+		repository.employees[employee.ID] = employee
+	}
+	return repository
+}
+
+func (r *fakeEmployeeRepository) Save(ctx context.Context, employee *domain.Employee) error {
+	copied := *employee
+	r.employees[employee.ID] = &copied
+	return nil
+}
+
+func (r *fakeEmployeeRepository) SaveWithOutbox(ctx context.Context, employee *domain.Employee, entry *domain.OutboxEntry) error {
+	return r.Save(ctx, employee)
+}
+
+func (r *fakeEmployeeRepository) SaveBatch(ctx context.Context, employees []*domain.Employee) ([]string, error) {
+	for _, employee := range employees {
+		r.Save(ctx, employee)
+	}
+	return nil, nil
+}
+
+func (r *fakeEmployeeRepository) SaveBatchWithOutbox(ctx context.Context, employees []*domain.Employee, entries []*domain.OutboxEntry) ([]string, error) {
+	return r.SaveBatch(ctx, employees)
+}
+
+func (r *fakeEmployeeRepository) SaveManagerAssignment(ctx context.Context, employee *domain.Employee, previousManagerID string, chain []domain.ManagerLink, entry *domain.OutboxEntry) error {
+	r.saves++
+	if r.beforeSave != nil {
+		r.beforeSave(r.saves)
+	}
+
+	stored, ok := r.employees[employee.ID]
+	if !ok || stored.ManagerID != previousManagerID {
+		return domain.ErrHierarchyChanged
+	}
+	for _, link := range chain {
+		linked, ok := r.employees[link.EmployeeID]
+		if !ok || linked.ManagerID != link.ManagerID {
+			return domain.ErrHierarchyChanged
+		}
+	}
+	return r.Save(ctx, employee)
+}
+
+func (r *fakeEmployeeRepository) FindByID(ctx context.Context, id string) (*domain.Employee, error) {
+	employee, ok := r.employees[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	copied := *employee
+	return &copied, nil
+}
+
+func (r *fakeEmployeeRepository) FindExistingEmails(ctx context.Context, emails []string) (map[string]bool, error) {
+	return map[string]bool{}, nil
+}
+
+func (r *fakeEmployeeRepository) FindAll(ctx context.Context) ([]*domain.Employee, error) {
+	var employees []*domain.Employee
+	for _, employee := range r.employees {
+		employees = append(employees, employee)
+	}
+	return employees, nil
+}
+
+func (r *fakeEmployeeRepository) FindPage(ctx context.Context, cursor string, limit int) (*domain.EmployeePage, error) {
+	employees, _ := r.FindAll(ctx)
+	return &domain.EmployeePage{Employees: employees}, nil
+}
+
+func (r *fakeEmployeeRepository) FindByManagerID(ctx context.Context, managerID string) ([]*domain.Employee, error) {
+	var employees []*domain.Employee
+	for _, employee := range r.employees {
+		if employee.ManagerID == managerID {
+			employees = append(employees, employee)
+		}
+	}
+	return employees, nil
+}
+
+func (r *fakeEmployeeRepository) FindByDepartmentID(ctx context.Context, departmentID string) ([]*domain.Employee, error) {
+	var employees []*domain.Employee
+	for _, employee := range r.employees {
+		if employee.DepartmentID == departmentID {
+			employees = append(employees, employee)
+		}
+	}
+	return employees, nil
+}
+
+func TestAssignManagerIsConditionalOnTheChainItRead(t *testing.T) {
+	tests := []struct {
+		name string
+		// concurrent modifica el repositorio entre la validación y la escritura del intento indicado
+		concurrent  func(repository *fakeEmployeeRepository, attempt int)
+		wantError   error
+		wantManager string // jefe de "x" tras la operación
+		wantSaves   int
+	}{
+		{
+			name:        "sin cambios concurrentes",
+			wantManager: "y",
+			wantSaves:   1,
+		},
+		{
+			name: "el nuevo jefe pasa a reportar al empleado: se detecta el ciclo al reintentar",
+			concurrent: func(repository *fakeEmployeeRepository, attempt int) {
+				if attempt == 1 {
+					repository.employees["y"].ManagerID = "x"
+				}
+			},
+			wantError: domain.ErrManagerCycle,
+			wantSaves: 1,
+		},
+		{
+			name: "cambio en la cadena sin ciclo: el reintento se guarda",
+			concurrent: func(repository *fakeEmployeeRepository, attempt int) {
+				if attempt == 1 {
+					repository.employees["y"].ManagerID = "z"
+				}
+			},
+			wantManager: "y",
+			wantSaves:   2,
+		},
+		{
+			name: "el empleado cambió de jefe: se reintenta",
+			concurrent: func(repository *fakeEmployeeRepository, attempt int) {
+				if attempt == 1 {
+					repository.employees["x"].ManagerID = "z"
+				}
+			},
+			wantManager: "y",
+			wantSaves:   2,
+		},
+		{
+			name: "la jerarquía cambia en cada intento",
+			concurrent: func(repository *fakeEmployeeRepository, attempt int) {
+				if attempt%2 == 1 {
+					repository.employees["y"].ManagerID = "z"
+				} else {
+					repository.employees["y"].ManagerID = ""
+				}
+			},
+			wantError: domain.ErrHierarchyChanged,
+			wantSaves: assignManagerMaxAttempts,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := newFakeEmployeeRepository(
+				&domain.Employee{ID: "x"},
+				&domain.Employee{ID: "y"},
+				&domain.Employee{ID: "z"},
+			)
+			if tt.concurrent != nil {
+				repository.beforeSave = func(attempt int) { tt.concurrent(repository, attempt) }
+			}
+			service := NewEmployeeService(repository, nil, newFakeOutboxStore(), nil)
+
+			_, err := service.AssignManager(context.Background(), "x", "y")
+			if !errors.Is(err, tt.wantError) {
+				t.Fatalf("AssignManager() = %v, want %v", err, tt.wantError)
+			}
+			if repository.saves != tt.wantSaves {
+				t.Errorf("SaveManagerAssignment called %d times, want %d", repository.saves, tt.wantSaves)
+			}
+			if tt.wantError == nil && repository.employees["x"].ManagerID != tt.wantManager {
+				t.Errorf("manager of x = %q, want %q", repository.employees["x"].ManagerID, tt.wantManager)
+			}
+		})
+	}
+}
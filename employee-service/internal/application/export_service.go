@@ -0,0 +1,60 @@
+package application
+
+import (
+	"context"
+	"employee-service/internal/domain"
+	"employee-service/internal/ports"
+	"log"
+)
+
+// exportPageSize es la cantidad de empleados leídos del repositorio por página
+const exportPageSize = 100
+
+// ExportEmployees recorre el repositorio página a página y escribe cada empleado
+// que cumple el filtro, sin cargar el directorio completo en memoria.
+// Devuelve la cantidad de filas exportadas. Si falla, la exportación se aborta
+// sin volcar lo que quede pendiente en el escritor.
+func (s *EmployeeService) ExportEmployees(ctx context.Context, filter domain.EmployeeFilter, columns []string, writer ports.EmployeeExportWriter) (int, error) {
+	exported, err := s.exportEmployees(ctx, filter, columns, writer)
+	if err != nil {
+		writer.Abort()
+		return exported, err
+	}
+	if err := writer.Close(); err != nil {
+		return exported, err
+	}
+
+	log.Printf("Export finished: %d employees", exported)
+	return exported, nil
+}
+
+func (s *EmployeeService) exportEmployees(ctx context.Context, filter domain.EmployeeFilter, columns []string, writer ports.EmployeeExportWriter) (int, error) {
+	if err := writer.WriteHeader(columns); err != nil {
+		return 0, err
+	}
+
+	exported := 0
+	cursor := ""
+	for {
+		page, err := s.repository.FindPage(ctx, cursor, exportPageSize)
+		if err != nil {
+			return exported, err
+		}
+
+		for _, employee := range page.Employees {
+			if !filter.Matches(employee) {
+				continue
+			}
+			if err := writer.WriteRow(employee.ExportValues(columns)); err != nil {
+				return exported, err
+			}
+			exported++
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	return exported, nil
+}
@@ -0,0 +1,192 @@
+package application
+
+import (
+	"context"
+	"employee-service/internal/domain"
+	"employee-service/internal/ports"
+	"io"
+	"log"
+	"strings"
+)
+
+// importBatchSize es la cantidad de empleados acumulados antes de escribir en el repositorio
+const importBatchSize = 25
+
+// pendingImport asocia un empleado validado con su fila de origen
+type pendingImport struct {
+	row      int
+	employee *domain.Employee
+	password string
+}
+
+// ImportEmployees importa empleados leyendo registros en streaming.
+// Cada fila se valida con Employee.Validate y se reportan los errores por fila.
+// Los emails se comparan dentro del archivo y, por lotes, con los empleados ya guardados.
+// En modo dry-run solo se valida, sin escribir ni publicar eventos.
+// Las filas válidas se escriben en lotes, cada empleado junto con su evento employee.created en el outbox.
+func (s *EmployeeService) ImportEmployees(ctx context.Context, reader ports.EmployeeRecordReader, dryRun bool) (*domain.ImportReport, error) {
+	report := &domain.ImportReport{
+		DryRun: dryRun,
+		Errors: []*domain.ImportRowError{},
+	}
+
+	seenEmails := make(map[string]bool)
+	referenceChecks := make(map[string]error)
+	batch := make([]*pendingImport, 0, importBatchSize)
+
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return report, err
+		}
+		report.TotalRows++
+
+		if record.ParseError != nil {
+			report.AddError(record.Row, record.Email, record.ParseError)
+			continue
+		}
+
+		employee, err := s.newValidatedEmployee(CreateEmployeeInput{
+			Name:         record.Name,
+			Email:        record.Email,
+			Password:     record.Password,
+			DepartmentID: record.DepartmentID,
+			ManagerID:    record.ManagerID,
+		})
+		if err != nil {
+			report.AddError(record.Row, record.Email, err)
+			continue
+		}
+
+		emailKey := strings.ToLower(employee.Email)
+		if seenEmails[emailKey] {
+			report.AddError(record.Row, record.Email, domain.ErrDuplicateEmail)
+			continue
+		}
+
+		// Cachear las verificaciones de departamento/jefe: suelen repetirse entre filas
+		referenceKey := employee.DepartmentID + "|" + employee.ManagerID
+		checkErr, checked := referenceChecks[referenceKey]
+		if !checked {
+			checkErr = s.checkReferences(ctx, employee.DepartmentID, employee.ManagerID)
+			referenceChecks[referenceKey] = checkErr
+		}
+		if checkErr != nil {
+			report.AddError(record.Row, record.Email, checkErr)
+			continue
+		}
+		seenEmails[emailKey] = true
+
+		batch = append(batch, &pendingImport{row: record.Row, employee: employee, password: record.Password})
+		if len(batch) == importBatchSize {
+			s.flushImportBatch(ctx, batch, dryRun, report)
+			batch = batch[:0]
+		}
+	}
+
+	if len(batch) > 0 {
+		s.flushImportBatch(ctx, batch, dryRun, report)
+	}
+
+	log.Printf("Import finished: rows=%d imported=%d failed=%d dry_run=%t", report.TotalRows, report.Imported, report.Failed, dryRun)
+	return report, nil
+}
+
+// flushImportBatch descarta las filas cuyo email ya pertenece a un empleado guardado
+// y escribe el resto del lote (en modo dry-run solo las cuenta)
+func (s *EmployeeService) flushImportBatch(ctx context.Context, batch []*pendingImport, dryRun bool, report *domain.ImportReport) {
+	emails := make([]string, len(batch))
+	for i, pending := range batch {
+		emails[i] = pending.employee.Email
+	}
+	existing, err := s.repository.FindExistingEmails(ctx, emails)
+	if err != nil {
+		log.Printf("Error checking existing emails for import batch: %v", err)
+		for _, pending := range batch {
+			report.AddError(pending.row, pending.employee.Email, err)
+		}
+		return
+	}
+
+	accepted := make([]*pendingImport, 0, len(batch))
+	for _, pending := range batch {
+		if existing[pending.employee.Email] {
+			report.AddError(pending.row, pending.employee.Email, domain.ErrEmailAlreadyExists)
+			continue
+		}
+		if dryRun {
+			report.Imported++
+			continue
+		}
+		if err := s.assignIdentity(pending.employee, pending.password); err != nil {
+			report.AddError(pending.row, pending.employee.Email, err)
+			continue
+		}
+		accepted = append(accepted, pending)
+	}
+
+	if len(accepted) > 0 {
+		s.saveImportBatch(ctx, accepted, report)
+	}
+}
+
+// saveImportBatch escribe un lote de empleados. Con outbox, cada empleado se escribe
+// junto con su evento employee.created en la misma transacción: una fila solo cuenta
+// como importada si se guardaron ambos. Sin outbox (modo stream) se usa BatchWriteItem.
+func (s *EmployeeService) saveImportBatch(ctx context.Context, batch []*pendingImport, report *domain.ImportReport) {
+	if s.outbox == nil {
+		employees := make([]*domain.Employee, len(batch))
+		for i, pending := range batch {
+			employees[i] = pending.employee
+		}
+		failedIDs, err := s.repository.SaveBatch(ctx, employees)
+		if err != nil {
+			log.Printf("Error saving import batch: %v", err)
+		}
+		s.reportImportBatch(batch, failedIDs, err, report)
+		return
+	}
+
+	saved := make([]*pendingImport, 0, len(batch))
+	employees := make([]*domain.Employee, 0, len(batch))
+	entries := make([]*domain.OutboxEntry, 0, len(batch))
+	for _, pending := range batch {
+		entry, err := domain.NewOutboxEntry(newEmployeeEvent("employee.created", pending.employee))
+		if err != nil {
+			report.AddError(pending.row, pending.employee.Email, err)
+			continue
+		}
+		saved = append(saved, pending)
+		employees = append(employees, pending.employee)
+		entries = append(entries, entry)
+	}
+	if len(saved) == 0 {
+		return
+	}
+
+	failedIDs, err := s.repository.SaveBatchWithOutbox(ctx, employees, entries)
+	if err != nil {
+		log.Printf("Error saving import batch with outbox entries: %v", err)
+	}
+	s.reportImportBatch(saved, failedIDs, err, report)
+}
+
+// reportImportBatch cuenta como importadas las filas del lote que se guardaron
+// y como error las de los empleados en failedIDs
+func (s *EmployeeService) reportImportBatch(batch []*pendingImport, failedIDs []string, err error, report *domain.ImportReport) {
+	failed := make(map[string]bool, len(failedIDs))
+	for _, id := range failedIDs {
+		failed[id] = true
+	}
+
+	for _, pending := range batch {
+		if failed[pending.employee.ID] {
+			report.AddError(pending.row, pending.employee.Email, err)
+			continue
+		}
+		report.Imported++
+	}
+}
@@ -0,0 +1,237 @@
+package application
+
+import (
+	"context"
+	"employee-service/internal/domain"
+	"employee-service/internal/ports"
+	"log"
+	"time"
+)
+
+// Parámetros de reintento del relay
+const (
+	outboxRelayBatchSize = 50
+	outboxRetryBaseDelay = 2 * time.Second
+	outboxRetryMaxDelay  = 5 * time.Minute
+	outboxClaimLease     = 30 * time.Second // Tiempo reservado para publicar una entrada
+)
+
+// OutboxRelay publica en SQS las entradas pendientes del outbox y las marca como enviadas.
+// Cada entrada se reserva (Claim) antes de publicarla para que varias réplicas no la
+// publiquen a la vez, y tras maxAttempts fallos queda como failed.
+// Las entradas de un mismo agregado (empleado) se publican en orden de Sequence: mientras
+// una siga pendiente (en backoff o reservada) las siguientes se aplazan con ella.
+// Las entradas reservadas se publican en lotes (SendMessageBatch/PublishBatch) con
+// como mucho una entrada por agregado en cada lote.
+// La entrega es at-least-once: si el proceso cae entre la publicación y MarkSent
+// el evento se volverá a publicar cuando venza la reserva.
+type OutboxRelay struct {
+	store       ports.OutboxStore
+	publisher   ports.EventPublisher
+	interval    time.Duration
+	maxAttempts int
+}
+
+// NewOutboxRelay crea una nueva instancia del relay
+func NewOutboxRelay(store ports.OutboxStore, publisher ports.EventPublisher, interval time.Duration, maxAttempts int) *OutboxRelay {
+	return &OutboxRelay{
+		store:       store,
+		publisher:   publisher,
+		interval:    interval,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Run ejecuta el relay periódicamente hasta que se cancele el contexto
+func (r *OutboxRelay) Run(ctx context.Context) {
+	log.Printf("Outbox relay started (interval %s)", r.interval)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.RelayPending(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Error relaying outbox entries: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Println("Outbox relay stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending publica las entradas pendientes de los agregados con alguna entrada vencida
+func (r *OutboxRelay) RelayPending(ctx context.Context) error {
+	now := time.Now()
+	entries, err := r.store.FindPending(ctx, now, outboxRelayBatchSize)
+	if err != nil {
+		return err
+	}
+
+	// Entradas pendientes de cada agregado en orden de Sequence, vencidas o no
+	var queues [][]*domain.OutboxEntry
+	found := make(map[string]bool)
+	for _, entry := range entries {
+		if found[entry.AggregateID] {
+			continue
+		}
+		found[entry.AggregateID] = true
+
+		pending, err := r.store.FindPendingByAggregate(ctx, entry.AggregateID, outboxRelayBatchSize)
+		if err != nil {
+			log.Printf("Error reading outbox entries of %s: %v", entry.AggregateID, err)
+			continue
+		}
+		if len(pending) > 0 {
+			queues = append(queues, pending)
+		}
+	}
+
+	// Cada ronda publica en lote la primera entrada de cada agregado; la siguiente de
+	// un agregado entra en la ronda posterior solo si la anterior quedó marcada como enviada
+	for len(queues) > 0 {
+		queues = r.relayRound(ctx, queues, now)
+	}
+
+	return nil
+}
+
+// relayRound reserva y publica la primera entrada de cada cola y devuelve las colas que
+// pueden seguir. Una cola se detiene en la primera entrada que no se publica: las
+// siguientes se aplazan hasta su próximo intento para que ninguna adelante a otra
+// anterior ni vuelva a ocupar la tanda mientras tanto.
+func (r *OutboxRelay) relayRound(ctx context.Context, queues [][]*domain.OutboxEntry, now time.Time) [][]*domain.OutboxEntry {
+	var claimed []*domain.OutboxEntry
+	var claimedQueues [][]*domain.OutboxEntry
+	for _, queue := range queues {
+		if blockedUntil, ok := r.claim(ctx, queue[0], now); !ok {
+			r.postpone(ctx, queue[1:], blockedUntil)
+			continue
+		}
+		claimed = append(claimed, queue[0])
+		claimedQueues = append(claimedQueues, queue)
+	}
+	if len(claimed) == 0 {
+		return nil
+	}
+
+	var next [][]*domain.OutboxEntry
+	for i, err := range r.publishBatch(ctx, claimed) {
+		queue := claimedQueues[i]
+		if blockedUntil, sent := r.complete(ctx, claimed[i], err); !sent {
+			r.postpone(ctx, queue[1:], blockedUntil)
+			continue
+		}
+		if len(queue) > 1 {
+			next = append(next, queue[1:])
+		}
+	}
+	return next
+}
+
+// claim reserva una entrada vencida. Si no la reserva devuelve hasta cuándo bloquea a
+// las siguientes de su agregado (cero si no hay que aplazarlas: otra réplica la
+// reservó y sigue con el agregado).
+func (r *OutboxRelay) claim(ctx context.Context, entry *domain.OutboxEntry, now time.Time) (time.Time, bool) {
+	if entry.NextAttemptAt > now.Unix() {
+		// En backoff o reservada: las siguientes esperan a su próximo intento
+		return time.Unix(entry.NextAttemptAt, 0), false
+	}
+
+	claimed, err := r.store.Claim(ctx, entry, time.Now().Add(outboxClaimLease))
+	if err != nil {
+		log.Printf("Error claiming outbox entry %s: %v", entry.ID, err)
+		return time.Time{}, false
+	}
+	if !claimed {
+		// Otra réplica la reservó entre la consulta y la reserva
+		return time.Time{}, false
+	}
+	return time.Time{}, true
+}
+
+// complete registra el resultado de publicar una entrada reservada. Si no queda
+// marcada como enviada devuelve hasta cuándo bloquea a las siguientes de su agregado
+// (cero si la entrada quedó como failed y ya no las bloquea).
+func (r *OutboxRelay) complete(ctx context.Context, entry *domain.OutboxEntry, publishErr error) (time.Time, bool) {
+	if publishErr != nil {
+		attempts := entry.Attempts + 1
+		if attempts >= r.maxAttempts {
+			log.Printf("Outbox entry %s failed after %d attempts, giving up: %v", entry.ID, attempts, publishErr)
+			if err := r.store.MarkFailed(ctx, entry.ID, attempts, publishErr.Error()); err != nil {
+				log.Printf("Error marking outbox entry %s as failed: %v", entry.ID, err)
+				return time.Unix(entry.NextAttemptAt, 0), false
+			}
+			return time.Time{}, false
+		}
+		nextAttempt := time.Now().Add(retryDelay(attempts))
+		log.Printf("Error publishing outbox entry %s (attempt %d, next at %s): %v", entry.ID, attempts, nextAttempt.Format(time.RFC3339), publishErr)
+		if err := r.store.MarkRetry(ctx, entry.ID, attempts, nextAttempt, publishErr.Error()); err != nil {
+			log.Printf("Error scheduling retry for outbox entry %s: %v", entry.ID, err)
+			return time.Unix(entry.NextAttemptAt, 0), false
+		}
+		return nextAttempt, false
+	}
+
+	if err := r.store.MarkSent(ctx, entry.ID); err != nil {
+		// Sigue pendiente: se volverá a publicar al vencer la reserva, antes que las siguientes
+		log.Printf("Error marking outbox entry %s as sent: %v", entry.ID, err)
+		return time.Unix(entry.NextAttemptAt, 0), false
+	}
+	return time.Time{}, true
+}
+
+// postpone aplaza hasta until las entradas cuyo próximo intento es anterior
+func (r *OutboxRelay) postpone(ctx context.Context, entries []*domain.OutboxEntry, until time.Time) {
+	if until.IsZero() {
+		return
+	}
+	for _, entry := range entries {
+		if entry.NextAttemptAt >= until.Unix() {
+			continue
+		}
+		if err := r.store.Postpone(ctx, entry, until); err != nil {
+			log.Printf("Error postponing outbox entry %s: %v", entry.ID, err)
+		}
+	}
+}
+
+// publishBatch publica en un lote los eventos almacenados en las entradas y devuelve
+// el resultado de cada una en el mismo orden
+func (r *OutboxRelay) publishBatch(ctx context.Context, entries []*domain.OutboxEntry) []error {
+	results := make([]error, len(entries))
+
+	events := make([]*domain.EmployeeEvent, 0, len(entries))
+	indexes := make([]int, 0, len(entries))
+	for i, entry := range entries {
+		event, err := entry.Event()
+		if err != nil {
+			results[i] = err
+			continue
+		}
+		events = append(events, event)
+		indexes = append(indexes, i)
+	}
+
+	if len(events) > 0 {
+		for i, err := range r.publisher.PublishBatch(ctx, events) {
+			results[indexes[i]] = err
+		}
+	}
+	return results
+}
+
+// retryDelay calcula el backoff exponencial para el intento indicado
+func retryDelay(attempts int) time.Duration {
+	delay := outboxRetryBaseDelay
+	for i := 1; i < attempts && delay < outboxRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > outboxRetryMaxDelay {
+		delay = outboxRetryMaxDelay
+	}
+	return delay
+}
@@ -0,0 +1,351 @@
+package application
+
+import (
+	"context"
+	"employee-service/internal/domain"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// fakeOutboxStore es un outbox en memoria; devuelve copias como una lectura de DynamoDB
+type fakeOutboxStore struct {
+	entries map[string]*domain.OutboxEntry
+}
+
+func newFakeOutboxStore(entries ...*domain.OutboxEntry) *fakeOutboxStore {
+	store := &fakeOutboxStore{entries: make(map[string]*domain.OutboxEntry)}
+	for _, entry := range entries {
+		store.entries[entry.ID] = entry
+	}
+	return store
+}
+
+func (s *fakeOutboxStore) pending(match func(*domain.OutboxEntry) bool) []*domain.OutboxEntry {
+	var entries []*domain.OutboxEntry
+	for _, entry := range s.entries {
+		if entry.Status == domain.OutboxPending && match(entry) {
+			copied := *entry
+			entries = append(entries, &copied)
+		}
+	}
+	return entries
+}
+
+func (s *fakeOutboxStore) FindPending(ctx context.Context, now time.Time, limit int) ([]*domain.OutboxEntry, error) {
+	entries := s.pending(func(entry *domain.OutboxEntry) bool { return entry.NextAttemptAt <= now.Unix() })
+	sort.Slice(entries, func(i, j int) bool { return entries[i].NextAttemptAt < entries[j].NextAttemptAt })
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+func (s *fakeOutboxStore) FindPendingByAggregate(ctx context.Context, aggregateID string, limit int) ([]*domain.OutboxEntry, error) {
+	entries := s.pending(func(entry *domain.OutboxEntry) bool { return entry.AggregateID == aggregateID })
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Sequence < entries[j].Sequence })
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+func (s *fakeOutboxStore) Claim(ctx context.Context, entry *domain.OutboxEntry, leaseUntil time.Time) (bool, error) {
+	stored := s.entries[entry.ID]
+	if stored.Status != domain.OutboxPending || stored.NextAttemptAt != entry.NextAttemptAt {
+		return false, nil
+	}
+	stored.NextAttemptAt = leaseUntil.Unix()
+	entry.NextAttemptAt = stored.NextAttemptAt
+	return true, nil
+}
+
+func (s *fakeOutboxStore) Postpone(ctx context.Context, entry *domain.OutboxEntry, until time.Time) error {
+	_, err := s.Claim(ctx, entry, until)
+	return err
+}
+
+func (s *fakeOutboxStore) MarkSent(ctx context.Context, id string) error {
+	s.entries[id].Status = domain.OutboxSent
+	return nil
+}
+
+func (s *fakeOutboxStore) MarkRetry(ctx context.Context, id string, attempts int, nextAttemptAt time.Time, lastErr string) error {
+	entry := s.entries[id]
+	entry.Attempts, entry.NextAttemptAt, entry.LastError = attempts, nextAttemptAt.Unix(), lastErr
+	return nil
+}
+
+func (s *fakeOutboxStore) MarkFailed(ctx context.Context, id string, attempts int, lastErr string) error {
+	entry := s.entries[id]
+	entry.Status, entry.Attempts, entry.LastError = domain.OutboxFailed, attempts, lastErr
+	return nil
+}
+
+// fakeEventPublisher registra los eventos publicados (y los lotes) y falla con los de failing
+type fakeEventPublisher struct {
+	published []string
+	batches   [][]string
+	failing   map[string]bool
+}
+
+func (p *fakeEventPublisher) Publish(ctx context.Context, event *domain.EmployeeEvent) error {
+	if p.failing[event.EventID] {
+		return errors.New("queue unavailable")
+	}
+	p.published = append(p.published, event.EventID)
+	return nil
+}
+
+func (p *fakeEventPublisher) PublishBatch(ctx context.Context, events []*domain.EmployeeEvent) []error {
+	results := make([]error, len(events))
+	batch := make([]string, len(events))
+	for i, event := range events {
+		batch[i] = event.EventID
+		results[i] = p.Publish(ctx, event)
+	}
+	p.batches = append(p.batches, batch)
+	return results
+}
+
+// testOutboxEntry crea una entrada del empleado aggregateID con la secuencia y el
+// próximo intento indicados (relativo a ahora, en segundos)
+func testOutboxEntry(t *testing.T, id, aggregateID string, sequence int64, nextAttemptIn int) *domain.OutboxEntry {
+	t.Helper()
+	entry, err := domain.NewOutboxEntry(&domain.EmployeeEvent{
+		EventID:   id,
+		EventType: "employee.updated",
+		Employee:  &domain.EmployeeEventData{ID: aggregateID},
+	})
+	if err != nil {
+		t.Fatalf("NewOutboxEntry() = %v", err)
+	}
+	entry.Sequence = sequence
+	entry.NextAttemptAt = time.Now().Add(time.Duration(nextAttemptIn) * time.Second).Unix()
+	return entry
+}
+
+func TestOutboxRelayHoldsBackLaterEntriesOfAFailedAggregate(t *testing.T) {
+	created := testOutboxEntry(t, "emp-1-created", "emp-1", 1, -5)
+	updated := testOutboxEntry(t, "emp-1-updated", "emp-1", 2, -5)
+	other := testOutboxEntry(t, "emp-2-created", "emp-2", 3, -5)
+	store := newFakeOutboxStore(created, updated, other)
+	publisher := &fakeEventPublisher{failing: map[string]bool{"emp-1-created": true}}
+	relay := NewOutboxRelay(store, publisher, time.Second, 10)
+
+	if err := relay.RelayPending(context.Background()); err != nil {
+		t.Fatalf("RelayPending() = %v", err)
+	}
+
+	if want := []string{"emp-2-created"}; !reflect.DeepEqual(publisher.published, want) {
+		t.Fatalf("published = %v, want %v", publisher.published, want)
+	}
+	if created.Status != domain.OutboxPending || created.Attempts != 1 || created.NextAttemptAt <= time.Now().Unix() {
+		t.Errorf("failed entry = %+v, want pending with a retry scheduled", created)
+	}
+	if updated.Status != domain.OutboxPending || updated.Attempts != 0 || updated.NextAttemptAt != created.NextAttemptAt {
+		t.Errorf("held back entry = %+v, want pending until the failed entry's retry at %d", updated, created.NextAttemptAt)
+	}
+
+	// Al vencer el backoff se publican las dos en orden
+	created.NextAttemptAt = time.Now().Add(-time.Second).Unix()
+	updated.NextAttemptAt = created.NextAttemptAt
+	publisher.failing = nil
+	publisher.published = nil
+
+	if err := relay.RelayPending(context.Background()); err != nil {
+		t.Fatalf("RelayPending() = %v", err)
+	}
+	if want := []string{"emp-1-created", "emp-1-updated"}; !reflect.DeepEqual(publisher.published, want) {
+		t.Errorf("published = %v, want %v", publisher.published, want)
+	}
+}
+
+func TestOutboxRelayAggregateOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		// setup devuelve las entradas de emp-1 en orden de secuencia
+		setup         func(t *testing.T) []*domain.OutboxEntry
+		maxAttempts   int
+		failing       []string
+		wantPublished []string
+		// wantHeldUntil es el índice de la entrada hasta cuyo próximo intento se
+		// aplazan las siguientes (-1 = no se aplazan)
+		wantHeldUntil int
+	}{
+		{
+			name: "en orden de secuencia aunque venzan al revés",
+			setup: func(t *testing.T) []*domain.OutboxEntry {
+				return []*domain.OutboxEntry{
+					testOutboxEntry(t, "first", "emp-1", 1, -1),
+					testOutboxEntry(t, "second", "emp-1", 2, -5),
+					testOutboxEntry(t, "third", "emp-1", 3, -10),
+				}
+			},
+			wantPublished: []string{"first", "second", "third"},
+			wantHeldUntil: -1,
+		},
+		{
+			name: "anterior en backoff",
+			setup: func(t *testing.T) []*domain.OutboxEntry {
+				first := testOutboxEntry(t, "first", "emp-1", 1, 60)
+				first.Attempts = 3
+				return []*domain.OutboxEntry{first, testOutboxEntry(t, "second", "emp-1", 2, -5)}
+			},
+			wantHeldUntil: 0,
+		},
+		{
+			name: "anterior reservada por otra réplica",
+			setup: func(t *testing.T) []*domain.OutboxEntry {
+				return []*domain.OutboxEntry{
+					testOutboxEntry(t, "first", "emp-1", 1, 20),
+					testOutboxEntry(t, "second", "emp-1", 2, -5),
+				}
+			},
+			wantHeldUntil: 0,
+		},
+		{
+			name: "falla una intermedia",
+			setup: func(t *testing.T) []*domain.OutboxEntry {
+				return []*domain.OutboxEntry{
+					testOutboxEntry(t, "first", "emp-1", 1, -5),
+					testOutboxEntry(t, "second", "emp-1", 2, -5),
+					testOutboxEntry(t, "third", "emp-1", 3, -5),
+				}
+			},
+			failing:       []string{"second"},
+			wantPublished: []string{"first"},
+			wantHeldUntil: 1,
+		},
+		{
+			name: "la anterior agota los intentos",
+			setup: func(t *testing.T) []*domain.OutboxEntry {
+				first := testOutboxEntry(t, "first", "emp-1", 1, -5)
+				first.Attempts = 2
+				return []*domain.OutboxEntry{first, testOutboxEntry(t, "second", "emp-1", 2, -5)}
+			},
+			maxAttempts:   3,
+			failing:       []string{"first"},
+			wantHeldUntil: -1,
+		},
+		{
+			name: "una entrada failed no bloquea a las siguientes",
+			setup: func(t *testing.T) []*domain.OutboxEntry {
+				first := testOutboxEntry(t, "first", "emp-1", 1, -5)
+				first.Status = domain.OutboxFailed
+				return []*domain.OutboxEntry{first, testOutboxEntry(t, "second", "emp-1", 2, -5)}
+			},
+			wantPublished: []string{"second"},
+			wantHeldUntil: -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := tt.setup(t)
+			before := make([]int64, len(entries))
+			for i, entry := range entries {
+				before[i] = entry.NextAttemptAt
+			}
+
+			publisher := &fakeEventPublisher{failing: make(map[string]bool)}
+			for _, id := range tt.failing {
+				publisher.failing[id] = true
+			}
+			maxAttempts := tt.maxAttempts
+			if maxAttempts == 0 {
+				maxAttempts = 10
+			}
+
+			relay := NewOutboxRelay(newFakeOutboxStore(entries...), publisher, time.Second, maxAttempts)
+			if err := relay.RelayPending(context.Background()); err != nil {
+				t.Fatalf("RelayPending() = %v", err)
+			}
+
+			if !reflect.DeepEqual(publisher.published, tt.wantPublished) {
+				t.Errorf("published = %v, want %v", publisher.published, tt.wantPublished)
+			}
+			for i, entry := range entries {
+				published := false
+				for _, id := range tt.wantPublished {
+					published = published || id == entry.ID
+				}
+				switch {
+				case published:
+					if entry.Status != domain.OutboxSent {
+						t.Errorf("entry %s status = %s, want sent", entry.ID, entry.Status)
+					}
+				case tt.wantHeldUntil >= 0 && i > tt.wantHeldUntil:
+					if entry.Status != domain.OutboxPending || entry.NextAttemptAt != entries[tt.wantHeldUntil].NextAttemptAt {
+						t.Errorf("entry %s = %s at %d, want pending until %d", entry.ID, entry.Status, entry.NextAttemptAt, entries[tt.wantHeldUntil].NextAttemptAt)
+					}
+				case publisher.failing[entry.ID]:
+					if entry.Attempts == 0 {
+						t.Errorf("entry %s attempts = 0, want the failure recorded", entry.ID)
+					}
+				case entry.NextAttemptAt != before[i]:
+					t.Errorf("entry %s next attempt = %d, want it unchanged (%d)", entry.ID, entry.NextAttemptAt, before[i])
+				}
+			}
+		})
+	}
+}
+
+func TestOutboxRelayPublishesOneEntryPerAggregateInEachBatch(t *testing.T) {
+	store := newFakeOutboxStore(
+		testOutboxEntry(t, "emp-1-created", "emp-1", 1, -30),
+		testOutboxEntry(t, "emp-1-updated", "emp-1", 2, -20),
+		testOutboxEntry(t, "emp-2-created", "emp-2", 3, -25),
+		testOutboxEntry(t, "emp-3-created", "emp-3", 4, -10),
+	)
+	publisher := &fakeEventPublisher{}
+
+	if err := NewOutboxRelay(store, publisher, time.Second, 10).RelayPending(context.Background()); err != nil {
+		t.Fatalf("RelayPending() = %v", err)
+	}
+
+	want := [][]string{
+		{"emp-1-created", "emp-2-created", "emp-3-created"},
+		{"emp-1-updated"},
+	}
+	if !reflect.DeepEqual(publisher.batches, want) {
+		t.Errorf("batches = %v, want %v", publisher.batches, want)
+	}
+	for id, entry := range store.entries {
+		if entry.Status != domain.OutboxSent {
+			t.Errorf("entry %s status = %s, want sent", id, entry.Status)
+		}
+	}
+}
+
+func TestOutboxRelayPublishesScheduledEntryWhenDue(t *testing.T) {
+	// Una baja con fecha efectiva futura se registra antes que un cambio de jefe posterior
+	effectiveAt := time.Now().Add(time.Hour)
+	terminated := testOutboxEntry(t, "emp-1-terminated", "emp-1", time.Now().UnixNano(), 0)
+	terminated.ScheduleAt(effectiveAt)
+	updated := testOutboxEntry(t, "emp-1-updated", "emp-1", time.Now().UnixNano(), 0)
+	store := newFakeOutboxStore(terminated, updated)
+	publisher := &fakeEventPublisher{}
+	relay := NewOutboxRelay(store, publisher, time.Second, 10)
+
+	if err := relay.RelayPending(context.Background()); err != nil {
+		t.Fatalf("RelayPending() = %v", err)
+	}
+	if want := []string{"emp-1-updated"}; !reflect.DeepEqual(publisher.published, want) {
+		t.Fatalf("published = %v, want %v", publisher.published, want)
+	}
+	if terminated.Status != domain.OutboxPending || terminated.Attempts != 0 || terminated.NextAttemptAt != effectiveAt.Unix() {
+		t.Errorf("scheduled entry = %+v, want pending until %d", terminated, effectiveAt.Unix())
+	}
+
+	// Al llegar la fecha efectiva se publica
+	terminated.NextAttemptAt = time.Now().Add(-time.Second).Unix()
+	publisher.published = nil
+	if err := relay.RelayPending(context.Background()); err != nil {
+		t.Fatalf("RelayPending() = %v", err)
+	}
+	if want := []string{"emp-1-terminated"}; !reflect.DeepEqual(publisher.published, want) {
+		t.Errorf("published = %v, want %v", publisher.published, want)
+	}
+}
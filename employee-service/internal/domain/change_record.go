@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// ChangeOperation representa el tipo de cambio capturado en el stream de la tabla
+type ChangeOperation string
+
+const (
+	ChangeInsert ChangeOperation = "INSERT"
+	ChangeModify ChangeOperation = "MODIFY"
+	ChangeRemove ChangeOperation = "REMOVE"
+)
+
+// ShardEndCheckpoint marca un shard cerrado que ya se procesó por completo
+const ShardEndCheckpoint = "SHARD_END"
+
+// StreamShard representa un shard del stream de cambios
+type StreamShard struct {
+	ID       string
+	ParentID string
+}
+
+// ChangeRecord representa un cambio sobre la tabla de empleados (change data capture)
+type ChangeRecord struct {
+	SequenceNumber string
+	Operation      ChangeOperation
+	OldImage       *Employee // nil en INSERT
+	NewImage       *Employee // nil en REMOVE
+	CreatedAt      time.Time
+}
+
+// ToEvents convierte el cambio en eventos de dominio:
+// INSERT → employee.created, MODIFY → employee.updated (más employee.status_changed
+// si se registró una transición de estado) y REMOVE → employee.deleted.
+// Los IDs de evento derivan del número de secuencia, por lo que reprocesar un
+// registro produce los mismos eventos. Devuelve ErrIncompleteChangeRecord si
+// falta la imagen que necesita la operación (p.ej. un stream sin NEW_AND_OLD_IMAGES).
+func (c *ChangeRecord) ToEvents() ([]*EmployeeEvent, error) {
+	switch c.Operation {
+	case ChangeInsert, ChangeModify:
+		if c.NewImage == nil {
+			return nil, fmt.Errorf("%w: %s %s without new image", ErrIncompleteChangeRecord, c.Operation, c.SequenceNumber)
+		}
+	case ChangeRemove:
+		if c.OldImage == nil {
+			return nil, fmt.Errorf("%w: %s %s without old image", ErrIncompleteChangeRecord, c.Operation, c.SequenceNumber)
+		}
+	}
+
+	timestamp := c.CreatedAt.Format(time.RFC3339)
+	eventID := "stream-" + c.SequenceNumber
+
+	switch c.Operation {
+	case ChangeInsert:
+		return []*EmployeeEvent{{
+			EventID:   eventID,
+			EventType: "employee.created",
+			Employee:  c.NewImage.EventData(),
+			Timestamp: timestamp,
+		}}, nil
+
+	case ChangeModify:
+		events := []*EmployeeEvent{{
+			EventID:   eventID,
+			EventType: "employee.updated",
+			Employee:  c.NewImage.EventData(),
+			Timestamp: timestamp,
+		}}
+
+		if c.OldImage != nil && len(c.NewImage.StatusHistory) > len(c.OldImage.StatusHistory) {
+			transition := c.NewImage.StatusHistory[len(c.NewImage.StatusHistory)-1]
+			events = append(events, &EmployeeEvent{
+				EventID:   eventID + "-status",
+				EventType: "employee.status_changed",
+				Employee:  c.NewImage.EventData(),
+				Transition: &StatusTransitionData{
+					From:          string(transition.From),
+					To:            string(transition.To),
+					ReasonCode:    string(transition.ReasonCode),
+					EffectiveDate: transition.EffectiveDate.Format(time.RFC3339),
+				},
+				Timestamp: timestamp,
+			})
+		}
+		return events, nil
+
+	case ChangeRemove:
+		return []*EmployeeEvent{{
+			EventID:   eventID,
+			EventType: "employee.deleted",
+			Employee:  c.OldImage.EventData(),
+			Timestamp: timestamp,
+		}}, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestChangeRecordToEvents(t *testing.T) {
+	hiredAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	effectiveDate := hiredAt.AddDate(0, 1, 0)
+
+	onboarding := &Employee{ID: "emp-1", Name: "Ana", Email: "ana@example.com", CreatedAt: hiredAt}
+	onboarding.StartOnboarding(hiredAt)
+
+	active := *onboarding
+	active.StatusHistory = append([]StatusTransition(nil), onboarding.StatusHistory...)
+	if _, err := active.TransitionTo(StatusActive, ReasonOnboardingCompleted, effectiveDate); err != nil {
+		t.Fatalf("TransitionTo() = %v", err)
+	}
+
+	renamed := active
+	renamed.Name = "Ana María"
+
+	tests := []struct {
+		name       string
+		record     ChangeRecord
+		wantTypes  []string
+		wantIDs    []string
+		wantError  error
+		transition *StatusTransitionData
+	}{
+		{
+			name:      "alta",
+			record:    ChangeRecord{SequenceNumber: "1", Operation: ChangeInsert, NewImage: onboarding},
+			wantTypes: []string{"employee.created"},
+			wantIDs:   []string{"stream-1"},
+		},
+		{
+			name:      "modificación sin cambio de estado",
+			record:    ChangeRecord{SequenceNumber: "2", Operation: ChangeModify, OldImage: &active, NewImage: &renamed},
+			wantTypes: []string{"employee.updated"},
+			wantIDs:   []string{"stream-2"},
+		},
+		{
+			name:      "modificación con cambio de estado",
+			record:    ChangeRecord{SequenceNumber: "3", Operation: ChangeModify, OldImage: onboarding, NewImage: &active},
+			wantTypes: []string{"employee.updated", "employee.status_changed"},
+			wantIDs:   []string{"stream-3", "stream-3-status"},
+			transition: &StatusTransitionData{
+				From:          string(StatusOnboarding),
+				To:            string(StatusActive),
+				ReasonCode:    string(ReasonOnboardingCompleted),
+				EffectiveDate: effectiveDate.Format(time.RFC3339),
+			},
+		},
+		{
+			name:      "baja",
+			record:    ChangeRecord{SequenceNumber: "4", Operation: ChangeRemove, OldImage: &active},
+			wantTypes: []string{"employee.deleted"},
+			wantIDs:   []string{"stream-4"},
+		},
+		{
+			name:      "alta sin imagen nueva",
+			record:    ChangeRecord{SequenceNumber: "5", Operation: ChangeInsert},
+			wantError: ErrIncompleteChangeRecord,
+		},
+		{
+			name:      "modificación sin imagen nueva",
+			record:    ChangeRecord{SequenceNumber: "6", Operation: ChangeModify, OldImage: &active},
+			wantError: ErrIncompleteChangeRecord,
+		},
+		{
+			name:      "baja sin imagen anterior",
+			record:    ChangeRecord{SequenceNumber: "7", Operation: ChangeRemove, NewImage: &active},
+			wantError: ErrIncompleteChangeRecord,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := tt.record.ToEvents()
+			if !errors.Is(err, tt.wantError) {
+				t.Fatalf("ToEvents() error = %v, want %v", err, tt.wantError)
+			}
+
+			var types, ids []string
+			for _, event := range events {
+				types = append(types, event.EventType)
+				ids = append(ids, event.EventID)
+			}
+			if !reflect.DeepEqual(types, tt.wantTypes) || !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Fatalf("ToEvents() = %v %v, want %v %v", types, ids, tt.wantTypes, tt.wantIDs)
+			}
+
+			if tt.transition != nil {
+				if got := events[len(events)-1].Transition; !reflect.DeepEqual(got, tt.transition) {
+					t.Errorf("Transition = %+v, want %+v", got, tt.transition)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Department representa un departamento de la organización
+type Department struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// NewDepartment crea una nueva instancia de Department
+func NewDepartment(name, description string) *Department {
+	return &Department{
+		Name:        strings.TrimSpace(name),
+		Description: strings.TrimSpace(description),
+		CreatedAt:   time.Now(),
+	}
+}
+
+// Validate valida los datos del departamento
+func (d *Department) Validate() error {
+	if d.Name == "" {
+		return ErrInvalidDepartmentName
+	}
+	return nil
+}
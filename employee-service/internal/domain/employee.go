@@ -7,39 +7,67 @@ import (
 
 // Employee representa la entidad de dominio para un empleado
 type Employee struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	Password  string    `json:"-"` // Hash del password (nunca se serializa en JSON)
-	CreatedAt time.Time `json:"created_at"`
+	ID                string             `json:"id"`
+	Name              string             `json:"name"`
+	Email             string             `json:"email"`
+	Password          string             `json:"-"`                                               // Hash del password (nunca se serializa en JSON)
+	DepartmentID      string             `json:"department_id,omitempty" dynamodbav:",omitempty"` // Clave del GSI disperso DepartmentIndex
+	ManagerID         string             `json:"manager_id,omitempty" dynamodbav:",omitempty"`    // ID del jefe directo ("" si no tiene); clave del GSI disperso ManagerIndex
+	Status            EmploymentStatus   `json:"status"`
+	StatusEffectiveAt time.Time          `json:"status_effective_at"`
+	StatusHistory     []StatusTransition `json:"status_history,omitempty"`
+	CreatedAt         time.Time          `json:"created_at"`
 }
 
 // EmployeePublic representa un empleado sin información sensible
 type EmployeePublic struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
+	ID                string           `json:"id"`
+	Name              string           `json:"name"`
+	Email             string           `json:"email"`
+	DepartmentID      string           `json:"department_id,omitempty"`
+	ManagerID         string           `json:"manager_id,omitempty"`
+	Status            EmploymentStatus `json:"status"`
+	StatusEffectiveAt *time.Time       `json:"status_effective_at,omitempty"`
+	ScheduledStatus   EmploymentStatus `json:"scheduled_status,omitempty"` // Cambio registrado con fecha efectiva futura
+	ScheduledAt       *time.Time       `json:"scheduled_at,omitempty"`
+	CreatedAt         time.Time        `json:"created_at"`
 }
 
 // ToPublic convierte un Employee a EmployeePublic (sin password)
 func (e *Employee) ToPublic() *EmployeePublic {
-	return &EmployeePublic{
-		ID:        e.ID,
-		Name:      e.Name,
-		Email:     e.Email,
-		CreatedAt: e.CreatedAt,
+	public := &EmployeePublic{
+		ID:           e.ID,
+		Name:         e.Name,
+		Email:        e.Email,
+		DepartmentID: e.DepartmentID,
+		ManagerID:    e.ManagerID,
+		CreatedAt:    e.CreatedAt,
 	}
+
+	now := time.Now()
+	status, effectiveAt := e.StatusAt(now)
+	public.Status = status
+	if !effectiveAt.IsZero() {
+		public.StatusEffectiveAt = &effectiveAt
+	}
+	if scheduled, scheduledAt, ok := e.ScheduledStatus(now); ok {
+		public.ScheduledStatus = scheduled
+		public.ScheduledAt = &scheduledAt
+	}
+	return public
 }
 
 // NewEmployee crea una nueva instancia de Employee
 func NewEmployee(name, email, password string) *Employee {
-	return &Employee{
+	employee := &Employee{
 		Name:      name,
 		Email:     email,
 		Password:  password,
 		CreatedAt: time.Now(),
 	}
+	// Todo empleado nuevo inicia su ciclo de vida en onboarding
+	employee.StartOnboarding(employee.CreatedAt)
+	return employee
 }
 
 // Validate valida los datos del empleado
@@ -0,0 +1,178 @@
+package domain
+
+import "time"
+
+// EmploymentStatus representa el estado del ciclo de vida laboral de un empleado
+type EmploymentStatus string
+
+const (
+	StatusOnboarding EmploymentStatus = "onboarding"
+	StatusActive     EmploymentStatus = "active"
+	StatusOnLeave    EmploymentStatus = "on_leave"
+	StatusTerminated EmploymentStatus = "terminated"
+)
+
+// ReasonCode representa el motivo de una transición de estado
+type ReasonCode string
+
+const (
+	ReasonHired               ReasonCode = "hired"
+	ReasonOnboardingCompleted ReasonCode = "onboarding_completed"
+	ReasonReturnFromLeave     ReasonCode = "return_from_leave"
+	ReasonMedicalLeave        ReasonCode = "medical_leave"
+	ReasonParentalLeave       ReasonCode = "parental_leave"
+	ReasonPersonalLeave       ReasonCode = "personal_leave"
+	ReasonResignation         ReasonCode = "resignation"
+	ReasonDismissal           ReasonCode = "dismissal"
+	ReasonRetirement          ReasonCode = "retirement"
+	ReasonContractEnd         ReasonCode = "contract_end"
+	ReasonOnboardingCancelled ReasonCode = "onboarding_cancelled"
+)
+
+// allowedTransitions define la máquina de estados: estado origen → estados destino permitidos
+var allowedTransitions = map[EmploymentStatus][]EmploymentStatus{
+	StatusOnboarding: {StatusActive, StatusTerminated},
+	StatusActive:     {StatusOnLeave, StatusTerminated},
+	StatusOnLeave:    {StatusActive, StatusTerminated},
+	StatusTerminated: {},
+}
+
+// allowedReasons define los códigos de motivo válidos para cada estado destino
+var allowedReasons = map[EmploymentStatus][]ReasonCode{
+	StatusOnboarding: {ReasonHired},
+	StatusActive:     {ReasonOnboardingCompleted, ReasonReturnFromLeave},
+	StatusOnLeave:    {ReasonMedicalLeave, ReasonParentalLeave, ReasonPersonalLeave},
+	StatusTerminated: {ReasonResignation, ReasonDismissal, ReasonRetirement, ReasonContractEnd, ReasonOnboardingCancelled},
+}
+
+// StatusTransition representa un cambio de estado registrado en el historial del empleado
+type StatusTransition struct {
+	From          EmploymentStatus `json:"from,omitempty"`
+	To            EmploymentStatus `json:"to"`
+	ReasonCode    ReasonCode       `json:"reason_code"`
+	EffectiveDate time.Time        `json:"effective_date"`
+	RecordedAt    time.Time        `json:"recorded_at"`
+}
+
+// IsValid indica si el estado es uno de los estados conocidos
+func (s EmploymentStatus) IsValid() bool {
+	_, ok := allowedTransitions[s]
+	return ok
+}
+
+// CanTransitionTo indica si la máquina de estados permite pasar de s a target
+func (s EmploymentStatus) CanTransitionTo(target EmploymentStatus) bool {
+	for _, allowed := range allowedTransitions[s] {
+		if allowed == target {
+			return true
+		}
+	}
+	return false
+}
+
+// isAllowedReason indica si el código de motivo es válido para el estado destino
+func isAllowedReason(target EmploymentStatus, reason ReasonCode) bool {
+	for _, allowed := range allowedReasons[target] {
+		if allowed == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// CurrentStatus devuelve el estado vigente del empleado en este momento.
+// Los empleados registrados antes de existir el ciclo de vida se consideran activos.
+func (e *Employee) CurrentStatus() EmploymentStatus {
+	status, _ := e.StatusAt(time.Now())
+	return status
+}
+
+// StatusAt devuelve el estado vigente en el instante at y la fecha desde la que rige.
+// Una transición con fecha efectiva futura queda registrada (Status y StatusEffectiveAt
+// guardan la última) pero no rige hasta esa fecha.
+func (e *Employee) StatusAt(at time.Time) (EmploymentStatus, time.Time) {
+	for i := len(e.StatusHistory) - 1; i >= 0; i-- {
+		if !e.StatusHistory[i].EffectiveDate.After(at) {
+			return e.StatusHistory[i].To, e.StatusHistory[i].EffectiveDate
+		}
+	}
+
+	// Todavía no rige ninguna transición: sigue el estado previo a la primera
+	// (o la primera misma si es la contratación)
+	if len(e.StatusHistory) > 0 {
+		first := e.StatusHistory[0]
+		if first.From != "" {
+			return first.From, time.Time{}
+		}
+		return first.To, first.EffectiveDate
+	}
+	return e.latestStatus(), e.StatusEffectiveAt
+}
+
+// ScheduledStatus devuelve el último estado registrado si su fecha efectiva aún no llegó
+func (e *Employee) ScheduledStatus(at time.Time) (EmploymentStatus, time.Time, bool) {
+	if !e.StatusEffectiveAt.After(at) {
+		return "", time.Time{}, false
+	}
+	return e.latestStatus(), e.StatusEffectiveAt, true
+}
+
+// latestStatus devuelve el último estado registrado, aunque su fecha efectiva sea futura
+func (e *Employee) latestStatus() EmploymentStatus {
+	if e.Status == "" {
+		return StatusActive
+	}
+	return e.Status
+}
+
+// StartOnboarding inicializa el ciclo de vida de un empleado recién contratado
+func (e *Employee) StartOnboarding(effectiveDate time.Time) {
+	transition := StatusTransition{
+		To:            StatusOnboarding,
+		ReasonCode:    ReasonHired,
+		EffectiveDate: effectiveDate,
+		RecordedAt:    time.Now(),
+	}
+	e.Status = StatusOnboarding
+	e.StatusEffectiveAt = effectiveDate
+	e.StatusHistory = []StatusTransition{transition}
+}
+
+// TransitionTo aplica una transición de estado validando las reglas de la máquina de estados,
+// el código de motivo y que la fecha efectiva no sea anterior a la transición previa.
+// La transición parte del último estado registrado, aunque todavía no rija.
+func (e *Employee) TransitionTo(target EmploymentStatus, reason ReasonCode, effectiveDate time.Time) (*StatusTransition, error) {
+	if !target.IsValid() {
+		return nil, ErrInvalidStatus
+	}
+
+	current := e.latestStatus()
+	if !current.CanTransitionTo(target) {
+		return nil, ErrInvalidStatusTransition
+	}
+
+	if !isAllowedReason(target, reason) {
+		return nil, ErrInvalidReasonCode
+	}
+
+	if effectiveDate.IsZero() {
+		effectiveDate = time.Now()
+	}
+	if !e.StatusEffectiveAt.IsZero() && effectiveDate.Before(e.StatusEffectiveAt) {
+		return nil, ErrInvalidEffectiveDate
+	}
+
+	transition := StatusTransition{
+		From:          current,
+		To:            target,
+		ReasonCode:    reason,
+		EffectiveDate: effectiveDate,
+		RecordedAt:    time.Now(),
+	}
+
+	e.Status = target
+	e.StatusEffectiveAt = effectiveDate
+	e.StatusHistory = append(e.StatusHistory, transition)
+
+	return &transition, nil
+}
@@ -0,0 +1,172 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTransitionTo(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		from      EmploymentStatus
+		to        EmploymentStatus
+		reason    ReasonCode
+		effective time.Time
+		wantError error
+	}{
+		{"onboarding → active", StatusOnboarding, StatusActive, ReasonOnboardingCompleted, start.AddDate(0, 0, 7), nil},
+		{"onboarding → terminated", StatusOnboarding, StatusTerminated, ReasonOnboardingCancelled, start, nil},
+		{"active → on_leave", StatusActive, StatusOnLeave, ReasonParentalLeave, start.AddDate(0, 1, 0), nil},
+		{"on_leave → active", StatusOnLeave, StatusActive, ReasonReturnFromLeave, start.AddDate(0, 2, 0), nil},
+		{"active → terminated", StatusActive, StatusTerminated, ReasonResignation, start.AddDate(1, 0, 0), nil},
+		{"sin estado previo cuenta como active", "", StatusOnLeave, ReasonMedicalLeave, start, nil},
+
+		{"onboarding → on_leave", StatusOnboarding, StatusOnLeave, ReasonMedicalLeave, start, ErrInvalidStatusTransition},
+		{"terminated es final", StatusTerminated, StatusActive, ReasonReturnFromLeave, start, ErrInvalidStatusTransition},
+		{"volver a onboarding", StatusActive, StatusOnboarding, ReasonHired, start, ErrInvalidStatusTransition},
+		{"mismo estado", StatusActive, StatusActive, ReasonReturnFromLeave, start, ErrInvalidStatusTransition},
+		{"estado desconocido", StatusActive, "retired", ReasonRetirement, start, ErrInvalidStatus},
+
+		{"motivo de otro estado", StatusActive, StatusOnLeave, ReasonResignation, start, ErrInvalidReasonCode},
+		{"motivo vacío", StatusActive, StatusTerminated, "", start, ErrInvalidReasonCode},
+
+		{"fecha anterior al cambio previo", StatusActive, StatusOnLeave, ReasonPersonalLeave, start.Add(-time.Hour), ErrInvalidEffectiveDate},
+		{"misma fecha que el cambio previo", StatusActive, StatusOnLeave, ReasonPersonalLeave, start, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			employee := &Employee{Status: tt.from, StatusEffectiveAt: start}
+			if tt.from != "" {
+				employee.StatusHistory = []StatusTransition{{To: tt.from, EffectiveDate: start}}
+			}
+
+			transition, err := employee.TransitionTo(tt.to, tt.reason, tt.effective)
+			if !errors.Is(err, tt.wantError) {
+				t.Fatalf("TransitionTo(%q, %q) = %v, want %v", tt.to, tt.reason, err, tt.wantError)
+			}
+
+			if tt.wantError != nil {
+				if employee.Status != tt.from || len(employee.StatusHistory) > 1 {
+					t.Errorf("rejected transition changed the employee: status %q, %d history entries", employee.Status, len(employee.StatusHistory))
+				}
+				return
+			}
+
+			if employee.Status != tt.to || !employee.StatusEffectiveAt.Equal(tt.effective) {
+				t.Errorf("status = %q at %v, want %q at %v", employee.Status, employee.StatusEffectiveAt, tt.to, tt.effective)
+			}
+			wantFrom := tt.from
+			if wantFrom == "" {
+				wantFrom = StatusActive
+			}
+			if transition.From != wantFrom || transition.To != tt.to || transition.ReasonCode != tt.reason {
+				t.Errorf("transition = %+v", transition)
+			}
+			if last := employee.StatusHistory[len(employee.StatusHistory)-1]; last != *transition {
+				t.Errorf("last history entry = %+v, want %+v", last, *transition)
+			}
+		})
+	}
+}
+
+func TestTransitionToSequence(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	employee := &Employee{}
+	employee.StartOnboarding(day(1))
+
+	steps := []struct {
+		to        EmploymentStatus
+		reason    ReasonCode
+		effective time.Time
+	}{
+		{StatusActive, ReasonOnboardingCompleted, day(10)},
+		{StatusOnLeave, ReasonMedicalLeave, day(15)},
+		{StatusActive, ReasonReturnFromLeave, day(20)},
+		{StatusTerminated, ReasonDismissal, day(31)},
+	}
+	for _, step := range steps {
+		if _, err := employee.TransitionTo(step.to, step.reason, step.effective); err != nil {
+			t.Fatalf("TransitionTo(%q) = %v", step.to, err)
+		}
+	}
+
+	if len(employee.StatusHistory) != len(steps)+1 {
+		t.Fatalf("history has %d entries, want %d", len(employee.StatusHistory), len(steps)+1)
+	}
+	for i := 1; i < len(employee.StatusHistory); i++ {
+		previous, current := employee.StatusHistory[i-1], employee.StatusHistory[i]
+		if current.From != previous.To {
+			t.Errorf("entry %d: from %q, want %q", i, current.From, previous.To)
+		}
+		if current.EffectiveDate.Before(previous.EffectiveDate) {
+			t.Errorf("entry %d: effective date %v precedes %v", i, current.EffectiveDate, previous.EffectiveDate)
+		}
+	}
+
+	if _, err := employee.TransitionTo(StatusActive, ReasonReturnFromLeave, day(31)); !errors.Is(err, ErrInvalidStatusTransition) {
+		t.Errorf("transition out of terminated = %v, want %v", err, ErrInvalidStatusTransition)
+	}
+}
+
+func TestScheduledTermination(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	hiredAt := now.AddDate(0, -6, 0)
+	activeSince := hiredAt.AddDate(0, 0, 7)
+	terminationDate := now.AddDate(0, 1, 0)
+
+	employee := &Employee{ID: "emp-1", CreatedAt: hiredAt}
+	employee.StartOnboarding(hiredAt)
+	if _, err := employee.TransitionTo(StatusActive, ReasonOnboardingCompleted, activeSince); err != nil {
+		t.Fatalf("TransitionTo(active) = %v", err)
+	}
+	if _, err := employee.TransitionTo(StatusTerminated, ReasonResignation, terminationDate); err != nil {
+		t.Fatalf("TransitionTo(terminated) = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		at        time.Time
+		want      EmploymentStatus
+		wantSince time.Time
+	}{
+		{"antes de la contratación", hiredAt.Add(-time.Hour), StatusOnboarding, hiredAt},
+		{"en onboarding", hiredAt.AddDate(0, 0, 1), StatusOnboarding, hiredAt},
+		{"hoy sigue activo", now, StatusActive, activeSince},
+		{"el día de la baja", terminationDate, StatusTerminated, terminationDate},
+		{"después de la baja", terminationDate.AddDate(0, 0, 1), StatusTerminated, terminationDate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, since := employee.StatusAt(tt.at)
+			if status != tt.want || !since.Equal(tt.wantSince) {
+				t.Errorf("StatusAt(%v) = %q since %v, want %q since %v", tt.at, status, since, tt.want, tt.wantSince)
+			}
+		})
+	}
+
+	// La vista pública y la exportación muestran el estado vigente y la baja como programada
+	public := employee.ToPublic()
+	if public.Status != StatusActive || public.StatusEffectiveAt == nil || !public.StatusEffectiveAt.Equal(activeSince) {
+		t.Errorf("ToPublic() status = %q since %v, want %q since %v", public.Status, public.StatusEffectiveAt, StatusActive, activeSince)
+	}
+	if public.ScheduledStatus != StatusTerminated || public.ScheduledAt == nil || !public.ScheduledAt.Equal(terminationDate) {
+		t.Errorf("ToPublic() scheduled = %q at %v, want %q at %v", public.ScheduledStatus, public.ScheduledAt, StatusTerminated, terminationDate)
+	}
+	values := employee.ExportValues([]string{"status", "status_effective_at"})
+	if want := []string{"active", activeSince.Format(time.RFC3339)}; values[0] != want[0] || values[1] != want[1] {
+		t.Errorf("ExportValues() = %v, want %v", values, want)
+	}
+	active, terminated := &EmployeeFilter{Status: StatusActive}, &EmployeeFilter{Status: StatusTerminated}
+	if !active.Matches(employee) || terminated.Matches(employee) {
+		t.Error("export filter should match the status in effect, not the scheduled one")
+	}
+
+	// La máquina de estados parte del último estado registrado: no hay transiciones desde terminated
+	if _, err := employee.TransitionTo(StatusOnLeave, ReasonMedicalLeave, terminationDate); !errors.Is(err, ErrInvalidStatusTransition) {
+		t.Errorf("transition after scheduled termination = %v, want %v", err, ErrInvalidStatusTransition)
+	}
+}
@@ -7,4 +7,31 @@ var (
 	ErrInvalidEmail    = errors.New("invalid employee email")
 	ErrInvalidPassword = errors.New("invalid password: must be at least 8 characters with at least one uppercase letter, one number, and one special character")
 	ErrNotFound        = errors.New("employee not found")
+
+	ErrInvalidDepartmentName = errors.New("invalid department name")
+	ErrDepartmentNotFound    = errors.New("department not found")
+	ErrManagerNotFound       = errors.New("manager not found")
+	ErrSelfManagement        = errors.New("an employee cannot be their own manager")
+	ErrManagerCycle          = errors.New("manager assignment would create a cycle in the reporting hierarchy")
+	ErrHierarchyChanged      = errors.New("the reporting hierarchy changed while the manager assignment was being validated")
+
+	ErrInvalidStatus           = errors.New("invalid employment status")
+	ErrInvalidStatusTransition = errors.New("employment status transition not allowed")
+	ErrInvalidReasonCode       = errors.New("invalid reason code for the target employment status")
+	ErrInvalidEffectiveDate    = errors.New("effective date cannot precede the previous status change")
+
+	ErrDuplicateEmail       = errors.New("duplicate email in import")
+	ErrEmailAlreadyExists   = errors.New("an employee with this email already exists")
+	ErrUnsupportedFormat    = errors.New("unsupported format")
+	ErrBatchWriteIncomplete = errors.New("batch write left unprocessed items after retries")
+
+	ErrInvalidExportColumn = errors.New("invalid export column")
+	ErrInvalidCursor       = errors.New("invalid pagination cursor")
+
+	ErrInvalidSubscription = errors.New("invalid event subscription")
+
+	ErrIncompleteChangeRecord = errors.New("change record is missing the image required by its operation")
+
+	ErrIdempotencyKeyReused         = errors.New("idempotency key was already used with a different request body")
+	ErrIdempotencyRequestInProgress = errors.New("a request with this idempotency key is still in progress")
 )
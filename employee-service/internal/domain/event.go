@@ -1,16 +1,49 @@
 package domain
 
+import (
+	"pkg/events"
+	"time"
+)
+
 // EmployeeEvent representa un evento relacionado con un empleado
 type EmployeeEvent struct {
-	EventType string             `json:"event_type"`
-	Employee  *EmployeeEventData `json:"employee"`
-	Timestamp string             `json:"timestamp"`
+	EventID    string                `json:"event_id"`
+	EventType  string                `json:"event_type"`
+	Employee   *EmployeeEventData    `json:"employee"`
+	Transition *StatusTransitionData `json:"transition,omitempty"`
+	Timestamp  string                `json:"timestamp"`
 }
 
-// EmployeeEventData representa los datos del empleado en el evento (sin información sensible)
-type EmployeeEventData struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	Email     string `json:"email"`
-	CreatedAt string `json:"created_at"`
+// EmployeeEventData representa los datos del empleado en el evento (contrato compartido)
+type EmployeeEventData = events.EmployeeData
+
+// StatusTransitionData representa los datos de una transición de estado en el evento (contrato compartido)
+type StatusTransitionData = events.StatusTransitionData
+
+// EventData devuelve los datos del empleado para incluir en un evento (sin información sensible)
+func (e *Employee) EventData() *EmployeeEventData {
+	return &EmployeeEventData{
+		ID:        e.ID,
+		Name:      e.Name,
+		Email:     e.Email,
+		CreatedAt: e.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToCloudEvent envuelve el evento en el envelope CloudEvents compartido
+func (e *EmployeeEvent) ToCloudEvent() (*events.CloudEvent, error) {
+	eventTime, err := time.Parse(time.RFC3339, e.Timestamp)
+	if err != nil {
+		eventTime = time.Now()
+	}
+
+	subject := ""
+	if e.Employee != nil {
+		subject = e.Employee.ID
+	}
+
+	return events.New(e.EventID, events.SourceEmployeeService, e.EventType, subject, eventTime, events.EmployeePayload{
+		Employee:   e.Employee,
+		Transition: e.Transition,
+	})
 }
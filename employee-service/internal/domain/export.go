@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// ExportColumns son las columnas que se pueden exportar, en su orden por defecto.
+// El hash del password nunca forma parte de la exportación.
+var ExportColumns = []string{
+	"id",
+	"name",
+	"email",
+	"department_id",
+	"manager_id",
+	"status",
+	"status_effective_at",
+	"created_at",
+}
+
+// EmployeeFilter define los criterios para filtrar empleados
+type EmployeeFilter struct {
+	DepartmentID  string
+	ManagerID     string
+	Status        EmploymentStatus
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+}
+
+// Matches indica si el empleado cumple todos los criterios del filtro
+func (f *EmployeeFilter) Matches(e *Employee) bool {
+	if f.DepartmentID != "" && e.DepartmentID != f.DepartmentID {
+		return false
+	}
+	if f.ManagerID != "" && e.ManagerID != f.ManagerID {
+		return false
+	}
+	if f.Status != "" && e.CurrentStatus() != f.Status {
+		return false
+	}
+	if !f.CreatedAfter.IsZero() && e.CreatedAt.Before(f.CreatedAfter) {
+		return false
+	}
+	if !f.CreatedBefore.IsZero() && !e.CreatedAt.Before(f.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+// EmployeePage representa una página de resultados del repositorio
+type EmployeePage struct {
+	Employees  []*Employee
+	NextCursor string // "" cuando no hay más páginas
+}
+
+// ParseExportColumns valida una lista de columnas separadas por comas.
+// Si la lista está vacía devuelve todas las columnas exportables.
+func ParseExportColumns(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return ExportColumns, nil
+	}
+
+	var columns []string
+	for _, column := range strings.Split(value, ",") {
+		column = strings.ToLower(strings.TrimSpace(column))
+		if !isExportColumn(column) {
+			return nil, ErrInvalidExportColumn
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// isExportColumn indica si la columna es exportable
+func isExportColumn(column string) bool {
+	for _, allowed := range ExportColumns {
+		if allowed == column {
+			return true
+		}
+	}
+	return false
+}
+
+// ExportValues devuelve los valores del empleado para las columnas indicadas
+func (e *Employee) ExportValues(columns []string) []string {
+	values := make([]string, len(columns))
+	for i, column := range columns {
+		switch column {
+		case "id":
+			values[i] = e.ID
+		case "name":
+			values[i] = e.Name
+		case "email":
+			values[i] = e.Email
+		case "department_id":
+			values[i] = e.DepartmentID
+		case "manager_id":
+			values[i] = e.ManagerID
+		case "status":
+			values[i] = string(e.CurrentStatus())
+		case "status_effective_at":
+			if _, effectiveAt := e.StatusAt(time.Now()); !effectiveAt.IsZero() {
+				values[i] = effectiveAt.Format(time.RFC3339)
+			}
+		case "created_at":
+			values[i] = e.CreatedAt.Format(time.RFC3339)
+		}
+	}
+	return values
+}
@@ -0,0 +1,126 @@
+package domain
+
+// ManagerLookup devuelve el ID del jefe directo de un empleado ("" si no tiene)
+type ManagerLookup func(employeeID string) (string, error)
+
+// ManagerLink es el jefe directo de un empleado tal como se leyó al validar una asignación
+type ManagerLink struct {
+	EmployeeID string
+	ManagerID  string
+}
+
+// ValidateManagerAssignment verifica que asignar managerID como jefe directo
+// de employeeID no genere un ciclo en la jerarquía.
+// Recorre la cadena de mando desde el nuevo jefe hacia arriba: si en algún
+// punto aparece el propio empleado, la asignación crearía un ciclo.
+func ValidateManagerAssignment(employeeID, managerID string, managerOf ManagerLookup) error {
+	if managerID == "" {
+		return nil
+	}
+	if managerID == employeeID {
+		return ErrSelfManagement
+	}
+
+	visited := make(map[string]bool)
+	current := managerID
+	for current != "" {
+		if current == employeeID {
+			return ErrManagerCycle
+		}
+		// Protección ante ciclos ya existentes en los datos
+		if visited[current] {
+			return ErrManagerCycle
+		}
+		visited[current] = true
+
+		next, err := managerOf(current)
+		if err != nil {
+			return err
+		}
+		current = next
+	}
+
+	return nil
+}
+
+// OrgChartNode representa un nodo del organigrama con sus reportes directos
+type OrgChartNode struct {
+	Employee *EmployeePublic `json:"employee"`
+	Reports  []*OrgChartNode `json:"reports"`
+}
+
+// BuildOrgChart construye el árbol del organigrama a partir de un conjunto de empleados.
+// Las raíces son los empleados sin jefe o cuyo jefe no forma parte del conjunto.
+// Un ciclo de jefes ya presente en los datos (ValidateManagerAssignment impide
+// crearlos, pero pueden venir de datos anteriores) se corta en uno de sus
+// empleados, que pasa a ser raíz: así ningún empleado desaparece del organigrama.
+func BuildOrgChart(employees []*Employee) []*OrgChartNode {
+	nodes := make(map[string]*OrgChartNode, len(employees))
+	managers := make(map[string]string, len(employees))
+	for _, emp := range employees {
+		nodes[emp.ID] = &OrgChartNode{
+			Employee: emp.ToPublic(),
+			Reports:  []*OrgChartNode{},
+		}
+		managers[emp.ID] = emp.ManagerID
+	}
+
+	roots := []*OrgChartNode{}
+	for _, emp := range employees {
+		node := nodes[emp.ID]
+		parent, ok := nodes[emp.ManagerID]
+		if emp.ManagerID == "" || !ok {
+			roots = append(roots, node)
+			continue
+		}
+		parent.Reports = append(parent.Reports, node)
+	}
+
+	visited := make(map[string]bool, len(employees))
+	for _, root := range roots {
+		markVisited(root, visited)
+	}
+
+	// Los empleados no alcanzados desde una raíz están en un ciclo o cuelgan
+	// de uno: se sube por la cadena de mando hasta repetir un empleado, que
+	// es parte del ciclo, y se corta ahí
+	for _, emp := range employees {
+		if visited[emp.ID] {
+			continue
+		}
+		seen := make(map[string]bool)
+		current := emp.ID
+		for !seen[current] {
+			seen[current] = true
+			current = managers[current]
+		}
+
+		node := nodes[current]
+		parent := nodes[managers[current]]
+		parent.Reports = removeNode(parent.Reports, node)
+		roots = append(roots, node)
+		markVisited(node, visited)
+	}
+
+	return roots
+}
+
+// markVisited marca el nodo y todos sus reportes
+func markVisited(node *OrgChartNode, visited map[string]bool) {
+	if visited[node.Employee.ID] {
+		return
+	}
+	visited[node.Employee.ID] = true
+	for _, report := range node.Reports {
+		markVisited(report, visited)
+	}
+}
+
+func removeNode(nodes []*OrgChartNode, node *OrgChartNode) []*OrgChartNode {
+	for i, candidate := range nodes {
+		if candidate == node {
+			return append(nodes[:i], nodes[i+1:]...)
+		}
+	}
+	return nodes
+}
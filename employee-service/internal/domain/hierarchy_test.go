@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"errors"
+	"sort"
+	"testing"
+)
+
+func TestValidateManagerAssignment(t *testing.T) {
+	// a ← b ← c (c reporta a b, b reporta a a); x e y forman un ciclo previo
+	managers := map[string]string{"b": "a", "c": "b", "x": "y", "y": "x"}
+	lookup := func(id string) (string, error) {
+		return managers[id], nil
+	}
+
+	tests := []struct {
+		name      string
+		employee  string
+		manager   string
+		wantError error
+	}{
+		{"sin jefe", "c", "", nil},
+		{"jefe válido", "d", "c", nil},
+		{"mover dentro de la rama", "c", "a", nil},
+		{"a sí mismo", "a", "a", ErrSelfManagement},
+		{"jefe es reporte directo", "b", "c", ErrManagerCycle},
+		{"jefe es reporte indirecto", "a", "c", ErrManagerCycle},
+		{"ciclo ya existente en los datos", "d", "x", ErrManagerCycle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateManagerAssignment(tt.employee, tt.manager, lookup)
+			if !errors.Is(err, tt.wantError) {
+				t.Fatalf("ValidateManagerAssignment(%q, %q) = %v, want %v", tt.employee, tt.manager, err, tt.wantError)
+			}
+		})
+	}
+
+	t.Run("error de lectura", func(t *testing.T) {
+		lookupErr := errors.New("read failed")
+		err := ValidateManagerAssignment("a", "b", func(string) (string, error) { return "", lookupErr })
+		if !errors.Is(err, lookupErr) {
+			t.Fatalf("got %v, want %v", err, lookupErr)
+		}
+	})
+}
+
+func TestBuildOrgChart(t *testing.T) {
+	tests := []struct {
+		name      string
+		employees map[string]string // ID → jefe; ids da el orden
+		ids       []string
+		wantRoots []string
+	}{
+		{
+			name:      "árbol",
+			employees: map[string]string{"a": "", "b": "a", "c": "a", "d": "b"},
+			ids:       []string{"a", "b", "c", "d"},
+			wantRoots: []string{"a"},
+		},
+		{
+			name:      "jefe fuera del conjunto",
+			employees: map[string]string{"b": "a", "c": "b"},
+			ids:       []string{"b", "c"},
+			wantRoots: []string{"b"},
+		},
+		{
+			name:      "ciclo de dos",
+			employees: map[string]string{"a": "", "x": "y", "y": "x"},
+			ids:       []string{"a", "x", "y"},
+			wantRoots: []string{"a", "x"},
+		},
+		{
+			name:      "reporte colgado de un ciclo",
+			employees: map[string]string{"c": "x", "x": "y", "y": "z", "z": "x"},
+			ids:       []string{"c", "x", "y", "z"},
+			wantRoots: []string{"x"},
+		},
+		{
+			name:      "jefe de sí mismo",
+			employees: map[string]string{"a": "a", "b": "a"},
+			ids:       []string{"a", "b"},
+			wantRoots: []string{"a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var employees []*Employee
+			for _, id := range tt.ids {
+				employees = append(employees, &Employee{ID: id, ManagerID: tt.employees[id]})
+			}
+
+			roots := BuildOrgChart(employees)
+
+			var rootIDs []string
+			for _, root := range roots {
+				rootIDs = append(rootIDs, root.Employee.ID)
+			}
+			sort.Strings(rootIDs)
+			if !equalStrings(rootIDs, tt.wantRoots) {
+				t.Errorf("roots = %v, want %v", rootIDs, tt.wantRoots)
+			}
+
+			// Cada empleado aparece exactamente una vez
+			count := make(map[string]int)
+			var walk func(nodes []*OrgChartNode)
+			walk = func(nodes []*OrgChartNode) {
+				for _, node := range nodes {
+					count[node.Employee.ID]++
+					walk(node.Reports)
+				}
+			}
+			walk(roots)
+			for _, id := range tt.ids {
+				if count[id] != 1 {
+					t.Errorf("employee %s appears %d times, want 1", id, count[id])
+				}
+			}
+		})
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
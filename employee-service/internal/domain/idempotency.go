@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// IdempotencyStatus representa el estado de una petición idempotente
+type IdempotencyStatus string
+
+const (
+	IdempotencyInProgress IdempotencyStatus = "in_progress"
+	IdempotencyCompleted  IdempotencyStatus = "completed"
+)
+
+// IdempotencyRecord almacena la huella de una petición y su respuesta
+// para poder reproducirla ante reintentos con la misma Idempotency-Key
+type IdempotencyRecord struct {
+	Key                 string            `json:"key"`
+	Fingerprint         string            `json:"fingerprint"` // SHA-256 del cuerpo de la petición
+	Status              IdempotencyStatus `json:"status"`
+	ResponseStatus      int               `json:"response_status,omitempty"`
+	ResponseContentType string            `json:"response_content_type,omitempty"`
+	ResponseBody        string            `json:"response_body,omitempty"`
+	CreatedAt           time.Time         `json:"created_at"`
+	ExpiresAt           int64             `json:"expires_at"` // Epoch en segundos (atributo TTL de DynamoDB)
+}
+
+// NewIdempotencyRecord crea un registro en curso que expira tras el lease indicado:
+// si el proceso cae antes de completarla, la clave se puede volver a reservar al
+// vencer el lease (al completarla se extiende a la ventana de idempotencia)
+func NewIdempotencyRecord(key, fingerprint string, lease time.Duration) *IdempotencyRecord {
+	now := time.Now()
+	return &IdempotencyRecord{
+		Key:         key,
+		Fingerprint: fingerprint,
+		Status:      IdempotencyInProgress,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(lease).Unix(),
+	}
+}
@@ -0,0 +1,35 @@
+package domain
+
+// ImportRecord representa una fila leída de un archivo de importación masiva
+type ImportRecord struct {
+	Row          int    `json:"row"`
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	Password     string `json:"password"`
+	DepartmentID string `json:"department_id"`
+	ManagerID    string `json:"manager_id"`
+	// ParseError contiene el error de formato de la fila (p.ej. JSON inválido), si lo hubo
+	ParseError error `json:"-"`
+}
+
+// ImportRowError describe por qué una fila no pudo importarse
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	Email string `json:"email,omitempty"`
+	Error string `json:"error"`
+}
+
+// ImportReport resume el resultado de una importación masiva
+type ImportReport struct {
+	DryRun    bool              `json:"dry_run"`
+	TotalRows int               `json:"total_rows"`
+	Imported  int               `json:"imported"` // En modo dry-run: filas válidas que se importarían
+	Failed    int               `json:"failed"`
+	Errors    []*ImportRowError `json:"errors"`
+}
+
+// AddError registra un error para una fila
+func (r *ImportReport) AddError(row int, email string, err error) {
+	r.Failed++
+	r.Errors = append(r.Errors, &ImportRowError{Row: row, Email: email, Error: err.Error()})
+}
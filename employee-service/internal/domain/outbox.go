@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxStatus representa el estado de publicación de una entrada del outbox
+type OutboxStatus string
+
+const (
+	OutboxPending OutboxStatus = "pending"
+	OutboxSent    OutboxStatus = "sent"
+	OutboxFailed  OutboxStatus = "failed" // Agotó los intentos: no se vuelve a publicar
+)
+
+// OutboxEntry representa un evento pendiente de publicar, escrito en la misma
+// transacción que el cambio de estado que lo origina (patrón Transactional Outbox)
+type OutboxEntry struct {
+	ID          string       `json:"id"` // Igual al EventID del evento
+	AggregateID string       `json:"aggregate_id"`
+	EventType   string       `json:"event_type"`
+	Payload     string       `json:"payload"` // Evento serializado en JSON
+	Status      OutboxStatus `json:"status"`
+	// Pending vale "pending" solo mientras la entrada espera publicarse: es la clave
+	// del índice disperso de pendientes y se elimina al marcarla sent o failed
+	Pending string `json:"-" dynamodbav:",omitempty"`
+	// PendingAggregateID vale el AggregateID mientras la entrada está pendiente: es la
+	// clave del índice disperso que ordena por Sequence las pendientes de cada agregado
+	PendingAggregateID string `json:"-" dynamodbav:",omitempty"`
+	// Sequence ordena las entradas de un mismo agregado (CreatedAt en nanosegundos)
+	Sequence      int64      `json:"sequence"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	NextAttemptAt int64      `json:"next_attempt_at"` // Epoch en segundos
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	ExpiresAt     int64      `json:"expires_at,omitempty"` // TTL una vez publicado
+}
+
+// NewOutboxEntry serializa un evento de empleado como entrada pendiente del outbox
+func NewOutboxEntry(event *EmployeeEvent) (*OutboxEntry, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &OutboxEntry{
+		ID:                 event.EventID,
+		AggregateID:        event.Employee.ID,
+		EventType:          event.EventType,
+		Payload:            string(payload),
+		Status:             OutboxPending,
+		Pending:            string(OutboxPending),
+		PendingAggregateID: event.Employee.ID,
+		Sequence:           now.UnixNano(),
+		CreatedAt:          now,
+		NextAttemptAt:      now.Unix(),
+	}, nil
+}
+
+// ScheduleAt aplaza la publicación hasta at (un cambio con fecha efectiva futura) y
+// ordena la entrada en esa posición entre las de su agregado, para que no retenga
+// los eventos posteriores que se registren mientras tanto
+func (o *OutboxEntry) ScheduleAt(at time.Time) {
+	if !at.After(o.CreatedAt) {
+		return
+	}
+	o.Sequence = at.UnixNano()
+	o.NextAttemptAt = at.Unix()
+}
+
+// Event deserializa el evento almacenado en la entrada
+func (o *OutboxEntry) Event() (*EmployeeEvent, error) {
+	var event EmployeeEvent
+	if err := json.Unmarshal([]byte(o.Payload), &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
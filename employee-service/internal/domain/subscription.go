@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Subscription representa una cola suscrita al bus de eventos.
+// EventTypes admite tipos exactos ("employee.created") o prefijos ("employee.*");
+// vacío significa que recibe todos los eventos.
+type Subscription struct {
+	Name       string
+	Endpoint   string
+	EventTypes []string
+}
+
+// Accepts indica si la suscripción recibe eventos del tipo indicado
+func (s Subscription) Accepts(eventType string) bool {
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+
+	for _, pattern := range s.EventTypes {
+		if prefix, ok := EventTypePrefix(pattern); ok {
+			if strings.HasPrefix(eventType, prefix) {
+				return true
+			}
+			continue
+		}
+		if pattern == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// EventTypePrefix devuelve el prefijo de un patrón "employee.*" ("employee.")
+func EventTypePrefix(pattern string) (string, bool) {
+	if !strings.HasSuffix(pattern, "*") {
+		return "", false
+	}
+	return strings.TrimSuffix(pattern, "*"), true
+}
+
+// ParseSubscriptions lee suscripciones con el formato "nombre=endpoint|tipo,tipo",
+// separadas por ";". Sin "|tipos" la suscripción recibe todos los eventos.
+func ParseSubscriptions(spec string) ([]Subscription, error) {
+	var subscriptions []Subscription
+	names := make(map[string]bool)
+	for _, item := range strings.Split(spec, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, target, ok := strings.Cut(item, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%w: %q needs a name=endpoint form", ErrInvalidSubscription, item)
+		}
+		if names[name] {
+			return nil, fmt.Errorf("%w: %s is declared twice", ErrInvalidSubscription, name)
+		}
+		names[name] = true
+
+		endpoint, types, _ := strings.Cut(target, "|")
+		subscription := Subscription{Name: name, Endpoint: strings.TrimSpace(endpoint)}
+		if subscription.Endpoint == "" {
+			return nil, fmt.Errorf("%w: %s has no endpoint", ErrInvalidSubscription, name)
+		}
+		for _, eventType := range strings.Split(types, ",") {
+			if eventType = strings.TrimSpace(eventType); eventType != "" {
+				subscription.EventTypes = append(subscription.EventTypes, eventType)
+			}
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, nil
+}
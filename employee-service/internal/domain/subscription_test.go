@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSubscriptionAccepts(t *testing.T) {
+	tests := []struct {
+		name       string
+		eventTypes []string
+		eventType  string
+		want       bool
+	}{
+		{name: "tipo exacto", eventTypes: []string{"employee.created"}, eventType: "employee.created", want: true},
+		{name: "otro tipo", eventTypes: []string{"employee.created"}, eventType: "employee.updated", want: false},
+		{name: "prefijo", eventTypes: []string{"employee.*"}, eventType: "employee.status_changed", want: true},
+		{name: "prefijo de otro dominio", eventTypes: []string{"employee.*"}, eventType: "message.sent", want: false},
+		{name: "varios patrones", eventTypes: []string{"employee.*", "message.*"}, eventType: "message.sent", want: true},
+		{name: "sin filtro", eventType: "auth.login", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subscription := Subscription{Name: "test", EventTypes: tt.eventTypes}
+			if got := subscription.Accepts(tt.eventType); got != tt.want {
+				t.Errorf("Accepts(%q) = %t, want %t", tt.eventType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSubscriptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      string
+		want      []Subscription
+		wantError error
+	}{
+		{
+			name: "varias suscripciones",
+			spec: "messaging=arn:aws:sqs:us-east-1:000000000000:employee-events-queue|employee.created; logger=arn:aws:sqs:us-east-1:000000000000:employee-queue|employee.*, message.*",
+			want: []Subscription{
+				{Name: "messaging", Endpoint: "arn:aws:sqs:us-east-1:000000000000:employee-events-queue", EventTypes: []string{"employee.created"}},
+				{Name: "logger", Endpoint: "arn:aws:sqs:us-east-1:000000000000:employee-queue", EventTypes: []string{"employee.*", "message.*"}},
+			},
+		},
+		{
+			name: "sin tipos recibe todo",
+			spec: "audit=arn:aws:sqs:us-east-1:000000000000:audit-queue;",
+			want: []Subscription{{Name: "audit", Endpoint: "arn:aws:sqs:us-east-1:000000000000:audit-queue"}},
+		},
+		{name: "vacío", spec: ""},
+		{name: "sin nombre", spec: "=arn:aws:sqs:us-east-1:000000000000:queue", wantError: ErrInvalidSubscription},
+		{name: "sin endpoint", spec: "logger=|employee.*", wantError: ErrInvalidSubscription},
+		{name: "sin separador", spec: "logger", wantError: ErrInvalidSubscription},
+		{name: "nombre repetido", spec: "logger=arn:a|employee.*;logger=arn:b", wantError: ErrInvalidSubscription},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSubscriptions(tt.spec)
+			if !errors.Is(err, tt.wantError) {
+				t.Fatalf("ParseSubscriptions() = %v, want %v", err, tt.wantError)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSubscriptions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
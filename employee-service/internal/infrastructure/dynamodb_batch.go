@@ -0,0 +1,180 @@
+package infrastructure
+
+import (
+	"context"
+	"employee-service/internal/domain"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+)
+
+// Límites de BatchWriteItem
+const (
+	batchWriteMaxItems   = 25 // Máximo de items por petición BatchWriteItem
+	batchWriteMaxRetries = 5  // Reintentos de items no procesados
+	batchWriteBaseDelay  = 100 * time.Millisecond
+)
+
+// transactWriteMaxItems es el máximo de items por TransactWriteItems
+const transactWriteMaxItems = 100
+
+// transactWriteMaxPairs es el máximo de pares empleado + entrada del outbox por
+// TransactWriteItems
+const transactWriteMaxPairs = transactWriteMaxItems / 2
+
+// batchPutItems escribe items (con clave de partición "ID") usando BatchWriteItem en
+// bloques de 25, reintentando con backoff exponencial los items no procesados.
+// Devuelve los IDs de los items que no pudieron escribirse.
+func batchPutItems(ctx context.Context, client *dynamodb.Client, tableName string, items []map[string]types.AttributeValue) ([]string, error) {
+	var failed []string
+	var firstErr error
+
+	for start := 0; start < len(items); start += batchWriteMaxItems {
+		end := start + batchWriteMaxItems
+		if end > len(items) {
+			end = len(items)
+		}
+
+		chunkFailed, err := writeChunk(ctx, client, tableName, items[start:end])
+		failed = append(failed, chunkFailed...)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if firstErr == nil && len(failed) > 0 {
+		firstErr = domain.ErrBatchWriteIncomplete
+	}
+
+	return failed, firstErr
+}
+
+// writeChunk escribe un bloque de hasta 25 items y reintenta los no procesados
+func writeChunk(ctx context.Context, client *dynamodb.Client, tableName string, items []map[string]types.AttributeValue) ([]string, error) {
+	requests := make([]types.WriteRequest, 0, len(items))
+	for _, item := range items {
+		requests = append(requests, types.WriteRequest{
+			PutRequest: &types.PutRequest{Item: item},
+		})
+	}
+
+	for attempt := 0; len(requests) > 0; attempt++ {
+		if attempt > 0 {
+			if attempt > batchWriteMaxRetries {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return requestIDs(requests), ctx.Err()
+			case <-time.After(batchWriteBaseDelay << (attempt - 1)):
+			}
+		}
+
+		output, err := client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]types.WriteRequest{
+				tableName: requests,
+			},
+		})
+		if err != nil {
+			log.Printf("Error writing batch to DynamoDB table %s: %v", tableName, err)
+			return requestIDs(requests), err
+		}
+
+		requests = output.UnprocessedItems[tableName]
+		if len(requests) > 0 {
+			log.Printf("BatchWriteItem left %d unprocessed items in %s (attempt %d)", len(requests), tableName, attempt+1)
+		}
+	}
+
+	return requestIDs(requests), nil
+}
+
+// requestIDs devuelve el atributo "ID" de cada petición de escritura
+func requestIDs(requests []types.WriteRequest) []string {
+	ids := make([]string, 0, len(requests))
+	for _, request := range requests {
+		if id, ok := request.PutRequest.Item["ID"].(*types.AttributeValueMemberS); ok {
+			ids = append(ids, id.Value)
+		}
+	}
+	return ids
+}
+
+// pairWriter escribe en una sola transacción los empleados y sus entradas del outbox
+type pairWriter func(ctx context.Context, employees []*domain.Employee, entries []*domain.OutboxEntry) error
+
+// writePairs escribe un bloque de pares con write, reintentando con backoff exponencial
+// los errores transitorios (throttling o conflicto con otra transacción). Si el bloque
+// sigue fallando lo divide en dos mitades y escribe cada una por separado, hasta aislar
+// los pares que no pueden escribirse. Devuelve los IDs de los empleados no guardados.
+func writePairs(ctx context.Context, employees []*domain.Employee, entries []*domain.OutboxEntry, write pairWriter) ([]string, error) {
+	err := writeWithRetry(ctx, employees, entries, write)
+	if err == nil {
+		return nil, nil
+	}
+	if len(employees) == 1 || ctx.Err() != nil {
+		return idsOf(employees), err
+	}
+
+	log.Printf("Splitting chunk of %d employees after transaction error: %v", len(employees), err)
+	middle := len(employees) / 2
+	failed, firstErr := writePairs(ctx, employees[:middle], entries[:middle], write)
+	secondFailed, secondErr := writePairs(ctx, employees[middle:], entries[middle:], write)
+	if firstErr == nil {
+		firstErr = secondErr
+	}
+	return append(failed, secondFailed...), firstErr
+}
+
+// writeWithRetry escribe el bloque reintentando los errores transitorios
+func writeWithRetry(ctx context.Context, employees []*domain.Employee, entries []*domain.OutboxEntry, write pairWriter) error {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(batchWriteBaseDelay << (attempt - 1)):
+			}
+		}
+
+		err := write(ctx, employees, entries)
+		if err == nil || !isRetryableTransactionError(err) || attempt == batchWriteMaxRetries {
+			return err
+		}
+		log.Printf("Retrying transaction of %d employees (attempt %d): %v", len(employees), attempt+1, err)
+	}
+}
+
+// isRetryableTransactionError indica si un error de TransactWriteItems es transitorio:
+// throttling, error interno o una transacción cancelada solo por conflictos o throttling
+func isRetryableTransactionError(err error) bool {
+	var canceled *types.TransactionCanceledException
+	if errors.As(err, &canceled) {
+		if len(canceled.CancellationReasons) == 0 {
+			return false
+		}
+		for _, reason := range canceled.CancellationReasons {
+			switch aws.ToString(reason.Code) {
+			case "", "None", "TransactionConflict", "ThrottlingError", "ProvisionedThroughputExceeded":
+			default:
+				return false
+			}
+		}
+		return true
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "ThrottlingException", "ProvisionedThroughputExceededException", "RequestLimitExceeded",
+			"TransactionInProgressException", "InternalServerError":
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package infrastructure
+
+import (
+	"context"
+	"employee-service/internal/domain"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func testPairs(n int) ([]*domain.Employee, []*domain.OutboxEntry) {
+	employees := make([]*domain.Employee, n)
+	entries := make([]*domain.OutboxEntry, n)
+	for i := range employees {
+		employees[i] = &domain.Employee{ID: fmt.Sprintf("emp-%d", i)}
+		entries[i] = &domain.OutboxEntry{ID: fmt.Sprintf("event-%d", i)}
+	}
+	return employees, entries
+}
+
+func canceled(codes ...string) error {
+	reasons := make([]types.CancellationReason, len(codes))
+	for i, code := range codes {
+		reasons[i] = types.CancellationReason{Code: aws.String(code)}
+	}
+	return &types.TransactionCanceledException{CancellationReasons: reasons}
+}
+
+func TestWritePairsIsolatesFailingRows(t *testing.T) {
+	employees, entries := testPairs(10)
+	rejected := map[string]bool{"emp-3": true, "emp-7": true}
+
+	var calls int
+	saved := make(map[string]bool)
+	write := func(ctx context.Context, employees []*domain.Employee, entries []*domain.OutboxEntry) error {
+		calls++
+		for _, employee := range employees {
+			if rejected[employee.ID] {
+				return canceled("None", "ConditionalCheckFailed")
+			}
+		}
+		for _, employee := range employees {
+			saved[employee.ID] = true
+		}
+		return nil
+	}
+
+	failed, err := writePairs(context.Background(), employees, entries, write)
+	var cancelErr *types.TransactionCanceledException
+	if !errors.As(err, &cancelErr) {
+		t.Fatalf("writePairs() error = %v, want the transaction error", err)
+	}
+	if want := []string{"emp-3", "emp-7"}; !reflect.DeepEqual(failed, want) {
+		t.Errorf("failed = %v, want %v", failed, want)
+	}
+	if len(saved) != 8 {
+		t.Errorf("saved %d employees, want 8 (%v)", len(saved), saved)
+	}
+	for id := range rejected {
+		if saved[id] {
+			t.Errorf("%s was saved", id)
+		}
+	}
+	// Un error permanente no se reintenta: solo se divide el bloque
+	if calls > 2*len(employees) {
+		t.Errorf("write called %d times", calls)
+	}
+}
+
+func TestWritePairsRetriesTransientErrors(t *testing.T) {
+	employees, entries := testPairs(4)
+
+	var calls int
+	write := func(ctx context.Context, employees []*domain.Employee, entries []*domain.OutboxEntry) error {
+		calls++
+		if calls <= 2 {
+			return canceled("None", "TransactionConflict", "None", "None")
+		}
+		return nil
+	}
+
+	failed, err := writePairs(context.Background(), employees, entries, write)
+	if err != nil || len(failed) != 0 {
+		t.Fatalf("writePairs() = %v, %v, want no failures", failed, err)
+	}
+	// El bloque entero se reintenta sin dividirse
+	if calls != 3 {
+		t.Errorf("write called %d times, want 3", calls)
+	}
+}
+
+func TestIsRetryableTransactionError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"conflicto con otra transacción", canceled("None", "TransactionConflict"), true},
+		{"throttling en un item", canceled("ThrottlingError", "None"), true},
+		{"capacidad excedida en un item", canceled("ProvisionedThroughputExceeded"), true},
+		{"condición fallida", canceled("None", "ConditionalCheckFailed"), false},
+		{"conflicto y condición fallida", canceled("TransactionConflict", "ConditionalCheckFailed"), false},
+		{"item inválido", canceled("ValidationError"), false},
+		{"cancelada sin motivos", &types.TransactionCanceledException{}, false},
+		{"throughput de la tabla", &types.ProvisionedThroughputExceededException{}, true},
+		{"límite de peticiones", &types.RequestLimitExceeded{}, true},
+		{"error interno", &types.InternalServerError{}, true},
+		{"transacción en curso", &types.TransactionInProgressException{}, true},
+		{"error envuelto", fmt.Errorf("save: %w", canceled("TransactionConflict")), true},
+		{"otro error", errors.New("network down"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableTransactionError(tt.err); got != tt.want {
+				t.Errorf("isRetryableTransactionError(%v) = %t, want %t", tt.err, got, tt.want)
+			}
+		})
+	}
+}
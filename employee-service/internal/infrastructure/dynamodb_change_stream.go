@@ -0,0 +1,219 @@
+package infrastructure
+
+import (
+	"context"
+	"employee-service/internal/domain"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
+	streamtypes "github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+)
+
+// streamRecordsLimit es el máximo de registros por llamada a GetRecords
+const streamRecordsLimit = 100
+
+// shardPosition guarda el iterador a reutilizar mientras la posición no cambie
+type shardPosition struct {
+	afterSequence string
+	iterator      *string
+}
+
+// DynamoDBChangeStream implementa el stream de cambios usando DynamoDB Streams
+type DynamoDBChangeStream struct {
+	client    *dynamodbstreams.Client
+	streamARN string
+
+	mu        sync.Mutex
+	iterators map[string]shardPosition
+}
+
+// NewDynamoDBChangeStream crea una nueva instancia del stream
+func NewDynamoDBChangeStream(client *dynamodbstreams.Client, streamARN string) *DynamoDBChangeStream {
+	return &DynamoDBChangeStream{
+		client:    client,
+		streamARN: streamARN,
+		iterators: make(map[string]shardPosition),
+	}
+}
+
+// LatestStreamARN obtiene el ARN del stream habilitado en la tabla
+func LatestStreamARN(ctx context.Context, client *dynamodb.Client, tableName string) (string, error) {
+	output, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(tableName),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if output.Table.LatestStreamArn == nil {
+		return "", fmt.Errorf("table %s has no stream enabled", tableName)
+	}
+	return *output.Table.LatestStreamArn, nil
+}
+
+// Shards lista los shards del stream (paginando con ExclusiveStartShardId)
+func (s *DynamoDBChangeStream) Shards(ctx context.Context) ([]domain.StreamShard, error) {
+	var shards []domain.StreamShard
+	var startShardID *string
+
+	for {
+		output, err := s.client.DescribeStream(ctx, &dynamodbstreams.DescribeStreamInput{
+			StreamArn:             aws.String(s.streamARN),
+			ExclusiveStartShardId: startShardID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, shard := range output.StreamDescription.Shards {
+			shards = append(shards, domain.StreamShard{
+				ID:       aws.ToString(shard.ShardId),
+				ParentID: aws.ToString(shard.ParentShardId),
+			})
+		}
+
+		if output.StreamDescription.LastEvaluatedShardId == nil {
+			return shards, nil
+		}
+		startShardID = output.StreamDescription.LastEvaluatedShardId
+	}
+}
+
+// Read obtiene los registros del shard posteriores a afterSequence
+func (s *DynamoDBChangeStream) Read(ctx context.Context, shardID, afterSequence string) ([]*domain.ChangeRecord, bool, error) {
+	iterator, err := s.iterator(ctx, shardID, afterSequence)
+	if err != nil {
+		return nil, false, err
+	}
+
+	output, err := s.client.GetRecords(ctx, &dynamodbstreams.GetRecordsInput{
+		ShardIterator: iterator,
+		Limit:         aws.Int32(streamRecordsLimit),
+	})
+
+	// Los iteradores caducan a los 15 minutos: se pide uno nuevo y se reintenta
+	var expired *streamtypes.ExpiredIteratorException
+	if errors.As(err, &expired) {
+		s.forget(shardID)
+		if iterator, err = s.iterator(ctx, shardID, afterSequence); err != nil {
+			return nil, false, err
+		}
+		output, err = s.client.GetRecords(ctx, &dynamodbstreams.GetRecordsInput{
+			ShardIterator: iterator,
+			Limit:         aws.Int32(streamRecordsLimit),
+		})
+	}
+	if err != nil {
+		s.forget(shardID)
+		return nil, false, err
+	}
+
+	records := make([]*domain.ChangeRecord, 0, len(output.Records))
+	for _, record := range output.Records {
+		change, err := toChangeRecord(record)
+		if err != nil {
+			s.forget(shardID)
+			return nil, false, err
+		}
+		records = append(records, change)
+	}
+
+	position := afterSequence
+	if len(records) > 0 {
+		position = records[len(records)-1].SequenceNumber
+	}
+
+	// Sin NextShardIterator el shard está cerrado y ya se leyó por completo
+	closed := output.NextShardIterator == nil
+	if closed {
+		s.forget(shardID)
+	} else {
+		s.remember(shardID, shardPosition{afterSequence: position, iterator: output.NextShardIterator})
+	}
+
+	return records, closed, nil
+}
+
+// iterator reutiliza el iterador guardado o solicita uno nuevo para la posición indicada
+func (s *DynamoDBChangeStream) iterator(ctx context.Context, shardID, afterSequence string) (*string, error) {
+	s.mu.Lock()
+	cached, ok := s.iterators[shardID]
+	s.mu.Unlock()
+	if ok && cached.afterSequence == afterSequence {
+		return cached.iterator, nil
+	}
+
+	input := &dynamodbstreams.GetShardIteratorInput{
+		StreamArn:         aws.String(s.streamARN),
+		ShardId:           aws.String(shardID),
+		ShardIteratorType: streamtypes.ShardIteratorTypeTrimHorizon,
+	}
+	if afterSequence != "" {
+		input.ShardIteratorType = streamtypes.ShardIteratorTypeAfterSequenceNumber
+		input.SequenceNumber = aws.String(afterSequence)
+	}
+
+	output, err := s.client.GetShardIterator(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return output.ShardIterator, nil
+}
+
+func (s *DynamoDBChangeStream) remember(shardID string, position shardPosition) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.iterators[shardID] = position
+}
+
+func (s *DynamoDBChangeStream) forget(shardID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.iterators, shardID)
+}
+
+// toChangeRecord convierte un registro del stream al modelo de dominio
+func toChangeRecord(record streamtypes.Record) (*domain.ChangeRecord, error) {
+	change := &domain.ChangeRecord{
+		SequenceNumber: aws.ToString(record.Dynamodb.SequenceNumber),
+		Operation:      domain.ChangeOperation(record.EventName),
+		CreatedAt:      time.Now().UTC(),
+	}
+	if record.Dynamodb.ApproximateCreationDateTime != nil {
+		change.CreatedAt = record.Dynamodb.ApproximateCreationDateTime.UTC()
+	}
+
+	var err error
+	if change.OldImage, err = toEmployeeImage(record.Dynamodb.OldImage); err != nil {
+		return nil, err
+	}
+	if change.NewImage, err = toEmployeeImage(record.Dynamodb.NewImage); err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+// toEmployeeImage convierte una imagen del stream en un empleado (nil si no hay imagen)
+func toEmployeeImage(image map[string]streamtypes.AttributeValue) (*domain.Employee, error) {
+	if len(image) == 0 {
+		return nil, nil
+	}
+
+	item, err := attributevalue.FromDynamoDBStreamsMap(image)
+	if err != nil {
+		return nil, err
+	}
+
+	var employee domain.Employee
+	if err := attributevalue.UnmarshalMap(item, &employee); err != nil {
+		return nil, err
+	}
+	return &employee, nil
+}
@@ -0,0 +1,57 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// DynamoDBCheckpointStore guarda la posición procesada de cada shard en DynamoDB
+type DynamoDBCheckpointStore struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewDynamoDBCheckpointStore crea una nueva instancia del almacén de checkpoints
+func NewDynamoDBCheckpointStore(client *dynamodb.Client, tableName string) *DynamoDBCheckpointStore {
+	return &DynamoDBCheckpointStore{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// Load obtiene el último número de secuencia procesado del shard ("" si no hay)
+func (s *DynamoDBCheckpointStore) Load(ctx context.Context, shardID string) (string, error) {
+	result, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"ShardID": &types.AttributeValueMemberS{Value: shardID},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sequence, ok := result.Item["SequenceNumber"].(*types.AttributeValueMemberS)
+	if !ok {
+		return "", nil
+	}
+	return sequence.Value, nil
+}
+
+// Save guarda el número de secuencia procesado del shard
+func (s *DynamoDBCheckpointStore) Save(ctx context.Context, shardID, sequenceNumber string) error {
+	_, err := s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item: map[string]types.AttributeValue{
+			"ShardID":        &types.AttributeValueMemberS{Value: shardID},
+			"SequenceNumber": &types.AttributeValueMemberS{Value: sequenceNumber},
+			"UpdatedAt":      &types.AttributeValueMemberS{Value: time.Now().UTC().Format(time.RFC3339)},
+		},
+	})
+	return err
+}
@@ -0,0 +1,97 @@
+package infrastructure
+
+import (
+	"context"
+	"employee-service/internal/domain"
+	"log"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// DynamoDBDepartmentRepository implementa el repositorio de departamentos usando DynamoDB
+type DynamoDBDepartmentRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewDynamoDBDepartmentRepository crea una nueva instancia del repositorio
+func NewDynamoDBDepartmentRepository(client *dynamodb.Client, tableName string) *DynamoDBDepartmentRepository {
+	return &DynamoDBDepartmentRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// Save guarda un departamento en DynamoDB
+func (r *DynamoDBDepartmentRepository) Save(ctx context.Context, department *domain.Department) error {
+	item, err := attributevalue.MarshalMap(department)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	})
+
+	if err != nil {
+		log.Printf("Error saving department to DynamoDB: %v", err)
+		return err
+	}
+
+	log.Printf("Department saved successfully: ID=%s, Name=%s", department.ID, department.Name)
+	return nil
+}
+
+// FindByID busca un departamento por su ID
+func (r *DynamoDBDepartmentRepository) FindByID(ctx context.Context, id string) (*domain.Department, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Item == nil {
+		return nil, domain.ErrDepartmentNotFound
+	}
+
+	var department domain.Department
+	err = attributevalue.UnmarshalMap(result.Item, &department)
+	if err != nil {
+		return nil, err
+	}
+
+	return &department, nil
+}
+
+// FindAll obtiene todos los departamentos
+func (r *DynamoDBDepartmentRepository) FindAll(ctx context.Context) ([]*domain.Department, error) {
+	result, err := r.client.Scan(ctx, &dynamodb.ScanInput{
+		TableName: aws.String(r.tableName),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var departments []*domain.Department
+	for _, item := range result.Items {
+		var department domain.Department
+		err := attributevalue.UnmarshalMap(item, &department)
+		if err != nil {
+			log.Printf("Error unmarshaling department: %v", err)
+			continue
+		}
+		departments = append(departments, &department)
+	}
+
+	return departments, nil
+}
@@ -0,0 +1,123 @@
+package infrastructure
+
+import (
+	"context"
+	"employee-service/internal/domain"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// DynamoDBIdempotencyStore implementa el almacén de claves de idempotencia usando DynamoDB.
+// La tabla usa "Key" como clave de partición y "ExpiresAt" como atributo TTL.
+type DynamoDBIdempotencyStore struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewDynamoDBIdempotencyStore crea una nueva instancia del almacén
+func NewDynamoDBIdempotencyStore(client *dynamodb.Client, tableName string) *DynamoDBIdempotencyStore {
+	return &DynamoDBIdempotencyStore{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// Reserve registra la clave con una escritura condicional; si ya existe una clave
+// vigente devuelve el registro almacenado
+func (s *DynamoDBIdempotencyStore) Reserve(ctx context.Context, record *domain.IdempotencyRecord) (*domain.IdempotencyRecord, bool, error) {
+	item, err := attributevalue.MarshalMap(record)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// El TTL de DynamoDB borra los items con retraso: una clave expirada se puede reutilizar
+	_, err = s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(s.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(#key) OR #expires < :now"),
+		ExpressionAttributeNames: map[string]string{
+			"#key":     "Key",
+			"#expires": "ExpiresAt",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(time.Now().Unix(), 10)},
+		},
+	})
+	if err == nil {
+		return nil, true, nil
+	}
+
+	var conditionFailed *types.ConditionalCheckFailedException
+	if !errors.As(err, &conditionFailed) {
+		log.Printf("Error reserving idempotency key: %v", err)
+		return nil, false, err
+	}
+
+	result, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(s.tableName),
+		ConsistentRead: aws.Bool(true),
+		Key: map[string]types.AttributeValue{
+			"Key": &types.AttributeValueMemberS{Value: record.Key},
+		},
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if result.Item == nil {
+		// La clave se liberó entre la escritura y la lectura: el cliente puede reintentar
+		return nil, false, domain.ErrIdempotencyRequestInProgress
+	}
+
+	var existing domain.IdempotencyRecord
+	if err := attributevalue.UnmarshalMap(result.Item, &existing); err != nil {
+		return nil, false, err
+	}
+	return &existing, false, nil
+}
+
+// Complete guarda la respuesta final de la petición y extiende su expiración del
+// lease de la reserva a la ventana de idempotencia
+func (s *DynamoDBIdempotencyStore) Complete(ctx context.Context, key string, status int, contentType, body string, expiresAt time.Time) error {
+	_, err := s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"Key": &types.AttributeValueMemberS{Value: key},
+		},
+		UpdateExpression: aws.String("SET #status = :status, ResponseStatus = :code, ResponseContentType = :contentType, ResponseBody = :body, ExpiresAt = :expiresAt"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "Status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":status":      &types.AttributeValueMemberS{Value: string(domain.IdempotencyCompleted)},
+			":code":        &types.AttributeValueMemberN{Value: strconv.Itoa(status)},
+			":contentType": &types.AttributeValueMemberS{Value: contentType},
+			":body":        &types.AttributeValueMemberS{Value: body},
+			":expiresAt":   &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt.Unix(), 10)},
+		},
+	})
+	if err != nil {
+		log.Printf("Error completing idempotency key: %v", err)
+	}
+	return err
+}
+
+// Release elimina la clave
+func (s *DynamoDBIdempotencyStore) Release(ctx context.Context, key string) error {
+	_, err := s.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"Key": &types.AttributeValueMemberS{Value: key},
+		},
+	})
+	if err != nil {
+		log.Printf("Error releasing idempotency key: %v", err)
+	}
+	return err
+}
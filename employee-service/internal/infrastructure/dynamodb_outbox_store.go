@@ -0,0 +1,213 @@
+package infrastructure
+
+import (
+	"context"
+	"employee-service/internal/domain"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// sentOutboxRetention es el tiempo que se conservan las entradas ya publicadas (TTL)
+const sentOutboxRetention = 7 * 24 * time.Hour
+
+// Índices dispersos de las entradas pendientes
+const (
+	pendingIndexName          = "PendingIndex"          // Pending + NextAttemptAt
+	pendingAggregateIndexName = "PendingAggregateIndex" // PendingAggregateID + Sequence
+)
+
+// DynamoDBOutboxStore implementa el outbox de eventos usando DynamoDB
+type DynamoDBOutboxStore struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewDynamoDBOutboxStore crea una nueva instancia del outbox
+func NewDynamoDBOutboxStore(client *dynamodb.Client, tableName string) *DynamoDBOutboxStore {
+	return &DynamoDBOutboxStore{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// FindPending obtiene entradas pendientes cuyo próximo intento ya venció, consultando
+// el índice disperso PendingIndex (Pending + NextAttemptAt): solo contiene las
+// entradas pendientes, así que el coste no crece con las entradas ya publicadas
+func (s *DynamoDBOutboxStore) FindPending(ctx context.Context, now time.Time, limit int) ([]*domain.OutboxEntry, error) {
+	paginator := dynamodb.NewQueryPaginator(s.client, &dynamodb.QueryInput{
+		TableName:              aws.String(s.tableName),
+		IndexName:              aws.String(pendingIndexName),
+		KeyConditionExpression: aws.String("Pending = :pending AND NextAttemptAt <= :now"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pending": &types.AttributeValueMemberS{Value: string(domain.OutboxPending)},
+			":now":     &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
+		},
+		Limit: aws.Int32(int32(limit)),
+	})
+
+	var entries []*domain.OutboxEntry
+	for paginator.HasMorePages() && len(entries) < limit {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range page.Items {
+			var entry domain.OutboxEntry
+			if err := attributevalue.UnmarshalMap(item, &entry); err != nil {
+				log.Printf("Error unmarshaling outbox entry: %v", err)
+				continue
+			}
+			entries = append(entries, &entry)
+			if len(entries) == limit {
+				break
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+// FindPendingByAggregate obtiene las entradas pendientes de un agregado en orden de
+// Sequence consultando el índice disperso PendingAggregateIndex
+func (s *DynamoDBOutboxStore) FindPendingByAggregate(ctx context.Context, aggregateID string, limit int) ([]*domain.OutboxEntry, error) {
+	output, err := s.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(s.tableName),
+		IndexName:              aws.String(pendingAggregateIndexName),
+		KeyConditionExpression: aws.String("PendingAggregateID = :aggregateID"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":aggregateID": &types.AttributeValueMemberS{Value: aggregateID},
+		},
+		ScanIndexForward: aws.Bool(true),
+		Limit:            aws.Int32(int32(limit)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Una entrada ilegible no se salta: las siguientes del agregado no deben adelantarla
+	entries := make([]*domain.OutboxEntry, 0, len(output.Items))
+	for _, item := range output.Items {
+		var entry domain.OutboxEntry
+		if err := attributevalue.UnmarshalMap(item, &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
+
+// Claim adelanta el próximo intento de la entrada hasta leaseUntil con una escritura
+// condicional sobre el NextAttemptAt leído: solo una réplica gana la reserva y, si
+// cae antes de marcarla, la entrada vuelve a estar pendiente al vencer el lease
+func (s *DynamoDBOutboxStore) Claim(ctx context.Context, entry *domain.OutboxEntry, leaseUntil time.Time) (bool, error) {
+	return s.setNextAttempt(ctx, entry, leaseUntil)
+}
+
+// Postpone retrasa el próximo intento de la entrada con la misma escritura
+// condicional que Claim; si otra réplica la reservó antes no hace nada
+func (s *DynamoDBOutboxStore) Postpone(ctx context.Context, entry *domain.OutboxEntry, until time.Time) error {
+	_, err := s.setNextAttempt(ctx, entry, until)
+	return err
+}
+
+// setNextAttempt cambia NextAttemptAt si la entrada sigue pendiente con el valor leído
+func (s *DynamoDBOutboxStore) setNextAttempt(ctx context.Context, entry *domain.OutboxEntry, next time.Time) (bool, error) {
+	_, err := s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: entry.ID},
+		},
+		UpdateExpression:    aws.String("SET NextAttemptAt = :next"),
+		ConditionExpression: aws.String("#status = :pending AND NextAttemptAt = :observed"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "Status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":next":     &types.AttributeValueMemberN{Value: strconv.FormatInt(next.Unix(), 10)},
+			":pending":  &types.AttributeValueMemberS{Value: string(domain.OutboxPending)},
+			":observed": &types.AttributeValueMemberN{Value: strconv.FormatInt(entry.NextAttemptAt, 10)},
+		},
+	})
+	if err == nil {
+		entry.NextAttemptAt = next.Unix()
+		return true, nil
+	}
+
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return false, nil
+	}
+	return false, err
+}
+
+// MarkSent marca una entrada como publicada y programa su expiración
+func (s *DynamoDBOutboxStore) MarkSent(ctx context.Context, id string) error {
+	now := time.Now()
+	sentAt, err := attributevalue.Marshal(now)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression: aws.String("SET #status = :sent, SentAt = :sentAt, ExpiresAt = :expiresAt REMOVE Pending, PendingAggregateID"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "Status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":sent":      &types.AttributeValueMemberS{Value: string(domain.OutboxSent)},
+			":sentAt":    sentAt,
+			":expiresAt": &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Add(sentOutboxRetention).Unix(), 10)},
+		},
+	})
+	return err
+}
+
+// MarkRetry registra un intento fallido y programa el siguiente
+func (s *DynamoDBOutboxStore) MarkRetry(ctx context.Context, id string, attempts int, nextAttemptAt time.Time, lastErr string) error {
+	_, err := s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression: aws.String("SET Attempts = :attempts, NextAttemptAt = :next, LastError = :lastError"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":attempts":  &types.AttributeValueMemberN{Value: strconv.Itoa(attempts)},
+			":next":      &types.AttributeValueMemberN{Value: strconv.FormatInt(nextAttemptAt.Unix(), 10)},
+			":lastError": &types.AttributeValueMemberS{Value: lastErr},
+		},
+	})
+	return err
+}
+
+// MarkFailed marca como failed una entrada que agotó los intentos y la saca del
+// índice de pendientes; se conserva (sin TTL) para revisarla y reencolarla a mano
+func (s *DynamoDBOutboxStore) MarkFailed(ctx context.Context, id string, attempts int, lastErr string) error {
+	_, err := s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression: aws.String("SET #status = :failed, Attempts = :attempts, LastError = :lastError REMOVE Pending, PendingAggregateID"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "Status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":failed":    &types.AttributeValueMemberS{Value: string(domain.OutboxFailed)},
+			":attempts":  &types.AttributeValueMemberN{Value: strconv.Itoa(attempts)},
+			":lastError": &types.AttributeValueMemberS{Value: lastErr},
+		},
+	})
+	return err
+}
@@ -3,6 +3,9 @@ package infrastructure
 import (
 	"context"
 	"employee-service/internal/domain"
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"log"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -11,17 +14,26 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
+// GSIs de la tabla de empleados
+const (
+	emailIndexName      = "EmailIndex"      // Email (solo claves)
+	managerIndexName    = "ManagerIndex"    // ManagerID, disperso
+	departmentIndexName = "DepartmentIndex" // DepartmentID, disperso
+)
+
 // DynamoDBRepository implementa el repositorio usando DynamoDB
 type DynamoDBRepository struct {
-	client    *dynamodb.Client
-	tableName string
+	client          *dynamodb.Client
+	tableName       string
+	outboxTableName string
 }
 
 // NewDynamoDBRepository crea una nueva instancia del repositorio
-func NewDynamoDBRepository(client *dynamodb.Client, tableName string) *DynamoDBRepository {
+func NewDynamoDBRepository(client *dynamodb.Client, tableName, outboxTableName string) *DynamoDBRepository {
 	return &DynamoDBRepository{
-		client:    client,
-		tableName: tableName,
+		client:          client,
+		tableName:       tableName,
+		outboxTableName: outboxTableName,
 	}
 }
 
@@ -46,6 +58,248 @@ func (r *DynamoDBRepository) Save(ctx context.Context, employee *domain.Employee
 	return nil
 }
 
+// SaveBatch guarda varios empleados usando BatchWriteItem en bloques de 25,
+// reintentando con backoff exponencial los items no procesados.
+// Devuelve los IDs de los empleados que no pudieron guardarse.
+func (r *DynamoDBRepository) SaveBatch(ctx context.Context, employees []*domain.Employee) ([]string, error) {
+	items := make([]map[string]types.AttributeValue, 0, len(employees))
+	for _, employee := range employees {
+		item, err := attributevalue.MarshalMap(employee)
+		if err != nil {
+			return idsOf(employees), err
+		}
+		items = append(items, item)
+	}
+
+	failed, err := batchPutItems(ctx, r.client, r.tableName, items)
+	log.Printf("Batch saved: %d employees, %d failed", len(employees)-len(failed), len(failed))
+	return failed, err
+}
+
+// SaveWithOutbox guarda el empleado y su entrada del outbox con TransactWriteItems:
+// o se escriben ambos o ninguno, de modo que ningún cambio queda sin su evento
+func (r *DynamoDBRepository) SaveWithOutbox(ctx context.Context, employee *domain.Employee, entry *domain.OutboxEntry) error {
+	employeeItem, err := attributevalue.MarshalMap(employee)
+	if err != nil {
+		return err
+	}
+
+	entryItem, err := attributevalue.MarshalMap(entry)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Put: &types.Put{
+					TableName: aws.String(r.tableName),
+					Item:      employeeItem,
+				},
+			},
+			{
+				Put: &types.Put{
+					TableName:           aws.String(r.outboxTableName),
+					Item:                entryItem,
+					ConditionExpression: aws.String("attribute_not_exists(ID)"),
+				},
+			},
+		},
+	})
+
+	if err != nil {
+		log.Printf("Error saving employee with outbox entry to DynamoDB: %v", err)
+		return err
+	}
+
+	log.Printf("Employee saved successfully with outbox entry: ID=%s, Event=%s", employee.ID, entry.EventType)
+	return nil
+}
+
+// SaveManagerAssignment guarda el empleado con TransactWriteItems condicionado a que su
+// jefe siga siendo previousManagerID y a que cada empleado de chain (la cadena de mando
+// recorrida al validar que no hay ciclos) conserve el jefe leído. Así dos asignaciones
+// concurrentes no pueden cerrar un ciclo que ninguna de las dos vio.
+func (r *DynamoDBRepository) SaveManagerAssignment(ctx context.Context, employee *domain.Employee, previousManagerID string, chain []domain.ManagerLink, entry *domain.OutboxEntry) error {
+	if len(chain)+2 > transactWriteMaxItems {
+		return fmt.Errorf("management chain of %d employees is too long to verify in one transaction", len(chain))
+	}
+
+	employeeItem, err := attributevalue.MarshalMap(employee)
+	if err != nil {
+		return err
+	}
+
+	condition, values := managerCondition(previousManagerID)
+	items := []types.TransactWriteItem{{
+		Put: &types.Put{
+			TableName:                 aws.String(r.tableName),
+			Item:                      employeeItem,
+			ConditionExpression:       condition,
+			ExpressionAttributeValues: values,
+		},
+	}}
+
+	for _, link := range chain {
+		condition, values := managerCondition(link.ManagerID)
+		items = append(items, types.TransactWriteItem{
+			ConditionCheck: &types.ConditionCheck{
+				TableName: aws.String(r.tableName),
+				Key: map[string]types.AttributeValue{
+					"ID": &types.AttributeValueMemberS{Value: link.EmployeeID},
+				},
+				ConditionExpression:       condition,
+				ExpressionAttributeValues: values,
+			},
+		})
+	}
+
+	if entry != nil {
+		entryItem, err := attributevalue.MarshalMap(entry)
+		if err != nil {
+			return err
+		}
+		items = append(items, types.TransactWriteItem{
+			Put: &types.Put{
+				TableName:           aws.String(r.outboxTableName),
+				Item:                entryItem,
+				ConditionExpression: aws.String("attribute_not_exists(ID)"),
+			},
+		})
+	}
+
+	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+	if err != nil {
+		var canceled *types.TransactionCanceledException
+		if errors.As(err, &canceled) && hasConditionalCheckFailed(canceled) {
+			log.Printf("Manager assignment of %s rejected: hierarchy changed concurrently", employee.ID)
+			return domain.ErrHierarchyChanged
+		}
+		log.Printf("Error saving manager assignment to DynamoDB: %v", err)
+		return err
+	}
+
+	log.Printf("Manager assigned: ID=%s, ManagerID=%s (%d links verified)", employee.ID, employee.ManagerID, len(chain))
+	return nil
+}
+
+// managerCondition exige que el empleado exista y que su jefe sea managerID
+// ("" = sin jefe: atributo ausente o vacío en items anteriores al índice disperso)
+func managerCondition(managerID string) (*string, map[string]types.AttributeValue) {
+	values := map[string]types.AttributeValue{
+		":manager": &types.AttributeValueMemberS{Value: managerID},
+	}
+	if managerID == "" {
+		return aws.String("attribute_exists(ID) AND (attribute_not_exists(ManagerID) OR ManagerID = :manager)"), values
+	}
+	return aws.String("attribute_exists(ID) AND ManagerID = :manager"), values
+}
+
+// hasConditionalCheckFailed indica si la transacción se canceló por una condición fallida
+func hasConditionalCheckFailed(canceled *types.TransactionCanceledException) bool {
+	for _, reason := range canceled.CancellationReasons {
+		if aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+			return true
+		}
+	}
+	return false
+}
+
+// SaveBatchWithOutbox guarda cada empleado junto con su entrada del outbox
+// (entries[i] corresponde a employees[i]) con TransactWriteItems en bloques de
+// transactWriteMaxPairs pares. Los errores transitorios se reintentan y un bloque
+// que sigue fallando se divide hasta aislar los pares que no pueden escribirse:
+// devuelve los IDs de esos empleados.
+func (r *DynamoDBRepository) SaveBatchWithOutbox(ctx context.Context, employees []*domain.Employee, entries []*domain.OutboxEntry) ([]string, error) {
+	if len(employees) != len(entries) {
+		return idsOf(employees), fmt.Errorf("got %d outbox entries for %d employees", len(entries), len(employees))
+	}
+
+	var failed []string
+	var firstErr error
+
+	for start := 0; start < len(employees); start += transactWriteMaxPairs {
+		end := start + transactWriteMaxPairs
+		if end > len(employees) {
+			end = len(employees)
+		}
+
+		chunkFailed, err := writePairs(ctx, employees[start:end], entries[start:end], r.transactPairs)
+		if err != nil {
+			log.Printf("Error saving import chunk with outbox entries to DynamoDB: %v", err)
+			failed = append(failed, chunkFailed...)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	log.Printf("Batch saved with outbox: %d employees, %d failed", len(employees)-len(failed), len(failed))
+	return failed, firstErr
+}
+
+// transactPairs escribe en una sola transacción los empleados y sus entradas del outbox
+func (r *DynamoDBRepository) transactPairs(ctx context.Context, employees []*domain.Employee, entries []*domain.OutboxEntry) error {
+	items := make([]types.TransactWriteItem, 0, 2*len(employees))
+	for i, employee := range employees {
+		employeeItem, err := attributevalue.MarshalMap(employee)
+		if err != nil {
+			return err
+		}
+		entryItem, err := attributevalue.MarshalMap(entries[i])
+		if err != nil {
+			return err
+		}
+
+		items = append(items,
+			types.TransactWriteItem{
+				Put: &types.Put{
+					TableName: aws.String(r.tableName),
+					Item:      employeeItem,
+				},
+			},
+			types.TransactWriteItem{
+				Put: &types.Put{
+					TableName:           aws.String(r.outboxTableName),
+					Item:                entryItem,
+					ConditionExpression: aws.String("attribute_not_exists(ID)"),
+				},
+			},
+		)
+	}
+
+	_, err := r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+	return err
+}
+
+// FindExistingEmails consulta el índice EmailIndex (una Query por email, proyectando
+// solo claves) y devuelve los emails que ya pertenecen a algún empleado
+func (r *DynamoDBRepository) FindExistingEmails(ctx context.Context, emails []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	for _, email := range emails {
+		result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(r.tableName),
+			IndexName:              aws.String(emailIndexName),
+			KeyConditionExpression: aws.String("Email = :email"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":email": &types.AttributeValueMemberS{Value: email},
+			},
+			Limit: aws.Int32(1),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(result.Items) > 0 {
+			existing[email] = true
+		}
+	}
+	return existing, nil
+}
+
 // FindByID busca un empleado por su ID
 func (r *DynamoDBRepository) FindByID(ctx context.Context, id string) (*domain.Employee, error) {
 	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
@@ -95,3 +349,96 @@ func (r *DynamoDBRepository) FindAll(ctx context.Context) ([]*domain.Employee, e
 
 	return employees, nil
 }
+
+// FindPage obtiene una página de empleados mediante Scan paginado.
+// El cursor codifica el ID del último empleado evaluado (LastEvaluatedKey).
+func (r *DynamoDBRepository) FindPage(ctx context.Context, cursor string, limit int) (*domain.EmployeePage, error) {
+	input := &dynamodb.ScanInput{
+		TableName: aws.String(r.tableName),
+		Limit:     aws.Int32(int32(limit)),
+	}
+
+	if cursor != "" {
+		lastID, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil {
+			return nil, domain.ErrInvalidCursor
+		}
+		input.ExclusiveStartKey = map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: string(lastID)},
+		}
+	}
+
+	result, err := r.client.Scan(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.EmployeePage{}
+	for _, item := range result.Items {
+		var employee domain.Employee
+		if err := attributevalue.UnmarshalMap(item, &employee); err != nil {
+			log.Printf("Error unmarshaling employee: %v", err)
+			continue
+		}
+		page.Employees = append(page.Employees, &employee)
+	}
+
+	if id, ok := result.LastEvaluatedKey["ID"].(*types.AttributeValueMemberS); ok {
+		page.NextCursor = base64.RawURLEncoding.EncodeToString([]byte(id.Value))
+	}
+
+	return page, nil
+}
+
+// FindByManagerID obtiene los reportes directos de un jefe con una Query sobre ManagerIndex
+func (r *DynamoDBRepository) FindByManagerID(ctx context.Context, managerID string) ([]*domain.Employee, error) {
+	return r.queryByIndex(ctx, managerIndexName, "ManagerID", managerID)
+}
+
+// FindByDepartmentID obtiene los empleados de un departamento con una Query sobre DepartmentIndex
+func (r *DynamoDBRepository) FindByDepartmentID(ctx context.Context, departmentID string) ([]*domain.Employee, error) {
+	return r.queryByIndex(ctx, departmentIndexName, "DepartmentID", departmentID)
+}
+
+// queryByIndex recorre todas las páginas de la Query sobre un GSI con proyección completa
+func (r *DynamoDBRepository) queryByIndex(ctx context.Context, indexName, attribute, value string) ([]*domain.Employee, error) {
+	paginator := dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String(indexName),
+		KeyConditionExpression: aws.String("#attr = :value"),
+		ExpressionAttributeNames: map[string]string{
+			"#attr": attribute,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":value": &types.AttributeValueMemberS{Value: value},
+		},
+	})
+
+	var employees []*domain.Employee
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range page.Items {
+			var employee domain.Employee
+			if err := attributevalue.UnmarshalMap(item, &employee); err != nil {
+				log.Printf("Error unmarshaling employee: %v", err)
+				continue
+			}
+			employees = append(employees, &employee)
+		}
+	}
+
+	return employees, nil
+}
+
+// idsOf devuelve los IDs de una lista de empleados
+func idsOf(employees []*domain.Employee) []string {
+	ids := make([]string, len(employees))
+	for i, employee := range employees {
+		ids[i] = employee.ID
+	}
+	return ids
+}
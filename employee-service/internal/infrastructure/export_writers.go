@@ -0,0 +1,282 @@
+package infrastructure
+
+import (
+	"bufio"
+	"employee-service/internal/domain"
+	"employee-service/internal/ports"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// FormatXLSX es el formato de hoja de cálculo de Excel
+const FormatXLSX = "xlsx"
+
+// Content types de los formatos de exportación
+const (
+	ContentTypeCSV    = "text/csv"
+	ContentTypeNDJSON = "application/x-ndjson"
+	ContentTypeXLSX   = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// FormatFromAccept elige el formato de exportación según el header Accept,
+// respetando los valores q: gana el tipo con mayor q y, a igual q, el más
+// específico (text/csv antes que text/* y que */*). Un tipo con q=0 se excluye
+// también de los comodines. Sin preferencia explícita (vacío) se exporta CSV.
+func FormatFromAccept(accept string) (string, error) {
+	if strings.TrimSpace(accept) == "" {
+		return FormatCSV, nil
+	}
+
+	excluded := make(map[string]bool)
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil || quality < 0 || quality > 1 {
+				continue
+			}
+		}
+		formats, specificity := formatsForMediaType(mediaType)
+		if formats == nil {
+			continue
+		}
+		if quality == 0 {
+			if specificity == specificityExact {
+				excluded[formats[0]] = true
+			}
+			continue
+		}
+		ranges = append(ranges, acceptRange{formats: formats, quality: quality, specificity: specificity})
+	}
+
+	// sort.SliceStable conserva el orden del header entre rangos equivalentes
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].quality != ranges[j].quality {
+			return ranges[i].quality > ranges[j].quality
+		}
+		return ranges[i].specificity > ranges[j].specificity
+	})
+	for _, r := range ranges {
+		for _, format := range r.formats {
+			if !excluded[format] {
+				return format, nil
+			}
+		}
+	}
+	return "", domain.ErrUnsupportedFormat
+}
+
+// Especificidad de un rango del header Accept
+const (
+	specificityAny = iota
+	specificityType
+	specificityExact
+)
+
+// acceptRange es un rango del header Accept con los formatos que admite
+type acceptRange struct {
+	formats     []string
+	quality     float64
+	specificity int
+}
+
+// formatsForMediaType devuelve los formatos, en orden de preferencia, que
+// satisfacen un media type del header Accept
+func formatsForMediaType(mediaType string) ([]string, int) {
+	switch mediaType {
+	case ContentTypeCSV, "application/csv":
+		return []string{FormatCSV}, specificityExact
+	case ContentTypeNDJSON, "application/ndjson", "application/jsonl":
+		return []string{FormatNDJSON}, specificityExact
+	case ContentTypeXLSX:
+		return []string{FormatXLSX}, specificityExact
+	case "text/*":
+		return []string{FormatCSV}, specificityType
+	case "application/*":
+		return []string{FormatNDJSON, FormatXLSX}, specificityType
+	case "*/*":
+		return []string{FormatCSV, FormatNDJSON, FormatXLSX}, specificityAny
+	}
+	return nil, 0
+}
+
+// sanitizeCell neutraliza los valores que una hoja de cálculo interpretaría como
+// fórmula (empiezan por =, +, -, @, tabulador o retorno de carro) anteponiendo
+// un apóstrofo, para que un nombre como "=HYPERLINK(...)" se muestre como texto
+func sanitizeCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// ContentTypeFor devuelve el Content-Type correspondiente a un formato de exportación
+func ContentTypeFor(format string) string {
+	switch format {
+	case FormatNDJSON:
+		return ContentTypeNDJSON
+	case FormatXLSX:
+		return ContentTypeXLSX
+	default:
+		return ContentTypeCSV
+	}
+}
+
+// NewExportWriter crea el escritor adecuado para el formato indicado
+func NewExportWriter(format string, w io.Writer) (ports.EmployeeExportWriter, error) {
+	switch format {
+	case FormatCSV:
+		return &CSVExportWriter{writer: csv.NewWriter(w)}, nil
+	case FormatNDJSON:
+		return &NDJSONExportWriter{writer: bufio.NewWriter(w)}, nil
+	case FormatXLSX:
+		return NewXLSXExportWriter(w)
+	default:
+		return nil, domain.ErrUnsupportedFormat
+	}
+}
+
+// CSVExportWriter escribe la exportación en formato CSV
+type CSVExportWriter struct {
+	writer *csv.Writer
+}
+
+// WriteHeader escribe la fila de cabecera
+func (c *CSVExportWriter) WriteHeader(columns []string) error {
+	return c.writer.Write(columns)
+}
+
+// WriteRow escribe una fila de datos, neutralizando las celdas que se
+// interpretarían como fórmula
+func (c *CSVExportWriter) WriteRow(values []string) error {
+	return c.writer.Write(sanitizeRow(values))
+}
+
+// Close vuelca el buffer del CSV
+func (c *CSVExportWriter) Close() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+// Abort descarta las filas que aún no se volcaron
+func (c *CSVExportWriter) Abort() {}
+
+// NDJSONExportWriter escribe la exportación como un objeto JSON por línea,
+// respetando el orden de las columnas seleccionadas
+type NDJSONExportWriter struct {
+	writer  *bufio.Writer
+	columns []string
+}
+
+// WriteHeader guarda las columnas que se usarán como claves de cada objeto
+func (n *NDJSONExportWriter) WriteHeader(columns []string) error {
+	n.columns = columns
+	return nil
+}
+
+// WriteRow escribe un objeto JSON con los valores de la fila
+func (n *NDJSONExportWriter) WriteRow(values []string) error {
+	n.writer.WriteByte('{')
+	for i, column := range n.columns {
+		if i > 0 {
+			n.writer.WriteByte(',')
+		}
+		key, _ := json.Marshal(column)
+		value, _ := json.Marshal(values[i])
+		n.writer.Write(key)
+		n.writer.WriteByte(':')
+		n.writer.Write(value)
+	}
+	n.writer.WriteByte('}')
+	return n.writer.WriteByte('\n')
+}
+
+// Close vuelca el buffer pendiente
+func (n *NDJSONExportWriter) Close() error {
+	return n.writer.Flush()
+}
+
+// Abort descarta el buffer pendiente
+func (n *NDJSONExportWriter) Abort() {
+	n.writer.Reset(io.Discard)
+}
+
+// XLSXExportWriter escribe la exportación como hoja de cálculo usando el
+// StreamWriter de excelize, que vuelca las filas a disco en lugar de mantenerlas en memoria
+type XLSXExportWriter struct {
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	out    io.Writer
+	row    int
+}
+
+// NewXLSXExportWriter crea un escritor XLSX sobre la hoja por defecto
+func NewXLSXExportWriter(w io.Writer) (*XLSXExportWriter, error) {
+	file := excelize.NewFile()
+	stream, err := file.NewStreamWriter("Sheet1")
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &XLSXExportWriter{file: file, stream: stream, out: w}, nil
+}
+
+// WriteHeader escribe la fila de cabecera
+func (x *XLSXExportWriter) WriteHeader(columns []string) error {
+	return x.writeRow(columns)
+}
+
+// WriteRow escribe una fila de datos, neutralizando las celdas que se
+// interpretarían como fórmula
+func (x *XLSXExportWriter) WriteRow(values []string) error {
+	return x.writeRow(sanitizeRow(values))
+}
+
+func (x *XLSXExportWriter) writeRow(values []string) error {
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+
+	row := make([]interface{}, len(values))
+	for i, value := range values {
+		row[i] = value
+	}
+	return x.stream.SetRow(cell, row)
+}
+
+// Close finaliza la hoja y escribe el archivo completo en el destino
+func (x *XLSXExportWriter) Close() error {
+	defer x.file.Close()
+	if err := x.stream.Flush(); err != nil {
+		return err
+	}
+	_, err := x.file.WriteTo(x.out)
+	return err
+}
+
+// Abort descarta la hoja sin escribir nada en el destino
+func (x *XLSXExportWriter) Abort() {
+	x.file.Close()
+}
+
+// sanitizeRow devuelve una copia de la fila con sus celdas neutralizadas
+func sanitizeRow(values []string) []string {
+	sanitized := make([]string, len(values))
+	for i, value := range values {
+		sanitized[i] = sanitizeCell(value)
+	}
+	return sanitized
+}
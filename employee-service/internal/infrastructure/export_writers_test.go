@@ -0,0 +1,154 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"employee-service/internal/application"
+	"employee-service/internal/domain"
+	"employee-service/internal/ports"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFormatFromAccept(t *testing.T) {
+	tests := []struct {
+		name    string
+		accept  string
+		want    string
+		wantErr bool
+	}{
+		{name: "sin header", accept: "", want: FormatCSV},
+		{name: "cualquiera", accept: "*/*", want: FormatCSV},
+		{name: "tipo exacto", accept: "application/x-ndjson", want: FormatNDJSON},
+		{name: "gana la mayor q", accept: "text/csv;q=0.5, " + ContentTypeXLSX + ";q=0.9", want: FormatXLSX},
+		{name: "a igual q gana el más específico", accept: "*/*, application/x-ndjson", want: FormatNDJSON},
+		{name: "a igual q y especificidad gana el orden", accept: "application/x-ndjson, text/csv", want: FormatNDJSON},
+		{name: "comodín con menor q", accept: "*/*;q=0.1, " + ContentTypeXLSX, want: FormatXLSX},
+		{name: "q=0 excluye el tipo", accept: "text/csv;q=0, application/x-ndjson;q=0.5", want: FormatNDJSON},
+		{name: "q=0 excluye el tipo del comodín", accept: "text/csv;q=0, */*", want: FormatNDJSON},
+		{name: "q inválida se ignora", accept: "application/x-ndjson;q=abc, text/csv;q=0.2", want: FormatCSV},
+		{name: "solo tipos no soportados", accept: "application/json", wantErr: true},
+		{name: "todos rechazados", accept: "text/csv;q=0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatFromAccept(tt.accept)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FormatFromAccept(%q) error = %v, wantErr %t", tt.accept, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("FormatFromAccept(%q) = %q, want %q", tt.accept, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVExportWriterNeutralizesFormulas(t *testing.T) {
+	var out bytes.Buffer
+	writer, _ := NewExportWriter(FormatCSV, &out)
+	writer.WriteHeader([]string{"id", "name"})
+	writer.WriteRow([]string{"emp-1", `=HYPERLINK("http://evil","x")`})
+	writer.WriteRow([]string{"emp-2", "+1"})
+	writer.WriteRow([]string{"emp-3", "-2"})
+	writer.WriteRow([]string{"emp-4", "@SUM(A1)"})
+	writer.WriteRow([]string{"emp-5", "Ana = Ana"})
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+
+	want := "id,name\n" +
+		`emp-1,"'=HYPERLINK(""http://evil"",""x"")"` + "\n" +
+		"emp-2,'+1\n" +
+		"emp-3,'-2\n" +
+		"emp-4,'@SUM(A1)\n" +
+		"emp-5,Ana = Ana\n"
+	if out.String() != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestXLSXExportWriterAbortWritesNothing(t *testing.T) {
+	var out bytes.Buffer
+	writer, err := NewExportWriter(FormatXLSX, &out)
+	if err != nil {
+		t.Fatalf("NewExportWriter() = %v", err)
+	}
+	writer.WriteHeader([]string{"id"})
+	writer.WriteRow([]string{"emp-1"})
+	writer.Abort()
+	if out.Len() != 0 {
+		t.Errorf("Abort() wrote %d bytes", out.Len())
+	}
+}
+
+// pagedRepository sirve las páginas indicadas y falla al pedir la página failAt
+type pagedRepository struct {
+	ports.EmployeeRepository
+	pages  [][]*domain.Employee
+	failAt int
+}
+
+func (r *pagedRepository) FindPage(ctx context.Context, cursor string, limit int) (*domain.EmployeePage, error) {
+	index := 0
+	fmt.Sscan(cursor, &index)
+	if index == r.failAt {
+		return nil, errors.New("dynamodb unavailable")
+	}
+	page := &domain.EmployeePage{Employees: r.pages[index]}
+	if index+1 < len(r.pages) {
+		page.NextCursor = fmt.Sprint(index + 1)
+	}
+	return page, nil
+}
+
+func employeesPage(from, n int) []*domain.Employee {
+	employees := make([]*domain.Employee, n)
+	for i := range employees {
+		id := fmt.Sprintf("emp-%04d", from+i)
+		employees[i] = &domain.Employee{ID: id, Name: strings.Repeat("x", 40), Email: id + "@example.com"}
+	}
+	return employees
+}
+
+func TestExportEmployeesReportsTheResult(t *testing.T) {
+	// Dos páginas grandes: la primera supera el buffer del escritor y llega al cliente
+	pages := [][]*domain.Employee{employeesPage(0, 100), employeesPage(100, 100)}
+
+	tests := []struct {
+		name        string
+		failAt      int
+		wantCode    int
+		wantTrailer string
+		wantRows    string
+	}{
+		{name: "exportación completa", failAt: -1, wantCode: http.StatusOK, wantTrailer: "complete", wantRows: "200"},
+		{name: "falla antes de enviar datos", failAt: 0, wantCode: http.StatusInternalServerError},
+		{name: "falla a mitad del streaming", failAt: 1, wantCode: http.StatusOK, wantTrailer: "error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := application.NewEmployeeService(&pagedRepository{pages: pages, failAt: tt.failAt}, nil, nil, nil)
+			handler := NewHTTPHandler(service, nil, nil)
+
+			request := httptest.NewRequest(http.MethodGet, "/employees/export?columns=id,name,email", nil)
+			request.Header.Set("Accept", "text/csv")
+			recorder := httptest.NewRecorder()
+			handler.ExportEmployees(recorder, request)
+
+			response := recorder.Result()
+			if response.StatusCode != tt.wantCode {
+				t.Fatalf("status = %d, want %d", response.StatusCode, tt.wantCode)
+			}
+			if got := response.Trailer.Get(exportStatusTrailer); got != tt.wantTrailer {
+				t.Errorf("%s = %q, want %q", exportStatusTrailer, got, tt.wantTrailer)
+			}
+			if got := response.Trailer.Get(exportRowsTrailer); got != tt.wantRows {
+				t.Errorf("%s = %q, want %q", exportRowsTrailer, got, tt.wantRows)
+			}
+		})
+	}
+}
@@ -5,28 +5,56 @@ import (
 	"employee-service/internal/application"
 	"employee-service/internal/domain"
 	"encoding/json"
+	"io"
 	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 )
 
 // HTTPHandler maneja las peticiones HTTP
 type HTTPHandler struct {
-	service *application.EmployeeService
+	service           *application.EmployeeService
+	departmentService *application.DepartmentService
+	idempotency       *IdempotencyMiddleware
 }
 
 // NewHTTPHandler crea un nuevo manejador HTTP
-func NewHTTPHandler(service *application.EmployeeService) *HTTPHandler {
+func NewHTTPHandler(service *application.EmployeeService, departmentService *application.DepartmentService, idempotency *IdempotencyMiddleware) *HTTPHandler {
 	return &HTTPHandler{
-		service: service,
+		service:           service,
+		departmentService: departmentService,
+		idempotency:       idempotency,
 	}
 }
 
 type CreateEmployeeRequest struct {
-	Name     string `json:"name"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	Password     string `json:"password"`
+	DepartmentID string `json:"department_id"`
+	ManagerID    string `json:"manager_id"`
+}
+
+type AssignManagerRequest struct {
+	ManagerID string `json:"manager_id"`
+}
+
+type AssignDepartmentRequest struct {
+	DepartmentID string `json:"department_id"`
+}
+
+type ChangeStatusRequest struct {
+	Status        string `json:"status"`
+	ReasonCode    string `json:"reason_code"`
+	EffectiveDate string `json:"effective_date"` // RFC3339 o YYYY-MM-DD; vacío = ahora
+}
+
+type CreateDepartmentRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
 }
 
 // CreateEmployee maneja la creación de un empleado
@@ -37,15 +65,16 @@ func (h *HTTPHandler) CreateEmployee(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	employee, err := h.service.CreateEmployee(context.Background(), req.Name, req.Email, req.Password)
+	employee, err := h.service.CreateEmployee(context.Background(), application.CreateEmployeeInput{
+		Name:         req.Name,
+		Email:        req.Email,
+		Password:     req.Password,
+		DepartmentID: req.DepartmentID,
+		ManagerID:    req.ManagerID,
+	})
 	if err != nil {
 		log.Printf("Error creating employee: %v", err)
-		// Diferenciar errores de validación del dominio
-		if err == domain.ErrInvalidPassword || err == domain.ErrInvalidName || err == domain.ErrInvalidEmail {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, err)
 		return
 	}
 
@@ -64,20 +93,373 @@ func (h *HTTPHandler) GetEmployees(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Convertir a versión pública sin passwords
-	publicEmployees := make([]*domain.EmployeePublic, len(employees))
-	for i, emp := range employees {
-		publicEmployees[i] = emp.ToPublic()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toPublicEmployees(employees))
+}
+
+// ImportEmployees importa empleados en bloque desde CSV o NDJSON.
+// El formato se deduce del Content-Type (o del parámetro ?format=csv|ndjson)
+// y ?dry_run=true valida las filas sin escribir nada.
+func (h *HTTPHandler) ImportEmployees(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = FormatFromContentType(r.Header.Get("Content-Type"))
+	}
+
+	reader, err := NewRecordReader(format, r.Body)
+	if err != nil {
+		if err == domain.ErrUnsupportedFormat {
+			http.Error(w, "Unsupported format: use text/csv or application/x-ndjson", http.StatusUnsupportedMediaType)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	report, err := h.service.ImportEmployees(r.Context(), reader, dryRun)
+	if err != nil {
+		log.Printf("Error importing employees: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(publicEmployees)
+	json.NewEncoder(w).Encode(report)
+}
+
+// ExportEmployees exporta el directorio en streaming en CSV, NDJSON o XLSX según el header Accept.
+// Admite selección de columnas (?columns=id,name,email) y filtros por department_id,
+// manager_id, status, created_after y created_before.
+func (h *HTTPHandler) ExportEmployees(w http.ResponseWriter, r *http.Request) {
+	format, err := FormatFromAccept(r.Header.Get("Accept"))
+	if err != nil {
+		http.Error(w, "Not acceptable: use text/csv, application/x-ndjson or "+ContentTypeXLSX, http.StatusNotAcceptable)
+		return
+	}
+
+	query := r.URL.Query()
+	columns, err := domain.ParseExportColumns(query.Get("columns"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter := domain.EmployeeFilter{
+		DepartmentID: query.Get("department_id"),
+		ManagerID:    query.Get("manager_id"),
+		Status:       domain.EmploymentStatus(query.Get("status")),
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		http.Error(w, domain.ErrInvalidStatus.Error(), http.StatusBadRequest)
+		return
+	}
+	if filter.CreatedAfter, err = parseEffectiveDate(query.Get("created_after")); err != nil {
+		http.Error(w, "Invalid created_after: use RFC3339 or YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+	if filter.CreatedBefore, err = parseEffectiveDate(query.Get("created_before")); err != nil {
+		http.Error(w, "Invalid created_before: use RFC3339 or YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+
+	body := &countingWriter{writer: w}
+	writer, err := NewExportWriter(format, body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentTypeFor(format))
+	w.Header().Set("Content-Disposition", `attachment; filename="employees.`+format+`"`)
+	// El resultado se informa en trailers porque, una vez iniciado el streaming,
+	// ya no se puede cambiar el código de estado
+	w.Header().Set("Trailer", exportStatusTrailer+", "+exportRowsTrailer)
+
+	exported, err := h.service.ExportEmployees(r.Context(), filter, columns, writer)
+	if err != nil {
+		log.Printf("Error exporting employees: %v", err)
+		if body.written == 0 {
+			// Nada llegó al cliente (el XLSX se arma completo antes de escribirse y
+			// CSV/NDJSON usan buffer): todavía se puede responder con un error
+			w.Header().Del("Content-Disposition")
+			w.Header().Del("Trailer")
+			http.Error(w, "Export failed", http.StatusInternalServerError)
+			return
+		}
+		// El cuerpo quedó truncado: el trailer avisa que el archivo está incompleto
+		w.Header().Set(exportStatusTrailer, "error")
+		return
+	}
+	w.Header().Set(exportStatusTrailer, "complete")
+	w.Header().Set(exportRowsTrailer, strconv.Itoa(exported))
+}
+
+// Trailers con el resultado de una exportación
+const (
+	exportStatusTrailer = "X-Export-Status"
+	exportRowsTrailer   = "X-Export-Rows"
+)
+
+// countingWriter cuenta los bytes escritos en la respuesta para saber si el
+// cliente ya recibió parte del cuerpo
+type countingWriter struct {
+	writer  io.Writer
+	written int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.writer.Write(p)
+	c.written += int64(n)
+	return n, err
+}
+
+// GetEmployee obtiene un empleado por su ID
+func (h *HTTPHandler) GetEmployee(w http.ResponseWriter, r *http.Request) {
+	employee, err := h.service.GetEmployeeByID(context.Background(), mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Error getting employee: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(employee.ToPublic())
+}
+
+// AssignManager asigna el jefe directo de un empleado
+func (h *HTTPHandler) AssignManager(w http.ResponseWriter, r *http.Request) {
+	var req AssignManagerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	employee, err := h.service.AssignManager(context.Background(), mux.Vars(r)["id"], req.ManagerID)
+	if err != nil {
+		log.Printf("Error assigning manager: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(employee.ToPublic())
+}
+
+// AssignDepartment asigna el departamento de un empleado
+func (h *HTTPHandler) AssignDepartment(w http.ResponseWriter, r *http.Request) {
+	var req AssignDepartmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	employee, err := h.service.AssignDepartment(context.Background(), mux.Vars(r)["id"], req.DepartmentID)
+	if err != nil {
+		log.Printf("Error assigning department: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(employee.ToPublic())
+}
+
+// GetDirectReports obtiene los reportes directos de un empleado
+func (h *HTTPHandler) GetDirectReports(w http.ResponseWriter, r *http.Request) {
+	reports, err := h.service.GetDirectReports(context.Background(), mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Error getting direct reports: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toPublicEmployees(reports))
+}
+
+// GetManagementChain obtiene la cadena de mando de un empleado
+func (h *HTTPHandler) GetManagementChain(w http.ResponseWriter, r *http.Request) {
+	chain, err := h.service.GetManagementChain(context.Background(), mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Error getting management chain: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(toPublicEmployees(chain))
+}
+
+// ChangeStatus aplica una transición del ciclo de vida laboral de un empleado
+func (h *HTTPHandler) ChangeStatus(w http.ResponseWriter, r *http.Request) {
+	var req ChangeStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	effectiveDate, err := parseEffectiveDate(req.EffectiveDate)
+	if err != nil {
+		http.Error(w, "Invalid effective_date: use RFC3339 or YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+
+	employee, err := h.service.ChangeStatus(
+		context.Background(),
+		mux.Vars(r)["id"],
+		domain.EmploymentStatus(req.Status),
+		domain.ReasonCode(req.ReasonCode),
+		effectiveDate,
+	)
+	if err != nil {
+		log.Printf("Error changing employee status: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(employee.ToPublic())
+}
+
+// GetStatusHistory obtiene el historial de estados de un empleado
+func (h *HTTPHandler) GetStatusHistory(w http.ResponseWriter, r *http.Request) {
+	history, err := h.service.GetStatusHistory(context.Background(), mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Error getting status history: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// CreateDepartment maneja la creación de un departamento
+func (h *HTTPHandler) CreateDepartment(w http.ResponseWriter, r *http.Request) {
+	var req CreateDepartmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	department, err := h.departmentService.CreateDepartment(context.Background(), req.Name, req.Description)
+	if err != nil {
+		log.Printf("Error creating department: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(department)
+}
+
+// GetDepartments obtiene todos los departamentos
+func (h *HTTPHandler) GetDepartments(w http.ResponseWriter, r *http.Request) {
+	departments, err := h.departmentService.GetAllDepartments(context.Background())
+	if err != nil {
+		log.Printf("Error getting departments: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if departments == nil {
+		departments = []*domain.Department{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(departments)
+}
+
+// GetDepartment obtiene un departamento por su ID
+func (h *HTTPHandler) GetDepartment(w http.ResponseWriter, r *http.Request) {
+	department, err := h.departmentService.GetDepartmentByID(context.Background(), mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Error getting department: %v", err)
+		if err == domain.ErrDepartmentNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(department)
+}
+
+// GetOrgChart obtiene el organigrama de un departamento
+func (h *HTTPHandler) GetOrgChart(w http.ResponseWriter, r *http.Request) {
+	chart, err := h.departmentService.GetOrgChart(context.Background(), mux.Vars(r)["id"])
+	if err != nil {
+		log.Printf("Error building org chart: %v", err)
+		if err == domain.ErrDepartmentNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(chart)
 }
 
 // SetupRoutes configura las rutas del servidor
 func (h *HTTPHandler) SetupRoutes() *mux.Router {
 	router := mux.NewRouter()
-	router.HandleFunc("/employees", h.CreateEmployee).Methods("POST")
+	router.HandleFunc("/employees", h.idempotency.Wrap(h.CreateEmployee)).Methods("POST")
 	router.HandleFunc("/employees", h.GetEmployees).Methods("GET")
+	router.HandleFunc("/employees/import", h.ImportEmployees).Methods("POST")
+	router.HandleFunc("/employees/export", h.ExportEmployees).Methods("GET")
+	router.HandleFunc("/employees/{id}", h.GetEmployee).Methods("GET")
+	router.HandleFunc("/employees/{id}/manager", h.AssignManager).Methods("PUT")
+	router.HandleFunc("/employees/{id}/department", h.AssignDepartment).Methods("PUT")
+	router.HandleFunc("/employees/{id}/reports", h.GetDirectReports).Methods("GET")
+	router.HandleFunc("/employees/{id}/management-chain", h.GetManagementChain).Methods("GET")
+	router.HandleFunc("/employees/{id}/status", h.ChangeStatus).Methods("POST")
+	router.HandleFunc("/employees/{id}/status-history", h.GetStatusHistory).Methods("GET")
+	router.HandleFunc("/departments", h.CreateDepartment).Methods("POST")
+	router.HandleFunc("/departments", h.GetDepartments).Methods("GET")
+	router.HandleFunc("/departments/{id}", h.GetDepartment).Methods("GET")
+	router.HandleFunc("/departments/{id}/org-chart", h.GetOrgChart).Methods("GET")
 	return router
 }
+
+// toPublicEmployees convierte una lista de empleados a su versión pública (sin passwords)
+func toPublicEmployees(employees []*domain.Employee) []*domain.EmployeePublic {
+	publicEmployees := make([]*domain.EmployeePublic, len(employees))
+	for i, emp := range employees {
+		publicEmployees[i] = emp.ToPublic()
+	}
+	return publicEmployees
+}
+
+// parseEffectiveDate interpreta una fecha efectiva en formato RFC3339 o YYYY-MM-DD
+func parseEffectiveDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// writeError traduce los errores del dominio a códigos de estado HTTP
+func writeError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidPassword, domain.ErrInvalidName, domain.ErrInvalidEmail,
+		domain.ErrInvalidDepartmentName, domain.ErrDepartmentNotFound, domain.ErrManagerNotFound,
+		domain.ErrSelfManagement, domain.ErrInvalidStatus, domain.ErrInvalidReasonCode,
+		domain.ErrInvalidEffectiveDate:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case domain.ErrNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case domain.ErrManagerCycle, domain.ErrHierarchyChanged, domain.ErrInvalidStatusTransition:
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,152 @@
+package infrastructure
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"employee-service/internal/domain"
+	"employee-service/internal/ports"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+const (
+	// IdempotencyKeyHeader es el header con el que el cliente identifica una petición reintentable
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader indica que la respuesta es una reproducción de la original
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength   = 255
+	maxIdempotentRequestBytes = 1 << 20 // 1 MB
+
+	// idempotencyProcessingLease es lo que dura la reserva de una petición en curso;
+	// si el proceso cae antes de completarla, la clave se libera sola al vencer
+	idempotencyProcessingLease = 1 * time.Minute
+)
+
+// IdempotencyMiddleware hace idempotentes las peticiones que incluyen el header
+// Idempotency-Key: la primera respuesta se almacena durante la ventana configurada y
+// se reproduce en los reintentos; reutilizar la clave con otro cuerpo se rechaza
+type IdempotencyMiddleware struct {
+	store  ports.IdempotencyStore
+	window time.Duration
+}
+
+// NewIdempotencyMiddleware crea un nuevo middleware de idempotencia
+func NewIdempotencyMiddleware(store ports.IdempotencyStore, window time.Duration) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{
+		store:  store,
+		window: window,
+	}
+}
+
+// Wrap aplica el control de idempotencia a un handler
+func (m *IdempotencyMiddleware) Wrap(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			http.Error(w, "Idempotency-Key is too long", http.StatusBadRequest)
+			return
+		}
+
+		// Se lee un byte más del límite para distinguir un cuerpo demasiado grande
+		// de uno que lo alcanza justo: la huella debe cubrir el cuerpo completo
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentRequestBytes+1))
+		if err != nil {
+			http.Error(w, "Error reading request body", http.StatusBadRequest)
+			return
+		}
+		if len(body) > maxIdempotentRequestBytes {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		// La clave se acota a la operación para que no colisione entre endpoints
+		scopedKey := r.Method + " " + r.URL.Path + " " + key
+		record := domain.NewIdempotencyRecord(scopedKey, fingerprint(body), idempotencyProcessingLease)
+
+		existing, reserved, err := m.store.Reserve(r.Context(), record)
+		if err == domain.ErrIdempotencyRequestInProgress {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Error checking idempotency key", http.StatusInternalServerError)
+			return
+		}
+
+		if !reserved {
+			m.replay(w, existing, record.Fingerprint)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(recorder, r)
+
+		// Los errores del servidor no se memorizan: el cliente debe poder reintentar
+		if recorder.status >= http.StatusInternalServerError {
+			m.store.Release(r.Context(), scopedKey)
+			return
+		}
+
+		if err := m.store.Complete(r.Context(), scopedKey, recorder.status, recorder.Header().Get("Content-Type"), recorder.body.String(), time.Now().Add(m.window)); err != nil {
+			log.Printf("Error storing idempotent response for key %s: %v", key, err)
+		}
+	}
+}
+
+// replay responde a un reintento a partir del registro almacenado
+func (m *IdempotencyMiddleware) replay(w http.ResponseWriter, existing *domain.IdempotencyRecord, requestFingerprint string) {
+	if existing.Fingerprint != requestFingerprint {
+		http.Error(w, domain.ErrIdempotencyKeyReused.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	if existing.Status != domain.IdempotencyCompleted {
+		http.Error(w, domain.ErrIdempotencyRequestInProgress.Error(), http.StatusConflict)
+		return
+	}
+
+	if existing.ResponseContentType != "" {
+		w.Header().Set("Content-Type", existing.ResponseContentType)
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(existing.ResponseStatus)
+	io.WriteString(w, existing.ResponseBody)
+}
+
+// fingerprint calcula la huella SHA-256 del cuerpo de la petición
+func fingerprint(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// responseRecorder captura el código y el cuerpo de la respuesta mientras los escribe al cliente
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+// WriteHeader captura el código de estado
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write captura el cuerpo de la respuesta
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
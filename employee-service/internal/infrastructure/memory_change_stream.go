@@ -0,0 +1,100 @@
+package infrastructure
+
+import (
+	"context"
+	"employee-service/internal/domain"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// localShardID es el único shard del stream en memoria
+const localShardID = "shard-local-0001"
+
+// InMemoryChangeStream es un stream de cambios en memoria con un único shard.
+// Sustituye a DynamoDB Streams en pruebas y desarrollo local.
+type InMemoryChangeStream struct {
+	mu       sync.Mutex
+	records  []*domain.ChangeRecord
+	sequence int64
+	closed   bool
+}
+
+// NewInMemoryChangeStream crea un stream en memoria vacío
+func NewInMemoryChangeStream() *InMemoryChangeStream {
+	return &InMemoryChangeStream{}
+}
+
+// Append agrega un cambio al stream asignándole el siguiente número de secuencia
+func (s *InMemoryChangeStream) Append(operation domain.ChangeOperation, oldImage, newImage *domain.Employee) *domain.ChangeRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sequence++
+	record := &domain.ChangeRecord{
+		SequenceNumber: fmt.Sprintf("%020d", s.sequence),
+		Operation:      operation,
+		OldImage:       oldImage,
+		NewImage:       newImage,
+		CreatedAt:      time.Now().UTC(),
+	}
+	s.records = append(s.records, record)
+	return record
+}
+
+// Close cierra el shard: una vez leídos los registros restantes Read devuelve closed=true
+func (s *InMemoryChangeStream) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+}
+
+// Shards devuelve el único shard del stream
+func (s *InMemoryChangeStream) Shards(ctx context.Context) ([]domain.StreamShard, error) {
+	return []domain.StreamShard{{ID: localShardID}}, nil
+}
+
+// Read devuelve los registros posteriores a afterSequence
+func (s *InMemoryChangeStream) Read(ctx context.Context, shardID, afterSequence string) ([]*domain.ChangeRecord, bool, error) {
+	if shardID != localShardID {
+		return nil, false, fmt.Errorf("unknown shard %s", shardID)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Los números de secuencia tienen ancho fijo, por lo que se comparan como texto
+	var records []*domain.ChangeRecord
+	for _, record := range s.records {
+		if record.SequenceNumber > afterSequence {
+			records = append(records, record)
+		}
+	}
+	return records, s.closed, nil
+}
+
+// InMemoryCheckpointStore guarda checkpoints en memoria
+type InMemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]string
+}
+
+// NewInMemoryCheckpointStore crea un almacén de checkpoints vacío
+func NewInMemoryCheckpointStore() *InMemoryCheckpointStore {
+	return &InMemoryCheckpointStore{checkpoints: make(map[string]string)}
+}
+
+// Load obtiene el checkpoint del shard
+func (s *InMemoryCheckpointStore) Load(ctx context.Context, shardID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.checkpoints[shardID], nil
+}
+
+// Save guarda el checkpoint del shard
+func (s *InMemoryCheckpointStore) Save(ctx context.Context, shardID, sequenceNumber string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoints[shardID] = sequenceNumber
+	return nil
+}
@@ -0,0 +1,75 @@
+package infrastructure
+
+import (
+	"context"
+	"employee-service/internal/domain"
+	"sync"
+)
+
+// memorySubscriber guarda los eventos pendientes de una suscripción
+type memorySubscriber struct {
+	subscription domain.Subscription
+	events       []*domain.EmployeeEvent
+}
+
+// InMemoryEventBus implementa el bus de eventos en memoria.
+// Sustituye a SNS/SQS en pruebas y desarrollo local.
+type InMemoryEventBus struct {
+	mu          sync.Mutex
+	subscribers map[string]*memorySubscriber
+}
+
+// NewInMemoryEventBus crea un bus en memoria sin suscripciones
+func NewInMemoryEventBus() *InMemoryEventBus {
+	return &InMemoryEventBus{subscribers: make(map[string]*memorySubscriber)}
+}
+
+// Publish entrega el evento a todas las suscripciones que aceptan su tipo
+func (b *InMemoryEventBus) Publish(ctx context.Context, event *domain.EmployeeEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, subscriber := range b.subscribers {
+		if subscriber.subscription.Accepts(event.EventType) {
+			subscriber.events = append(subscriber.events, event)
+		}
+	}
+	return nil
+}
+
+// PublishBatch entrega los eventos en orden como Publish
+func (b *InMemoryEventBus) PublishBatch(ctx context.Context, events []*domain.EmployeeEvent) []error {
+	results := make([]error, len(events))
+	for i, event := range events {
+		results[i] = b.Publish(ctx, event)
+	}
+	return results
+}
+
+// Subscribe registra (o reemplaza) una suscripción por nombre
+func (b *InMemoryEventBus) Subscribe(ctx context.Context, subscription domain.Subscription) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if existing, ok := b.subscribers[subscription.Name]; ok {
+		existing.subscription = subscription
+		return nil
+	}
+	b.subscribers[subscription.Name] = &memorySubscriber{subscription: subscription}
+	return nil
+}
+
+// Receive devuelve y vacía los eventos pendientes de una suscripción
+func (b *InMemoryEventBus) Receive(name string) []*domain.EmployeeEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subscriber, ok := b.subscribers[name]
+	if !ok {
+		return nil
+	}
+
+	events := subscriber.events
+	subscriber.events = nil
+	return events
+}
@@ -0,0 +1,89 @@
+package infrastructure
+
+import (
+	"context"
+	"employee-service/internal/domain"
+	"employee-service/internal/ports"
+	"reflect"
+	"testing"
+)
+
+func eventIDs(events []*domain.EmployeeEvent) []string {
+	var ids []string
+	for _, event := range events {
+		ids = append(ids, event.EventID)
+	}
+	return ids
+}
+
+func TestInMemoryEventBusFanOut(t *testing.T) {
+	ctx := context.Background()
+	var bus ports.EventBus = NewInMemoryEventBus()
+
+	// Las mismas suscripciones que declara el bootstrap con EVENT_SUBSCRIPTIONS
+	subscriptions, err := domain.ParseSubscriptions("messaging=arn:messaging|employee.created;logger=arn:logger|employee.*,message.*;audit=arn:audit")
+	if err != nil {
+		t.Fatalf("ParseSubscriptions() = %v", err)
+	}
+	for _, subscription := range subscriptions {
+		if err := bus.Subscribe(ctx, subscription); err != nil {
+			t.Fatalf("Subscribe(%s) = %v", subscription.Name, err)
+		}
+	}
+
+	published := []*domain.EmployeeEvent{
+		{EventID: "created", EventType: "employee.created"},
+		{EventID: "updated", EventType: "employee.updated"},
+		{EventID: "sent", EventType: "message.sent"},
+		{EventID: "login", EventType: "auth.login"},
+	}
+	if err := bus.Publish(ctx, published[0]); err != nil {
+		t.Fatalf("Publish() = %v", err)
+	}
+	for i, err := range bus.PublishBatch(ctx, published[1:]) {
+		if err != nil {
+			t.Fatalf("PublishBatch()[%d] = %v", i, err)
+		}
+	}
+
+	memory := bus.(*InMemoryEventBus)
+	want := map[string][]string{
+		"messaging": {"created"},
+		"logger":    {"created", "updated", "sent"},
+		"audit":     {"created", "updated", "sent", "login"},
+	}
+	for name, wantIDs := range want {
+		if got := eventIDs(memory.Receive(name)); !reflect.DeepEqual(got, wantIDs) {
+			t.Errorf("subscription %s received %v, want %v", name, got, wantIDs)
+		}
+		if got := memory.Receive(name); len(got) != 0 {
+			t.Errorf("subscription %s kept %d events after Receive", name, len(got))
+		}
+	}
+}
+
+func TestInMemoryEventBusSubscribeReplacesFilter(t *testing.T) {
+	ctx := context.Background()
+	bus := NewInMemoryEventBus()
+
+	subscription := domain.Subscription{Name: "messaging", Endpoint: "arn:messaging", EventTypes: []string{"employee.created"}}
+	if err := bus.Subscribe(ctx, subscription); err != nil {
+		t.Fatalf("Subscribe() = %v", err)
+	}
+	bus.Publish(ctx, &domain.EmployeeEvent{EventID: "created", EventType: "employee.created"})
+
+	// Volver a declararla con otro filtro conserva los eventos pendientes y aplica el nuevo filtro
+	subscription.EventTypes = []string{"employee.status_changed"}
+	if err := bus.Subscribe(ctx, subscription); err != nil {
+		t.Fatalf("Subscribe() = %v", err)
+	}
+	bus.Publish(ctx, &domain.EmployeeEvent{EventID: "another", EventType: "employee.created"})
+	bus.Publish(ctx, &domain.EmployeeEvent{EventID: "terminated", EventType: "employee.status_changed"})
+
+	if got, want := eventIDs(bus.Receive("messaging")), []string{"created", "terminated"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("received %v, want %v", got, want)
+	}
+	if got := bus.Receive("unknown"); got != nil {
+		t.Errorf("unknown subscription received %v", got)
+	}
+}
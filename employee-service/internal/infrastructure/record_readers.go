@@ -0,0 +1,167 @@
+package infrastructure
+
+import (
+	"bufio"
+	"employee-service/internal/domain"
+	"employee-service/internal/ports"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Formatos soportados para la importación masiva
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// maxNDJSONLineSize es el tamaño máximo de una línea NDJSON (1 MB)
+const maxNDJSONLineSize = 1024 * 1024
+
+// NewRecordReader crea el lector adecuado para el formato indicado
+func NewRecordReader(format string, r io.Reader) (ports.EmployeeRecordReader, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVRecordReader(r)
+	case FormatNDJSON:
+		return NewNDJSONRecordReader(r), nil
+	default:
+		return nil, domain.ErrUnsupportedFormat
+	}
+}
+
+// FormatFromContentType deduce el formato de importación a partir del header Content-Type
+func FormatFromContentType(contentType string) string {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	switch mediaType {
+	case "text/csv", "application/csv":
+		return FormatCSV
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return FormatNDJSON
+	default:
+		return ""
+	}
+}
+
+// CSVRecordReader lee registros de empleados desde un CSV con cabecera.
+// Columnas reconocidas: name, email, password, department_id, manager_id
+type CSVRecordReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+	row     int
+}
+
+// NewCSVRecordReader crea un lector CSV y procesa la fila de cabecera
+func NewCSVRecordReader(r io.Reader) (*CSVRecordReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // Las filas con columnas de más o de menos se reportan por fila
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error reading CSV header: %w", err)
+	}
+
+	// Una columna repetida haría ambiguo qué valor se importa: se rechaza el archivo
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		column := strings.ToLower(strings.TrimSpace(name))
+		if first, ok := columns[column]; ok {
+			return nil, fmt.Errorf("CSV header has duplicate column %q (columns %d and %d)", column, first+1, i+1)
+		}
+		columns[column] = i
+	}
+
+	for _, required := range []string{"name", "email", "password"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV header is missing required column %q", required)
+		}
+	}
+
+	return &CSVRecordReader{
+		reader:  reader,
+		columns: columns,
+	}, nil
+}
+
+// Next devuelve el siguiente registro del CSV
+func (c *CSVRecordReader) Next() (*domain.ImportRecord, error) {
+	fields, err := c.reader.Read()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	c.row++
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return &domain.ImportRecord{Row: c.row, ParseError: err}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fields) != len(c.columns) {
+		return &domain.ImportRecord{
+			Row:        c.row,
+			ParseError: fmt.Errorf("expected %d fields, got %d", len(c.columns), len(fields)),
+		}, nil
+	}
+
+	return &domain.ImportRecord{
+		Row:          c.row,
+		Name:         c.field(fields, "name"),
+		Email:        c.field(fields, "email"),
+		Password:     c.field(fields, "password"),
+		DepartmentID: c.field(fields, "department_id"),
+		ManagerID:    c.field(fields, "manager_id"),
+	}, nil
+}
+
+// field obtiene el valor de una columna opcional ("" si no existe en la cabecera)
+func (c *CSVRecordReader) field(fields []string, column string) string {
+	i, ok := c.columns[column]
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(fields[i])
+}
+
+// NDJSONRecordReader lee registros de empleados desde JSON delimitado por saltos de línea
+type NDJSONRecordReader struct {
+	scanner *bufio.Scanner
+	row     int
+}
+
+// NewNDJSONRecordReader crea un lector NDJSON
+func NewNDJSONRecordReader(r io.Reader) *NDJSONRecordReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxNDJSONLineSize)
+	return &NDJSONRecordReader{scanner: scanner}
+}
+
+// Next devuelve el siguiente registro NDJSON, ignorando líneas vacías
+func (n *NDJSONRecordReader) Next() (*domain.ImportRecord, error) {
+	for n.scanner.Scan() {
+		line := strings.TrimSpace(n.scanner.Text())
+		if line == "" {
+			continue
+		}
+		n.row++
+
+		var record domain.ImportRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			return &domain.ImportRecord{Row: n.row, ParseError: err}, nil
+		}
+		record.Row = n.row
+		return &record, nil
+	}
+
+	if err := n.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
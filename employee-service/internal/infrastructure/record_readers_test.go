@@ -0,0 +1,34 @@
+package infrastructure
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewCSVRecordReaderHeader(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    string
+		wantError string
+	}{
+		{name: "columnas requeridas", header: "name,email,password"},
+		{name: "con opcionales y mayúsculas", header: "Name, Email ,password,department_id,manager_id"},
+		{name: "falta una requerida", header: "name,email", wantError: `missing required column "password"`},
+		{name: "columna repetida", header: "name,email,password,email", wantError: `duplicate column "email" (columns 2 and 4)`},
+		{name: "repetida con otro formato", header: "name,EMAIL,password, email", wantError: `duplicate column "email"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCSVRecordReader(strings.NewReader(tt.header + "\n"))
+			if tt.wantError == "" {
+				if err != nil {
+					t.Fatalf("NewCSVRecordReader() = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("NewCSVRecordReader() = %v, want error containing %q", err, tt.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package infrastructure
+
+import (
+	"context"
+	"employee-service/internal/domain"
+	"encoding/json"
+	"fmt"
+	"log"
+	"pkg/eventschema"
+	"pkg/sqsqueue"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+)
+
+// Límites de PublishBatch
+const (
+	maxPublishBatchEntries = 10
+	maxPublishBatchBytes   = 256 * 1024
+)
+
+// SNSEventBus implementa el bus de eventos con un topic SNS y colas SQS suscritas
+type SNSEventBus struct {
+	client   *sns.Client
+	topicARN string
+	registry *eventschema.Registry
+}
+
+// NewSNSEventBus crea una nueva instancia del bus
+func NewSNSEventBus(client *sns.Client, topicARN string, registry *eventschema.Registry) *SNSEventBus {
+	return &SNSEventBus{
+		client:   client,
+		topicARN: topicARN,
+		registry: registry,
+	}
+}
+
+// Publish publica el evento (envelope CloudEvents) en el topic con su tipo como atributo de mensaje
+func (b *SNSEventBus) Publish(ctx context.Context, event *domain.EmployeeEvent) error {
+	entry, err := b.batchEntry("0", event)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.client.Publish(ctx, &sns.PublishInput{
+		TopicArn:               aws.String(b.topicARN),
+		Message:                entry.Message,
+		MessageAttributes:      entry.MessageAttributes,
+		MessageGroupId:         entry.MessageGroupId,
+		MessageDeduplicationId: entry.MessageDeduplicationId,
+	})
+
+	if err != nil {
+		log.Printf("Error publishing event to SNS: %v", err)
+		return err
+	}
+
+	log.Printf("Event published successfully: %s", event.EventType)
+	return nil
+}
+
+// PublishBatch publica los eventos con llamadas PublishBatch de hasta 10 mensajes y
+// 256 KB, en el orden recibido; devuelve el resultado de cada evento en el mismo orden
+func (b *SNSEventBus) PublishBatch(ctx context.Context, batch []*domain.EmployeeEvent) []error {
+	results := make([]error, len(batch))
+
+	var entries []types.PublishBatchRequestEntry
+	var batchBytes int
+	for i, event := range batch {
+		// El Id de cada entrada es su posición en batch
+		entry, err := b.batchEntry(strconv.Itoa(i), event)
+		if err != nil {
+			results[i] = err
+			continue
+		}
+
+		size := len(aws.ToString(entry.Message))
+		if len(entries) == maxPublishBatchEntries || (len(entries) > 0 && batchBytes+size > maxPublishBatchBytes) {
+			b.sendBatch(ctx, entries, results)
+			entries, batchBytes = nil, 0
+		}
+		entries = append(entries, entry)
+		batchBytes += size
+	}
+	if len(entries) > 0 {
+		b.sendBatch(ctx, entries, results)
+	}
+	return results
+}
+
+// batchEntry valida el evento contra su esquema y construye el mensaje del topic
+func (b *SNSEventBus) batchEntry(id string, event *domain.EmployeeEvent) (types.PublishBatchRequestEntry, error) {
+	envelope, err := event.ToCloudEvent()
+	if err != nil {
+		return types.PublishBatchRequestEntry{}, err
+	}
+
+	// Validar contra el esquema registrado antes de enviar
+	messageBody, err := b.registry.Marshal(envelope)
+	if err != nil {
+		log.Printf("Refusing to publish invalid %s event: %v", event.EventType, err)
+		return types.PublishBatchRequestEntry{}, err
+	}
+
+	entry := types.PublishBatchRequestEntry{
+		Id:      aws.String(id),
+		Message: aws.String(string(messageBody)),
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			sqsqueue.EventTypeAttribute: {
+				DataType:    aws.String("String"),
+				StringValue: aws.String(event.EventType),
+			},
+		},
+	}
+	// Un topic FIFO ordena por empleado y propaga grupo y deduplicación a las colas FIFO suscritas
+	if sqsqueue.IsFIFO(b.topicARN) {
+		entry.MessageGroupId = aws.String(sqsqueue.MessageGroupID(envelope))
+		entry.MessageDeduplicationId = aws.String(envelope.ID)
+	}
+	return entry, nil
+}
+
+// sendBatch envía un lote con PublishBatch y registra en results el resultado de cada entrada
+func (b *SNSEventBus) sendBatch(ctx context.Context, entries []types.PublishBatchRequestEntry, results []error) {
+	output, err := b.client.PublishBatch(ctx, &sns.PublishBatchInput{
+		TopicArn:                   aws.String(b.topicARN),
+		PublishBatchRequestEntries: entries,
+	})
+	if err != nil {
+		log.Printf("Error publishing batch of %d events to SNS: %v", len(entries), err)
+		for _, entry := range entries {
+			index, _ := strconv.Atoi(aws.ToString(entry.Id))
+			results[index] = err
+		}
+		return
+	}
+
+	for _, failed := range output.Failed {
+		index, convErr := strconv.Atoi(aws.ToString(failed.Id))
+		if convErr != nil || index < 0 || index >= len(results) {
+			continue
+		}
+		results[index] = fmt.Errorf("publish event: %s: %s", aws.ToString(failed.Code), aws.ToString(failed.Message))
+		log.Printf("Error publishing event to SNS: %v", results[index])
+	}
+
+	log.Printf("Published batch of %d events to SNS (%d failed)", len(entries), len(output.Failed))
+}
+
+// Subscribe suscribe una cola SQS (Endpoint = ARN de la cola) al topic o, si la cola ya
+// estaba suscrita, actualiza su suscripción con la filter policy declarada. Se usa
+// entrega raw para que la cola reciba el mismo JSON que se publicó.
+func (b *SNSEventBus) Subscribe(ctx context.Context, subscription domain.Subscription) error {
+	// Una filter policy vacía ({}) entrega todos los eventos
+	policy := "{}"
+	if len(subscription.EventTypes) > 0 {
+		var err error
+		if policy, err = filterPolicy(subscription.EventTypes); err != nil {
+			return err
+		}
+	}
+	attributes := map[string]string{
+		"RawMessageDelivery": "true",
+		"FilterPolicyScope":  "MessageAttributes",
+		"FilterPolicy":       policy,
+	}
+
+	subscriptionARN, err := b.findSubscription(ctx, subscription.Endpoint)
+	if err != nil {
+		log.Printf("Error listing subscriptions of SNS topic: %v", err)
+		return err
+	}
+
+	if subscriptionARN == "" {
+		output, err := b.client.Subscribe(ctx, &sns.SubscribeInput{
+			TopicArn:              aws.String(b.topicARN),
+			Protocol:              aws.String("sqs"),
+			Endpoint:              aws.String(subscription.Endpoint),
+			Attributes:            attributes,
+			ReturnSubscriptionArn: true,
+		})
+		if err != nil {
+			log.Printf("Error subscribing %s to SNS topic: %v", subscription.Name, err)
+			return err
+		}
+
+		log.Printf("Subscription %s registered: %s", subscription.Name, aws.ToString(output.SubscriptionArn))
+		return nil
+	}
+
+	for _, name := range []string{"RawMessageDelivery", "FilterPolicyScope", "FilterPolicy"} {
+		_, err := b.client.SetSubscriptionAttributes(ctx, &sns.SetSubscriptionAttributesInput{
+			SubscriptionArn: aws.String(subscriptionARN),
+			AttributeName:   aws.String(name),
+			AttributeValue:  aws.String(attributes[name]),
+		})
+		if err != nil {
+			log.Printf("Error updating %s of subscription %s: %v", name, subscription.Name, err)
+			return err
+		}
+	}
+
+	log.Printf("Subscription %s updated: %s", subscription.Name, subscriptionARN)
+	return nil
+}
+
+// findSubscription devuelve el ARN de la suscripción SQS del endpoint al topic (vacío si no existe)
+func (b *SNSEventBus) findSubscription(ctx context.Context, endpoint string) (string, error) {
+	paginator := sns.NewListSubscriptionsByTopicPaginator(b.client, &sns.ListSubscriptionsByTopicInput{
+		TopicArn: aws.String(b.topicARN),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return "", err
+		}
+		for _, subscription := range page.Subscriptions {
+			if aws.ToString(subscription.Protocol) == "sqs" && aws.ToString(subscription.Endpoint) == endpoint {
+				return aws.ToString(subscription.SubscriptionArn), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// filterPolicy construye la filter policy sobre event_type ("employee.*" → prefix)
+func filterPolicy(eventTypes []string) (string, error) {
+	conditions := make([]interface{}, 0, len(eventTypes))
+	for _, pattern := range eventTypes {
+		if prefix, ok := domain.EventTypePrefix(pattern); ok {
+			conditions = append(conditions, map[string]string{"prefix": prefix})
+			continue
+		}
+		conditions = append(conditions, pattern)
+	}
+
+	policy, err := json.Marshal(map[string]interface{}{sqsqueue.EventTypeAttribute: conditions})
+	if err != nil {
+		return "", err
+	}
+	return string(policy), nil
+}
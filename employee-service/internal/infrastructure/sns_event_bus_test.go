@@ -0,0 +1,38 @@
+package infrastructure
+
+import "testing"
+
+func TestFilterPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		eventTypes []string
+		want       string
+	}{
+		{
+			name:       "tipo exacto",
+			eventTypes: []string{"employee.created"},
+			want:       `{"event_type":["employee.created"]}`,
+		},
+		{
+			name:       "prefijos",
+			eventTypes: []string{"employee.*", "message.*"},
+			want:       `{"event_type":[{"prefix":"employee."},{"prefix":"message."}]}`,
+		},
+		{
+			name:       "mixta",
+			eventTypes: []string{"employee.created", "message.*"},
+			want:       `{"event_type":["employee.created",{"prefix":"message."}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := filterPolicy(tt.eventTypes)
+			if err != nil {
+				t.Fatalf("filterPolicy() = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("filterPolicy() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
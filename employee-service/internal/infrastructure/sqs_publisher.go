@@ -3,44 +3,47 @@ package infrastructure
 import (
 	"context"
 	"employee-service/internal/domain"
-	"encoding/json"
-	"log"
-
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"pkg/events"
+	"pkg/sqsqueue"
 )
 
-// SQSEventPublisher implementa el publicador de eventos usando SQS
+// SQSEventPublisher implementa el publicador de eventos usando una cola SQS
 type SQSEventPublisher struct {
-	client   *sqs.Client
-	queueURL string
+	queue *sqsqueue.Publisher
 }
 
 // NewSQSEventPublisher crea una nueva instancia del publicador
-func NewSQSEventPublisher(client *sqs.Client, queueURL string) *SQSEventPublisher {
-	return &SQSEventPublisher{
-		client:   client,
-		queueURL: queueURL,
-	}
+func NewSQSEventPublisher(queue *sqsqueue.Publisher) *SQSEventPublisher {
+	return &SQSEventPublisher{queue: queue}
 }
 
-// PublishEmployeeCreated publica un evento de empleado creado
-func (p *SQSEventPublisher) PublishEmployeeCreated(ctx context.Context, event *domain.EmployeeEvent) error {
-	messageBody, err := json.Marshal(event)
+// Publish envuelve el evento en el envelope CloudEvents y lo envía a la cola
+func (p *SQSEventPublisher) Publish(ctx context.Context, event *domain.EmployeeEvent) error {
+	envelope, err := event.ToCloudEvent()
 	if err != nil {
 		return err
 	}
+	return p.queue.Publish(ctx, envelope)
+}
 
-	_, err = p.client.SendMessage(ctx, &sqs.SendMessageInput{
-		QueueUrl:    aws.String(p.queueURL),
-		MessageBody: aws.String(string(messageBody)),
-	})
-
-	if err != nil {
-		log.Printf("Error publishing event to SQS: %v", err)
-		return err
+// PublishBatch envía los eventos a la cola con llamadas SendMessageBatch
+func (p *SQSEventPublisher) PublishBatch(ctx context.Context, batch []*domain.EmployeeEvent) []error {
+	results := make([]error, len(batch))
+
+	envelopes := make([]*events.CloudEvent, 0, len(batch))
+	indexes := make([]int, 0, len(batch))
+	for i, event := range batch {
+		envelope, err := event.ToCloudEvent()
+		if err != nil {
+			results[i] = err
+			continue
+		}
+		envelopes = append(envelopes, envelope)
+		indexes = append(indexes, i)
 	}
 
-	log.Printf("Event published successfully: %s", event.EventType)
-	return nil
+	for i, err := range p.queue.PublishBatch(ctx, envelopes) {
+		results[indexes[i]] = err
+	}
+	return results
 }
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+	"employee-service/internal/domain"
+)
+
+// ChangeStream define el puerto para leer el stream de cambios de la tabla de empleados
+type ChangeStream interface {
+	// Shards lista los shards disponibles del stream
+	Shards(ctx context.Context) ([]domain.StreamShard, error)
+
+	// Read devuelve los registros posteriores a afterSequence ("" = desde el inicio del shard).
+	// closed=true indica que el shard está cerrado y ya no tiene más registros.
+	Read(ctx context.Context, shardID, afterSequence string) (records []*domain.ChangeRecord, closed bool, err error)
+}
+
+// CheckpointStore define el puerto para persistir la posición procesada de cada shard
+type CheckpointStore interface {
+	Load(ctx context.Context, shardID string) (string, error)
+	Save(ctx context.Context, shardID, sequenceNumber string) error
+}
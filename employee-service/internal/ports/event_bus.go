@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+	"employee-service/internal/domain"
+)
+
+// EventBus define el puerto de un bus de eventos con fan-out: cada evento publicado
+// se entrega a todas las suscripciones cuyo filtro acepta su tipo
+type EventBus interface {
+	EventPublisher
+
+	// Subscribe registra una suscripción con su filtro por tipo de evento
+	Subscribe(ctx context.Context, subscription domain.Subscription) error
+}
@@ -7,5 +7,9 @@ import (
 
 // EventPublisher define el puerto para publicar eventos
 type EventPublisher interface {
-	PublishEmployeeCreated(ctx context.Context, event *domain.EmployeeEvent) error
+	Publish(ctx context.Context, event *domain.EmployeeEvent) error
+
+	// PublishBatch publica los eventos agrupándolos en el menor número de llamadas y
+	// devuelve el resultado de cada uno en el mismo orden (nil si se publicó)
+	PublishBatch(ctx context.Context, events []*domain.EmployeeEvent) []error
 }
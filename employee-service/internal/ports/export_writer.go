@@ -0,0 +1,13 @@
+package ports
+
+// EmployeeExportWriter define el puerto para escribir una exportación de empleados
+// fila a fila, independiente del formato de salida
+type EmployeeExportWriter interface {
+	WriteHeader(columns []string) error
+	WriteRow(values []string) error
+	// Close vuelca cualquier dato pendiente al destino
+	Close() error
+	// Abort descarta la exportación tras un error: libera los recursos sin
+	// volcar los datos pendientes
+	Abort()
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+	"employee-service/internal/domain"
+	"time"
+)
+
+// IdempotencyStore define el puerto para almacenar las respuestas de peticiones idempotentes
+type IdempotencyStore interface {
+	// Reserve registra la clave como en curso. Si la clave ya existe (y no expiró)
+	// devuelve el registro existente y reserved=false.
+	Reserve(ctx context.Context, record *domain.IdempotencyRecord) (existing *domain.IdempotencyRecord, reserved bool, err error)
+
+	// Complete guarda la respuesta final asociada a la clave y la conserva hasta expiresAt
+	Complete(ctx context.Context, key string, status int, contentType, body string, expiresAt time.Time) error
+
+	// Release elimina la clave para permitir reintentar la petición (p.ej. tras un error 5xx)
+	Release(ctx context.Context, key string) error
+}
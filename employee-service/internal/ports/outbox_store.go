@@ -0,0 +1,34 @@
+package ports
+
+import (
+	"context"
+	"employee-service/internal/domain"
+	"time"
+)
+
+// OutboxStore define el puerto para gestionar las entradas del outbox de eventos
+type OutboxStore interface {
+	// FindPending obtiene entradas pendientes cuyo próximo intento ya venció
+	FindPending(ctx context.Context, now time.Time, limit int) ([]*domain.OutboxEntry, error)
+
+	// FindPendingByAggregate obtiene las entradas pendientes de un agregado en orden
+	// de Sequence, hayan vencido o no
+	FindPendingByAggregate(ctx context.Context, aggregateID string, limit int) ([]*domain.OutboxEntry, error)
+
+	// Claim reserva la entrada hasta leaseUntil si nadie la reservó desde que se leyó
+	// (su próximo intento sigue siendo el leído). Devuelve false si otra réplica la tomó.
+	Claim(ctx context.Context, entry *domain.OutboxEntry, leaseUntil time.Time) (bool, error)
+
+	// Postpone retrasa el próximo intento de la entrada hasta until sin contarlo como
+	// intento, si nadie la reservó desde que se leyó
+	Postpone(ctx context.Context, entry *domain.OutboxEntry, until time.Time) error
+
+	// MarkSent marca una entrada como publicada
+	MarkSent(ctx context.Context, id string) error
+
+	// MarkRetry registra un intento fallido y programa el siguiente
+	MarkRetry(ctx context.Context, id string, attempts int, nextAttemptAt time.Time, lastErr string) error
+
+	// MarkFailed marca como failed una entrada que agotó los intentos
+	MarkFailed(ctx context.Context, id string, attempts int, lastErr string) error
+}
@@ -0,0 +1,10 @@
+package ports
+
+import "employee-service/internal/domain"
+
+// EmployeeRecordReader define el puerto para leer registros de importación en streaming.
+// Next devuelve io.EOF cuando no quedan más registros; los errores de formato de una
+// fila concreta se informan en ImportRecord.ParseError sin interrumpir la lectura.
+type EmployeeRecordReader interface {
+	Next() (*domain.ImportRecord, error)
+}
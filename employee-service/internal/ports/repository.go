@@ -8,6 +8,30 @@ import (
 // EmployeeRepository define el puerto para el repositorio de empleados
 type EmployeeRepository interface {
 	Save(ctx context.Context, employee *domain.Employee) error
+	// SaveWithOutbox guarda el empleado y la entrada del outbox en una única transacción
+	SaveWithOutbox(ctx context.Context, employee *domain.Employee, entry *domain.OutboxEntry) error
+	// SaveBatch guarda varios empleados y devuelve los IDs que no pudieron escribirse
+	SaveBatch(ctx context.Context, employees []*domain.Employee) ([]string, error)
+	// SaveBatchWithOutbox guarda cada empleado con su entrada del outbox (entries[i] es la de
+	// employees[i]) de forma transaccional y devuelve los IDs que no pudieron escribirse
+	SaveBatchWithOutbox(ctx context.Context, employees []*domain.Employee, entries []*domain.OutboxEntry) ([]string, error)
+	// SaveManagerAssignment guarda el empleado (con su entrada del outbox, si entry no es nil)
+	// solo si su jefe sigue siendo previousManagerID y ningún empleado de chain cambió de
+	// jefe desde que se validó la asignación; si no, devuelve domain.ErrHierarchyChanged
+	SaveManagerAssignment(ctx context.Context, employee *domain.Employee, previousManagerID string, chain []domain.ManagerLink, entry *domain.OutboxEntry) error
 	FindByID(ctx context.Context, id string) (*domain.Employee, error)
+	// FindExistingEmails devuelve cuáles de los emails ya pertenecen a un empleado
+	FindExistingEmails(ctx context.Context, emails []string) (map[string]bool, error)
 	FindAll(ctx context.Context) ([]*domain.Employee, error)
+	// FindPage obtiene una página de empleados a partir de un cursor opaco ("" para la primera)
+	FindPage(ctx context.Context, cursor string, limit int) (*domain.EmployeePage, error)
+	FindByManagerID(ctx context.Context, managerID string) ([]*domain.Employee, error)
+	FindByDepartmentID(ctx context.Context, departmentID string) ([]*domain.Employee, error)
+}
+
+// DepartmentRepository define el puerto para el repositorio de departamentos
+type DepartmentRepository interface {
+	Save(ctx context.Context, department *domain.Department) error
+	FindByID(ctx context.Context, id string) (*domain.Department, error)
+	FindAll(ctx context.Context) ([]*domain.Department, error)
 }
@@ -14,22 +14,202 @@ aws --endpoint-url=http://localhost:4566 sqs create-queue \
     --queue-name employee-events-queue \
     --region us-east-1
 
+echo "Creando dead-letter queues y redrive policies..."
+# Respaldo de la cuarentena que hacen los consumidores tras SQS_MAX_RECEIVE_COUNT intentos
+for queue in employee-queue employee-events-queue; do
+    aws --endpoint-url=http://localhost:4566 sqs create-queue \
+        --queue-name "${queue}-dlq" \
+        --attributes MessageRetentionPeriod=1209600 \
+        --region us-east-1
+
+    aws --endpoint-url=http://localhost:4566 sqs set-queue-attributes \
+        --queue-url "http://sqs.us-east-1.localhost.localstack.cloud:4566/000000000000/${queue}" \
+        --attributes "{\"RedrivePolicy\":\"{\\\"deadLetterTargetArn\\\":\\\"arn:aws:sqs:us-east-1:000000000000:${queue}-dlq\\\",\\\"maxReceiveCount\\\":\\\"5\\\"}\"}" \
+        --region us-east-1
+done
+
+echo "Creando topic SNS de eventos de empleados..."
+aws --endpoint-url=http://localhost:4566 sns create-topic \
+    --name employee-events-topic \
+    --region us-east-1
+
+# Las suscripciones de las colas al topic las declara employee-service al arrancar a partir de
+# EVENT_SUBSCRIPTIONS (nombre=ARN de la cola|tipos de evento), con entrega raw y filter policy por
+# event_type. En AWS real cada cola necesita además una policy que permita sqs:SendMessage desde el topic.
+
+echo "Creando variantes FIFO (orden de eventos por empleado)..."
+# Alternativa FIFO: MessageGroupId = ID del empleado y MessageDeduplicationId = ID del evento.
+# Para usarla apunta EVENT_TOPIC_ARN, EVENT_SUBSCRIPTIONS, SQS_QUEUE_URL, EMPLOYEE_EVENTS_QUEUE_URL,
+# LOG_QUEUE_URL y SQS_DLQ_URL a los recursos .fifo (ver README). La DLQ de una cola FIFO también es FIFO.
+for queue in employee-queue employee-events-queue; do
+    aws --endpoint-url=http://localhost:4566 sqs create-queue \
+        --queue-name "${queue}-dlq.fifo" \
+        --attributes FifoQueue=true,MessageRetentionPeriod=1209600 \
+        --region us-east-1
+
+    aws --endpoint-url=http://localhost:4566 sqs create-queue \
+        --queue-name "${queue}.fifo" \
+        --attributes FifoQueue=true \
+        --region us-east-1
+
+    aws --endpoint-url=http://localhost:4566 sqs set-queue-attributes \
+        --queue-url "http://sqs.us-east-1.localhost.localstack.cloud:4566/000000000000/${queue}.fifo" \
+        --attributes "{\"RedrivePolicy\":\"{\\\"deadLetterTargetArn\\\":\\\"arn:aws:sqs:us-east-1:000000000000:${queue}-dlq.fifo\\\",\\\"maxReceiveCount\\\":\\\"5\\\"}\"}" \
+        --region us-east-1
+done
+
+aws --endpoint-url=http://localhost:4566 sns create-topic \
+    --name employee-events-topic.fifo \
+    --attributes FifoTopic=true \
+    --region us-east-1
+
 echo "Creando tabla DynamoDB para empleados..."
 aws --endpoint-url=http://localhost:4566 dynamodb create-table \
     --table-name employees \
+    --attribute-definitions AttributeName=ID,AttributeType=S AttributeName=Email,AttributeType=S AttributeName=ManagerID,AttributeType=S AttributeName=DepartmentID,AttributeType=S \
+    --key-schema AttributeName=ID,KeyType=HASH \
+    --global-secondary-indexes \
+        "IndexName=EmailIndex,KeySchema=[{AttributeName=Email,KeyType=HASH}],Projection={ProjectionType=KEYS_ONLY},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+        "IndexName=ManagerIndex,KeySchema=[{AttributeName=ManagerID,KeyType=HASH}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+        "IndexName=DepartmentIndex,KeySchema=[{AttributeName=DepartmentID,KeyType=HASH}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --stream-specification StreamEnabled=true,StreamViewType=NEW_AND_OLD_IMAGES \
+    --region us-east-1
+
+echo "Creando tabla DynamoDB para departamentos..."
+aws --endpoint-url=http://localhost:4566 dynamodb create-table \
+    --table-name departments \
     --attribute-definitions AttributeName=ID,AttributeType=S \
     --key-schema AttributeName=ID,KeyType=HASH \
     --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5 \
     --region us-east-1
 
+echo "Creando tabla DynamoDB para claves de idempotencia..."
+aws --endpoint-url=http://localhost:4566 dynamodb create-table \
+    --table-name idempotency-keys \
+    --attribute-definitions AttributeName=Key,AttributeType=S \
+    --key-schema AttributeName=Key,KeyType=HASH \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --region us-east-1
+
+aws --endpoint-url=http://localhost:4566 dynamodb update-time-to-live \
+    --table-name idempotency-keys \
+    --time-to-live-specification Enabled=true,AttributeName=ExpiresAt \
+    --region us-east-1
+
+echo "Creando tabla DynamoDB para el outbox de eventos de empleados..."
+aws --endpoint-url=http://localhost:4566 dynamodb create-table \
+    --table-name employee-outbox \
+    --attribute-definitions AttributeName=ID,AttributeType=S AttributeName=Pending,AttributeType=S AttributeName=NextAttemptAt,AttributeType=N AttributeName=PendingAggregateID,AttributeType=S AttributeName=Sequence,AttributeType=N \
+    --key-schema AttributeName=ID,KeyType=HASH \
+    --global-secondary-indexes \
+        "IndexName=PendingIndex,KeySchema=[{AttributeName=Pending,KeyType=HASH},{AttributeName=NextAttemptAt,KeyType=RANGE}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+        "IndexName=PendingAggregateIndex,KeySchema=[{AttributeName=PendingAggregateID,KeyType=HASH},{AttributeName=Sequence,KeyType=RANGE}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --region us-east-1
+
+aws --endpoint-url=http://localhost:4566 dynamodb update-time-to-live \
+    --table-name employee-outbox \
+    --time-to-live-specification Enabled=true,AttributeName=ExpiresAt \
+    --region us-east-1
+
+echo "Creando tabla DynamoDB para checkpoints del stream de empleados..."
+aws --endpoint-url=http://localhost:4566 dynamodb create-table \
+    --table-name stream-checkpoints \
+    --attribute-definitions AttributeName=ShardID,AttributeType=S \
+    --key-schema AttributeName=ShardID,KeyType=HASH \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --region us-east-1
+
+echo "Creando tabla DynamoDB de eventos procesados (deduplicación de consumidores)..."
+aws --endpoint-url=http://localhost:4566 dynamodb create-table \
+    --table-name processed-events \
+    --attribute-definitions AttributeName=Key,AttributeType=S \
+    --key-schema AttributeName=Key,KeyType=HASH \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --region us-east-1
+
+aws --endpoint-url=http://localhost:4566 dynamodb update-time-to-live \
+    --table-name processed-events \
+    --time-to-live-specification Enabled=true,AttributeName=ExpiresAt \
+    --region us-east-1
+
 echo "Creando tabla DynamoDB para logs..."
+# Índices de la API de consulta (GET /logs): historial por empleado, por tipo de
+# evento y por día, todos ordenados por SortKey (timestamp#id) para evitar Scans.
+# ChainIndex recorre la cadena de auditoría de cada empleado por secuencia.
+# ArchiveIndex (disperso) agrupa por día de vencimiento las entradas con
+# retención que aún no se archivaron.
 aws --endpoint-url=http://localhost:4566 dynamodb create-table \
     --table-name employee-logs \
-    --attribute-definitions AttributeName=ID,AttributeType=S \
+    --attribute-definitions \
+        AttributeName=ID,AttributeType=S \
+        AttributeName=EmployeeID,AttributeType=S \
+        AttributeName=EventType,AttributeType=S \
+        AttributeName=LogDate,AttributeType=S \
+        AttributeName=SortKey,AttributeType=S \
+        AttributeName=ChainID,AttributeType=S \
+        AttributeName=ChainSeq,AttributeType=N \
+        AttributeName=ArchiveDay,AttributeType=S \
     --key-schema AttributeName=ID,KeyType=HASH \
+    --global-secondary-indexes \
+        "IndexName=EmployeeIndex,KeySchema=[{AttributeName=EmployeeID,KeyType=HASH},{AttributeName=SortKey,KeyType=RANGE}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+        "IndexName=EventTypeIndex,KeySchema=[{AttributeName=EventType,KeyType=HASH},{AttributeName=SortKey,KeyType=RANGE}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+        "IndexName=LogDateIndex,KeySchema=[{AttributeName=LogDate,KeyType=HASH},{AttributeName=SortKey,KeyType=RANGE}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+        "IndexName=ChainIndex,KeySchema=[{AttributeName=ChainID,KeyType=HASH},{AttributeName=ChainSeq,KeyType=RANGE}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+        "IndexName=ArchiveIndex,KeySchema=[{AttributeName=ArchiveDay,KeyType=HASH},{AttributeName=SortKey,KeyType=RANGE}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --region us-east-1
+
+# El TTL solo se asigna a las entradas ya archivadas
+aws --endpoint-url=http://localhost:4566 dynamodb update-time-to-live \
+    --table-name employee-logs \
+    --time-to-live-specification Enabled=true,AttributeName=ExpiresAt \
+    --region us-east-1
+
+echo "Creando bucket S3 para los archivos de logs vencidos..."
+aws --endpoint-url=http://localhost:4566 s3 mb s3://employee-logs-archive \
+    --region us-east-1
+
+echo "Creando tablas DynamoDB de la cadena de auditoría (cabezas y checkpoints firmados)..."
+aws --endpoint-url=http://localhost:4566 dynamodb create-table \
+    --table-name audit-chain-heads \
+    --attribute-definitions AttributeName=ChainID,AttributeType=S \
+    --key-schema AttributeName=ChainID,KeyType=HASH \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --region us-east-1
+
+aws --endpoint-url=http://localhost:4566 dynamodb create-table \
+    --table-name audit-checkpoints \
+    --attribute-definitions AttributeName=ChainID,AttributeType=S AttributeName=Seq,AttributeType=N \
+    --key-schema AttributeName=ChainID,KeyType=HASH AttributeName=Seq,KeyType=RANGE \
     --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5 \
     --region us-east-1
 
+echo "Creando tabla DynamoDB de estadísticas de actividad (contadores por hora y por día)..."
+aws --endpoint-url=http://localhost:4566 dynamodb create-table \
+    --table-name activity-stats \
+    --attribute-definitions AttributeName=Series,AttributeType=S AttributeName=Bucket,AttributeType=S \
+    --key-schema AttributeName=Series,KeyType=HASH AttributeName=Bucket,KeyType=RANGE \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --region us-east-1
+
+# Los contadores por hora caducan (ExpiresAt); los diarios no tienen TTL
+aws --endpoint-url=http://localhost:4566 dynamodb update-time-to-live \
+    --table-name activity-stats \
+    --time-to-live-specification Enabled=true,AttributeName=ExpiresAt \
+    --region us-east-1
+
+echo "Creando clave KMS para cifrar los datos personales de los logs..."
+PII_KEY_ID=$(aws --endpoint-url=http://localhost:4566 kms create-key \
+    --description "logger-service PII" \
+    --query KeyMetadata.KeyId --output text \
+    --region us-east-1)
+aws --endpoint-url=http://localhost:4566 kms create-alias \
+    --alias-name alias/logger-pii \
+    --target-key-id "$PII_KEY_ID" \
+    --region us-east-1
+
 echo "Creando tabla DynamoDB para mensajes..."
 aws --endpoint-url=http://localhost:4566 dynamodb create-table \
     --table-name messages \
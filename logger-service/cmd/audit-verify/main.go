@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"logger-service/internal/application"
+	"logger-service/internal/domain"
+	"logger-service/internal/infrastructure"
+	"os"
+	"pkg/awsclient"
+)
+
+// Comando de verificación de la cadena de auditoría de employee-logs.
+// Recorre cada cadena, recalcula los hashes y comprueba secuencias, enlaces,
+// cabezas y checkpoints firmados. Las entradas que ya se archivaron por
+// retención se leen de los archivos (ARCHIVE_STORE, ...) salvo con -archives=false.
+// Sale con código 1 si encuentra problemas.
+//
+// Uso:
+//
+//	go run ./cmd/audit-verify [-chain <employee-id>] [-public-key <base64>] [-json] [-archives=false]
+//
+// La clave pública se toma de -public-key, de AUDIT_VERIFY_KEY o se deriva de AUDIT_SIGNING_KEY.
+func main() {
+	chainID := flag.String("chain", "", "Verificar solo esta cadena (ID de empleado o \"system\")")
+	publicKey := flag.String("public-key", os.Getenv("AUDIT_VERIFY_KEY"), "Clave pública Ed25519 en base64 para verificar los checkpoints")
+	asJSON := flag.Bool("json", false, "Imprimir un informe JSON por cadena")
+	verbose := flag.Bool("v", false, "Mostrar también las cadenas sin problemas")
+	includeArchives := flag.Bool("archives", true, "Incluir las entradas archivadas por retención")
+	flag.Parse()
+
+	signer, err := loadVerifier(*publicKey)
+	if err != nil {
+		log.Fatalf("Error loading verification key: %v", err)
+	}
+
+	ctx := context.Background()
+
+	clients, err := awsclient.NewFactoryFromEnv(ctx)
+	if err != nil {
+		log.Fatalf("Error loading AWS config: %v", err)
+	}
+
+	logsTable := envOr("DYNAMODB_TABLE", "employee-logs")
+	chainHeadTable := envOr("AUDIT_CHAIN_HEAD_TABLE", "audit-chain-heads")
+	store := infrastructure.NewDynamoDBAuditChainStore(clients.DynamoDB(),
+		logsTable,
+		chainHeadTable,
+		envOr("AUDIT_CHECKPOINT_TABLE", "audit-checkpoints"))
+	verifier := application.NewAuditVerifier(store, signer)
+
+	if *includeArchives {
+		archiveStore, err := infrastructure.NewArchiveStoreFromEnv(clients)
+		if err != nil {
+			log.Fatalf("Invalid archive configuration: %v", err)
+		}
+		repository := infrastructure.NewDynamoDBLogRepository(clients.DynamoDB(), logsTable, chainHeadTable)
+		archiver := application.NewLogArchiver(repository, archiveStore, 0, 0)
+		err = archiver.ReadArchives(ctx, "", func(_ string, entry *domain.LogEntry) error {
+			if *chainID == "" || entry.ChainID == *chainID {
+				verifier.IncludeArchived(entry)
+			}
+			return nil
+		})
+		if err != nil {
+			log.Fatalf("Error reading log archives: %v", err)
+		}
+	}
+
+	chains, failed := 0, 0
+	report := func(report *domain.ChainReport) error {
+		chains++
+		if !report.OK() {
+			failed++
+		}
+		if *asJSON {
+			return json.NewEncoder(os.Stdout).Encode(report)
+		}
+		printReport(report, *verbose)
+		return nil
+	}
+
+	if *chainID != "" {
+		result, err := verifier.VerifyChain(ctx, *chainID)
+		if err == nil {
+			err = report(result)
+		}
+		if err != nil {
+			log.Fatalf("Error verifying chain %s: %v", *chainID, err)
+		}
+	} else if err := verifier.VerifyAll(ctx, report); err != nil {
+		log.Fatalf("Error verifying audit chains: %v", err)
+	}
+
+	if !*asJSON {
+		fmt.Printf("%d cadena(s) verificada(s), %d con problemas\n", chains, failed)
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func loadVerifier(publicKey string) (*infrastructure.Ed25519Signer, error) {
+	if publicKey != "" {
+		return infrastructure.NewEd25519Verifier(publicKey)
+	}
+	if seed := os.Getenv("AUDIT_SIGNING_KEY"); seed != "" {
+		return infrastructure.NewEd25519SignerFromSeed(seed)
+	}
+	return nil, fmt.Errorf("use -public-key, AUDIT_VERIFY_KEY or AUDIT_SIGNING_KEY")
+}
+
+func printReport(report *domain.ChainReport, verbose bool) {
+	if report.OK() {
+		if verbose {
+			fmt.Printf("✓ %s: %d entradas, %d archivadas, %d checkpoints\n", report.ChainID, report.Entries, report.Archived, report.Checkpoints)
+		}
+		return
+	}
+
+	fmt.Printf("✗ %s: %d entradas, %d archivadas, %d checkpoints, %d problema(s)\n",
+		report.ChainID, report.Entries, report.Archived, report.Checkpoints, len(report.Issues))
+	for _, issue := range report.Issues {
+		fmt.Printf("    seq %-6d %-20s %s\n", issue.Seq, issue.Kind, issue.Detail)
+	}
+}
+
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
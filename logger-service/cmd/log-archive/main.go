@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"logger-service/internal/application"
+	"logger-service/internal/infrastructure"
+	"os"
+	"os/signal"
+	"pkg/awsclient"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// log-archive administra los archivos de entradas vencidas de employee-logs.
+// Usa la configuración del servicio (AWS_*, DYNAMODB_TABLE, ARCHIVE_STORE,
+// ARCHIVE_DIR, ARCHIVE_BUCKET, ARCHIVE_PREFIX). Uso:
+//
+//	log-archive list    [-prefix 2024/05/]
+//	log-archive run     [-lookback 30]
+//	log-archive restore [-retain-days 7] <clave | prefijo/>...
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	var err error
+	switch command, args := os.Args[1], os.Args[2:]; command {
+	case "list":
+		err = runList(ctx, args)
+	case "run":
+		err = runArchive(ctx, args)
+	case "restore":
+		err = runRestore(ctx, args)
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `Uso: log-archive <comando> [opciones]
+
+Comandos:
+  list     Lista los archivos (día de vencimiento/hora de la pasada-parte.ndjson.gz)
+  run      Ejecuta ahora una pasada de archivado
+  restore  Vuelve a importar a employee-logs las entradas de uno o más archivos
+
+Ejecuta "log-archive <comando> -h" para ver sus opciones.`)
+}
+
+func runList(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	prefix := fs.String("prefix", "", "Listar solo las claves con este prefijo (p.ej. 2024/05/)")
+	fs.Parse(args)
+
+	archiver, err := newArchiver(ctx, 0)
+	if err != nil {
+		return err
+	}
+
+	keys, err := archiver.ListArchives(ctx, *prefix)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		fmt.Println(key)
+	}
+	log.Printf("%d archivo(s)", len(keys))
+	return nil
+}
+
+func runArchive(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	lookback := fs.Int("lookback", 30, "Días hacia atrás que se revisan")
+	fs.Parse(args)
+
+	archiver, err := newArchiver(ctx, *lookback)
+	if err != nil {
+		return err
+	}
+
+	archived, err := archiver.ArchiveExpired(ctx, time.Now())
+	log.Printf("%d entrada(s) archivada(s)", archived)
+	return err
+}
+
+func runRestore(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	retainDays := fs.Int("retain-days", 7, "Días que se conservan las entradas restauradas antes de volver a vencer (0 = indefinidamente)")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		return fmt.Errorf("indica al menos una clave de archivo o un prefijo terminado en /")
+	}
+
+	archiver, err := newArchiver(ctx, 0)
+	if err != nil {
+		return err
+	}
+
+	// Un argumento terminado en "/" restaura todos los archivos bajo ese prefijo
+	var keys []string
+	for _, arg := range fs.Args() {
+		if !strings.HasSuffix(arg, "/") {
+			keys = append(keys, arg)
+			continue
+		}
+		matched, err := archiver.ListArchives(ctx, arg)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, matched...)
+	}
+
+	retainFor := time.Duration(*retainDays) * 24 * time.Hour
+	totalRestored, totalSkipped := 0, 0
+	for _, key := range keys {
+		restored, skipped, err := archiver.Restore(ctx, key, retainFor)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		log.Printf("%s: %d restaurada(s), %d ya existía(n)", key, restored, skipped)
+		totalRestored += restored
+		totalSkipped += skipped
+	}
+
+	log.Printf("%d archivo(s): %d entrada(s) restaurada(s), %d ya existía(n)", len(keys), totalRestored, totalSkipped)
+	return nil
+}
+
+func newArchiver(ctx context.Context, lookbackDays int) (*application.LogArchiver, error) {
+	clients, err := awsclient.NewFactoryFromEnv(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := infrastructure.NewArchiveStoreFromEnv(clients)
+	if err != nil {
+		return nil, err
+	}
+
+	repository := infrastructure.NewDynamoDBLogRepository(clients.DynamoDB(),
+		envOr("DYNAMODB_TABLE", "employee-logs"),
+		envOr("AUDIT_CHAIN_HEAD_TABLE", "audit-chain-heads"))
+	return application.NewLogArchiver(repository, store, lookbackDays, 0), nil
+}
+
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
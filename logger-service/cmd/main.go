@@ -2,55 +2,38 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"logger-service/internal/application"
+	"logger-service/internal/domain"
 	"logger-service/internal/infrastructure"
+	"logger-service/internal/ports"
+	"net/http"
 	"os"
 	"os/signal"
+	"pkg/awsclient"
+	"pkg/dedup"
+	"pkg/eventschema"
+	"pkg/health"
+	"pkg/sqsqueue"
+	"strconv"
+	"strings"
 	"syscall"
-
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials"
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
-	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"time"
 )
 
 func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Configurar AWS SDK
-	awsEndpoint := os.Getenv("AWS_ENDPOINT")
-	awsRegion := os.Getenv("AWS_REGION")
-	if awsRegion == "" {
-		awsRegion = "us-east-1"
-	}
-
-	cfg, err := config.LoadDefaultConfig(ctx,
-		config.WithRegion(awsRegion),
-		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
-			os.Getenv("AWS_ACCESS_KEY_ID"),
-			os.Getenv("AWS_SECRET_ACCESS_KEY"),
-			"",
-		)),
-	)
+	// Configurar AWS SDK y crear clientes (endpoint de LocalStack vía AWS_ENDPOINT)
+	clients, err := awsclient.NewFactoryFromEnv(ctx)
 	if err != nil {
 		log.Fatalf("Error loading AWS config: %v", err)
 	}
 
-	// Crear clientes de AWS
-	dynamoClient := dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
-		if awsEndpoint != "" {
-			o.BaseEndpoint = aws.String(awsEndpoint)
-		}
-	})
-
-	sqsClient := sqs.NewFromConfig(cfg, func(o *sqs.Options) {
-		if awsEndpoint != "" {
-			o.BaseEndpoint = aws.String(awsEndpoint)
-		}
-	})
+	dynamoClient := clients.DynamoDB()
+	sqsClient := clients.SQS()
 
 	// Obtener variables de entorno
 	tableName := os.Getenv("DYNAMODB_TABLE")
@@ -58,17 +41,138 @@ func main() {
 		tableName = "employee-logs"
 	}
 
+	// Cadena de auditoría: cabezas por cadena y checkpoints firmados
+	chainHeadTableName := os.Getenv("AUDIT_CHAIN_HEAD_TABLE")
+	if chainHeadTableName == "" {
+		chainHeadTableName = "audit-chain-heads"
+	}
+
+	checkpointTableName := os.Getenv("AUDIT_CHECKPOINT_TABLE")
+	if checkpointTableName == "" {
+		checkpointTableName = "audit-checkpoints"
+	}
+
+	// Contadores de actividad por tipo de evento, por hora y por día
+	statsTableName := os.Getenv("ACTIVITY_STATS_TABLE")
+	if statsTableName == "" {
+		statsTableName = "activity-stats"
+	}
+
 	queueURL := os.Getenv("SQS_QUEUE_URL")
 	if queueURL == "" {
 		log.Fatal("SQS_QUEUE_URL environment variable is required")
 	}
 
+	// Registro de esquemas para validar los eventos
+	registry, err := eventschema.Default()
+	if err != nil {
+		log.Fatalf("Error loading event schemas: %v", err)
+	}
+
 	// Crear instancias de infraestructura
-	repository := infrastructure.NewDynamoDBLogRepository(dynamoClient, tableName)
-	consumer := infrastructure.NewSQSEventConsumer(sqsClient, queueURL)
+	repository := infrastructure.NewDynamoDBLogRepository(dynamoClient, tableName, chainHeadTableName)
+	consumerOptions := sqsqueue.OptionsFromEnv()
+	consumer := infrastructure.NewSQSEventConsumer(sqsqueue.NewConsumer(sqsClient, queueURL, consumerOptions), registry)
+
+	// Registro de eventos procesados para descartar entregas duplicadas
+	// (DEDUP_STORE=memory lo mantiene en memoria, sin DynamoDB)
+	dedupTableName := os.Getenv("DEDUP_TABLE")
+	if dedupTableName == "" {
+		dedupTableName = "processed-events"
+	}
+
+	dedupTTL := dedup.DefaultTTL
+	if value := os.Getenv("DEDUP_TTL_HOURS"); value != "" {
+		if hours, err := strconv.Atoi(value); err == nil && hours > 0 {
+			dedupTTL = time.Duration(hours) * time.Hour
+		}
+	}
+
+	var dedupStore ports.DeduplicationStore
+	if os.Getenv("DEDUP_STORE") == "memory" {
+		dedupStore = dedup.NewMemoryStore(dedupTTL)
+	} else {
+		dedupStore = dedup.NewDynamoDBStore(dynamoClient, dedupTableName, "logger-service", dedupTTL)
+	}
+
+	// Retención por tipo de evento (días; 0 = sin vencimiento)
+	retention, err := domain.ParseRetentionPolicy(envInt("LOG_RETENTION_DEFAULT_DAYS"), os.Getenv("LOG_RETENTION_RULES"))
+	if err != nil {
+		log.Fatalf("Invalid log retention configuration: %v", err)
+	}
+	log.Printf("Log retention: %s", retention)
+
+	// Los contadores por hora caducan a los ACTIVITY_STATS_HOURLY_RETENTION_DAYS
+	// (90 por defecto, 0 = nunca); los diarios se conservan siempre
+	hourlyRetentionDays := 90
+	if value := os.Getenv("ACTIVITY_STATS_HOURLY_RETENTION_DAYS"); value != "" {
+		if days, err := strconv.Atoi(value); err == nil && days >= 0 {
+			hourlyRetentionDays = days
+		}
+	}
+	stats := infrastructure.NewDynamoDBActivityStats(dynamoClient, statsTableName, time.Duration(hourlyRetentionDays)*24*time.Hour)
+
+	// Hub del stream en vivo (SSE y WebSocket)
+	hub := infrastructure.NewLogStreamHub(envInt("LOG_STREAM_BUFFER_SIZE"), envInt("LOG_STREAM_REPLAY_SIZE"))
+
+	// Reglas de alerta (ALERT_RULES_FILE; sin él no se evalúan alertas)
+	var alertEngine *application.AlertEngine
+	var alertQueueURL string
+	var alerts ports.AlertEvaluator
+	if rulesFile := os.Getenv("ALERT_RULES_FILE"); rulesFile != "" {
+		alertConfig, err := infrastructure.LoadAlertConfig(rulesFile)
+		if err != nil {
+			log.Fatalf("Invalid alert rules: %v", err)
+		}
+
+		notifiers := make(map[string]ports.AlertNotifier)
+		if alertConfig.WebhookURL != "" {
+			notifiers["webhook"] = infrastructure.NewWebhookAlertNotifier(alertConfig.WebhookURL, alertConfig.WebhookSecret)
+		}
+		if alertConfig.MessagingQueueURL != "" {
+			alertQueueURL = alertConfig.MessagingQueueURL
+			publisher := sqsqueue.NewPublisher(sqsClient, alertQueueURL, registry)
+			notifiers["messaging"] = infrastructure.NewMessagingAlertNotifier(publisher, alertConfig.MessagingRecipients)
+		}
+
+		alertEngine = application.NewAlertEngine(alertConfig.Rules, notifiers)
+		alerts = alertEngine
+		log.Printf("Loaded %d alert rules from %s", len(alertConfig.Rules), rulesFile)
+	} else {
+		log.Println("ALERT_RULES_FILE not set: alerting is disabled")
+	}
+
+	// Datos personales (PII_FIELDS): se ocultan en la consola y el stream según
+	// LOG_REDACTION y, con PII_KEY_PROVIDER, se guardan cifrados. Solo los
+	// usuarios de PII_READERS pueden descifrarlos en la API, con un token del
+	// auth-service firmado con JWT_SECRET.
+	piiFields := domain.ParsePIIFields(os.Getenv("PII_FIELDS"))
+	redactionMode, err := domain.ParseRedactionMode(os.Getenv("LOG_REDACTION"))
+	if err != nil {
+		log.Fatalf("Invalid LOG_REDACTION: %v", err)
+	}
+	redactor := domain.NewRedactor(redactionMode, piiFields)
+
+	keyProvider, err := infrastructure.NewKeyProviderFromEnv(clients)
+	if err != nil {
+		log.Fatalf("Invalid PII encryption configuration: %v", err)
+	}
+	var protector *application.PIIProtector
+	if keyProvider != nil {
+		readers := envList("PII_READERS")
+		protector = application.NewPIIProtector(keyProvider, piiFields, readers)
+		log.Printf("PII encryption enabled (%s) for %s; %d authorized readers", os.Getenv("PII_KEY_PROVIDER"), strings.Join(piiFields, ", "), len(readers))
+	} else {
+		log.Println("PII_KEY_PROVIDER not set: personal data is stored in clear text")
+	}
+
+	var tokenVerifier ports.TokenVerifier
+	if jwtSecret := os.Getenv("JWT_SECRET"); jwtSecret != "" {
+		tokenVerifier = infrastructure.NewJWTTokenVerifier(jwtSecret)
+	}
 
 	// Crear servicio de aplicación
-	service := application.NewLoggerService(repository, consumer)
+	service := application.NewLoggerService(repository, consumer, dedupStore, hub, stats, alerts, retention, protector, redactor)
 
 	// Manejar señales de interrupción
 	sigChan := make(chan os.Signal, 1)
@@ -80,6 +184,84 @@ func main() {
 		cancel()
 	}()
 
+	// API de consulta y endpoints de salud (HEALTH_PORT se mantiene por compatibilidad)
+	httpPort := os.Getenv("HTTP_PORT")
+	if httpPort == "" {
+		httpPort = os.Getenv("HEALTH_PORT")
+	}
+	if httpPort == "" {
+		httpPort = "8084"
+	}
+	healthHandler := health.New("logger-service")
+	healthHandler.AddCheck("logs-table", awsclient.TableCheck(dynamoClient, tableName))
+	healthHandler.AddCheck("audit-chain-head-table", awsclient.TableCheck(dynamoClient, chainHeadTableName))
+	healthHandler.AddCheck("audit-checkpoint-table", awsclient.TableCheck(dynamoClient, checkpointTableName))
+	healthHandler.AddCheck("activity-stats-table", awsclient.TableCheck(dynamoClient, statsTableName))
+	healthHandler.AddCheck("queue", awsclient.QueueCheck(sqsClient, queueURL))
+	if os.Getenv("DEDUP_STORE") != "memory" {
+		healthHandler.AddCheck("dedup-table", awsclient.TableCheck(dynamoClient, dedupTableName))
+	}
+	if alertQueueURL != "" {
+		healthHandler.AddCheck("alert-queue", awsclient.QueueCheck(sqsClient, alertQueueURL))
+	}
+	if consumerOptions.DeadLetterQueueURL != "" {
+		healthHandler.AddCheck("dead-letter-queue", awsclient.QueueCheck(sqsClient, consumerOptions.DeadLetterQueueURL))
+	}
+
+	// El stream en vivo exige un token del auth-service; LOG_STREAM_ALLOWED_ORIGINS
+	// son los orígenes de navegador (además del propio) que pueden abrirlo
+	if tokenVerifier == nil {
+		log.Println("JWT_SECRET not set: the live log stream rejects every connection")
+	}
+	streamOrigins := envList("LOG_STREAM_ALLOWED_ORIGINS")
+	router := infrastructure.NewHTTPHandler(service, hub, tokenVerifier, streamOrigins).SetupRoutes()
+	router.HandleFunc("/health", healthHandler.Live).Methods("GET")
+	router.HandleFunc("/health/ready", healthHandler.Ready).Methods("GET")
+	go serveHTTP(ctx, ":"+httpPort, router)
+
+	// Entrega de las alertas disparadas
+	if alertEngine != nil {
+		go alertEngine.Run(ctx)
+	}
+
+	// Checkpoints firmados de la cadena de auditoría (requiere AUDIT_SIGNING_KEY)
+	if signingKey := os.Getenv("AUDIT_SIGNING_KEY"); signingKey != "" {
+		signer, err := infrastructure.NewEd25519SignerFromSeed(signingKey)
+		if err != nil {
+			log.Fatalf("Invalid AUDIT_SIGNING_KEY: %v", err)
+		}
+		interval := 5 * time.Minute
+		if seconds := envInt("AUDIT_CHECKPOINT_INTERVAL_SECONDS"); seconds > 0 {
+			interval = time.Duration(seconds) * time.Second
+		}
+		chainStore := infrastructure.NewDynamoDBAuditChainStore(dynamoClient, tableName, chainHeadTableName, checkpointTableName)
+		log.Printf("Audit checkpoint public key: %s", signer.PublicKey())
+		go application.NewAuditCheckpointer(chainStore, signer, interval).Run(ctx)
+	} else {
+		log.Println("AUDIT_SIGNING_KEY not set: audit chain checkpoints are disabled")
+	}
+
+	// Archivado de las entradas vencidas antes de que las borre el TTL. Es opt-in
+	// (ARCHIVE_ENABLED=true): debe correr en una sola instancia, porque dos pasadas
+	// simultáneas archivarían las mismas entradas en archivos distintos
+	if os.Getenv("ARCHIVE_ENABLED") == "true" {
+		archiveStore, err := infrastructure.NewArchiveStoreFromEnv(clients)
+		if err != nil {
+			log.Fatalf("Invalid archive configuration: %v", err)
+		}
+		interval := time.Hour
+		if seconds := envInt("ARCHIVE_INTERVAL_SECONDS"); seconds > 0 {
+			interval = time.Duration(seconds) * time.Second
+		}
+		lookbackDays := 30
+		if days := envInt("ARCHIVE_LOOKBACK_DAYS"); days > 0 {
+			lookbackDays = days
+		}
+		go application.NewLogArchiver(repository, archiveStore, lookbackDays, interval).Run(ctx)
+	} else {
+		log.Println("ARCHIVE_ENABLED is not true: expired log entries are not archived by this instance")
+	}
+
 	// Iniciar consumo de eventos
 	log.Println("Logger service starting...")
 	if err := service.StartConsuming(ctx); err != nil {
@@ -90,3 +272,40 @@ func main() {
 
 	log.Println("Logger service stopped")
 }
+
+// serveHTTP expone la API en addr hasta que se cancele el contexto
+func serveHTTP(ctx context.Context, addr string, handler http.Handler) {
+	server := &http.Server{Addr: addr, Handler: handler}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("Logger service HTTP API listening on %s", addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Printf("HTTP server error: %v", err)
+	}
+}
+
+// envInt lee una variable de entorno entera (0 si no está definida o no es válida)
+func envInt(name string) int {
+	value, err := strconv.Atoi(os.Getenv(name))
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// envList lee una variable de entorno con valores separados por comas
+func envList(name string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(name), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,187 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"logger-service/internal/domain"
+	"logger-service/internal/ports"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Parámetros de la entrega de alertas
+const (
+	alertQueueSize       = 100
+	alertDeliveryRetries = 3
+	alertRetryDelay      = 2 * time.Second
+	alertNotifyTimeout   = 10 * time.Second
+	alertSweepInterval   = time.Minute
+)
+
+// windowKey identifica la ventana de una regla y grupo
+type windowKey struct {
+	rule  int
+	group string
+}
+
+// AlertEngine evalúa las reglas de alerta a medida que se guardan las
+// entradas de log y entrega las alertas por sus canales. Las ventanas viven en
+// memoria: con varias instancias cada una cuenta solo los eventos que procesa.
+type AlertEngine struct {
+	rules     []domain.AlertRule
+	notifiers map[string]ports.AlertNotifier
+
+	mu        sync.Mutex
+	windows   map[windowKey]*domain.AlertWindow
+	groups    map[windowKey]map[string]string
+	lastSweep time.Time
+
+	alerts chan *domain.Alert
+}
+
+// NewAlertEngine crea el motor con reglas ya validadas y los canales de aviso
+// disponibles por nombre ("webhook", "messaging")
+func NewAlertEngine(rules []domain.AlertRule, notifiers map[string]ports.AlertNotifier) *AlertEngine {
+	return &AlertEngine{
+		rules:     rules,
+		notifiers: notifiers,
+		windows:   make(map[windowKey]*domain.AlertWindow),
+		groups:    make(map[windowKey]map[string]string),
+		alerts:    make(chan *domain.Alert, alertQueueSize),
+	}
+}
+
+// Evaluate cuenta la entrada en las ventanas de las reglas que cumple y encola
+// las alertas disparadas. No bloquea: si la cola de entrega está llena la
+// alerta solo se registra en el log.
+func (e *AlertEngine) Evaluate(entry *domain.LogEntry) {
+	now := time.Now()
+
+	e.mu.Lock()
+	var fired []*domain.Alert
+	for i := range e.rules {
+		rule := &e.rules[i]
+		if !rule.Matches(entry) {
+			continue
+		}
+
+		group, groupKey := rule.Group(entry)
+		key := windowKey{rule: i, group: groupKey}
+		window, ok := e.windows[key]
+		if !ok {
+			window = &domain.AlertWindow{}
+			e.windows[key] = window
+			e.groups[key] = group
+		}
+
+		if alert := window.Add(rule, entry, now); alert != nil {
+			alert.ID = uuid.New().String()
+			alert.Group = e.groups[key]
+			fired = append(fired, alert)
+		}
+	}
+	if now.Sub(e.lastSweep) >= alertSweepInterval {
+		e.sweep(now)
+	}
+	e.mu.Unlock()
+
+	for _, alert := range fired {
+		e.displayAlert(alert)
+		select {
+		case e.alerts <- alert:
+		default:
+			log.Printf("Alert queue full: alert %s (rule %s) will not be delivered", alert.ID, alert.Rule)
+		}
+	}
+}
+
+// sweep descarta las ventanas vacías fuera de cooldown (p.ej. de grupos que
+// ya no reciben eventos). Se llama con mu tomado.
+func (e *AlertEngine) sweep(now time.Time) {
+	for key, window := range e.windows {
+		if window.Idle(&e.rules[key.rule], now) {
+			delete(e.windows, key)
+			delete(e.groups, key)
+		}
+	}
+	e.lastSweep = now
+}
+
+// Run entrega las alertas encoladas hasta que se cancele el contexto
+func (e *AlertEngine) Run(ctx context.Context) {
+	channels := make([]string, 0, len(e.notifiers))
+	for name := range e.notifiers {
+		channels = append(channels, name)
+	}
+	sort.Strings(channels)
+	log.Printf("Alert engine started (%d rules, channels: %s)", len(e.rules), strings.Join(channels, ", "))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case alert := <-e.alerts:
+			e.deliver(ctx, alert)
+		}
+	}
+}
+
+// deliver envía la alerta por cada canal de su regla, con reintentos
+func (e *AlertEngine) deliver(ctx context.Context, alert *domain.Alert) {
+	for _, channel := range alert.Notify {
+		notifier, ok := e.notifiers[channel]
+		if !ok {
+			log.Printf("Alert %s (rule %s): channel %s is not configured", alert.ID, alert.Rule, channel)
+			continue
+		}
+
+		var err error
+		for attempt := 1; attempt <= alertDeliveryRetries; attempt++ {
+			notifyCtx, cancel := context.WithTimeout(ctx, alertNotifyTimeout)
+			err = notifier.Notify(notifyCtx, alert)
+			cancel()
+			if err == nil || attempt == alertDeliveryRetries {
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Duration(attempt) * alertRetryDelay):
+			}
+		}
+
+		if err != nil {
+			log.Printf("Error delivering alert %s (rule %s) via %s: %v", alert.ID, alert.Rule, channel, err)
+			continue
+		}
+		log.Printf("Alert %s (rule %s) delivered via %s", alert.ID, alert.Rule, channel)
+	}
+}
+
+// displayAlert muestra la alerta en consola en un solo bloque
+func (e *AlertEngine) displayAlert(alert *domain.Alert) {
+	var b strings.Builder
+	b.WriteString("########################################\n")
+	fmt.Fprintf(&b, "ALERTA [%s]: %s\n", strings.ToUpper(alert.Severity), alert.Rule)
+	if alert.Description != "" {
+		fmt.Fprintf(&b, "%s\n", alert.Description)
+	}
+	fmt.Fprintf(&b, "Eventos: %d en %s (umbral: %d)\n", alert.Count, time.Duration(alert.WindowSeconds)*time.Second, alert.Threshold)
+
+	keys := make([]string, 0, len(alert.Group))
+	for key := range alert.Group {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "  %s: %s\n", key, alert.Group[key])
+	}
+	b.WriteString("########################################")
+	log.Print(b.String())
+}
@@ -0,0 +1,68 @@
+package application
+
+import (
+	"context"
+	"log"
+	"logger-service/internal/domain"
+	"logger-service/internal/ports"
+	"time"
+)
+
+// AuditCheckpointer firma periódicamente la cabeza de las cadenas de
+// auditoría que avanzaron desde su último checkpoint
+type AuditCheckpointer struct {
+	store    ports.AuditChainStore
+	signer   ports.CheckpointSigner
+	interval time.Duration
+}
+
+// NewAuditCheckpointer crea una nueva instancia del servicio
+func NewAuditCheckpointer(store ports.AuditChainStore, signer ports.CheckpointSigner, interval time.Duration) *AuditCheckpointer {
+	return &AuditCheckpointer{
+		store:    store,
+		signer:   signer,
+		interval: interval,
+	}
+}
+
+// Run crea checkpoints cada interval hasta que se cancele el contexto
+func (c *AuditCheckpointer) Run(ctx context.Context) {
+	log.Printf("Audit checkpointer started (interval: %s, key: %s)", c.interval, c.signer.KeyID())
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if created, err := c.CreateCheckpoints(ctx); err != nil {
+				log.Printf("Error creating audit checkpoints: %v", err)
+			} else if created > 0 {
+				log.Printf("Created %d audit checkpoints", created)
+			}
+		}
+	}
+}
+
+// CreateCheckpoints firma y guarda un checkpoint por cada cadena pendiente
+func (c *AuditCheckpointer) CreateCheckpoints(ctx context.Context) (int, error) {
+	heads, err := c.store.HeadsPendingCheckpoint(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, head := range heads {
+		checkpoint := domain.NewCheckpoint(head, time.Now(), c.signer.KeyID())
+		if checkpoint.Signature, err = c.signer.Sign(checkpoint.SigningPayload()); err != nil {
+			return created, err
+		}
+		if err := c.store.SaveCheckpoint(ctx, checkpoint); err != nil {
+			return created, err
+		}
+		created++
+	}
+	return created, nil
+}
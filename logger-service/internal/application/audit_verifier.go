@@ -0,0 +1,193 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"logger-service/internal/domain"
+	"logger-service/internal/ports"
+	"sort"
+)
+
+// AuditVerifier recorre las cadenas de auditoría y detecta huecos,
+// reordenamientos, entradas modificadas y cabezas o checkpoints que no coinciden
+type AuditVerifier struct {
+	store    ports.AuditChainStore
+	signer   ports.CheckpointSigner
+	archived map[string]map[int64]*domain.LogEntry // cadena → secuencia → entrada archivada
+}
+
+// NewAuditVerifier crea una nueva instancia del servicio
+func NewAuditVerifier(store ports.AuditChainStore, signer ports.CheckpointSigner) *AuditVerifier {
+	return &AuditVerifier{
+		store:    store,
+		signer:   signer,
+		archived: make(map[string]map[int64]*domain.LogEntry),
+	}
+}
+
+// IncludeArchived agrega una entrada archivada a la verificación: ocupa su
+// lugar en la cadena si ya no está en la tabla por haber vencido su retención
+func (v *AuditVerifier) IncludeArchived(entry *domain.LogEntry) {
+	if entry.ChainID == "" {
+		return
+	}
+	if v.archived[entry.ChainID] == nil {
+		v.archived[entry.ChainID] = make(map[int64]*domain.LogEntry)
+	}
+	v.archived[entry.ChainID][entry.ChainSeq] = entry
+}
+
+// chainLink son los datos de una entrada necesarios para verificar la cadena
+type chainLink struct {
+	seq      int64
+	prevHash string
+	hash     string
+	computed string
+}
+
+// VerifyAll verifica todas las cadenas y entrega cada informe a fn
+func (v *AuditVerifier) VerifyAll(ctx context.Context, fn func(*domain.ChainReport) error) error {
+	return v.store.ListHeads(ctx, func(head *domain.ChainHead) error {
+		report, err := v.VerifyChain(ctx, head.ChainID)
+		if err != nil {
+			return err
+		}
+		return fn(report)
+	})
+}
+
+// VerifyChain verifica una cadena: recalcula el hash de cada entrada, comprueba
+// la continuidad de secuencias y enlaces, y la contrasta con su cabeza y con
+// los checkpoints cuya firma es válida
+func (v *AuditVerifier) VerifyChain(ctx context.Context, chainID string) (*domain.ChainReport, error) {
+	report := &domain.ChainReport{ChainID: chainID}
+
+	head, err := v.store.GetHead(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints, err := v.store.ListCheckpoints(ctx, chainID)
+	if err != nil {
+		return nil, err
+	}
+	report.Checkpoints = len(checkpoints)
+
+	trusted := make(map[int64]*domain.Checkpoint, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		if !v.signer.Verify(checkpoint.KeyID, checkpoint.SigningPayload(), checkpoint.Signature) {
+			report.AddIssue(checkpoint.Seq, domain.IssueBadSignature,
+				fmt.Sprintf("checkpoint signature invalid or unknown key %q", checkpoint.KeyID))
+			continue
+		}
+		trusted[checkpoint.Seq] = checkpoint
+	}
+
+	var links []chainLink
+	err = v.store.ReadChain(ctx, chainID, func(entry *domain.LogEntry) error {
+		links = append(links, chainLink{
+			seq:      entry.ChainSeq,
+			prevHash: entry.PrevHash,
+			hash:     entry.Hash,
+			computed: entry.ComputeHash(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	report.Entries = len(links)
+	links = v.mergeArchived(report, links)
+
+	// Hash guardado → secuencia, para distinguir un reordenamiento de un enlace roto
+	seqByHash := make(map[string]int64, len(links))
+	for _, link := range links {
+		seqByHash[link.hash] = link.seq
+	}
+
+	expectedSeq, prevHash := int64(1), domain.GenesisHash
+	for _, link := range links {
+		if link.seq < expectedSeq {
+			report.AddIssue(link.seq, domain.IssueDuplicate, "sequence number appears more than once")
+			continue
+		}
+
+		contiguous := link.seq == expectedSeq
+		if !contiguous {
+			report.AddIssue(link.seq, domain.IssueGap, fmt.Sprintf("missing entries %d to %d", expectedSeq, link.seq-1))
+		}
+
+		if link.computed != link.hash {
+			report.AddIssue(link.seq, domain.IssueModified, "entry content does not match its hash")
+		}
+
+		if contiguous && link.prevHash != prevHash {
+			if seq, ok := seqByHash[link.prevHash]; ok {
+				report.AddIssue(link.seq, domain.IssueReordered, fmt.Sprintf("entry links to seq %d instead of %d", seq, link.seq-1))
+			} else {
+				report.AddIssue(link.seq, domain.IssueBrokenLink, "previous hash does not match any entry")
+			}
+		}
+
+		if checkpoint, ok := trusted[link.seq]; ok && checkpoint.Hash != link.hash {
+			report.AddIssue(link.seq, domain.IssueCheckpointMismatch,
+				fmt.Sprintf("entry hash differs from checkpoint signed at %s", checkpoint.CreatedAt.Format("2006-01-02 15:04:05")))
+		}
+
+		expectedSeq, prevHash = link.seq+1, link.hash
+	}
+	lastSeq := expectedSeq - 1
+
+	if head != nil {
+		switch {
+		case head.Seq > lastSeq:
+			report.AddIssue(head.Seq, domain.IssueTruncated, fmt.Sprintf("chain head is at seq %d but the last entry is %d", head.Seq, lastSeq))
+		case head.Seq < lastSeq:
+			report.AddIssue(lastSeq, domain.IssueHeadMismatch, fmt.Sprintf("entries continue past the chain head (seq %d)", head.Seq))
+		case head.Hash != prevHash:
+			report.AddIssue(lastSeq, domain.IssueHeadMismatch, "last entry hash differs from the chain head")
+		}
+	}
+
+	for _, checkpoint := range checkpoints {
+		if _, ok := trusted[checkpoint.Seq]; ok && checkpoint.Seq > lastSeq {
+			report.AddIssue(checkpoint.Seq, domain.IssueTruncated,
+				fmt.Sprintf("signed checkpoint at seq %d is past the last entry %d", checkpoint.Seq, lastSeq))
+		}
+	}
+
+	return report, nil
+}
+
+// mergeArchived completa la cadena con las entradas archivadas que ya no están
+// en la tabla. Una entrada presente en ambos lados debe coincidir con su copia.
+func (v *AuditVerifier) mergeArchived(report *domain.ChainReport, links []chainLink) []chainLink {
+	archived := v.archived[report.ChainID]
+	if len(archived) == 0 {
+		return links
+	}
+
+	live := make(map[int64]bool, len(links))
+	for _, link := range links {
+		live[link.seq] = true
+		if entry, ok := archived[link.seq]; ok && entry.Hash != link.hash {
+			report.AddIssue(link.seq, domain.IssueModified, "entry differs from its archived copy")
+		}
+	}
+
+	for seq, entry := range archived {
+		if live[seq] {
+			continue
+		}
+		links = append(links, chainLink{
+			seq:      seq,
+			prevHash: entry.PrevHash,
+			hash:     entry.Hash,
+			computed: entry.ComputeHash(),
+		})
+		report.Archived++
+	}
+
+	sort.SliceStable(links, func(i, j int) bool { return links[i].seq < links[j].seq })
+	return links
+}
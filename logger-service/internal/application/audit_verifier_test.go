@@ -0,0 +1,267 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"logger-service/internal/domain"
+	"testing"
+	"time"
+)
+
+// fakeChainStore es una cadena en memoria para el verificador
+type fakeChainStore struct {
+	head        *domain.ChainHead
+	entries     []*domain.LogEntry
+	checkpoints []*domain.Checkpoint
+}
+
+func (s *fakeChainStore) ListHeads(ctx context.Context, fn func(*domain.ChainHead) error) error {
+	return fn(s.head)
+}
+
+func (s *fakeChainStore) GetHead(ctx context.Context, chainID string) (*domain.ChainHead, error) {
+	return s.head, nil
+}
+
+func (s *fakeChainStore) HeadsPendingCheckpoint(ctx context.Context) ([]*domain.ChainHead, error) {
+	return nil, nil
+}
+
+func (s *fakeChainStore) SaveCheckpoint(ctx context.Context, checkpoint *domain.Checkpoint) error {
+	s.checkpoints = append(s.checkpoints, checkpoint)
+	return nil
+}
+
+func (s *fakeChainStore) ListCheckpoints(ctx context.Context, chainID string) ([]*domain.Checkpoint, error) {
+	return s.checkpoints, nil
+}
+
+func (s *fakeChainStore) ReadChain(ctx context.Context, chainID string, fn func(*domain.LogEntry) error) error {
+	for _, entry := range s.entries {
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fakeSigner "firma" con el payload invertido; solo acepta su propia clave
+type fakeSigner struct{}
+
+func (fakeSigner) KeyID() string { return "test-key" }
+
+func (fakeSigner) Sign(payload []byte) ([]byte, error) {
+	return reversed(payload), nil
+}
+
+func (fakeSigner) Verify(keyID string, payload, signature []byte) bool {
+	return keyID == "test-key" && bytes.Equal(signature, reversed(payload))
+}
+
+func reversed(data []byte) []byte {
+	out := make([]byte, len(data))
+	for i, b := range data {
+		out[len(data)-1-i] = b
+	}
+	return out
+}
+
+// newTestChain encadena n entradas del empleado emp-1 y devuelve el almacén con su cabeza
+func newTestChain(n int) *fakeChainStore {
+	base := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	store := &fakeChainStore{}
+	var head *domain.ChainHead
+	for i := 0; i < n; i++ {
+		entry := &domain.LogEntry{
+			ID:          "entry-" + string(rune('a'+i)),
+			Version:     domain.LogEntryVersion,
+			EventType:   "employee.updated",
+			Action:      "updated",
+			EmployeeID:  "emp-1",
+			Metadata:    map[string]string{"department": "sales"},
+			Timestamp:   base.Add(time.Duration(i) * time.Minute),
+			ProcessedAt: base.Add(time.Duration(i)*time.Minute + time.Second),
+		}
+		entry.Link(head)
+		store.entries = append(store.entries, entry)
+		head = &domain.ChainHead{ChainID: entry.ChainID, Seq: entry.ChainSeq, Hash: entry.Hash}
+	}
+	store.head = head
+	return store
+}
+
+func TestVerifyChain(t *testing.T) {
+	tests := []struct {
+		name string
+		// tamper altera la cadena de 4 entradas antes de verificarla
+		tamper    func(store *fakeChainStore)
+		wantKinds []string
+		wantSeqs  []int64
+	}{
+		{
+			name:   "cadena íntegra",
+			tamper: func(store *fakeChainStore) {},
+		},
+		{
+			name: "contenido modificado",
+			tamper: func(store *fakeChainStore) {
+				store.entries[1].Metadata = map[string]string{"department": "finance"}
+			},
+			wantKinds: []string{domain.IssueModified},
+			wantSeqs:  []int64{2},
+		},
+		{
+			name: "contenido modificado con el hash recalculado",
+			tamper: func(store *fakeChainStore) {
+				store.entries[1].Action = "deleted"
+				store.entries[1].Hash = store.entries[1].ComputeHash()
+			},
+			wantKinds: []string{domain.IssueBrokenLink},
+			wantSeqs:  []int64{3},
+		},
+		{
+			name: "enlace roto",
+			tamper: func(store *fakeChainStore) {
+				entry := store.entries[2]
+				entry.PrevHash = domain.GenesisHash
+				entry.Hash = entry.ComputeHash()
+				store.entries[3].PrevHash = entry.Hash
+				store.entries[3].Hash = store.entries[3].ComputeHash()
+				store.head.Hash = store.entries[3].Hash
+			},
+			wantKinds: []string{domain.IssueBrokenLink},
+			wantSeqs:  []int64{3},
+		},
+		{
+			name: "entrada que enlaza con otra anterior",
+			tamper: func(store *fakeChainStore) {
+				entry := store.entries[2]
+				entry.PrevHash = store.entries[0].Hash
+				entry.Hash = entry.ComputeHash()
+				store.entries[3].PrevHash = entry.Hash
+				store.entries[3].Hash = store.entries[3].ComputeHash()
+				store.head.Hash = store.entries[3].Hash
+			},
+			wantKinds: []string{domain.IssueReordered},
+			wantSeqs:  []int64{3},
+		},
+		{
+			name: "entrada borrada",
+			tamper: func(store *fakeChainStore) {
+				store.entries = append(store.entries[:1], store.entries[2:]...)
+			},
+			wantKinds: []string{domain.IssueGap},
+			wantSeqs:  []int64{3},
+		},
+		{
+			name: "entradas borradas al final",
+			tamper: func(store *fakeChainStore) {
+				store.entries = store.entries[:2]
+			},
+			wantKinds: []string{domain.IssueTruncated},
+			wantSeqs:  []int64{4},
+		},
+		{
+			name: "secuencia repetida",
+			tamper: func(store *fakeChainStore) {
+				duplicate := *store.entries[1]
+				store.entries = append(store.entries[:2], append([]*domain.LogEntry{&duplicate}, store.entries[2:]...)...)
+			},
+			wantKinds: []string{domain.IssueDuplicate},
+			wantSeqs:  []int64{2},
+		},
+		{
+			name: "cabeza distinta de la última entrada",
+			tamper: func(store *fakeChainStore) {
+				store.head.Hash = store.entries[0].Hash
+			},
+			wantKinds: []string{domain.IssueHeadMismatch},
+			wantSeqs:  []int64{4},
+		},
+		{
+			name: "cadena reescrita desde un checkpoint firmado",
+			tamper: func(store *fakeChainStore) {
+				checkpoint := domain.NewCheckpoint(&domain.ChainHead{ChainID: "emp-1", Seq: 2, Hash: store.entries[1].Hash}, time.Now(), "test-key")
+				checkpoint.Signature, _ = fakeSigner{}.Sign(checkpoint.SigningPayload())
+				store.checkpoints = append(store.checkpoints, checkpoint)
+
+				// Se reescribe toda la cadena desde la entrada 2, sin dejar huecos en los enlaces
+				head := &domain.ChainHead{ChainID: "emp-1", Seq: 1, Hash: store.entries[0].Hash}
+				for _, entry := range store.entries[1:] {
+					entry.Action = "rewritten"
+					entry.Link(head)
+					head = &domain.ChainHead{ChainID: entry.ChainID, Seq: entry.ChainSeq, Hash: entry.Hash}
+				}
+				store.head.Hash = head.Hash
+			},
+			wantKinds: []string{domain.IssueCheckpointMismatch},
+			wantSeqs:  []int64{2},
+		},
+		{
+			name: "checkpoint con firma inválida",
+			tamper: func(store *fakeChainStore) {
+				checkpoint := domain.NewCheckpoint(store.head, time.Now(), "test-key")
+				checkpoint.Signature = []byte("forged")
+				store.checkpoints = append(store.checkpoints, checkpoint)
+			},
+			wantKinds: []string{domain.IssueBadSignature},
+			wantSeqs:  []int64{4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestChain(4)
+			tt.tamper(store)
+
+			report, err := NewAuditVerifier(store, fakeSigner{}).VerifyChain(context.Background(), "emp-1")
+			if err != nil {
+				t.Fatalf("VerifyChain() = %v", err)
+			}
+
+			if len(report.Issues) != len(tt.wantKinds) {
+				t.Fatalf("issues = %+v, want kinds %v", report.Issues, tt.wantKinds)
+			}
+			for i, issue := range report.Issues {
+				if issue.Kind != tt.wantKinds[i] || issue.Seq != tt.wantSeqs[i] {
+					t.Errorf("issue %d = %s at seq %d, want %s at seq %d", i, issue.Kind, issue.Seq, tt.wantKinds[i], tt.wantSeqs[i])
+				}
+			}
+			if report.OK() != (len(tt.wantKinds) == 0) {
+				t.Errorf("OK() = %t with issues %+v", report.OK(), report.Issues)
+			}
+		})
+	}
+}
+
+func TestVerifyChainWithArchivedEntries(t *testing.T) {
+	store := newTestChain(4)
+	archived := store.entries[:2]
+	store.entries = store.entries[2:]
+
+	verifier := NewAuditVerifier(store, fakeSigner{})
+	for _, entry := range archived {
+		verifier.IncludeArchived(entry)
+	}
+
+	report, err := verifier.VerifyChain(context.Background(), "emp-1")
+	if err != nil {
+		t.Fatalf("VerifyChain() = %v", err)
+	}
+	if !report.OK() || report.Archived != 2 {
+		t.Fatalf("report = %+v, want a clean chain with 2 archived entries", report)
+	}
+
+	// Una copia archivada alterada no reemplaza a la entrada viva
+	tampered := *store.entries[0]
+	tampered.Action = "deleted"
+	tampered.Hash = tampered.ComputeHash()
+	verifier.IncludeArchived(&tampered)
+
+	report, err = verifier.VerifyChain(context.Background(), "emp-1")
+	if err != nil {
+		t.Fatalf("VerifyChain() = %v", err)
+	}
+	if len(report.Issues) != 1 || report.Issues[0].Kind != domain.IssueModified || report.Issues[0].Seq != 3 {
+		t.Errorf("issues = %+v, want modified at seq 3", report.Issues)
+	}
+}
@@ -0,0 +1,220 @@
+package application
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"logger-service/internal/domain"
+	"logger-service/internal/ports"
+	"time"
+)
+
+// maxEntriesPerArchive limita el tamaño de cada archivo NDJSON
+const maxEntriesPerArchive = 5000
+
+// LogArchiver exporta a archivos NDJSON comprimidos (gzip) las entradas cuya
+// retención venció y solo después les asigna el TTL, así DynamoDB nunca borra
+// una entrada que no esté archivada. Trabaja por días UTC completos: las
+// entradas cuya retención vence hoy se archivan a partir de mañana.
+type LogArchiver struct {
+	repository   ports.LogArchiveRepository
+	store        ports.ObjectStore
+	lookbackDays int
+	interval     time.Duration
+}
+
+// NewLogArchiver crea una nueva instancia del servicio. lookbackDays es cuántos
+// días hacia atrás se revisan en cada pasada (cubre las pasadas perdidas).
+func NewLogArchiver(repo ports.LogArchiveRepository, store ports.ObjectStore, lookbackDays int, interval time.Duration) *LogArchiver {
+	return &LogArchiver{
+		repository:   repo,
+		store:        store,
+		lookbackDays: lookbackDays,
+		interval:     interval,
+	}
+}
+
+// Run archiva al arrancar y después cada interval hasta que se cancele el contexto
+func (a *LogArchiver) Run(ctx context.Context) {
+	log.Printf("Log archiver started (interval: %s, lookback: %d days)", a.interval, a.lookbackDays)
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		if archived, err := a.ArchiveExpired(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			log.Printf("Error archiving expired log entries: %v", err)
+		} else if archived > 0 {
+			log.Printf("Archived %d expired log entries", archived)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ArchiveExpired archiva las entradas cuya retención venció antes de hoy (UTC)
+// y devuelve cuántas archivó. Cada archivo se escribe antes de marcar sus
+// entradas: si la pasada se interrumpe entre ambos pasos, la siguiente las
+// vuelve a archivar en otro archivo (nunca se pierden).
+func (a *LogArchiver) ArchiveExpired(ctx context.Context, now time.Time) (int, error) {
+	today := now.UTC().Truncate(24 * time.Hour)
+	archived := 0
+
+	for day := today.AddDate(0, 0, -a.lookbackDays); day.Before(today); day = day.AddDate(0, 0, 1) {
+		var batch []*domain.LogEntry
+		part := 0
+
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			part++
+			key := archiveKey(day, now, part)
+
+			data, err := encodeArchive(batch)
+			if err != nil {
+				return err
+			}
+			if err := a.store.Put(ctx, key, data); err != nil {
+				return fmt.Errorf("error writing archive %s: %w", key, err)
+			}
+			if err := a.repository.MarkArchived(ctx, batch, key, now); err != nil {
+				return fmt.Errorf("error marking entries of %s as archived: %w", key, err)
+			}
+
+			log.Printf("Archived %d log entries to %s", len(batch), key)
+			archived += len(batch)
+			batch = nil
+			return nil
+		}
+
+		err := a.repository.FindPendingArchive(ctx, day, func(entries []*domain.LogEntry) error {
+			batch = append(batch, entries...)
+			if len(batch) >= maxEntriesPerArchive {
+				return flush()
+			}
+			return nil
+		})
+		if err == nil {
+			err = flush()
+		}
+		if err != nil {
+			return archived, err
+		}
+	}
+
+	return archived, nil
+}
+
+// ListArchives devuelve las claves de los archivos que empiezan por prefix
+// ("" = todos; "2024/05/" = los del mes)
+func (a *LogArchiver) ListArchives(ctx context.Context, prefix string) ([]string, error) {
+	return a.store.List(ctx, prefix)
+}
+
+// ReadArchives recorre las entradas de todos los archivos que empiezan por prefix
+func (a *LogArchiver) ReadArchives(ctx context.Context, prefix string, fn func(key string, entry *domain.LogEntry) error) error {
+	keys, err := a.store.List(ctx, prefix)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		data, err := a.store.Get(ctx, key)
+		if err != nil {
+			return fmt.Errorf("error reading archive %s: %w", key, err)
+		}
+		err = decodeArchive(data, func(entry *domain.LogEntry) error {
+			return fn(key, entry)
+		})
+		if err != nil {
+			return fmt.Errorf("error decoding archive %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// Restore vuelve a importar las entradas de un archivo que ya no estén en la
+// tabla. Con retainFor > 0 las entradas restauradas vuelven a vencer (sin
+// archivarse de nuevo) pasado ese tiempo; con 0 se conservan indefinidamente.
+func (a *LogArchiver) Restore(ctx context.Context, key string, retainFor time.Duration) (restored, skipped int, err error) {
+	data, err := a.store.Get(ctx, key)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var expiresAt time.Time
+	if retainFor > 0 {
+		expiresAt = time.Now().Add(retainFor)
+	}
+
+	err = decodeArchive(data, func(entry *domain.LogEntry) error {
+		inserted, err := a.repository.Restore(ctx, entry, key, expiresAt)
+		if err != nil {
+			return fmt.Errorf("error restoring log entry %s: %w", entry.ID, err)
+		}
+		if inserted {
+			restored++
+		} else {
+			skipped++
+		}
+		return nil
+	})
+	return restored, skipped, err
+}
+
+// archiveKey construye la clave de un archivo: el día en que venció la
+// retención de sus entradas, la hora de la pasada y el número de parte
+// (p.ej. "2024/05/31/20240601T030000Z-001.ndjson.gz")
+func archiveKey(day, now time.Time, part int) string {
+	return fmt.Sprintf("%s/%s-%03d.ndjson.gz", day.Format("2006/01/02"), now.UTC().Format("20060102T150405Z"), part)
+}
+
+// encodeArchive serializa las entradas como NDJSON comprimido con gzip
+func encodeArchive(entries []*domain.LogEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	encoder := json.NewEncoder(writer)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeArchive recorre las entradas de un archivo NDJSON comprimido
+func decodeArchive(data []byte, fn func(*domain.LogEntry) error) error {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var entry domain.LogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return err
+		}
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
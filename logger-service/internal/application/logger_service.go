@@ -6,6 +6,9 @@ import (
 	"log"
 	"logger-service/internal/domain"
 	"logger-service/internal/ports"
+	"pkg/dedup"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,64 +16,198 @@ import (
 
 // LoggerService implementa la lógica de negocio para el logger
 type LoggerService struct {
-	repository ports.LogRepository
-	consumer   ports.EventConsumer
+	repository  ports.LogRepository
+	consumer    ports.EventConsumer
+	dedup       ports.DeduplicationStore
+	broadcaster ports.LogBroadcaster
+	stats       ports.ActivityStatsRepository
+	alerts      ports.AlertEvaluator
+	retention   domain.RetentionPolicy
+	protector   *PIIProtector
+	redactor    *domain.Redactor
+	projections *domain.Projections
 }
 
-// NewLoggerService crea una nueva instancia del servicio
-func NewLoggerService(repo ports.LogRepository, consumer ports.EventConsumer) *LoggerService {
+// NewLoggerService crea una nueva instancia del servicio. alerts puede ser
+// nil: entonces no se evalúan reglas de alerta. protector también: entonces
+// los datos personales se guardan en claro. redactor oculta los datos
+// personales en la consola y el stream en vivo.
+func NewLoggerService(repo ports.LogRepository, consumer ports.EventConsumer, dedupStore ports.DeduplicationStore, broadcaster ports.LogBroadcaster, stats ports.ActivityStatsRepository, alerts ports.AlertEvaluator, retention domain.RetentionPolicy, protector *PIIProtector, redactor *domain.Redactor) *LoggerService {
 	return &LoggerService{
-		repository: repo,
-		consumer:   consumer,
+		repository:  repo,
+		consumer:    consumer,
+		dedup:       dedupStore,
+		broadcaster: broadcaster,
+		stats:       stats,
+		alerts:      alerts,
+		retention:   retention,
+		protector:   protector,
+		redactor:    redactor,
+		projections: domain.DefaultProjections(),
 	}
 }
 
-// ProcessEvent procesa un evento de empleado
-func (s *LoggerService) ProcessEvent(ctx context.Context, event *domain.EmployeeEvent) error {
-	// Parsear el timestamp del evento
-	timestamp, err := time.Parse(time.RFC3339, event.Timestamp)
+// ProcessEvent procesa un evento una sola vez: las entregas duplicadas del
+// mismo evento (mismo ID) se descartan
+func (s *LoggerService) ProcessEvent(ctx context.Context, event *domain.Event) error {
+	return dedup.Once(ctx, s.dedup, event.ID, func(ctx context.Context) error {
+		return s.logEvent(ctx, event)
+	})
+}
+
+// logEvent proyecta el evento en su entrada de log y la guarda, muestra,
+// difunde, cuenta en las estadísticas de actividad y evalúa con las reglas de alerta
+func (s *LoggerService) logEvent(ctx context.Context, event *domain.Event) error {
+	logEntry, err := s.projections.Project(event)
 	if err != nil {
-		timestamp = time.Now()
+		return fmt.Errorf("error projecting event %s: %w", event.ID, err)
 	}
-
-	// Crear entrada de log
-	logEntry := domain.NewLogEntry(
-		event.EventType,
-		event.Employee.ID,
-		event.Employee.Name,
-		event.Employee.Email,
-		timestamp,
-	)
 	logEntry.ID = uuid.New().String()
+	s.retention.Apply(logEntry)
 
-	// Guardar en la base de datos
-	if err := s.repository.Save(ctx, logEntry); err != nil {
+	// Guardar en la base de datos, con los datos personales cifrados. La
+	// entrada en claro sigue sirviendo para las estadísticas y las alertas.
+	stored := logEntry
+	if s.protector != nil {
+		if stored, err = s.protector.Encrypt(ctx, logEntry); err != nil {
+			return fmt.Errorf("error encrypting log entry: %w", err)
+		}
+	}
+	if err := s.repository.Save(ctx, stored); err != nil {
 		return fmt.Errorf("error saving log entry: %w", err)
 	}
+	logEntry.ChainID, logEntry.ChainSeq = stored.ChainID, stored.ChainSeq
+	logEntry.PrevHash, logEntry.Hash = stored.PrevHash, stored.Hash
 
-	// Mostrar en consola
-	s.displayEventInfo(logEntry)
+	// Mostrar en consola y difundir a los clientes del stream en vivo, con
+	// los datos personales ocultos
+	redacted := s.redactor.Entry(logEntry)
+	s.displayEventInfo(redacted)
+	s.broadcaster.Broadcast(redacted)
+
+	// La entrada ya está guardada: si el contador fallara y se devolviera el
+	// error, el reintento del mensaje la registraría dos veces. Se deja constancia
+	// y la estadística de ese evento se pierde.
+	if err := s.stats.Increment(ctx, logEntry.EventType, logEntry.Timestamp); err != nil {
+		log.Printf("Error updating activity stats for event %s: %v", event.ID, err)
+	}
+
+	if s.alerts != nil {
+		s.alerts.Evaluate(logEntry)
+	}
 
 	return nil
 }
 
-// displayEventInfo muestra la información del evento en consola
+// displayEventInfo muestra la información del evento en consola. El bloque se
+// escribe en una sola llamada para no mezclarse con el de otros workers.
 func (s *LoggerService) displayEventInfo(entry *domain.LogEntry) {
-	log.Println("========================================")
-	log.Printf("EVENTO RECIBIDO: %s", entry.EventType)
-	log.Printf("ID Empleado: %s", entry.EmployeeID)
-	log.Printf("Nombre: %s", entry.Name)
-	log.Printf("Email: %s", entry.Email)
-	log.Printf("Timestamp del evento: %s", entry.Timestamp.Format("2006-01-02 15:04:05"))
-	log.Printf("Procesado el: %s", entry.ProcessedAt.Format("2006-01-02 15:04:05"))
-	log.Println("========================================")
+	var b strings.Builder
+	b.WriteString("========================================\n")
+	fmt.Fprintf(&b, "EVENTO RECIBIDO: %s\n", entry.EventType)
+	if entry.Source != "" {
+		fmt.Fprintf(&b, "Origen: %s\n", entry.Source)
+	}
+	fmt.Fprintf(&b, "Acción: %s\n", entry.Action)
+	fmt.Fprintf(&b, "Sujeto: %s\n", formatReference(entry.Subject))
+	if entry.EmployeeID != "" && entry.EmployeeID != entry.Subject.ID {
+		fmt.Fprintf(&b, "ID Empleado: %s\n", entry.EmployeeID)
+	}
+
+	keys := make([]string, 0, len(entry.Metadata))
+	for key := range entry.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "  %s: %s\n", key, entry.Metadata[key])
+	}
+
+	fmt.Fprintf(&b, "Timestamp del evento: %s\n", entry.Timestamp.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&b, "Procesado el: %s\n", entry.ProcessedAt.Format("2006-01-02 15:04:05"))
+	b.WriteString("========================================")
+	log.Print(b.String())
+}
+
+// formatReference muestra una referencia como "tipo id" (solo el id si no tiene tipo)
+func formatReference(ref domain.Reference) string {
+	if ref.Type == "" {
+		return ref.ID
+	}
+	return ref.Type + " " + ref.ID
+}
+
+// ListLogs obtiene una página de entradas de log filtradas. limit 0 usa el
+// tamaño de página por defecto. Con el cifrado activo, los datos personales
+// solo se descifran si reader (el usuario que lo pide) está autorizado; sin
+// reader se devuelven ocultos.
+func (s *LoggerService) ListLogs(ctx context.Context, filter domain.LogFilter, cursor string, limit int, reader string) (*domain.LogPage, error) {
+	if limit == 0 {
+		limit = domain.DefaultPageSize
+	}
+	if limit < 0 || limit > domain.MaxPageSize {
+		return nil, domain.ErrInvalidPageSize
+	}
+	if err := filter.Normalize(time.Now()); err != nil {
+		return nil, err
+	}
+	if reader != "" && s.protector != nil {
+		if err := s.protector.Authorize(reader); err != nil {
+			return nil, err
+		}
+	}
+
+	page, err := s.repository.FindPage(ctx, filter, cursor, limit)
+	if err != nil || s.protector == nil {
+		return page, err
+	}
+
+	if reader == "" {
+		for _, entry := range page.Entries {
+			s.protector.Redact(entry)
+		}
+		return page, nil
+	}
+
+	// Una entrada que no se puede descifrar (clave maestra borrada o
+	// inaccesible) se devuelve oculta sin impedir la consulta
+	for _, entry := range page.Entries {
+		if err := s.protector.Decrypt(ctx, entry); err != nil {
+			log.Printf("Error decrypting log entry %s: %v", entry.ID, err)
+			s.protector.Redact(entry)
+		}
+	}
+	log.Printf("Personal data of %d log entries decrypted for user %s", len(page.Entries), reader)
+	return page, nil
+}
+
+// ActivityStats obtiene la serie temporal de eventos de cada tipo pedido, con
+// un punto por intervalo del rango (los intervalos sin eventos valen 0)
+func (s *LoggerService) ActivityStats(ctx context.Context, query domain.ActivityQuery) (*domain.ActivityStats, error) {
+	if err := query.Normalize(time.Now()); err != nil {
+		return nil, err
+	}
+
+	from, to := query.CounterRange()
+	stats := &domain.ActivityStats{
+		Bucket: query.Bucket,
+		From:   query.From,
+		To:     query.To,
+		Series: make([]domain.ActivitySeries, 0, len(query.EventTypes)),
+	}
+	for _, eventType := range query.EventTypes {
+		counters, err := s.stats.FindCounters(ctx, eventType, query.Bucket.CounterSize(), from, to)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s activity: %w", eventType, err)
+		}
+		stats.Series = append(stats.Series, query.BuildSeries(eventType, counters))
+	}
+	return stats, nil
 }
 
 // StartConsuming inicia el consumo de eventos
 func (s *LoggerService) StartConsuming(ctx context.Context) error {
 	log.Println("Logger service started consuming events...")
 
-	return s.consumer.ConsumeEvents(ctx, func(event *domain.EmployeeEvent) error {
-		return s.ProcessEvent(ctx, event)
-	})
+	return s.consumer.ConsumeEvents(ctx, s.ProcessEvent)
 }
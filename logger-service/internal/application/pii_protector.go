@@ -0,0 +1,125 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"logger-service/internal/domain"
+	"logger-service/internal/ports"
+	"sync"
+	"time"
+)
+
+// dataKeyLifetime es el tiempo durante el que se reutiliza una clave de datos
+// para cifrar entradas nuevas; así no se llama al KeyProvider por cada
+// entrada y una página de la API comparte pocas claves de datos
+const dataKeyLifetime = 5 * time.Minute
+
+// maxCachedDataKeys acota las claves de datos descifradas que se conservan en memoria
+const maxCachedDataKeys = 256
+
+// PIIProtector cifra los datos personales de las entradas antes de guardarlas
+// y los descifra para los lectores autorizados (IDs de usuario de readers)
+type PIIProtector struct {
+	keys    ports.KeyProvider
+	fields  []string
+	readers map[string]bool
+
+	mu        sync.Mutex
+	current   *domain.DataKey
+	expiresAt time.Time
+	decrypted map[string][]byte // clave de datos cifrada (base64) → en claro
+}
+
+// NewPIIProtector crea el protector de las claves fields
+func NewPIIProtector(keys ports.KeyProvider, fields []string, readers []string) *PIIProtector {
+	allowed := make(map[string]bool, len(readers))
+	for _, reader := range readers {
+		allowed[reader] = true
+	}
+	return &PIIProtector{
+		keys:      keys,
+		fields:    fields,
+		readers:   allowed,
+		decrypted: make(map[string][]byte),
+	}
+}
+
+// Encrypt devuelve una copia de la entrada con sus datos personales cifrados
+func (p *PIIProtector) Encrypt(ctx context.Context, entry *domain.LogEntry) (*domain.LogEntry, error) {
+	key, err := p.dataKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error generating data key: %w", err)
+	}
+	return entry.EncryptPII(key, p.fields)
+}
+
+// Authorize comprueba que el usuario pueda leer datos personales
+func (p *PIIProtector) Authorize(userID string) error {
+	if userID == "" || !p.readers[userID] {
+		return domain.ErrPIIAccessDenied
+	}
+	return nil
+}
+
+// Decrypt descifra en la entrada sus datos personales
+func (p *PIIProtector) Decrypt(ctx context.Context, entry *domain.LogEntry) error {
+	if entry.Encryption == nil {
+		return nil
+	}
+	key, err := p.decryptDataKey(ctx, entry.Encryption)
+	if err != nil {
+		return err
+	}
+	return entry.DecryptPII(key)
+}
+
+// Redact oculta los datos personales de la entrada a quien no puede leerlos
+func (p *PIIProtector) Redact(entry *domain.LogEntry) {
+	entry.RedactPII(p.fields)
+}
+
+// dataKey devuelve la clave de datos vigente, generando otra si caducó
+func (p *PIIProtector) dataKey(ctx context.Context) (*domain.DataKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.current != nil && time.Now().Before(p.expiresAt) {
+		return p.current, nil
+	}
+	key, err := p.keys.GenerateDataKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.current = key
+	p.expiresAt = time.Now().Add(dataKeyLifetime)
+	return key, nil
+}
+
+// decryptDataKey descifra la clave de datos de una entrada con el KeyProvider,
+// o la toma de la caché si ya se descifró
+func (p *PIIProtector) decryptDataKey(ctx context.Context, encryption *domain.EntryEncryption) ([]byte, error) {
+	cacheKey := encryption.KeyID + "/" + encryption.EncryptedKey
+
+	p.mu.Lock()
+	key, ok := p.decrypted[cacheKey]
+	p.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	encrypted, err := encryption.EncryptedDataKey()
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed data key", domain.ErrPIIDecryption)
+	}
+	if key, err = p.keys.DecryptDataKey(ctx, encryption.KeyID, encrypted); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	if len(p.decrypted) >= maxCachedDataKeys {
+		p.decrypted = make(map[string][]byte)
+	}
+	p.decrypted[cacheKey] = key
+	p.mu.Unlock()
+	return key, nil
+}
@@ -0,0 +1,238 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"logger-service/internal/domain"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeKeyProvider envuelve las claves de datos con XOR sobre una clave maestra
+// fija y cuenta las llamadas
+type fakeKeyProvider struct {
+	master    []byte
+	generated int
+	decrypted int
+}
+
+func newFakeKeyProvider() *fakeKeyProvider {
+	master := make([]byte, 32)
+	for i := range master {
+		master[i] = byte(i * 7)
+	}
+	return &fakeKeyProvider{master: master}
+}
+
+func (p *fakeKeyProvider) GenerateDataKey(ctx context.Context) (*domain.DataKey, error) {
+	p.generated++
+	plaintext := make([]byte, 32)
+	if _, err := rand.Read(plaintext); err != nil {
+		return nil, err
+	}
+	return &domain.DataKey{KeyID: "test-master", Plaintext: plaintext, Encrypted: p.xor(plaintext)}, nil
+}
+
+func (p *fakeKeyProvider) DecryptDataKey(ctx context.Context, keyID string, encrypted []byte) ([]byte, error) {
+	p.decrypted++
+	if keyID != "test-master" {
+		return nil, errors.New("unknown master key")
+	}
+	return p.xor(encrypted), nil
+}
+
+func (p *fakeKeyProvider) xor(key []byte) []byte {
+	out := make([]byte, len(key))
+	for i := range key {
+		out[i] = key[i] ^ p.master[i]
+	}
+	return out
+}
+
+func newPIIEntry(id string) *domain.LogEntry {
+	return &domain.LogEntry{
+		ID:        id,
+		Version:   domain.LogEntryVersion,
+		EventType: "employee.created",
+		Metadata: map[string]string{
+			"name":       "Juan Pérez",
+			"email":      "juan.perez@example.com",
+			"department": "sales",
+		},
+		Payload:   json.RawMessage(`{"employee":{"name":"Juan Pérez","email":"juan.perez@example.com"}}`),
+		Timestamp: time.Date(2026, 6, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestPIIProtectorRoundTrip(t *testing.T) {
+	keys := newFakeKeyProvider()
+	protector := NewPIIProtector(keys, []string{"name", "email"}, []string{"auditor"})
+	ctx := context.Background()
+
+	original := newPIIEntry("entry-1")
+	encrypted, err := protector.Encrypt(ctx, original)
+	if err != nil {
+		t.Fatalf("Encrypt() = %v", err)
+	}
+
+	if !reflect.DeepEqual(original, newPIIEntry("entry-1")) {
+		t.Error("Encrypt() modified the original entry")
+	}
+	for _, name := range []string{"name", "email"} {
+		if !strings.HasPrefix(encrypted.Metadata[name], "enc:v1:") {
+			t.Errorf("metadata %s = %q, want an encrypted value", name, encrypted.Metadata[name])
+		}
+	}
+	if encrypted.Metadata["department"] != "sales" {
+		t.Errorf("non-personal metadata = %q, want it in clear text", encrypted.Metadata["department"])
+	}
+	if strings.Contains(string(encrypted.Payload), "juan.perez") {
+		t.Errorf("payload = %s, want it encrypted", encrypted.Payload)
+	}
+	if want := []string{"metadata.email", "metadata.name", "payload"}; encrypted.Encryption == nil || !reflect.DeepEqual(encrypted.Encryption.Fields, want) {
+		t.Fatalf("encryption = %+v, want fields %v", encrypted.Encryption, want)
+	}
+
+	// El hash de la cadena se calcula sobre la entrada cifrada y se conserva
+	hash := encrypted.ComputeHash()
+
+	stored := *encrypted
+	if err := protector.Decrypt(ctx, &stored); err != nil {
+		t.Fatalf("Decrypt() = %v", err)
+	}
+	if !reflect.DeepEqual(stored.Metadata, original.Metadata) || string(stored.Payload) != string(original.Payload) || stored.Encryption != nil {
+		t.Errorf("decrypted entry = %+v, want %+v", stored, original)
+	}
+	if encrypted.ComputeHash() != hash {
+		t.Error("Decrypt() modified the stored (encrypted) entry")
+	}
+}
+
+func TestPIIProtectorRejectsTamperedEntries(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(entry *domain.LogEntry, other *domain.LogEntry)
+	}{
+		{
+			name: "valor movido a otra entrada",
+			tamper: func(entry, other *domain.LogEntry) {
+				entry.Metadata["email"] = other.Metadata["email"]
+			},
+		},
+		{
+			name: "entrada con otro ID",
+			tamper: func(entry, other *domain.LogEntry) {
+				entry.ID = "entry-forged"
+			},
+		},
+		{
+			name: "valores intercambiados entre campos",
+			tamper: func(entry, other *domain.LogEntry) {
+				entry.Metadata["name"], entry.Metadata["email"] = entry.Metadata["email"], entry.Metadata["name"]
+			},
+		},
+		{
+			name: "payload movido a un metadato",
+			tamper: func(entry, other *domain.LogEntry) {
+				var sealed string
+				json.Unmarshal(entry.Payload, &sealed)
+				entry.Metadata["name"] = sealed
+			},
+		},
+		{
+			name: "texto cifrado alterado",
+			tamper: func(entry, other *domain.LogEntry) {
+				sealed, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(entry.Metadata["name"], "enc:v1:"))
+				sealed[len(sealed)-1] ^= 0x01
+				entry.Metadata["name"] = "enc:v1:" + base64.StdEncoding.EncodeToString(sealed)
+			},
+		},
+		{
+			name: "valor en claro en un campo cifrado",
+			tamper: func(entry, other *domain.LogEntry) {
+				entry.Metadata["name"] = "Juan Pérez"
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			protector := NewPIIProtector(newFakeKeyProvider(), []string{"name", "email"}, nil)
+			ctx := context.Background()
+
+			entry, err := protector.Encrypt(ctx, newPIIEntry("entry-1"))
+			if err != nil {
+				t.Fatalf("Encrypt() = %v", err)
+			}
+			other, err := protector.Encrypt(ctx, newPIIEntry("entry-2"))
+			if err != nil {
+				t.Fatalf("Encrypt() = %v", err)
+			}
+
+			tt.tamper(entry, other)
+			if err := protector.Decrypt(ctx, entry); !errors.Is(err, domain.ErrPIIDecryption) {
+				t.Errorf("Decrypt() = %v, want %v", err, domain.ErrPIIDecryption)
+			}
+		})
+	}
+}
+
+func TestPIIProtectorReusesDataKeys(t *testing.T) {
+	keys := newFakeKeyProvider()
+	protector := NewPIIProtector(keys, []string{"name", "email"}, nil)
+	ctx := context.Background()
+
+	var entries []*domain.LogEntry
+	for _, id := range []string{"entry-1", "entry-2", "entry-3"} {
+		entry, err := protector.Encrypt(ctx, newPIIEntry(id))
+		if err != nil {
+			t.Fatalf("Encrypt(%s) = %v", id, err)
+		}
+		entries = append(entries, entry)
+	}
+	if keys.generated != 1 {
+		t.Errorf("GenerateDataKey called %d times, want 1 while the data key is current", keys.generated)
+	}
+
+	for _, entry := range entries {
+		if err := protector.Decrypt(ctx, entry); err != nil {
+			t.Fatalf("Decrypt(%s) = %v", entry.ID, err)
+		}
+	}
+	if keys.decrypted != 1 {
+		t.Errorf("DecryptDataKey called %d times, want 1 with the decrypted key cached", keys.decrypted)
+	}
+
+	// Una clave de datos de otra clave maestra no se puede descifrar
+	entry, _ := protector.Encrypt(ctx, newPIIEntry("entry-4"))
+	entry.Encryption.KeyID = "retired-master"
+	if err := NewPIIProtector(keys, nil, nil).Decrypt(ctx, entry); err == nil {
+		t.Error("Decrypt() with an unknown master key succeeded")
+	}
+}
+
+func TestPIIProtectorAuthorizeAndRedact(t *testing.T) {
+	protector := NewPIIProtector(newFakeKeyProvider(), []string{"name", "email"}, []string{"auditor"})
+
+	for reader, wantErr := range map[string]error{"auditor": nil, "employee-1": domain.ErrPIIAccessDenied, "": domain.ErrPIIAccessDenied} {
+		if err := protector.Authorize(reader); !errors.Is(err, wantErr) {
+			t.Errorf("Authorize(%q) = %v, want %v", reader, err, wantErr)
+		}
+	}
+
+	entry, err := protector.Encrypt(context.Background(), newPIIEntry("entry-1"))
+	if err != nil {
+		t.Fatalf("Encrypt() = %v", err)
+	}
+	protector.Redact(entry)
+	if entry.Metadata["name"] != domain.RedactedValue || entry.Metadata["email"] != domain.RedactedValue {
+		t.Errorf("redacted metadata = %v", entry.Metadata)
+	}
+	if entry.Metadata["department"] != "sales" || entry.Payload != nil {
+		t.Errorf("redacted entry = %+v, want department kept and payload omitted", entry)
+	}
+}
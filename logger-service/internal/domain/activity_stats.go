@@ -0,0 +1,204 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BucketSize es el tamaño de los intervalos de una serie de actividad. Solo se
+// guardan contadores por hora y por día (ver CounterSize): las semanas
+// (de lunes a domingo) y los meses se calculan sumando los días.
+type BucketSize string
+
+const (
+	BucketHour  BucketSize = "hour"
+	BucketDay   BucketSize = "day"
+	BucketWeek  BucketSize = "week"
+	BucketMonth BucketSize = "month"
+)
+
+// Límites de la consulta de actividad
+const (
+	MaxActivitySeries  = 10   // tipos de evento por consulta
+	MaxActivityBuckets = 1000 // intervalos por serie
+)
+
+// defaultActivityBuckets es el número de intervalos que se devuelven cuando la
+// consulta no indica from
+var defaultActivityBuckets = map[BucketSize]int{
+	BucketHour:  24,
+	BucketDay:   30,
+	BucketWeek:  12,
+	BucketMonth: 12,
+}
+
+// ParseBucketSize interpreta el tamaño de intervalo ("" = day)
+func ParseBucketSize(value string) (BucketSize, error) {
+	switch size := BucketSize(value); size {
+	case "":
+		return BucketDay, nil
+	case BucketHour, BucketDay, BucketWeek, BucketMonth:
+		return size, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidBucketSize, value)
+	}
+}
+
+// CounterSize es la granularidad de los contadores con los que se calcula el
+// intervalo: por hora para BucketHour y por día para el resto
+func (b BucketSize) CounterSize() BucketSize {
+	if b == BucketHour {
+		return BucketHour
+	}
+	return BucketDay
+}
+
+// Start devuelve el inicio (UTC) del intervalo que contiene t
+func (b BucketSize) Start(t time.Time) time.Time {
+	t = t.UTC()
+	switch b {
+	case BucketHour:
+		return t.Truncate(time.Hour)
+	case BucketWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case BucketMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// Next devuelve el inicio del intervalo siguiente al que empieza en start
+func (b BucketSize) Next(start time.Time) time.Time {
+	switch b {
+	case BucketHour:
+		return start.Add(time.Hour)
+	case BucketWeek:
+		return start.AddDate(0, 0, 7)
+	case BucketMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// ActivityCounter es el número de eventos de un tipo en una hora o un día (UTC)
+type ActivityCounter struct {
+	EventType string
+	Size      BucketSize // BucketHour o BucketDay
+	Start     time.Time
+	Count     int64
+}
+
+// ActivityQuery son los criterios de una consulta de actividad. From y To
+// acotan los intervalos (ambos inclusive): se devuelven todos los intervalos
+// que contienen algún instante del rango.
+type ActivityQuery struct {
+	EventTypes []string
+	Bucket     BucketSize
+	From       time.Time
+	To         time.Time
+}
+
+// Normalize valida la consulta, completa el rango (To vacío es now y From
+// vacío cubre los últimos intervalos por defecto) y lo alinea al inicio de
+// sus intervalos
+func (q *ActivityQuery) Normalize(now time.Time) error {
+	types := make([]string, 0, len(q.EventTypes))
+	seen := make(map[string]bool)
+	for _, eventType := range q.EventTypes {
+		eventType = strings.TrimSpace(eventType)
+		if eventType == "" || seen[eventType] {
+			continue
+		}
+		// Los contadores son por tipo exacto: no admiten prefijos
+		if strings.HasSuffix(eventType, "*") {
+			return fmt.Errorf("%w: %q", ErrInvalidActivityQuery, eventType)
+		}
+		seen[eventType] = true
+		types = append(types, eventType)
+	}
+	if len(types) == 0 || len(types) > MaxActivitySeries {
+		return ErrInvalidActivityQuery
+	}
+	q.EventTypes = types
+
+	if q.Bucket == "" {
+		q.Bucket = BucketDay
+	}
+	if q.To.IsZero() {
+		q.To = now
+	}
+	q.To = q.Bucket.Start(q.To)
+	if q.From.IsZero() {
+		// Se retrocede intervalo a intervalo porque semanas y meses no tienen
+		// una duración fija
+		q.From = q.To
+		for i := 1; i < defaultActivityBuckets[q.Bucket]; i++ {
+			q.From = q.Bucket.Start(q.From.Add(-time.Nanosecond))
+		}
+	}
+	q.From = q.Bucket.Start(q.From)
+	if q.From.After(q.To) {
+		return ErrInvalidTimeRange
+	}
+
+	buckets := 0
+	for start := q.From; !start.After(q.To); start = q.Bucket.Next(start) {
+		if buckets++; buckets > MaxActivityBuckets {
+			return ErrTooManyBuckets
+		}
+	}
+	return nil
+}
+
+// CounterRange devuelve el inicio del primer y del último contador que
+// alimentan la consulta (ambos inclusive)
+func (q *ActivityQuery) CounterRange() (time.Time, time.Time) {
+	size := q.Bucket.CounterSize()
+	last := q.Bucket.Next(q.To).Add(-time.Nanosecond)
+	return size.Start(q.From), size.Start(last)
+}
+
+// ActivityPoint es el número de eventos de un intervalo
+type ActivityPoint struct {
+	Start time.Time `json:"start"`
+	Count int64     `json:"count"`
+}
+
+// ActivitySeries es la serie temporal de un tipo de evento, con un punto por
+// intervalo del rango (los intervalos sin eventos valen 0)
+type ActivitySeries struct {
+	EventType string          `json:"event_type"`
+	Total     int64           `json:"total"`
+	Points    []ActivityPoint `json:"points"`
+}
+
+// ActivityStats es la respuesta de una consulta de actividad
+type ActivityStats struct {
+	Bucket BucketSize       `json:"bucket"`
+	From   time.Time        `json:"from"`
+	To     time.Time        `json:"to"` // inicio del último intervalo
+	Series []ActivitySeries `json:"series"`
+}
+
+// BuildSeries suma los contadores de un tipo de evento en los intervalos de
+// la consulta (ya normalizada)
+func (q *ActivityQuery) BuildSeries(eventType string, counters []ActivityCounter) ActivitySeries {
+	series := ActivitySeries{EventType: eventType, Points: []ActivityPoint{}}
+	index := make(map[time.Time]int)
+	for start := q.From; !start.After(q.To); start = q.Bucket.Next(start) {
+		index[start] = len(series.Points)
+		series.Points = append(series.Points, ActivityPoint{Start: start})
+	}
+
+	for _, counter := range counters {
+		if i, ok := index[q.Bucket.Start(counter.Start)]; ok {
+			series.Points[i].Count += counter.Count
+			series.Total += counter.Count
+		}
+	}
+	return series
+}
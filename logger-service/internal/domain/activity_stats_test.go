@@ -0,0 +1,262 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestActivityQueryNormalize(t *testing.T) {
+	// Miércoles: la semana empieza el lunes 16
+	now := time.Date(2026, 3, 18, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		query     ActivityQuery
+		wantFrom  time.Time
+		wantTo    time.Time
+		wantTypes []string
+		wantError error
+	}{
+		{
+			name:      "semanas por defecto",
+			query:     ActivityQuery{EventTypes: []string{"employee.created"}, Bucket: BucketWeek},
+			wantFrom:  date(2025, 12, 29),
+			wantTo:    date(2026, 3, 16),
+			wantTypes: []string{"employee.created"},
+		},
+		{
+			name:      "meses por defecto",
+			query:     ActivityQuery{EventTypes: []string{"employee.created"}, Bucket: BucketMonth},
+			wantFrom:  date(2025, 4, 1),
+			wantTo:    date(2026, 3, 1),
+			wantTypes: []string{"employee.created"},
+		},
+		{
+			name: "semana alineada al lunes",
+			query: ActivityQuery{
+				EventTypes: []string{"employee.created"},
+				Bucket:     BucketWeek,
+				From:       date(2026, 3, 1), // domingo
+				To:         time.Date(2026, 3, 22, 23, 59, 0, 0, time.UTC),
+			},
+			wantFrom:  date(2026, 2, 23),
+			wantTo:    date(2026, 3, 16),
+			wantTypes: []string{"employee.created"},
+		},
+		{
+			name: "mes alineado al día 1",
+			query: ActivityQuery{
+				EventTypes: []string{"employee.created"},
+				Bucket:     BucketMonth,
+				From:       date(2026, 1, 31),
+				To:         date(2026, 3, 2),
+			},
+			wantFrom:  date(2026, 1, 1),
+			wantTo:    date(2026, 3, 1),
+			wantTypes: []string{"employee.created"},
+		},
+		{
+			name: "from y to en la misma semana",
+			query: ActivityQuery{
+				EventTypes: []string{"employee.created"},
+				Bucket:     BucketWeek,
+				From:       date(2026, 3, 18),
+				To:         date(2026, 3, 17),
+			},
+			wantFrom:  date(2026, 3, 16),
+			wantTo:    date(2026, 3, 16),
+			wantTypes: []string{"employee.created"},
+		},
+		{
+			name:      "tipos repetidos y vacíos",
+			query:     ActivityQuery{EventTypes: []string{" employee.created ", "", "message.sent", "employee.created"}, Bucket: BucketDay},
+			wantFrom:  date(2026, 2, 17),
+			wantTo:    date(2026, 3, 18),
+			wantTypes: []string{"employee.created", "message.sent"},
+		},
+
+		{
+			name: "from posterior a to",
+			query: ActivityQuery{
+				EventTypes: []string{"employee.created"},
+				Bucket:     BucketMonth,
+				From:       date(2026, 4, 1),
+				To:         date(2026, 3, 31),
+			},
+			wantError: ErrInvalidTimeRange,
+		},
+		{
+			name:      "prefijo de tipo",
+			query:     ActivityQuery{EventTypes: []string{"employee.*"}, Bucket: BucketWeek},
+			wantError: ErrInvalidActivityQuery,
+		},
+		{
+			name:      "sin tipos",
+			query:     ActivityQuery{EventTypes: []string{""}, Bucket: BucketWeek},
+			wantError: ErrInvalidActivityQuery,
+		},
+		{
+			name:      "demasiados tipos",
+			query:     ActivityQuery{EventTypes: []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"}, Bucket: BucketWeek},
+			wantError: ErrInvalidActivityQuery,
+		},
+		{
+			name: "demasiados intervalos",
+			query: ActivityQuery{
+				EventTypes: []string{"employee.created"},
+				Bucket:     BucketHour,
+				From:       date(2026, 1, 1),
+				To:         date(2026, 3, 1),
+			},
+			wantError: ErrTooManyBuckets,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := tt.query
+			err := query.Normalize(now)
+			if !errors.Is(err, tt.wantError) {
+				t.Fatalf("Normalize() = %v, want %v", err, tt.wantError)
+			}
+			if tt.wantError != nil {
+				return
+			}
+
+			if !query.From.Equal(tt.wantFrom) || !query.To.Equal(tt.wantTo) {
+				t.Errorf("range = %s..%s, want %s..%s", query.From, query.To, tt.wantFrom, tt.wantTo)
+			}
+			if len(query.EventTypes) != len(tt.wantTypes) {
+				t.Fatalf("event types = %q, want %q", query.EventTypes, tt.wantTypes)
+			}
+			for i := range tt.wantTypes {
+				if query.EventTypes[i] != tt.wantTypes[i] {
+					t.Errorf("event types = %q, want %q", query.EventTypes, tt.wantTypes)
+				}
+			}
+		})
+	}
+}
+
+func TestActivityQueryBuildSeries(t *testing.T) {
+	day := func(d time.Time, count int64) ActivityCounter {
+		return ActivityCounter{EventType: "employee.created", Size: BucketDay, Start: d, Count: count}
+	}
+	hour := func(h int, count int64) ActivityCounter {
+		return ActivityCounter{EventType: "employee.created", Size: BucketHour, Start: date(2026, 3, 18).Add(time.Duration(h) * time.Hour), Count: count}
+	}
+	at := func(h int) time.Time { return date(2026, 3, 18).Add(time.Duration(h) * time.Hour) }
+
+	tests := []struct {
+		name             string
+		query            ActivityQuery
+		counters         []ActivityCounter
+		wantStarts       []time.Time
+		wantCounts       []int64
+		wantTotal        int64
+		wantCounterRange [2]time.Time
+	}{
+		{
+			name:  "horas con huecos",
+			query: ActivityQuery{Bucket: BucketHour, From: at(9), To: at(14)},
+			counters: []ActivityCounter{
+				hour(8, 6), // fuera del rango
+				hour(9, 2),
+				hour(11, 1),
+				hour(14, 3),
+				hour(15, 4), // fuera del rango
+			},
+			wantStarts:       []time.Time{at(9), at(10), at(11), at(12), at(13), at(14)},
+			wantCounts:       []int64{2, 0, 1, 0, 0, 3},
+			wantTotal:        6,
+			wantCounterRange: [2]time.Time{at(9), at(14)},
+		},
+		{
+			name:  "días con huecos",
+			query: ActivityQuery{Bucket: BucketDay, From: date(2026, 2, 27), To: date(2026, 3, 3)},
+			counters: []ActivityCounter{
+				day(date(2026, 2, 26), 5), // fuera del rango
+				day(date(2026, 2, 27), 1),
+				day(date(2026, 3, 1), 2), // cruza el fin de febrero
+				day(date(2026, 3, 3), 4),
+			},
+			wantStarts:       []time.Time{date(2026, 2, 27), date(2026, 2, 28), date(2026, 3, 1), date(2026, 3, 2), date(2026, 3, 3)},
+			wantCounts:       []int64{1, 0, 2, 0, 4},
+			wantTotal:        7,
+			wantCounterRange: [2]time.Time{date(2026, 2, 27), date(2026, 3, 3)},
+		},
+		{
+			name:             "un solo día sin eventos",
+			query:            ActivityQuery{Bucket: BucketDay, From: date(2026, 3, 18), To: date(2026, 3, 18)},
+			counters:         []ActivityCounter{day(date(2026, 3, 17), 3)},
+			wantStarts:       []time.Time{date(2026, 3, 18)},
+			wantCounts:       []int64{0},
+			wantCounterRange: [2]time.Time{date(2026, 3, 18), date(2026, 3, 18)},
+		},
+		{
+			name:  "semanas",
+			query: ActivityQuery{Bucket: BucketWeek, From: date(2026, 2, 23), To: date(2026, 3, 16)},
+			counters: []ActivityCounter{
+				day(date(2026, 2, 22), 7), // domingo anterior: fuera del rango
+				day(date(2026, 2, 23), 2),
+				day(date(2026, 3, 1), 3), // domingo: misma semana que el lunes 23
+				day(date(2026, 3, 2), 1),
+				day(date(2026, 3, 22), 4), // domingo de la última semana
+				day(date(2026, 3, 23), 9), // fuera del rango
+			},
+			wantStarts:       []time.Time{date(2026, 2, 23), date(2026, 3, 2), date(2026, 3, 9), date(2026, 3, 16)},
+			wantCounts:       []int64{5, 1, 0, 4},
+			wantTotal:        10,
+			wantCounterRange: [2]time.Time{date(2026, 2, 23), date(2026, 3, 22)},
+		},
+		{
+			name:  "meses",
+			query: ActivityQuery{Bucket: BucketMonth, From: date(2026, 1, 1), To: date(2026, 3, 1)},
+			counters: []ActivityCounter{
+				day(date(2025, 12, 31), 8),
+				day(date(2026, 1, 31), 2),
+				day(date(2026, 2, 1), 3),
+				day(date(2026, 2, 28), 1),
+				day(date(2026, 3, 31), 5),
+				day(date(2026, 4, 1), 9),
+			},
+			wantStarts:       []time.Time{date(2026, 1, 1), date(2026, 2, 1), date(2026, 3, 1)},
+			wantCounts:       []int64{2, 4, 5},
+			wantTotal:        11,
+			wantCounterRange: [2]time.Time{date(2026, 1, 1), date(2026, 3, 31)},
+		},
+		{
+			name:             "sin contadores",
+			query:            ActivityQuery{Bucket: BucketMonth, From: date(2026, 1, 1), To: date(2026, 2, 1)},
+			wantStarts:       []time.Time{date(2026, 1, 1), date(2026, 2, 1)},
+			wantCounts:       []int64{0, 0},
+			wantCounterRange: [2]time.Time{date(2026, 1, 1), date(2026, 2, 28)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := tt.query.BuildSeries("employee.created", tt.counters)
+
+			if series.EventType != "employee.created" || series.Total != tt.wantTotal {
+				t.Errorf("series = %s total %d, want employee.created total %d", series.EventType, series.Total, tt.wantTotal)
+			}
+			if len(series.Points) != len(tt.wantStarts) {
+				t.Fatalf("points = %+v, want %d points", series.Points, len(tt.wantStarts))
+			}
+			for i, point := range series.Points {
+				if !point.Start.Equal(tt.wantStarts[i]) || point.Count != tt.wantCounts[i] {
+					t.Errorf("point %d = %s: %d, want %s: %d", i, point.Start, point.Count, tt.wantStarts[i], tt.wantCounts[i])
+				}
+			}
+
+			first, last := tt.query.CounterRange()
+			if !first.Equal(tt.wantCounterRange[0]) || !last.Equal(tt.wantCounterRange[1]) {
+				t.Errorf("CounterRange() = %s..%s, want %s..%s", first, last, tt.wantCounterRange[0], tt.wantCounterRange[1])
+			}
+		})
+	}
+}
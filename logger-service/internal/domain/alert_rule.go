@@ -0,0 +1,314 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Severidades de una alerta
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Operadores de los predicados sobre campos de la entrada
+const (
+	OpEquals    = "eq"
+	OpNotEquals = "ne"
+	OpIn        = "in"
+	OpNotIn     = "not_in"
+	OpExists    = "exists"
+	OpMissing   = "not_exists"
+	OpContains  = "contains"
+	OpPrefix    = "prefix"
+	OpMatches   = "matches" // expresión regular
+)
+
+// FieldPredicate es una condición sobre un campo de la entrada de log (ver
+// FieldValue). Values es el valor (eq, ne, contains, prefix, matches) o la
+// lista de valores (in, not_in); exists y not_exists no lo usan.
+type FieldPredicate struct {
+	Field  string
+	Op     string
+	Values []string
+
+	pattern *regexp.Regexp
+}
+
+// compile valida el predicado y prepara la expresión regular de matches
+func (p *FieldPredicate) compile() error {
+	if err := validateField(p.Field); err != nil {
+		return err
+	}
+
+	switch p.Op {
+	case OpExists, OpMissing:
+		return nil
+	case OpIn, OpNotIn:
+		if len(p.Values) == 0 {
+			return fmt.Errorf("%s: %s needs at least one value", p.Field, p.Op)
+		}
+		return nil
+	case OpEquals, OpNotEquals, OpContains, OpPrefix, OpMatches:
+		if len(p.Values) != 1 {
+			return fmt.Errorf("%s: %s needs exactly one value", p.Field, p.Op)
+		}
+		if p.Op == OpMatches {
+			pattern, err := regexp.Compile(p.Values[0])
+			if err != nil {
+				return fmt.Errorf("%s: %w", p.Field, err)
+			}
+			p.pattern = pattern
+		}
+		return nil
+	default:
+		return fmt.Errorf("%s: unknown operator %q", p.Field, p.Op)
+	}
+}
+
+// Matches indica si la entrada cumple el predicado. Un campo ausente solo
+// cumple not_exists, ne y not_in.
+func (p *FieldPredicate) Matches(entry *LogEntry) bool {
+	value, ok := FieldValue(entry, p.Field)
+	switch p.Op {
+	case OpExists:
+		return ok
+	case OpMissing:
+		return !ok
+	case OpNotEquals:
+		return !ok || value != p.Values[0]
+	case OpNotIn:
+		return !ok || !containsValue(p.Values, value)
+	}
+	if !ok {
+		return false
+	}
+
+	switch p.Op {
+	case OpEquals:
+		return value == p.Values[0]
+	case OpIn:
+		return containsValue(p.Values, value)
+	case OpContains:
+		return strings.Contains(value, p.Values[0])
+	case OpPrefix:
+		return strings.HasPrefix(value, p.Values[0])
+	case OpMatches:
+		return p.pattern.MatchString(value)
+	}
+	return false
+}
+
+// AlertRule es una regla de alerta: se dispara cuando, en una ventana
+// deslizante de Window, llegan al menos Threshold entradas del tipo EventType
+// (exacto o prefijo "employee.*") que cumplen todos los predicados Where. Con
+// GroupBy cada combinación de valores de esos campos tiene su propia ventana.
+// Tras dispararse no vuelve a hacerlo para el mismo grupo durante Cooldown.
+type AlertRule struct {
+	Name        string
+	Description string
+	EventType   string
+	Where       []FieldPredicate
+	GroupBy     []string
+	Window      time.Duration
+	Threshold   int
+	Cooldown    time.Duration
+	Severity    string
+	// Notify son los canales del aviso ("webhook", "messaging"); Recipients,
+	// si no está vacío, reemplaza a los destinatarios del canal messaging
+	Notify     []string
+	Recipients []string
+}
+
+// Validate comprueba la regla y completa los valores por defecto (severidad
+// warning y cooldown igual a la ventana)
+func (r *AlertRule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidAlertRule)
+	}
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s: %s", ErrInvalidAlertRule, r.Name, fmt.Sprintf(format, args...))
+	}
+
+	if r.EventType == "" {
+		return invalid("event_type is required")
+	}
+	if r.Window <= 0 {
+		return invalid("window must be positive")
+	}
+	if r.Threshold < 1 {
+		return invalid("threshold must be at least 1")
+	}
+	if r.Cooldown < 0 {
+		return invalid("cooldown must not be negative")
+	}
+	if r.Cooldown == 0 {
+		r.Cooldown = r.Window
+	}
+
+	switch r.Severity {
+	case "":
+		r.Severity = SeverityWarning
+	case SeverityInfo, SeverityWarning, SeverityCritical:
+	default:
+		return invalid("unknown severity %q", r.Severity)
+	}
+
+	for i := range r.Where {
+		if err := r.Where[i].compile(); err != nil {
+			return invalid("%v", err)
+		}
+	}
+	for _, field := range r.GroupBy {
+		if err := validateField(field); err != nil {
+			return invalid("group_by %v", err)
+		}
+	}
+	return nil
+}
+
+// Matches indica si la entrada cuenta para la regla
+func (r *AlertRule) Matches(entry *LogEntry) bool {
+	if _, ok := matchEventType(r.EventType, entry.EventType); !ok {
+		return false
+	}
+	for i := range r.Where {
+		if !r.Where[i].Matches(entry) {
+			return false
+		}
+	}
+	return true
+}
+
+// Group devuelve los valores de GroupBy de la entrada y la clave de su ventana
+func (r *AlertRule) Group(entry *LogEntry) (map[string]string, string) {
+	if len(r.GroupBy) == 0 {
+		return nil, ""
+	}
+
+	group := make(map[string]string, len(r.GroupBy))
+	parts := make([]string, len(r.GroupBy))
+	for i, field := range r.GroupBy {
+		value, _ := FieldValue(entry, field)
+		group[field] = value
+		parts[i] = value
+	}
+	key, _ := json.Marshal(parts)
+	return group, string(key)
+}
+
+// Alert es el aviso de una regla que superó su umbral
+type Alert struct {
+	ID            string            `json:"id"`
+	Rule          string            `json:"rule"`
+	Description   string            `json:"description,omitempty"`
+	Severity      string            `json:"severity"`
+	Count         int               `json:"count"`
+	Threshold     int               `json:"threshold"`
+	WindowSeconds int               `json:"window_seconds"`
+	Group         map[string]string `json:"group,omitempty"`
+	FirstEventAt  time.Time         `json:"first_event_at"`
+	LastEventAt   time.Time         `json:"last_event_at"`
+	FiredAt       time.Time         `json:"fired_at"`
+	// EntryIDs son las entradas de log que dispararon la alerta (las últimas
+	// maxAlertEntryIDs)
+	EntryIDs []string `json:"entry_ids"`
+
+	Notify     []string `json:"-"`
+	Recipients []string `json:"-"`
+}
+
+// FieldValue devuelve el valor de un campo de la entrada para los predicados
+// y GroupBy: event_type, action, source, employee_id, actor.type, actor.id,
+// subject.type, subject.id, metadata.<clave> o payload.<ruta.con.puntos>. Los
+// valores del payload que no son texto se devuelven como JSON (20, true...).
+func FieldValue(entry *LogEntry, field string) (string, bool) {
+	switch field {
+	case "event_type":
+		return entry.EventType, entry.EventType != ""
+	case "action":
+		return entry.Action, entry.Action != ""
+	case "source":
+		return entry.Source, entry.Source != ""
+	case "employee_id":
+		return entry.EmployeeID, entry.EmployeeID != ""
+	case "actor.type":
+		return entry.Actor.Type, entry.Actor.Type != ""
+	case "actor.id":
+		return entry.Actor.ID, entry.Actor.ID != ""
+	case "subject.type":
+		return entry.Subject.Type, entry.Subject.Type != ""
+	case "subject.id":
+		return entry.Subject.ID, entry.Subject.ID != ""
+	}
+
+	if key, ok := strings.CutPrefix(field, "metadata."); ok {
+		value, ok := entry.Metadata[key]
+		return value, ok
+	}
+	if path, ok := strings.CutPrefix(field, "payload."); ok {
+		return payloadValue(entry.Payload, strings.Split(path, "."))
+	}
+	return "", false
+}
+
+// validateField comprueba que FieldValue reconozca el campo
+func validateField(field string) error {
+	switch field {
+	case "event_type", "action", "source", "employee_id", "actor.type", "actor.id", "subject.type", "subject.id":
+		return nil
+	}
+	for _, prefix := range []string{"metadata.", "payload."} {
+		if rest, ok := strings.CutPrefix(field, prefix); ok && rest != "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown field %q", field)
+}
+
+// payloadValue recorre el payload JSON siguiendo path
+func payloadValue(payload json.RawMessage, path []string) (string, bool) {
+	if len(payload) == 0 {
+		return "", false
+	}
+
+	var current interface{}
+	if err := json.Unmarshal(payload, &current); err != nil {
+		return "", false
+	}
+	for _, key := range path {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		if current, ok = object[key]; !ok {
+			return "", false
+		}
+	}
+
+	switch value := current.(type) {
+	case nil:
+		return "", false
+	case string:
+		return value, true
+	default:
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return "", false
+		}
+		return string(encoded), true
+	}
+}
+
+func containsValue(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
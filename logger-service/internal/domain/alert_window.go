@@ -0,0 +1,109 @@
+package domain
+
+import "time"
+
+// maxAlertEntryIDs es el número de entradas que se citan en una alerta
+const maxAlertEntryIDs = 10
+
+// maxWindowEvents acota la memoria de una ventana: basta con conservar
+// Threshold eventos para saber si se alcanzó el umbral, pero se guardan hasta
+// este número para informar el total de la ventana
+const maxWindowEvents = 1000
+
+// windowEvent es una entrada contada en una ventana
+type windowEvent struct {
+	at      time.Time
+	entryID string
+}
+
+// AlertWindow es la ventana deslizante de una regla (y grupo): las entradas
+// que la cumplieron ordenadas por timestamp y el momento del último disparo
+type AlertWindow struct {
+	events    []windowEvent
+	lastFired time.Time
+}
+
+// Add cuenta la entrada en la ventana y devuelve la alerta si con ella se
+// alcanza el umbral fuera del cooldown. La ventana es relativa a now: las
+// entradas con timestamp anterior a now-Window llegan tarde y no se cuentan.
+// Al dispararse la ventana se vacía.
+func (w *AlertWindow) Add(rule *AlertRule, entry *LogEntry, now time.Time) *Alert {
+	at := entry.Timestamp
+	if at.After(now) {
+		at = now
+	}
+	start := now.Add(-rule.Window)
+	if at.Before(start) {
+		return nil
+	}
+
+	w.prune(start)
+	w.insert(windowEvent{at: at, entryID: entry.ID})
+
+	limit := rule.Threshold
+	if limit < maxWindowEvents {
+		limit = maxWindowEvents
+	}
+	if len(w.events) > limit {
+		w.events = w.events[len(w.events)-limit:]
+	}
+
+	if len(w.events) < rule.Threshold {
+		return nil
+	}
+	if !w.lastFired.IsZero() && now.Sub(w.lastFired) < rule.Cooldown {
+		return nil
+	}
+
+	alert := &Alert{
+		Rule:          rule.Name,
+		Description:   rule.Description,
+		Severity:      rule.Severity,
+		Count:         len(w.events),
+		Threshold:     rule.Threshold,
+		WindowSeconds: int(rule.Window / time.Second),
+		FirstEventAt:  w.events[0].at,
+		LastEventAt:   w.events[len(w.events)-1].at,
+		FiredAt:       now,
+		Notify:        rule.Notify,
+		Recipients:    rule.Recipients,
+	}
+	first := len(w.events) - maxAlertEntryIDs
+	if first < 0 {
+		first = 0
+	}
+	for _, event := range w.events[first:] {
+		alert.EntryIDs = append(alert.EntryIDs, event.entryID)
+	}
+
+	w.lastFired = now
+	w.events = nil
+	return alert
+}
+
+// Idle indica si la ventana está vacía y fuera del cooldown, de modo que se
+// puede descartar
+func (w *AlertWindow) Idle(rule *AlertRule, now time.Time) bool {
+	w.prune(now.Add(-rule.Window))
+	return len(w.events) == 0 && (w.lastFired.IsZero() || now.Sub(w.lastFired) >= rule.Cooldown)
+}
+
+// prune descarta los eventos anteriores a start
+func (w *AlertWindow) prune(start time.Time) {
+	i := 0
+	for i < len(w.events) && w.events[i].at.Before(start) {
+		i++
+	}
+	w.events = w.events[i:]
+}
+
+// insert agrega el evento conservando el orden por timestamp (casi siempre al final)
+func (w *AlertWindow) insert(event windowEvent) {
+	i := len(w.events)
+	for i > 0 && w.events[i-1].at.After(event.at) {
+		i--
+	}
+	w.events = append(w.events, windowEvent{})
+	copy(w.events[i+1:], w.events[i:])
+	w.events[i] = event
+}
@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestAlertWindowAdd(t *testing.T) {
+	base := time.Date(2026, 6, 1, 10, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return base.Add(time.Duration(seconds) * time.Second) }
+
+	// step es una entrada con timestamp en el segundo entry que se procesa en el segundo now
+	type step struct {
+		entry, now int
+		wantCount  int // 0 = no se dispara
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name:  "alcanza el umbral",
+			steps: []step{{0, 0, 0}, {10, 10, 0}, {20, 20, 3}},
+		},
+		{
+			name:  "bajo el umbral",
+			steps: []step{{0, 0, 0}, {10, 10, 0}},
+		},
+		{
+			name: "los eventos salen de la ventana",
+			// En 70 la ventana empieza en 10: el evento de 0 ya no cuenta
+			steps: []step{{0, 0, 0}, {30, 30, 0}, {70, 70, 0}, {80, 80, 3}},
+		},
+		{
+			name:  "entrada tardía fuera de la ventana",
+			steps: []step{{0, 100, 0}, {90, 100, 0}, {95, 100, 0}, {100, 100, 3}},
+		},
+		{
+			name:  "entrada tardía dentro de la ventana",
+			steps: []step{{50, 100, 0}, {90, 100, 0}, {45, 100, 3}},
+		},
+		{
+			name:  "timestamp futuro cuenta como now",
+			steps: []step{{500, 0, 0}, {500, 10, 0}, {500, 20, 3}},
+		},
+		{
+			name: "cooldown",
+			// Dispara en 20; hasta 140 (cooldown de 120 s) no vuelve a hacerlo
+			steps: []step{
+				{0, 0, 0}, {10, 10, 0}, {20, 20, 3},
+				{30, 30, 0}, {40, 40, 0}, {50, 50, 0},
+				{100, 100, 0}, {110, 110, 0}, {139, 139, 0},
+				{140, 140, 4},
+			},
+		},
+		{
+			name: "la ventana se vacía al dispararse",
+			// Tras el cooldown hacen falta de nuevo 3 eventos en la ventana
+			steps: []step{
+				{0, 0, 0}, {1, 1, 0}, {2, 2, 3},
+				{200, 200, 0}, {201, 201, 0}, {202, 202, 3},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := &AlertRule{Name: "failed-logins", EventType: "auth.login_failed", Window: time.Minute, Threshold: 3, Cooldown: 2 * time.Minute}
+			if err := rule.Validate(); err != nil {
+				t.Fatalf("Validate() = %v", err)
+			}
+			window := &AlertWindow{}
+
+			for i, s := range tt.steps {
+				entry := &LogEntry{ID: fmt.Sprintf("entry-%d", i), Timestamp: at(s.entry)}
+				alert := window.Add(rule, entry, at(s.now))
+
+				if s.wantCount == 0 {
+					if alert != nil {
+						t.Fatalf("step %d: fired with %d events, want no alert", i, alert.Count)
+					}
+					continue
+				}
+				if alert == nil {
+					t.Fatalf("step %d: no alert, want one with %d events", i, s.wantCount)
+				}
+				if alert.Count != s.wantCount || len(alert.EntryIDs) != s.wantCount {
+					t.Errorf("step %d: alert count %d with %d entry IDs, want %d", i, alert.Count, len(alert.EntryIDs), s.wantCount)
+				}
+				if !alert.FiredAt.Equal(at(s.now)) || alert.Threshold != 3 || alert.WindowSeconds != 60 {
+					t.Errorf("step %d: alert = %+v", i, alert)
+				}
+				if alert.LastEventAt.Before(alert.FirstEventAt) {
+					t.Errorf("step %d: first event %s after last %s", i, alert.FirstEventAt, alert.LastEventAt)
+				}
+			}
+		})
+	}
+}
+
+func TestAlertWindowIdle(t *testing.T) {
+	base := time.Date(2026, 6, 1, 10, 0, 0, 0, time.UTC)
+	rule := &AlertRule{Name: "deletions", EventType: "employee.deleted", Window: time.Minute, Threshold: 1}
+	if err := rule.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+
+	window := &AlertWindow{}
+	if !window.Idle(rule, base) {
+		t.Error("new window is not idle")
+	}
+
+	if alert := window.Add(rule, &LogEntry{ID: "entry-1", Timestamp: base}, base); alert == nil {
+		t.Fatal("threshold of 1 did not fire")
+	}
+	// El cooldown por defecto es la ventana
+	if window.Idle(rule, base.Add(59*time.Second)) {
+		t.Error("window is idle during the cooldown")
+	}
+	if !window.Idle(rule, base.Add(time.Minute)) {
+		t.Error("window is not idle after the cooldown")
+	}
+}
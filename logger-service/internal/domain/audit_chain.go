@@ -0,0 +1,216 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Las entradas de log forman una cadena de hashes por partición (ChainID): cada
+// entrada guarda su número de secuencia, el hash de la anterior y su propio
+// hash, calculado sobre su contenido y esos campos. Modificar, borrar o
+// reordenar una entrada rompe la cadena desde ese punto, y los checkpoints
+// firmados fijan periódicamente la cabeza de cada cadena.
+
+// GenesisHash es el PrevHash de la primera entrada de cada cadena
+var GenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// systemChainID agrupa las entradas que no corresponden a un empleado
+const systemChainID = "system"
+
+// ChainIDFor devuelve la partición de la cadena de una entrada: el empleado,
+// de modo que el orden por empleado de las colas FIFO se refleja en la cadena
+func ChainIDFor(entry *LogEntry) string {
+	if entry.EmployeeID != "" {
+		return entry.EmployeeID
+	}
+	return systemChainID
+}
+
+// ChainHead es el último eslabón de una cadena
+type ChainHead struct {
+	ChainID         string
+	Seq             int64
+	Hash            string
+	CheckpointedSeq int64 // secuencia del último checkpoint firmado
+}
+
+// Link encadena la entrada a continuación de head (nil si la cadena está vacía)
+// y calcula su hash
+func (e *LogEntry) Link(head *ChainHead) {
+	e.ChainID = ChainIDFor(e)
+	e.ChainSeq = 1
+	e.PrevHash = GenesisHash
+	if head != nil && head.Seq > 0 {
+		e.ChainSeq = head.Seq + 1
+		e.PrevHash = head.Hash
+	}
+	e.Hash = e.ComputeHash()
+}
+
+// chainedContent es la representación canónica que se hashea: orden de campos
+// fijo y tiempos en UTC con nanosegundos
+type chainedContent struct {
+	Version     int               `json:"version"`
+	ChainID     string            `json:"chain_id"`
+	ChainSeq    int64             `json:"chain_seq"`
+	PrevHash    string            `json:"prev_hash"`
+	ID          string            `json:"id"`
+	EventID     string            `json:"event_id"`
+	EventType   string            `json:"event_type"`
+	Source      string            `json:"source"`
+	Action      string            `json:"action"`
+	Actor       Reference         `json:"actor"`
+	Subject     Reference         `json:"subject"`
+	EmployeeID  string            `json:"employee_id"`
+	Metadata    map[string]string `json:"metadata"`
+	Payload     json.RawMessage   `json:"payload"`
+	Timestamp   string            `json:"timestamp"`
+	ProcessedAt string            `json:"processed_at"`
+	// Solo en las entradas cifradas, de modo que el hash de las demás no cambia
+	Encryption *EntryEncryption `json:"encryption,omitempty"`
+}
+
+// legacyChainedContent es la representación que se hasheaba en las entradas
+// del formato anterior (Version 0); name y email están ahora en Metadata
+type legacyChainedContent struct {
+	ChainID     string `json:"chain_id"`
+	ChainSeq    int64  `json:"chain_seq"`
+	PrevHash    string `json:"prev_hash"`
+	ID          string `json:"id"`
+	EventID     string `json:"event_id"`
+	EventType   string `json:"event_type"`
+	Source      string `json:"source"`
+	EmployeeID  string `json:"employee_id"`
+	Name        string `json:"name"`
+	Email       string `json:"email"`
+	Timestamp   string `json:"timestamp"`
+	ProcessedAt string `json:"processed_at"`
+}
+
+// ComputeHash calcula el SHA-256 (hex) del contenido de la entrada y su
+// posición en la cadena, con la representación de su versión de formato
+func (e *LogEntry) ComputeHash() string {
+	var content []byte
+	if e.Version == 0 {
+		content, _ = json.Marshal(legacyChainedContent{
+			ChainID:     e.ChainID,
+			ChainSeq:    e.ChainSeq,
+			PrevHash:    e.PrevHash,
+			ID:          e.ID,
+			EventID:     e.EventID,
+			EventType:   e.EventType,
+			Source:      e.Source,
+			EmployeeID:  e.EmployeeID,
+			Name:        e.Metadata["name"],
+			Email:       e.Metadata["email"],
+			Timestamp:   e.Timestamp.UTC().Format(time.RFC3339Nano),
+			ProcessedAt: e.ProcessedAt.UTC().Format(time.RFC3339Nano),
+		})
+	} else {
+		// Metadatos y payload vacíos se hashean como null, estén ausentes o no
+		metadata, payload := e.Metadata, e.Payload
+		if len(metadata) == 0 {
+			metadata = nil
+		}
+		if len(payload) == 0 {
+			payload = nil
+		}
+		content, _ = json.Marshal(chainedContent{
+			Version:     e.Version,
+			ChainID:     e.ChainID,
+			ChainSeq:    e.ChainSeq,
+			PrevHash:    e.PrevHash,
+			ID:          e.ID,
+			EventID:     e.EventID,
+			EventType:   e.EventType,
+			Source:      e.Source,
+			Action:      e.Action,
+			Actor:       e.Actor,
+			Subject:     e.Subject,
+			EmployeeID:  e.EmployeeID,
+			Metadata:    metadata,
+			Payload:     payload,
+			Timestamp:   e.Timestamp.UTC().Format(time.RFC3339Nano),
+			ProcessedAt: e.ProcessedAt.UTC().Format(time.RFC3339Nano),
+			Encryption:  e.Encryption,
+		})
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// Checkpoint fija con una firma la cabeza de una cadena en un momento dado
+type Checkpoint struct {
+	ChainID   string
+	Seq       int64
+	Hash      string
+	CreatedAt time.Time
+	KeyID     string
+	Signature []byte
+}
+
+// NewCheckpoint crea el checkpoint (sin firmar) de la cabeza de una cadena
+func NewCheckpoint(head *ChainHead, at time.Time, keyID string) *Checkpoint {
+	return &Checkpoint{
+		ChainID:   head.ChainID,
+		Seq:       head.Seq,
+		Hash:      head.Hash,
+		CreatedAt: at.UTC(),
+		KeyID:     keyID,
+	}
+}
+
+// SigningPayload devuelve los bytes que se firman
+func (c *Checkpoint) SigningPayload() []byte {
+	return []byte(strings.Join([]string{
+		"audit-checkpoint/v1",
+		c.ChainID,
+		strconv.FormatInt(c.Seq, 10),
+		c.Hash,
+		c.CreatedAt.UTC().Format(time.RFC3339Nano),
+		c.KeyID,
+	}, "\n"))
+}
+
+// Tipos de problema que detecta la verificación de una cadena
+const (
+	IssueGap                = "gap"                 // faltan secuencias
+	IssueReordered          = "reordered"           // la entrada enlaza con otra que no es la anterior
+	IssueBrokenLink         = "broken_link"         // PrevHash no coincide con ninguna entrada
+	IssueModified           = "modified"            // el contenido no coincide con su hash
+	IssueDuplicate          = "duplicate"           // secuencia repetida
+	IssueTruncated          = "truncated"           // faltan entradas al final de la cadena
+	IssueHeadMismatch       = "head_mismatch"       // la cabeza no coincide con la última entrada
+	IssueBadSignature       = "bad_signature"       // firma de checkpoint inválida o de clave desconocida
+	IssueCheckpointMismatch = "checkpoint_mismatch" // la entrada no coincide con el checkpoint firmado
+)
+
+// ChainIssue es un problema detectado en una cadena
+type ChainIssue struct {
+	Seq    int64  `json:"seq"`
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+}
+
+// ChainReport es el resultado de verificar una cadena
+type ChainReport struct {
+	ChainID     string       `json:"chain_id"`
+	Entries     int          `json:"entries"`
+	Checkpoints int          `json:"checkpoints"`
+	Archived    int          `json:"archived,omitempty"` // entradas tomadas de los archivos
+	Issues      []ChainIssue `json:"issues,omitempty"`
+}
+
+// OK indica si la cadena no tiene problemas
+func (r *ChainReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// AddIssue registra un problema en el informe
+func (r *ChainReport) AddIssue(seq int64, kind, detail string) {
+	r.Issues = append(r.Issues, ChainIssue{Seq: seq, Kind: kind, Detail: detail})
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "errors"
+
+var (
+	ErrInvalidCursor    = errors.New("invalid pagination cursor")
+	ErrInvalidPageSize  = errors.New("invalid page size")
+	ErrInvalidTimeRange = errors.New("invalid time range: from must not be after to")
+	ErrTimeRangeTooWide = errors.New("time range too wide: filter by event_type or employee_id, or narrow from/to")
+
+	ErrInvalidRetentionRule = errors.New("invalid retention rule: expected event_type=days")
+	ErrArchiveNotFound      = errors.New("archive not found")
+
+	ErrInvalidEventPayload = errors.New("invalid event payload")
+
+	ErrInvalidBucketSize    = errors.New("invalid bucket: expected hour, day, week or month")
+	ErrInvalidActivityQuery = errors.New("invalid event_type: expected 1 to 10 exact event types")
+	ErrTooManyBuckets       = errors.New("too many buckets: use a larger bucket or narrow from/to")
+
+	ErrInvalidAlertRule = errors.New("invalid alert rule")
+
+	ErrPIIAccessDenied = errors.New("not authorized to read personal data")
+	ErrPIIDecryption   = errors.New("error decrypting personal data")
+	ErrUnknownKey      = errors.New("unknown master key")
+)
@@ -1,16 +1,64 @@
 package domain
 
-// EmployeeEvent representa un evento relacionado con un empleado
-type EmployeeEvent struct {
-	EventType string   `json:"event_type"`
-	Employee  Employee `json:"employee"`
-	Timestamp string   `json:"timestamp"`
+import (
+	"bytes"
+	"encoding/json"
+	"pkg/events"
+	"time"
+)
+
+// Event representa un evento recibido de cualquier tipo, normalizado a partir
+// del envelope CloudEvents o del formato anterior. Data es el payload original.
+type Event struct {
+	ID      string
+	Type    string
+	Source  string
+	Subject string
+	Time    time.Time
+	Data    json.RawMessage
+}
+
+// DecodeEvent decodifica un evento en formato CloudEvents o en el formato
+// anterior ({event_type, employee, timestamp}) durante la migración
+func DecodeEvent(body []byte) (*Event, error) {
+	envelope, err := events.Decode(body)
+	if err != nil {
+		return nil, err
+	}
+	if envelope.Type == "" {
+		return nil, events.ErrInvalidEvent
+	}
+
+	event := &Event{
+		ID:      envelope.ID,
+		Type:    envelope.Type,
+		Source:  envelope.Source,
+		Subject: envelope.Subject,
+		Time:    envelope.Time.UTC(),
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	// El payload se guarda compactado para que su representación (y el hash
+	// de la entrada) no dependa de los espacios con que se publicó
+	if len(envelope.Data) > 0 {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, envelope.Data); err != nil {
+			return nil, events.ErrInvalidEvent
+		}
+		event.Data = compact.Bytes()
+	}
+	return event, nil
 }
 
-// Employee representa los datos básicos de un empleado en el evento
-type Employee struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	Email     string `json:"email"`
-	CreatedAt string `json:"created_at"`
+// DecodeData decodifica el payload del evento
+func (e *Event) DecodeData(v interface{}) error {
+	if len(e.Data) == 0 {
+		return ErrInvalidEventPayload
+	}
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return ErrInvalidEventPayload
+	}
+	return nil
 }
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"math"
+	"strings"
+)
+
+// matchEventType compara un tipo de evento con un patrón exacto o de prefijo
+// ("employee.*"). specificity permite elegir el patrón más concreto: la
+// coincidencia exacta gana y entre prefijos gana el más largo.
+func matchEventType(pattern, eventType string) (specificity int, ok bool) {
+	if prefix, isPrefix := strings.CutSuffix(pattern, "*"); isPrefix {
+		return len(prefix), strings.HasPrefix(eventType, prefix)
+	}
+	return math.MaxInt, pattern == eventType
+}
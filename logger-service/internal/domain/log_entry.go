@@ -1,26 +1,140 @@
 package domain
 
-import "time"
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
 
-// LogEntry representa una entrada de log en el sistema
+// LogEntryVersion es la versión actual del formato de las entradas. Las
+// entradas sin versión (0) son del formato anterior centrado en empleados
+// (EmployeeID, Name, Email) y se convierten al leerlas (ver UpgradeLegacy).
+const LogEntryVersion = 2
+
+// Reference identifica una entidad que participa en un evento (un empleado,
+// un mensaje, un servicio...)
+type Reference struct {
+	Type string `json:"type,omitempty"`
+	ID   string `json:"id,omitempty"`
+}
+
+// Tipos de entidad conocidos
+const (
+	ReferenceEmployee = "employee"
+	ReferenceMessage  = "message"
+	ReferenceService  = "service"
+)
+
+// LogEntry representa una entrada de log en el sistema: quién (Actor) hizo
+// qué (Action) sobre qué (Subject), con metadatos para mostrar y filtrar y el
+// payload original del evento
 type LogEntry struct {
-	ID          string    `json:"id"`
-	EventType   string    `json:"event_type"`
-	EmployeeID  string    `json:"employee_id"`
-	Name        string    `json:"name"`
-	Email       string    `json:"email"`
-	Timestamp   time.Time `json:"timestamp"`
-	ProcessedAt time.Time `json:"processed_at"`
+	ID        string    `json:"id"`
+	Version   int       `json:"version,omitempty"`
+	EventID   string    `json:"event_id,omitempty"`
+	EventType string    `json:"event_type"`
+	Source    string    `json:"source,omitempty"`
+	Action    string    `json:"action"`
+	Actor     Reference `json:"actor"`
+	Subject   Reference `json:"subject"`
+	// EmployeeID es el empleado relacionado con el evento (el sujeto o, p.ej.,
+	// el destinatario de un mensaje). Particiona la cadena de auditoría y
+	// alimenta el filtro employee_id de la API.
+	EmployeeID  string            `json:"employee_id,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty" dynamodbav:",omitempty"`
+	Payload     json.RawMessage   `json:"payload,omitempty" dynamodbav:",omitempty"`
+	Timestamp   time.Time         `json:"timestamp"`
+	ProcessedAt time.Time         `json:"processed_at"`
+
+	// Encadenamiento de auditoría (ver audit_chain.go)
+	ChainID  string `json:"chain_id,omitempty"`
+	ChainSeq int64  `json:"chain_seq,omitempty"`
+	PrevHash string `json:"prev_hash,omitempty"`
+	Hash     string `json:"hash,omitempty"`
+
+	// Cifrado de los datos personales (ver pii_encryption.go); nil = en claro
+	Encryption *EntryEncryption `json:"encryption,omitempty" dynamodbav:",omitempty"`
+
+	// Retención (ver retention.go): vencida esta fecha, la entrada se archiva y
+	// después DynamoDB la borra por TTL. Vacío = sin vencimiento.
+	RetainUntil *time.Time `json:"retain_until,omitempty" dynamodbav:",omitempty"`
 }
 
-// NewLogEntry crea una nueva entrada de log
-func NewLogEntry(eventType, employeeID, name, email string, timestamp time.Time) *LogEntry {
-	return &LogEntry{
-		EventType:   eventType,
-		EmployeeID:  employeeID,
-		Name:        name,
-		Email:       email,
-		Timestamp:   timestamp,
+// NewLogEntry crea la entrada de log base de un evento: la acción sale del
+// tipo, el actor del origen y el sujeto del subject del envelope. La
+// proyección del tipo de evento completa el resto.
+func NewLogEntry(event *Event) *LogEntry {
+	entry := &LogEntry{
+		Version:     LogEntryVersion,
+		EventID:     event.ID,
+		EventType:   event.Type,
+		Source:      event.Source,
+		Action:      ActionFromType(event.Type),
+		Subject:     Reference{ID: event.Subject},
+		Payload:     event.Data,
+		Timestamp:   event.Time,
 		ProcessedAt: time.Now(),
 	}
+	if event.Source != "" {
+		entry.Actor = Reference{Type: ReferenceService, ID: strings.TrimPrefix(event.Source, "/")}
+	}
+	return entry
+}
+
+// ActionFromType devuelve la acción de un tipo de evento: lo que sigue al
+// último punto ("employee.status_changed" → "status_changed")
+func ActionFromType(eventType string) string {
+	if i := strings.LastIndex(eventType, "."); i >= 0 {
+		return eventType[i+1:]
+	}
+	return eventType
+}
+
+// SetMetadata agrega un metadato; los valores vacíos se omiten
+func (e *LogEntry) SetMetadata(key, value string) {
+	if value == "" {
+		return
+	}
+	if e.Metadata == nil {
+		e.Metadata = make(map[string]string)
+	}
+	e.Metadata[key] = value
+}
+
+// UpgradeLegacy convierte una entrada del formato anterior (Version 0), leída
+// junto con sus campos name y email, al formato genérico. El sujeto pasa a
+// ser el empleado y name/email se conservan como metadatos, que es lo que
+// usa el hash de esas entradas.
+func (e *LogEntry) UpgradeLegacy(name, email string) {
+	if e.Version != 0 {
+		return
+	}
+	if e.Action == "" {
+		e.Action = ActionFromType(e.EventType)
+	}
+	if e.Subject == (Reference{}) && e.EmployeeID != "" {
+		e.Subject = Reference{Type: ReferenceEmployee, ID: e.EmployeeID}
+	}
+	if e.Actor == (Reference{}) && e.Source != "" {
+		e.Actor = Reference{Type: ReferenceService, ID: strings.TrimPrefix(e.Source, "/")}
+	}
+	e.SetMetadata("name", name)
+	e.SetMetadata("email", email)
+}
+
+// UnmarshalJSON lee el formato actual y el anterior (name y email en la raíz)
+func (e *LogEntry) UnmarshalJSON(data []byte) error {
+	type plain LogEntry
+	var decoded struct {
+		plain
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*e = LogEntry(decoded.plain)
+	e.UpgradeLegacy(decoded.Name, decoded.Email)
+	return nil
 }
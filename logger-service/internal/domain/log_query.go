@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+// Tamaños de página de la consulta de logs
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// Ventana de tiempo de las consultas sin filtro por tipo de evento ni empleado:
+// se recorren particiones diarias, por lo que el rango se acota
+const (
+	DefaultUnfilteredRange = 7 * 24 * time.Hour
+	MaxUnfilteredRange     = 31 * 24 * time.Hour
+)
+
+// LogFilter son los criterios de búsqueda de entradas de log. From y To
+// acotan el timestamp del evento (ambos inclusive); los campos vacíos no filtran.
+type LogFilter struct {
+	EventType  string
+	EmployeeID string
+	From       time.Time
+	To         time.Time
+}
+
+// Normalize completa los límites del rango y lo valida: To vacío es now y,
+// sin filtro por tipo ni empleado, From vacío es To menos DefaultUnfilteredRange
+func (f *LogFilter) Normalize(now time.Time) error {
+	if f.To.IsZero() {
+		f.To = now
+	}
+	if !f.From.IsZero() && f.From.After(f.To) {
+		return ErrInvalidTimeRange
+	}
+
+	if f.EventType == "" && f.EmployeeID == "" {
+		if f.From.IsZero() {
+			f.From = f.To.Add(-DefaultUnfilteredRange)
+		}
+		if f.To.Sub(f.From) > MaxUnfilteredRange {
+			return ErrTimeRangeTooWide
+		}
+	}
+	return nil
+}
+
+// LogPage es una página de entradas de log, de la más reciente a la más antigua
+type LogPage struct {
+	Entries    []*LogEntry `json:"entries"`
+	NextCursor string      `json:"next_cursor,omitempty"` // "" cuando no hay más páginas
+}
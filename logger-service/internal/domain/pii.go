@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// DefaultPIIFields son las claves con datos personales: los metadatos name,
+// email y to de las proyecciones y, dentro del payload, los campos con esos
+// nombres y la lista recipients de las alertas
+var DefaultPIIFields = []string{"name", "email", "to", "recipients"}
+
+// ParsePIIFields interpreta la lista de claves con datos personales separadas
+// por comas ("" = DefaultPIIFields)
+func ParsePIIFields(value string) []string {
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return DefaultPIIFields
+	}
+	return fields
+}
+
+// RedactionMode es la forma de ocultar un dato personal en la consola y el
+// stream en vivo
+type RedactionMode string
+
+const (
+	RedactMask   RedactionMode = "mask"   // primera letra de cada parte: "j***@e***.com"
+	RedactHash   RedactionMode = "hash"   // prefijo del SHA-256, para correlacionar sin mostrar el valor
+	RedactRemove RedactionMode = "remove" // RedactedValue
+	RedactNone   RedactionMode = "none"   // sin redacción
+)
+
+// RedactedValue reemplaza a un dato personal oculto por completo (también en
+// las respuestas de la API a quien no puede descifrarlo)
+const RedactedValue = "[redacted]"
+
+// ParseRedactionMode interpreta el modo de redacción ("" = mask)
+func ParseRedactionMode(value string) (RedactionMode, error) {
+	switch mode := RedactionMode(value); mode {
+	case "":
+		return RedactMask, nil
+	case RedactMask, RedactHash, RedactRemove, RedactNone:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid redaction mode %q: expected mask, hash, remove or none", value)
+	}
+}
+
+// Redactor oculta los datos personales de una entrada de log: los metadatos
+// con esas claves y, en el payload, los campos con esos nombres a cualquier
+// profundidad
+type Redactor struct {
+	mode   RedactionMode
+	fields map[string]bool
+}
+
+// NewRedactor crea un redactor para las claves fields
+func NewRedactor(mode RedactionMode, fields []string) *Redactor {
+	set := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		set[field] = true
+	}
+	return &Redactor{mode: mode, fields: set}
+}
+
+// IsPII indica si la clave contiene datos personales
+func (r *Redactor) IsPII(key string) bool {
+	return r.fields[key]
+}
+
+// Entry devuelve una copia de la entrada con los datos personales ocultos (la
+// misma entrada si el modo es none)
+func (r *Redactor) Entry(entry *LogEntry) *LogEntry {
+	if r.mode == RedactNone {
+		return entry
+	}
+
+	redacted := *entry
+	if len(entry.Metadata) > 0 {
+		redacted.Metadata = make(map[string]string, len(entry.Metadata))
+		for key, value := range entry.Metadata {
+			if r.fields[key] {
+				value = r.Value(value)
+			}
+			redacted.Metadata[key] = value
+		}
+	}
+	redacted.Payload = r.payload(entry.Payload)
+	return &redacted
+}
+
+// Value oculta un valor según el modo
+func (r *Redactor) Value(value string) string {
+	switch r.mode {
+	case RedactNone:
+		return value
+	case RedactRemove:
+		return RedactedValue
+	case RedactHash:
+		sum := sha256.Sum256([]byte(value))
+		return "sha256:" + hex.EncodeToString(sum[:6])
+	default:
+		return maskValue(value)
+	}
+}
+
+// payload oculta los campos personales del payload JSON. Un payload que no
+// es JSON válido se descarta entero.
+func (r *Redactor) payload(payload json.RawMessage) json.RawMessage {
+	if len(payload) == 0 {
+		return payload
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		return nil
+	}
+	redacted, err := json.Marshal(r.walk(decoded, false))
+	if err != nil {
+		return nil
+	}
+	return redacted
+}
+
+// walk recorre el valor JSON; personal indica que cuelga de un campo personal
+func (r *Redactor) walk(value interface{}, personal bool) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			typed[key] = r.walk(child, personal || r.fields[key])
+		}
+		return typed
+	case []interface{}:
+		for i, child := range typed {
+			typed[i] = r.walk(child, personal)
+		}
+		return typed
+	case nil:
+		return nil
+	case string:
+		if personal {
+			return r.Value(typed)
+		}
+		return typed
+	default:
+		if personal {
+			encoded, _ := json.Marshal(typed)
+			return r.Value(string(encoded))
+		}
+		return typed
+	}
+}
+
+// maskValue conserva la primera letra de cada parte del valor (separadas por
+// espacios, "@" y "."), salvo el dominio de primer nivel de un email:
+// "Ana Pérez" → "A*** P***", "ana@example.com" → "a***@e***.com"
+func maskValue(value string) string {
+	if value == "" {
+		return value
+	}
+
+	var b strings.Builder
+	keepTLD := strings.Contains(value, "@")
+	lastDot := strings.LastIndex(value, ".")
+	start := true
+	for i, char := range value {
+		switch {
+		case char == ' ' || char == '@' || char == '.':
+			b.WriteRune(char)
+			start = true
+		case keepTLD && i > lastDot && lastDot > strings.Index(value, "@"):
+			b.WriteRune(char)
+		case start:
+			b.WriteRune(char)
+			b.WriteString("***")
+			start = false
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,215 @@
+package domain
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Los datos personales se guardan cifrados con cifrado envolvente: cada clave
+// de datos (AES-256) cifra con AES-GCM los campos de varias entradas y se
+// guarda en la propia entrada cifrada a su vez por la clave maestra del
+// KeyProvider (un archivo local o KMS), que nunca sale de él. Para leerlos
+// hay que pedir al KeyProvider que descifre la clave de datos.
+//
+// Se cifran los metadatos personales (ver DefaultPIIFields) y el payload
+// entero, que es libre y puede contener datos personales en cualquier campo.
+// El hash de la cadena de auditoría se calcula sobre la entrada ya cifrada:
+// la verificación no necesita las claves y destruir una clave maestra hace
+// ilegibles sus entradas sin romper la cadena.
+
+// encryptedValuePrefix identifica un valor cifrado: "enc:v1:" + base64(nonce + texto cifrado)
+const encryptedValuePrefix = "enc:v1:"
+
+// payloadField es el nombre del payload en EntryEncryption.Fields
+const payloadField = "payload"
+
+// DataKey es una clave de datos generada por el KeyProvider: Plaintext cifra
+// los campos y Encrypted es la misma clave cifrada por la clave maestra KeyID
+type DataKey struct {
+	KeyID     string
+	Plaintext []byte
+	Encrypted []byte
+}
+
+// EntryEncryption describe los campos cifrados de una entrada y la clave de
+// datos (cifrada) con que se cifraron
+type EntryEncryption struct {
+	KeyID        string   `json:"key_id"`
+	EncryptedKey string   `json:"encrypted_key"` // base64
+	Fields       []string `json:"fields"`        // "metadata.<clave>" o "payload"
+}
+
+// EncryptedDataKey devuelve la clave de datos cifrada
+func (e *EntryEncryption) EncryptedDataKey() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(e.EncryptedKey)
+}
+
+// EncryptPII devuelve una copia de la entrada con los metadatos personales y
+// el payload cifrados con la clave de datos. El ID de la entrada y el nombre
+// del campo son datos autenticados: un valor cifrado no se puede mover a otro
+// campo ni a otra entrada sin que falle el descifrado.
+func (e *LogEntry) EncryptPII(key *DataKey, fields []string) (*LogEntry, error) {
+	aead, err := newAEAD(key.Plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted := *e
+	encryption := &EntryEncryption{
+		KeyID:        key.KeyID,
+		EncryptedKey: base64.StdEncoding.EncodeToString(key.Encrypted),
+	}
+
+	if len(e.Metadata) > 0 {
+		encrypted.Metadata = make(map[string]string, len(e.Metadata))
+		for name, value := range e.Metadata {
+			encrypted.Metadata[name] = value
+		}
+		for _, name := range fields {
+			value, ok := e.Metadata[name]
+			if !ok {
+				continue
+			}
+			field := "metadata." + name
+			if encrypted.Metadata[name], err = seal(aead, e.ID, field, []byte(value)); err != nil {
+				return nil, err
+			}
+			encryption.Fields = append(encryption.Fields, field)
+		}
+	}
+
+	if len(e.Payload) > 0 {
+		sealed, err := seal(aead, e.ID, payloadField, e.Payload)
+		if err != nil {
+			return nil, err
+		}
+		// El payload cifrado se guarda como un string JSON para que siga siendo JSON válido
+		if encrypted.Payload, err = json.Marshal(sealed); err != nil {
+			return nil, err
+		}
+		encryption.Fields = append(encryption.Fields, payloadField)
+	}
+
+	if len(encryption.Fields) == 0 {
+		return e, nil
+	}
+	sort.Strings(encryption.Fields)
+	encrypted.Encryption = encryption
+	return &encrypted, nil
+}
+
+// DecryptPII descifra en la entrada los campos cifrados con la clave de datos
+// (ya descifrada por el KeyProvider)
+func (e *LogEntry) DecryptPII(dataKey []byte) error {
+	if e.Encryption == nil {
+		return nil
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return err
+	}
+
+	metadata := make(map[string]string, len(e.Metadata))
+	for name, value := range e.Metadata {
+		metadata[name] = value
+	}
+	payload := e.Payload
+
+	for _, field := range e.Encryption.Fields {
+		if field == payloadField {
+			var sealed string
+			if err := json.Unmarshal(e.Payload, &sealed); err != nil {
+				return fmt.Errorf("%w: %s: %v", ErrPIIDecryption, field, err)
+			}
+			if payload, err = open(aead, e.ID, field, sealed); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name, ok := strings.CutPrefix(field, "metadata.")
+		if !ok {
+			return fmt.Errorf("%w: unknown field %q", ErrPIIDecryption, field)
+		}
+		plaintext, err := open(aead, e.ID, field, metadata[name])
+		if err != nil {
+			return err
+		}
+		metadata[name] = string(plaintext)
+	}
+
+	e.Metadata = metadata
+	e.Payload = payload
+	e.Encryption = nil
+	return nil
+}
+
+// RedactPII oculta los datos personales a quien no puede descifrarlos: los
+// campos cifrados y, en las entradas guardadas en claro, los metadatos
+// personales se reemplazan por RedactedValue y el payload se omite. Encryption
+// se conserva para indicar qué campos están cifrados.
+func (e *LogEntry) RedactPII(fields []string) {
+	hidden := make(map[string]bool)
+	for _, name := range fields {
+		hidden["metadata."+name] = true
+	}
+	if e.Encryption != nil {
+		for _, field := range e.Encryption.Fields {
+			hidden[field] = true
+		}
+	}
+
+	if len(e.Metadata) > 0 {
+		metadata := make(map[string]string, len(e.Metadata))
+		for name, value := range e.Metadata {
+			if hidden["metadata."+name] {
+				value = RedactedValue
+			}
+			metadata[name] = value
+		}
+		e.Metadata = metadata
+	}
+	e.Payload = nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal cifra un campo de la entrada entryID
+func seal(aead cipher.AEAD, entryID, field string, plaintext []byte) (string, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, []byte(entryID+"/"+field))
+	return encryptedValuePrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// open descifra un campo cifrado con seal
+func open(aead cipher.AEAD, entryID, field, value string) ([]byte, error) {
+	encoded, ok := strings.CutPrefix(value, encryptedValuePrefix)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not encrypted", ErrPIIDecryption, field)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("%w: %s is malformed", ErrPIIDecryption, field)
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(entryID+"/"+field))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrPIIDecryption, field, err)
+	}
+	return plaintext, nil
+}
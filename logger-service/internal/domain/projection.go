@@ -0,0 +1,164 @@
+package domain
+
+import (
+	"fmt"
+	"pkg/events"
+)
+
+// Projection completa la entrada de log de un tipo de evento a partir de su
+// payload: sujeto, empleado relacionado y metadatos
+type Projection func(event *Event, entry *LogEntry) error
+
+// projectionRule asocia un patrón de tipo de evento ("employee.*") a su proyección
+type projectionRule struct {
+	pattern    string
+	projection Projection
+}
+
+// Projections elige la proyección de cada tipo de evento. Los tipos sin
+// proyección se guardan igualmente con la entrada base (acción, actor, sujeto
+// del envelope y payload).
+type Projections struct {
+	rules []projectionRule
+}
+
+// NewProjections crea un registro de proyecciones vacío
+func NewProjections() *Projections {
+	return &Projections{}
+}
+
+// DefaultProjections devuelve las proyecciones de los eventos conocidos
+func DefaultProjections() *Projections {
+	projections := NewProjections()
+	projections.Register("employee.*", projectEmployee)
+	projections.Register(events.TypeMessageSent, projectMessageSent)
+	projections.Register(events.TypeMessageFailed, projectMessageFailed)
+	projections.Register(events.TypeAuthLogin, projectAuthLogin)
+	projections.Register(events.TypeAuthLoginFailed, projectAuthLoginFailed)
+	return projections
+}
+
+// Register asocia una proyección a un tipo exacto o a un prefijo ("employee.*").
+// Gana el patrón exacto y después el prefijo más largo.
+func (p *Projections) Register(pattern string, projection Projection) {
+	p.rules = append(p.rules, projectionRule{pattern: pattern, projection: projection})
+}
+
+// Project construye la entrada de log del evento
+func (p *Projections) Project(event *Event) (*LogEntry, error) {
+	entry := NewLogEntry(event)
+
+	var projection Projection
+	best := -1
+	for _, rule := range p.rules {
+		if specificity, ok := matchEventType(rule.pattern, event.Type); ok && specificity > best {
+			projection, best = rule.projection, specificity
+		}
+	}
+
+	if projection != nil {
+		if err := projection(event, entry); err != nil {
+			return nil, fmt.Errorf("%s: %w", event.Type, err)
+		}
+	}
+	return entry, nil
+}
+
+// projectEmployee proyecta los eventos employee.*: el sujeto es el empleado
+func projectEmployee(event *Event, entry *LogEntry) error {
+	var payload events.EmployeePayload
+	if err := event.DecodeData(&payload); err != nil {
+		return err
+	}
+	if payload.Employee == nil || payload.Employee.ID == "" {
+		return ErrInvalidEventPayload
+	}
+
+	entry.Subject = Reference{Type: ReferenceEmployee, ID: payload.Employee.ID}
+	entry.EmployeeID = payload.Employee.ID
+	entry.SetMetadata("name", payload.Employee.Name)
+	entry.SetMetadata("email", payload.Employee.Email)
+
+	if transition := payload.Transition; transition != nil {
+		entry.SetMetadata("status_from", transition.From)
+		entry.SetMetadata("status_to", transition.To)
+		entry.SetMetadata("reason_code", transition.ReasonCode)
+		entry.SetMetadata("effective_date", transition.EffectiveDate)
+	}
+	return nil
+}
+
+// projectMessageSent proyecta message.sent: el sujeto es el mensaje y el
+// empleado relacionado, su destinatario
+func projectMessageSent(event *Event, entry *LogEntry) error {
+	var payload events.MessageSentPayload
+	if err := event.DecodeData(&payload); err != nil {
+		return err
+	}
+	if payload.MessageID == "" {
+		return ErrInvalidEventPayload
+	}
+
+	entry.Subject = Reference{Type: ReferenceMessage, ID: payload.MessageID}
+	entry.EmployeeID = payload.EmployeeID
+	entry.SetMetadata("channel", payload.Channel)
+	entry.SetMetadata("to", payload.To)
+	entry.SetMetadata("subject", payload.Subject)
+	return nil
+}
+
+// projectMessageFailed proyecta message.failed: como message.sent, con el
+// error del intento
+func projectMessageFailed(event *Event, entry *LogEntry) error {
+	var payload events.MessageFailedPayload
+	if err := event.DecodeData(&payload); err != nil {
+		return err
+	}
+	if payload.MessageID == "" {
+		return ErrInvalidEventPayload
+	}
+
+	entry.Subject = Reference{Type: ReferenceMessage, ID: payload.MessageID}
+	entry.EmployeeID = payload.EmployeeID
+	entry.SetMetadata("channel", payload.Channel)
+	entry.SetMetadata("to", payload.To)
+	entry.SetMetadata("subject", payload.Subject)
+	entry.SetMetadata("error", payload.Error)
+	return nil
+}
+
+// projectAuthLogin proyecta auth.login: el sujeto es el empleado que inició sesión
+func projectAuthLogin(event *Event, entry *LogEntry) error {
+	var payload events.AuthLoginPayload
+	if err := event.DecodeData(&payload); err != nil {
+		return err
+	}
+	if payload.UserID == "" {
+		return ErrInvalidEventPayload
+	}
+
+	entry.Subject = Reference{Type: ReferenceEmployee, ID: payload.UserID}
+	entry.EmployeeID = payload.UserID
+	entry.SetMetadata("email", payload.Email)
+	return nil
+}
+
+// projectAuthLoginFailed proyecta auth.login_failed: el sujeto es el empleado
+// si el email existe; el email y el motivo quedan en los metadatos
+func projectAuthLoginFailed(event *Event, entry *LogEntry) error {
+	var payload events.AuthLoginFailedPayload
+	if err := event.DecodeData(&payload); err != nil {
+		return err
+	}
+	if payload.Email == "" {
+		return ErrInvalidEventPayload
+	}
+
+	if payload.UserID != "" {
+		entry.Subject = Reference{Type: ReferenceEmployee, ID: payload.UserID}
+		entry.EmployeeID = payload.UserID
+	}
+	entry.SetMetadata("email", payload.Email)
+	entry.SetMetadata("reason", payload.Reason)
+	return nil
+}
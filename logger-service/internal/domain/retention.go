@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetentionRule fija cuánto se conservan los eventos de un tipo. Pattern admite
+// un tipo exacto o un prefijo ("employee.*"); Retention 0 = sin vencimiento.
+type RetentionRule struct {
+	Pattern   string
+	Retention time.Duration
+}
+
+// RetentionPolicy decide el vencimiento de cada entrada según su tipo. Gana la
+// regla exacta, después el prefijo más largo y por último Default (0 = sin vencimiento).
+type RetentionPolicy struct {
+	Default time.Duration
+	Rules   []RetentionRule
+}
+
+// ParseRetentionPolicy construye la política a partir de los días por defecto y
+// de una lista "tipo=días" separada por comas
+// (p.ej. "employee.deleted=2555,message.*=90")
+func ParseRetentionPolicy(defaultDays int, rules string) (RetentionPolicy, error) {
+	if defaultDays < 0 {
+		return RetentionPolicy{}, fmt.Errorf("%w: negative default days", ErrInvalidRetentionRule)
+	}
+	policy := RetentionPolicy{Default: days(defaultDays)}
+
+	for _, rule := range strings.Split(rules, ",") {
+		if rule = strings.TrimSpace(rule); rule == "" {
+			continue
+		}
+		pattern, value, ok := strings.Cut(rule, "=")
+		pattern = strings.TrimSpace(pattern)
+		count, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || pattern == "" || err != nil || count < 0 {
+			return RetentionPolicy{}, fmt.Errorf("%w: %q", ErrInvalidRetentionRule, rule)
+		}
+		policy.Rules = append(policy.Rules, RetentionRule{Pattern: pattern, Retention: days(count)})
+	}
+	return policy, nil
+}
+
+// RetentionFor devuelve la retención de un tipo de evento (0 = sin vencimiento)
+func (p RetentionPolicy) RetentionFor(eventType string) time.Duration {
+	retention, best := p.Default, -1
+	for _, rule := range p.Rules {
+		if specificity, ok := matchEventType(rule.Pattern, eventType); ok && specificity > best {
+			retention, best = rule.Retention, specificity
+		}
+	}
+	return retention
+}
+
+// Apply fija RetainUntil a partir del timestamp del evento; sin vencimiento lo deja vacío
+func (p RetentionPolicy) Apply(entry *LogEntry) {
+	entry.RetainUntil = nil
+	if retention := p.RetentionFor(entry.EventType); retention > 0 {
+		retainUntil := entry.Timestamp.UTC().Add(retention)
+		entry.RetainUntil = &retainUntil
+	}
+}
+
+// String describe la política para los logs de arranque
+func (p RetentionPolicy) String() string {
+	describe := func(retention time.Duration) string {
+		if retention == 0 {
+			return "forever"
+		}
+		return fmt.Sprintf("%dd", int(retention/(24*time.Hour)))
+	}
+
+	parts := []string{"default=" + describe(p.Default)}
+	for _, rule := range p.Rules {
+		parts = append(parts, rule.Pattern+"="+describe(rule.Retention))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func days(count int) time.Duration {
+	return time.Duration(count) * 24 * time.Hour
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "strings"
+
+// StreamFilter selecciona las entradas que recibe un suscriptor del stream en
+// vivo. EventTypes admite tipos exactos y prefijos ("employee.*"); vacío = todos.
+type StreamFilter struct {
+	EventTypes []string
+}
+
+// ParseStreamFilter construye el filtro a partir de una lista separada por comas
+func ParseStreamFilter(value string) StreamFilter {
+	var filter StreamFilter
+	for _, eventType := range strings.Split(value, ",") {
+		if eventType = strings.TrimSpace(eventType); eventType != "" {
+			filter.EventTypes = append(filter.EventTypes, eventType)
+		}
+	}
+	return filter
+}
+
+// Matches indica si la entrada cumple el filtro
+func (f StreamFilter) Matches(entry *LogEntry) bool {
+	if len(f.EventTypes) == 0 {
+		return true
+	}
+	for _, pattern := range f.EventTypes {
+		if _, ok := matchEventType(pattern, entry.EventType); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package infrastructure
+
+import (
+	"fmt"
+	"logger-service/internal/domain"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AlertConfig es la configuración de alertas leída del archivo YAML: las
+// reglas y los canales de aviso. Un canal sin configurar (URL o cola vacía)
+// queda desactivado.
+type AlertConfig struct {
+	Rules               []domain.AlertRule
+	WebhookURL          string
+	WebhookSecret       string
+	MessagingQueueURL   string
+	MessagingRecipients []string
+}
+
+// alertFile es el formato del archivo de reglas. Los valores de notifiers
+// admiten variables de entorno (${ALERT_WEBHOOK_URL}).
+type alertFile struct {
+	Notifiers struct {
+		Webhook struct {
+			URL    string `yaml:"url"`
+			Secret string `yaml:"secret"`
+		} `yaml:"webhook"`
+		Messaging struct {
+			QueueURL   string   `yaml:"queue_url"`
+			Recipients []string `yaml:"recipients"`
+		} `yaml:"messaging"`
+	} `yaml:"notifiers"`
+	Rules []alertRuleYAML `yaml:"rules"`
+}
+
+type alertRuleYAML struct {
+	Name        string          `yaml:"name"`
+	Description string          `yaml:"description"`
+	EventType   string          `yaml:"event_type"`
+	Where       []predicateYAML `yaml:"where"`
+	GroupBy     []string        `yaml:"group_by"`
+	Window      string          `yaml:"window"`
+	Threshold   int             `yaml:"threshold"`
+	Cooldown    string          `yaml:"cooldown"`
+	Severity    string          `yaml:"severity"`
+	Notify      []string        `yaml:"notify"`
+	Recipients  []string        `yaml:"recipients"`
+}
+
+// predicateYAML admite value (un valor) o values (lista)
+type predicateYAML struct {
+	Field  string   `yaml:"field"`
+	Op     string   `yaml:"op"`
+	Value  *string  `yaml:"value"`
+	Values []string `yaml:"values"`
+}
+
+// LoadAlertConfig lee y valida el archivo de reglas de alerta
+func LoadAlertConfig(path string) (*AlertConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file alertFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	config := &AlertConfig{
+		WebhookURL:        os.ExpandEnv(file.Notifiers.Webhook.URL),
+		WebhookSecret:     os.ExpandEnv(file.Notifiers.Webhook.Secret),
+		MessagingQueueURL: os.ExpandEnv(file.Notifiers.Messaging.QueueURL),
+	}
+	for _, recipient := range file.Notifiers.Messaging.Recipients {
+		if recipient = os.ExpandEnv(recipient); recipient != "" {
+			config.MessagingRecipients = append(config.MessagingRecipients, recipient)
+		}
+	}
+
+	names := make(map[string]bool)
+	for i, raw := range file.Rules {
+		rule, err := raw.toRule()
+		if err != nil {
+			return nil, fmt.Errorf("%s: rule %d: %w", path, i+1, err)
+		}
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: rule %d: %w", path, i+1, err)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("%s: %w: duplicate name %q", path, domain.ErrInvalidAlertRule, rule.Name)
+		}
+		names[rule.Name] = true
+		config.Rules = append(config.Rules, rule)
+	}
+	return config, nil
+}
+
+// toRule convierte la regla del archivo (duraciones como "5m") a la del dominio
+func (r alertRuleYAML) toRule() (domain.AlertRule, error) {
+	rule := domain.AlertRule{
+		Name:        r.Name,
+		Description: r.Description,
+		EventType:   r.EventType,
+		GroupBy:     r.GroupBy,
+		Threshold:   r.Threshold,
+		Severity:    r.Severity,
+		Notify:      r.Notify,
+		Recipients:  r.Recipients,
+	}
+
+	var err error
+	if rule.Window, err = parseRuleDuration("window", r.Window); err != nil {
+		return rule, err
+	}
+	if rule.Cooldown, err = parseRuleDuration("cooldown", r.Cooldown); err != nil {
+		return rule, err
+	}
+
+	for _, predicate := range r.Where {
+		values := predicate.Values
+		if predicate.Value != nil {
+			values = append([]string{*predicate.Value}, values...)
+		}
+		rule.Where = append(rule.Where, domain.FieldPredicate{
+			Field:  predicate.Field,
+			Op:     predicate.Op,
+			Values: values,
+		})
+	}
+	return rule, nil
+}
+
+// parseRuleDuration interpreta una duración de Go ("30s", "5m", "1h"); vacía = 0
+func parseRuleDuration(name, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid %s %q", domain.ErrInvalidAlertRule, name, value)
+	}
+	return duration, nil
+}
@@ -0,0 +1,36 @@
+package infrastructure
+
+import (
+	"fmt"
+	"logger-service/internal/ports"
+	"os"
+	"pkg/awsclient"
+)
+
+// NewArchiveStoreFromEnv crea el almacenamiento de archivos de logs según
+// ARCHIVE_STORE: "local" (por defecto) escribe en ARCHIVE_DIR ("archives") y
+// "s3" en el bucket ARCHIVE_BUCKET bajo ARCHIVE_PREFIX ("employee-logs/")
+func NewArchiveStoreFromEnv(clients *awsclient.Factory) (ports.ObjectStore, error) {
+	switch kind := os.Getenv("ARCHIVE_STORE"); kind {
+	case "", "local":
+		dir := os.Getenv("ARCHIVE_DIR")
+		if dir == "" {
+			dir = "archives"
+		}
+		return NewLocalObjectStore(dir), nil
+
+	case "s3":
+		bucket := os.Getenv("ARCHIVE_BUCKET")
+		if bucket == "" {
+			return nil, fmt.Errorf("ARCHIVE_BUCKET is required when ARCHIVE_STORE=s3")
+		}
+		prefix, ok := os.LookupEnv("ARCHIVE_PREFIX")
+		if !ok {
+			prefix = "employee-logs/"
+		}
+		return NewS3ObjectStore(clients.S3(), bucket, prefix), nil
+
+	default:
+		return nil, fmt.Errorf("unknown ARCHIVE_STORE %q (use local or s3)", kind)
+	}
+}
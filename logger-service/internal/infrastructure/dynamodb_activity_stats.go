@@ -0,0 +1,140 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"logger-service/internal/domain"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Atributos de la tabla de contadores de actividad. Cada serie (tipo de evento
+// y granularidad, p.ej. "employee.created#day") es una partición y Bucket, el
+// inicio del intervalo en RFC3339 UTC, la ordena cronológicamente.
+const (
+	seriesAttribute      = "Series"
+	bucketAttribute      = "Bucket"
+	countAttribute       = "Count"
+	statsEventAttribute  = "EventType"
+	statsExpiryAttribute = "ExpiresAt" // TTL de los contadores por hora
+)
+
+// ActivityStatsAPI es el subconjunto del cliente de DynamoDB que usan los
+// contadores de actividad; *dynamodb.Client lo implementa
+type ActivityStatsAPI interface {
+	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	dynamodb.QueryAPIClient
+}
+
+// DynamoDBActivityStats implementa los contadores de actividad con
+// incrementos atómicos (UpdateItem ADD) en DynamoDB
+type DynamoDBActivityStats struct {
+	client          ActivityStatsAPI
+	tableName       string
+	hourlyRetention time.Duration
+}
+
+// NewDynamoDBActivityStats crea el repositorio de contadores. Los contadores
+// por hora caducan (TTL) hourlyRetention después de su hora (0 = nunca); los
+// diarios se conservan siempre.
+func NewDynamoDBActivityStats(client ActivityStatsAPI, tableName string, hourlyRetention time.Duration) *DynamoDBActivityStats {
+	return &DynamoDBActivityStats{
+		client:          client,
+		tableName:       tableName,
+		hourlyRetention: hourlyRetention,
+	}
+}
+
+// Increment suma un evento a los contadores de la hora y del día de at
+func (s *DynamoDBActivityStats) Increment(ctx context.Context, eventType string, at time.Time) error {
+	for _, size := range []domain.BucketSize{domain.BucketHour, domain.BucketDay} {
+		start := size.Start(at)
+
+		update := "ADD #count :one SET #eventType = :eventType"
+		names := map[string]string{
+			"#count":     countAttribute,
+			"#eventType": statsEventAttribute,
+		}
+		values := map[string]types.AttributeValue{
+			":one":       &types.AttributeValueMemberN{Value: "1"},
+			":eventType": &types.AttributeValueMemberS{Value: eventType},
+		}
+		if size == domain.BucketHour && s.hourlyRetention > 0 {
+			update += ", #expiresAt = :expiresAt"
+			names["#expiresAt"] = statsExpiryAttribute
+			values[":expiresAt"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(start.Add(s.hourlyRetention).Unix(), 10)}
+		}
+
+		_, err := s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName: aws.String(s.tableName),
+			Key: map[string]types.AttributeValue{
+				seriesAttribute: &types.AttributeValueMemberS{Value: seriesKey(eventType, size)},
+				bucketAttribute: &types.AttributeValueMemberS{Value: start.Format(time.RFC3339)},
+			},
+			UpdateExpression:          aws.String(update),
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+		})
+		if err != nil {
+			return fmt.Errorf("error incrementing %s counter of %s: %w", size, eventType, err)
+		}
+	}
+	return nil
+}
+
+// FindCounters obtiene los contadores de una serie entre from y to (inclusive)
+func (s *DynamoDBActivityStats) FindCounters(ctx context.Context, eventType string, size domain.BucketSize, from, to time.Time) ([]domain.ActivityCounter, error) {
+	paginator := dynamodb.NewQueryPaginator(s.client, &dynamodb.QueryInput{
+		TableName:              aws.String(s.tableName),
+		KeyConditionExpression: aws.String("#series = :series AND #bucket BETWEEN :from AND :to"),
+		ExpressionAttributeNames: map[string]string{
+			"#series": seriesAttribute,
+			"#bucket": bucketAttribute,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":series": &types.AttributeValueMemberS{Value: seriesKey(eventType, size)},
+			":from":   &types.AttributeValueMemberS{Value: from.UTC().Format(time.RFC3339)},
+			":to":     &types.AttributeValueMemberS{Value: to.UTC().Format(time.RFC3339)},
+		},
+	})
+
+	var counters []domain.ActivityCounter
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range result.Items {
+			bucket, ok := item[bucketAttribute].(*types.AttributeValueMemberS)
+			if !ok {
+				continue
+			}
+			start, err := time.Parse(time.RFC3339, bucket.Value)
+			if err != nil {
+				continue
+			}
+
+			var count int64
+			if value, ok := item[countAttribute].(*types.AttributeValueMemberN); ok {
+				count, _ = strconv.ParseInt(value.Value, 10, 64)
+			}
+			counters = append(counters, domain.ActivityCounter{
+				EventType: eventType,
+				Size:      size,
+				Start:     start,
+				Count:     count,
+			})
+		}
+	}
+	return counters, nil
+}
+
+// seriesKey es la partición de los contadores de un tipo y granularidad
+func seriesKey(eventType string, size domain.BucketSize) string {
+	return eventType + "#" + string(size)
+}
@@ -0,0 +1,213 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"logger-service/internal/domain"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// statsQueryPageSize fuerza varias páginas en las consultas del fake
+const statsQueryPageSize = 2
+
+// fakeStatsTable es una tabla de contadores en memoria. Aplica las
+// actualizaciones como DynamoDB: de forma atómica sobre el item, sin leerlo
+// antes desde el cliente.
+type fakeStatsTable struct {
+	mu      sync.Mutex
+	items   map[string]map[string]types.AttributeValue // Series → Bucket → Count/EventType/ExpiresAt
+	updates []*dynamodb.UpdateItemInput
+	queries int
+}
+
+func newFakeStatsTable() *fakeStatsTable {
+	return &fakeStatsTable{items: make(map[string]map[string]types.AttributeValue)}
+}
+
+func (f *fakeStatsTable) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.updates = append(f.updates, params)
+
+	expression := aws.ToString(params.UpdateExpression)
+	if !strings.HasPrefix(expression, "ADD #count :one SET #eventType = :eventType") {
+		return nil, errors.New("unexpected update expression: " + expression)
+	}
+	series := params.Key[seriesAttribute].(*types.AttributeValueMemberS).Value
+	bucket := params.Key[bucketAttribute].(*types.AttributeValueMemberS).Value
+	key := series + "|" + bucket
+
+	item, ok := f.items[key]
+	if !ok {
+		item = map[string]types.AttributeValue{
+			seriesAttribute: params.Key[seriesAttribute],
+			bucketAttribute: params.Key[bucketAttribute],
+		}
+		f.items[key] = item
+	}
+	count := numberValue(item[countAttribute]) + numberValue(params.ExpressionAttributeValues[":one"])
+	item[countAttribute] = &types.AttributeValueMemberN{Value: strconv.FormatInt(count, 10)}
+	item[statsEventAttribute] = params.ExpressionAttributeValues[":eventType"]
+	if strings.Contains(expression, "#expiresAt = :expiresAt") {
+		item[statsExpiryAttribute] = params.ExpressionAttributeValues[":expiresAt"]
+	}
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func (f *fakeStatsTable) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queries++
+
+	values := params.ExpressionAttributeValues
+	series := values[":series"].(*types.AttributeValueMemberS).Value
+	from := values[":from"].(*types.AttributeValueMemberS).Value
+	to := values[":to"].(*types.AttributeValueMemberS).Value
+	after := ""
+	if params.ExclusiveStartKey != nil {
+		after = params.ExclusiveStartKey[bucketAttribute].(*types.AttributeValueMemberS).Value
+	}
+
+	var buckets []string
+	for key := range f.items {
+		itemSeries, bucket, _ := strings.Cut(key, "|")
+		if itemSeries == series && bucket >= from && bucket <= to && bucket > after {
+			buckets = append(buckets, bucket)
+		}
+	}
+	sort.Strings(buckets)
+
+	output := &dynamodb.QueryOutput{}
+	for _, bucket := range buckets {
+		if len(output.Items) == statsQueryPageSize {
+			last := output.Items[len(output.Items)-1]
+			output.LastEvaluatedKey = map[string]types.AttributeValue{
+				seriesAttribute: last[seriesAttribute],
+				bucketAttribute: last[bucketAttribute],
+			}
+			break
+		}
+		output.Items = append(output.Items, f.items[series+"|"+bucket])
+	}
+	return output, nil
+}
+
+func numberValue(value types.AttributeValue) int64 {
+	number, ok := value.(*types.AttributeValueMemberN)
+	if !ok {
+		return 0
+	}
+	n, _ := strconv.ParseInt(number.Value, 10, 64)
+	return n
+}
+
+func TestDynamoDBActivityStatsIncrement(t *testing.T) {
+	table := newFakeStatsTable()
+	stats := NewDynamoDBActivityStats(table, "activity-stats", 48*time.Hour)
+	at := time.Date(2026, 3, 18, 15, 30, 0, 0, time.UTC)
+
+	// Incrementos concurrentes (p.ej. varios workers) no se pierden: cada uno es un ADD
+	const increments = 20
+	var wg sync.WaitGroup
+	for i := 0; i < increments; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := stats.Increment(context.Background(), "employee.created", at); err != nil {
+				t.Errorf("Increment() = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(table.updates) != 2*increments {
+		t.Fatalf("UpdateItem called %d times, want %d (hour and day per event)", len(table.updates), 2*increments)
+	}
+
+	hourly := table.items["employee.created#hour|2026-03-18T15:00:00Z"]
+	daily := table.items["employee.created#day|2026-03-18T00:00:00Z"]
+	if numberValue(hourly[countAttribute]) != increments || numberValue(daily[countAttribute]) != increments {
+		t.Errorf("counts = %d hourly, %d daily, want %d", numberValue(hourly[countAttribute]), numberValue(daily[countAttribute]), increments)
+	}
+
+	// Solo los contadores por hora caducan, hourlyRetention después de su hora
+	wantExpiry := time.Date(2026, 3, 20, 15, 0, 0, 0, time.UTC).Unix()
+	if got := numberValue(hourly[statsExpiryAttribute]); got != wantExpiry {
+		t.Errorf("hourly %s = %d, want %d", statsExpiryAttribute, got, wantExpiry)
+	}
+	if _, ok := daily[statsExpiryAttribute]; ok {
+		t.Errorf("daily counter has %s", statsExpiryAttribute)
+	}
+	if eventType, _ := daily[statsEventAttribute].(*types.AttributeValueMemberS); eventType == nil || eventType.Value != "employee.created" {
+		t.Errorf("daily %s = %v, want employee.created", statsEventAttribute, daily[statsEventAttribute])
+	}
+}
+
+func TestDynamoDBActivityStatsIncrementWithoutRetention(t *testing.T) {
+	table := newFakeStatsTable()
+	stats := NewDynamoDBActivityStats(table, "activity-stats", 0)
+
+	if err := stats.Increment(context.Background(), "message.sent", time.Date(2026, 3, 18, 15, 30, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("Increment() = %v", err)
+	}
+	for key, item := range table.items {
+		if _, ok := item[statsExpiryAttribute]; ok {
+			t.Errorf("%s has %s with retention disabled", key, statsExpiryAttribute)
+		}
+	}
+}
+
+func TestDynamoDBActivityStatsFindCounters(t *testing.T) {
+	table := newFakeStatsTable()
+	stats := NewDynamoDBActivityStats(table, "activity-stats", 0)
+	ctx := context.Background()
+
+	increments := map[time.Time]int{
+		time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC):  1, // fuera del rango
+		time.Date(2026, 3, 15, 9, 0, 0, 0, time.UTC):  2,
+		time.Date(2026, 3, 16, 23, 0, 0, 0, time.UTC): 1,
+		time.Date(2026, 3, 17, 0, 30, 0, 0, time.UTC): 3,
+		time.Date(2026, 3, 18, 12, 0, 0, 0, time.UTC): 1,
+	}
+	for at, n := range increments {
+		for i := 0; i < n; i++ {
+			if err := stats.Increment(ctx, "employee.created", at); err != nil {
+				t.Fatalf("Increment() = %v", err)
+			}
+		}
+	}
+	// Otro tipo de evento no se mezcla con la serie consultada
+	stats.Increment(ctx, "employee.deleted", time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC))
+
+	counters, err := stats.FindCounters(ctx, "employee.created", domain.BucketDay,
+		time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 18, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("FindCounters() = %v", err)
+	}
+
+	var got []string
+	for _, counter := range counters {
+		if counter.EventType != "employee.created" || counter.Size != domain.BucketDay {
+			t.Errorf("counter %+v has the wrong series", counter)
+		}
+		got = append(got, counter.Start.Format("2006-01-02")+"="+strconv.FormatInt(counter.Count, 10))
+	}
+	want := []string{"2026-03-15=2", "2026-03-16=1", "2026-03-17=3", "2026-03-18=1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindCounters() = %v, want %v", got, want)
+	}
+	// Los resultados se recorren página a página
+	if table.queries != 2 {
+		t.Errorf("Query called %d times, want 2 pages", table.queries)
+	}
+}
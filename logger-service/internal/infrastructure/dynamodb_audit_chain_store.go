@@ -0,0 +1,199 @@
+package infrastructure
+
+import (
+	"context"
+	"logger-service/internal/domain"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// chainIndex es el índice de la tabla de logs que ordena cada cadena por
+// secuencia (ChainID + ChainSeq)
+const chainIndex = "ChainIndex"
+
+// DynamoDBAuditChainStore implementa el almacén de cadenas de auditoría: las
+// entradas en la tabla de logs, las cabezas en chainHeadTable y los checkpoints
+// firmados en checkpointTable (ChainID + Seq)
+type DynamoDBAuditChainStore struct {
+	client          *dynamodb.Client
+	logsTable       string
+	chainHeadTable  string
+	checkpointTable string
+}
+
+// NewDynamoDBAuditChainStore crea una nueva instancia del almacén
+func NewDynamoDBAuditChainStore(client *dynamodb.Client, logsTable, chainHeadTable, checkpointTable string) *DynamoDBAuditChainStore {
+	return &DynamoDBAuditChainStore{
+		client:          client,
+		logsTable:       logsTable,
+		chainHeadTable:  chainHeadTable,
+		checkpointTable: checkpointTable,
+	}
+}
+
+// getChainHead lee con consistencia fuerte la cabeza de una cadena (nil si no existe)
+func getChainHead(ctx context.Context, client *dynamodb.Client, table, chainID string) (*domain.ChainHead, error) {
+	result, err := client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(table),
+		ConsistentRead: aws.Bool(true),
+		Key: map[string]types.AttributeValue{
+			"ChainID": &types.AttributeValueMemberS{Value: chainID},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	var head domain.ChainHead
+	if err := attributevalue.UnmarshalMap(result.Item, &head); err != nil {
+		return nil, err
+	}
+	return &head, nil
+}
+
+// GetHead obtiene la cabeza de una cadena (nil si no existe)
+func (s *DynamoDBAuditChainStore) GetHead(ctx context.Context, chainID string) (*domain.ChainHead, error) {
+	return getChainHead(ctx, s.client, s.chainHeadTable, chainID)
+}
+
+// ListHeads recorre las cabezas de todas las cadenas (Scan de la tabla de
+// cabezas, que tiene un ítem por cadena)
+func (s *DynamoDBAuditChainStore) ListHeads(ctx context.Context, fn func(*domain.ChainHead) error) error {
+	return s.scanHeads(ctx, &dynamodb.ScanInput{
+		TableName:      aws.String(s.chainHeadTable),
+		ConsistentRead: aws.Bool(true),
+	}, fn)
+}
+
+// HeadsPendingCheckpoint obtiene las cabezas que avanzaron desde su último checkpoint
+func (s *DynamoDBAuditChainStore) HeadsPendingCheckpoint(ctx context.Context) ([]*domain.ChainHead, error) {
+	var heads []*domain.ChainHead
+	err := s.scanHeads(ctx, &dynamodb.ScanInput{
+		TableName:        aws.String(s.chainHeadTable),
+		FilterExpression: aws.String("attribute_not_exists(CheckpointedSeq) OR CheckpointedSeq < Seq"),
+	}, func(head *domain.ChainHead) error {
+		heads = append(heads, head)
+		return nil
+	})
+	return heads, err
+}
+
+func (s *DynamoDBAuditChainStore) scanHeads(ctx context.Context, input *dynamodb.ScanInput, fn func(*domain.ChainHead) error) error {
+	paginator := dynamodb.NewScanPaginator(s.client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, item := range page.Items {
+			var head domain.ChainHead
+			if err := attributevalue.UnmarshalMap(item, &head); err != nil {
+				return err
+			}
+			if err := fn(&head); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// SaveCheckpoint guarda el checkpoint y avanza CheckpointedSeq en la cabeza.
+// Si otra instancia ya registró un checkpoint igual o posterior no hace nada.
+func (s *DynamoDBAuditChainStore) SaveCheckpoint(ctx context.Context, checkpoint *domain.Checkpoint) error {
+	item, err := attributevalue.MarshalMap(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Put: &types.Put{
+					TableName: aws.String(s.checkpointTable),
+					Item:      item,
+				},
+			},
+			{
+				Update: &types.Update{
+					TableName: aws.String(s.chainHeadTable),
+					Key: map[string]types.AttributeValue{
+						"ChainID": &types.AttributeValueMemberS{Value: checkpoint.ChainID},
+					},
+					UpdateExpression:    aws.String("SET CheckpointedSeq = :seq"),
+					ConditionExpression: aws.String("attribute_exists(ChainID) AND (attribute_not_exists(CheckpointedSeq) OR CheckpointedSeq < :seq)"),
+					ExpressionAttributeValues: map[string]types.AttributeValue{
+						":seq": &types.AttributeValueMemberN{Value: strconv.FormatInt(checkpoint.Seq, 10)},
+					},
+				},
+			},
+		},
+	})
+	if isConditionalCancel(err) {
+		return nil
+	}
+	return err
+}
+
+// ListCheckpoints obtiene los checkpoints de una cadena ordenados por secuencia
+func (s *DynamoDBAuditChainStore) ListCheckpoints(ctx context.Context, chainID string) ([]*domain.Checkpoint, error) {
+	paginator := dynamodb.NewQueryPaginator(s.client, &dynamodb.QueryInput{
+		TableName:              aws.String(s.checkpointTable),
+		KeyConditionExpression: aws.String("ChainID = :chainID"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":chainID": &types.AttributeValueMemberS{Value: chainID},
+		},
+	})
+
+	var checkpoints []*domain.Checkpoint
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range page.Items {
+			var checkpoint domain.Checkpoint
+			if err := attributevalue.UnmarshalMap(item, &checkpoint); err != nil {
+				return nil, err
+			}
+			checkpoints = append(checkpoints, &checkpoint)
+		}
+	}
+	return checkpoints, nil
+}
+
+// ReadChain recorre las entradas de una cadena en orden de secuencia mediante ChainIndex
+func (s *DynamoDBAuditChainStore) ReadChain(ctx context.Context, chainID string, fn func(*domain.LogEntry) error) error {
+	paginator := dynamodb.NewQueryPaginator(s.client, &dynamodb.QueryInput{
+		TableName:              aws.String(s.logsTable),
+		IndexName:              aws.String(chainIndex),
+		KeyConditionExpression: aws.String("ChainID = :chainID"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":chainID": &types.AttributeValueMemberS{Value: chainID},
+		},
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, item := range page.Items {
+			entry, err := unmarshalLogEntry(item)
+			if err != nil {
+				return err
+			}
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
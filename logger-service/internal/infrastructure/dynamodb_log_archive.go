@@ -0,0 +1,122 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"log"
+	"logger-service/internal/domain"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// archiveIndex es un índice disperso de la tabla de logs (ArchiveDay + SortKey):
+// solo contiene las entradas con retención que aún no se archivaron, agrupadas
+// por el día UTC en que vence su retención. Al archivarlas se borra ArchiveDay
+// y salen del índice.
+const archiveIndex = "ArchiveIndex"
+
+// Atributos de la tabla de logs que usa el archivado
+const (
+	archiveDayAttribute = "ArchiveDay"
+	archiveKeyAttribute = "ArchiveKey" // archivo que contiene la entrada
+	expiresAtAttribute  = "ExpiresAt"  // atributo TTL de la tabla, en segundos Unix
+)
+
+// FindPendingArchive recorre, en páginas, las entradas cuya retención vence el
+// día day (UTC) y que todavía no se archivaron
+func (r *DynamoDBLogRepository) FindPendingArchive(ctx context.Context, day time.Time, fn func([]*domain.LogEntry) error) error {
+	paginator := dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String(archiveIndex),
+		KeyConditionExpression: aws.String("#day = :day"),
+		ExpressionAttributeNames: map[string]string{
+			"#day": archiveDayAttribute,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":day": &types.AttributeValueMemberS{Value: day.UTC().Format(logDateLayout)},
+		},
+	})
+
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		entries := make([]*domain.LogEntry, 0, len(result.Items))
+		for _, item := range result.Items {
+			entry, err := unmarshalLogEntry(item)
+			if err != nil {
+				log.Printf("Error unmarshaling log entry: %v", err)
+				continue
+			}
+			entries = append(entries, entry)
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		if err := fn(entries); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MarkArchived registra el archivo de cada entrada, la saca de ArchiveIndex y
+// le asigna el TTL con el que DynamoDB la borra. Las entradas que ya no
+// existen se ignoran.
+func (r *DynamoDBLogRepository) MarkArchived(ctx context.Context, entries []*domain.LogEntry, archiveKey string, expiresAt time.Time) error {
+	for _, entry := range entries {
+		_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName: aws.String(r.tableName),
+			Key: map[string]types.AttributeValue{
+				"ID": &types.AttributeValueMemberS{Value: entry.ID},
+			},
+			UpdateExpression:    aws.String("SET #archiveKey = :archiveKey, #expiresAt = :expiresAt REMOVE #archiveDay"),
+			ConditionExpression: aws.String("attribute_exists(ID)"),
+			ExpressionAttributeNames: map[string]string{
+				"#archiveKey": archiveKeyAttribute,
+				"#expiresAt":  expiresAtAttribute,
+				"#archiveDay": archiveDayAttribute,
+			},
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":archiveKey": &types.AttributeValueMemberS{Value: archiveKey},
+				":expiresAt":  &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt.Unix(), 10)},
+			},
+		})
+		var conditionFailed *types.ConditionalCheckFailedException
+		if err != nil && !errors.As(err, &conditionFailed) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Restore vuelve a insertar una entrada archivada con sus campos de cadena
+// originales, sin tocar la cabeza de la cadena ni volver a ArchiveIndex.
+// Devuelve false si la entrada ya existe. expiresAt cero = sin TTL.
+func (r *DynamoDBLogRepository) Restore(ctx context.Context, entry *domain.LogEntry, archiveKey string, expiresAt time.Time) (bool, error) {
+	item, err := marshalLogEntry(entry)
+	if err != nil {
+		return false, err
+	}
+	item[archiveKeyAttribute] = &types.AttributeValueMemberS{Value: archiveKey}
+	if !expiresAt.IsZero() {
+		item[expiresAtAttribute] = &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt.Unix(), 10)}
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(ID)"),
+	})
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -2,47 +2,178 @@ package infrastructure
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"log"
 	"logger-service/internal/domain"
+	"strconv"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
+// Índices secundarios globales de la tabla de logs. Todos usan SortKey como
+// clave de ordenamiento, así que cada consulta es un Query por partición
+// ordenado por timestamp y nunca un Scan:
+//   - EmployeeIndex:  EmployeeID + SortKey (historial de un empleado)
+//   - EventTypeIndex: EventType + SortKey (eventos de un tipo)
+//   - LogDateIndex:   LogDate + SortKey (todos los eventos de un día UTC)
+const (
+	employeeIndex  = "EmployeeIndex"
+	eventTypeIndex = "EventTypeIndex"
+	logDateIndex   = "LogDateIndex"
+)
+
+// Atributos derivados que se agregan a cada entrada para los índices
+const (
+	sortKeyAttribute = "SortKey"
+	logDateAttribute = "LogDate"
+)
+
+// sortKeyLayout tiene ancho fijo para que el orden lexicográfico de SortKey
+// coincida con el cronológico
+const sortKeyLayout = "2006-01-02T15:04:05.000000000Z"
+
+// logDateLayout es el formato de la partición diaria de LogDateIndex
+const logDateLayout = "2006-01-02"
+
+// maxChainAppendAttempts es el número de intentos de agregar una entrada a su
+// cadena cuando otro worker avanzó la cabeza al mismo tiempo
+const maxChainAppendAttempts = 5
+
 // DynamoDBLogRepository implementa el repositorio de logs usando DynamoDB
 type DynamoDBLogRepository struct {
-	client    *dynamodb.Client
-	tableName string
+	client         *dynamodb.Client
+	tableName      string
+	chainHeadTable string
 }
 
-// NewDynamoDBLogRepository crea una nueva instancia del repositorio
-func NewDynamoDBLogRepository(client *dynamodb.Client, tableName string) *DynamoDBLogRepository {
+// NewDynamoDBLogRepository crea una nueva instancia del repositorio.
+// chainHeadTable guarda la cabeza de cada cadena de auditoría.
+func NewDynamoDBLogRepository(client *dynamodb.Client, tableName, chainHeadTable string) *DynamoDBLogRepository {
 	return &DynamoDBLogRepository{
-		client:    client,
-		tableName: tableName,
+		client:         client,
+		tableName:      tableName,
+		chainHeadTable: chainHeadTable,
 	}
 }
 
-// Save guarda una entrada de log en DynamoDB
+// Save encadena la entrada a continuación de la cabeza de su cadena y la
+// guarda en una transacción que avanza la cabeza solo si nadie la movió desde
+// que se leyó. Si otro worker ganó la carrera se relee la cabeza y se reintenta.
 func (r *DynamoDBLogRepository) Save(ctx context.Context, entry *domain.LogEntry) error {
-	item, err := attributevalue.MarshalMap(entry)
+	var err error
+	for attempt := 1; attempt <= maxChainAppendAttempts; attempt++ {
+		var head *domain.ChainHead
+		if head, err = getChainHead(ctx, r.client, r.chainHeadTable, domain.ChainIDFor(entry)); err != nil {
+			return err
+		}
+		entry.Link(head)
+
+		if err = r.appendToChain(ctx, entry); err == nil {
+			log.Printf("Log entry saved successfully: %s (chain %s, seq %d)", entry.ID, entry.ChainID, entry.ChainSeq)
+			return nil
+		}
+		if !isConditionalCancel(err) {
+			break
+		}
+		time.Sleep(time.Duration(attempt*20) * time.Millisecond)
+	}
+
+	log.Printf("Error saving log entry to DynamoDB: %v", err)
+	return err
+}
+
+// appendToChain escribe la entrada y avanza la cabeza de la cadena en una transacción
+func (r *DynamoDBLogRepository) appendToChain(ctx context.Context, entry *domain.LogEntry) error {
+	item, err := marshalLogEntry(entry)
 	if err != nil {
 		return err
 	}
+	if entry.RetainUntil != nil {
+		item[archiveDayAttribute] = &types.AttributeValueMemberS{Value: entry.RetainUntil.UTC().Format(logDateLayout)}
+	}
 
-	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(r.tableName),
-		Item:      item,
+	_, err = r.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Put: &types.Put{
+					TableName:           aws.String(r.tableName),
+					Item:                item,
+					ConditionExpression: aws.String("attribute_not_exists(ID)"),
+				},
+			},
+			{
+				Update: &types.Update{
+					TableName: aws.String(r.chainHeadTable),
+					Key: map[string]types.AttributeValue{
+						"ChainID": &types.AttributeValueMemberS{Value: entry.ChainID},
+					},
+					UpdateExpression:    aws.String("SET Seq = :seq, #hash = :hash, UpdatedAt = :now"),
+					ConditionExpression: aws.String("attribute_not_exists(ChainID) OR Seq = :prevSeq"),
+					ExpressionAttributeNames: map[string]string{
+						"#hash": "Hash",
+					},
+					ExpressionAttributeValues: map[string]types.AttributeValue{
+						":seq":     &types.AttributeValueMemberN{Value: strconv.FormatInt(entry.ChainSeq, 10)},
+						":prevSeq": &types.AttributeValueMemberN{Value: strconv.FormatInt(entry.ChainSeq-1, 10)},
+						":hash":    &types.AttributeValueMemberS{Value: entry.Hash},
+						":now":     &types.AttributeValueMemberS{Value: time.Now().UTC().Format(time.RFC3339)},
+					},
+				},
+			},
+		},
 	})
+	return err
+}
 
+// marshalLogEntry convierte la entrada en un ítem con los atributos derivados de los índices
+func marshalLogEntry(entry *domain.LogEntry) (map[string]types.AttributeValue, error) {
+	item, err := attributevalue.MarshalMap(entry)
 	if err != nil {
-		log.Printf("Error saving log entry to DynamoDB: %v", err)
-		return err
+		return nil, err
 	}
+	timestamp := entry.Timestamp.UTC()
+	item[sortKeyAttribute] = &types.AttributeValueMemberS{Value: timestamp.Format(sortKeyLayout) + "#" + entry.ID}
+	item[logDateAttribute] = &types.AttributeValueMemberS{Value: timestamp.Format(logDateLayout)}
+	return item, nil
+}
 
-	log.Printf("Log entry saved successfully: %s", entry.ID)
-	return nil
+// storedLogEntry agrega a la entrada los campos del formato anterior
+// (name y email en la raíz) para poder convertir los ítems antiguos
+type storedLogEntry struct {
+	domain.LogEntry
+	Name  string
+	Email string
+}
+
+// unmarshalLogEntry lee un ítem de la tabla de logs en cualquiera de sus formatos
+func unmarshalLogEntry(item map[string]types.AttributeValue) (*domain.LogEntry, error) {
+	var stored storedLogEntry
+	if err := attributevalue.UnmarshalMap(item, &stored); err != nil {
+		return nil, err
+	}
+	stored.LogEntry.UpgradeLegacy(stored.Name, stored.Email)
+	return &stored.LogEntry, nil
+}
+
+// isConditionalCancel indica si la transacción se canceló por una condición
+// (la cabeza de la cadena cambió entre la lectura y la escritura)
+func isConditionalCancel(err error) bool {
+	var canceled *types.TransactionCanceledException
+	if !errors.As(err, &canceled) {
+		return false
+	}
+	for _, reason := range canceled.CancellationReasons {
+		if aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+			return true
+		}
+	}
+	return false
 }
 
 // FindAll obtiene todas las entradas de log
@@ -57,14 +188,188 @@ func (r *DynamoDBLogRepository) FindAll(ctx context.Context) ([]*domain.LogEntry
 
 	var entries []*domain.LogEntry
 	for _, item := range result.Items {
-		var entry domain.LogEntry
-		err := attributevalue.UnmarshalMap(item, &entry)
+		entry, err := unmarshalLogEntry(item)
 		if err != nil {
 			log.Printf("Error unmarshaling log entry: %v", err)
 			continue
 		}
-		entries = append(entries, &entry)
+		entries = append(entries, entry)
 	}
 
 	return entries, nil
 }
+
+// logCursor es el estado de paginación: la partición diaria en curso (solo en
+// consultas por rango de tiempo) y la LastEvaluatedKey de DynamoDB
+type logCursor struct {
+	Day string            `json:"day,omitempty"`
+	Key map[string]string `json:"key,omitempty"`
+}
+
+// FindPage obtiene una página de entradas filtradas, de la más reciente a la
+// más antigua. El filtro debe estar normalizado (To definido y, sin tipo ni
+// empleado, también From). Elige el índice según el filtro:
+// empleado → EmployeeIndex (con el tipo como FilterExpression), tipo →
+// EventTypeIndex y solo rango de tiempo → LogDateIndex, un día por consulta.
+func (r *DynamoDBLogRepository) FindPage(ctx context.Context, filter domain.LogFilter, cursor string, limit int) (*domain.LogPage, error) {
+	start, err := decodeLogCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	var startKey map[string]types.AttributeValue
+	if len(start.Key) > 0 {
+		if startKey, err = attributevalue.MarshalMap(start.Key); err != nil {
+			return nil, domain.ErrInvalidCursor
+		}
+	}
+
+	page := &domain.LogPage{Entries: []*domain.LogEntry{}}
+
+	if filter.EmployeeID != "" || filter.EventType != "" {
+		input := r.newQuery(filter)
+		input.ExclusiveStartKey = startKey
+		lastKey, err := r.collect(ctx, input, limit, page)
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor, err = encodeLogCursor(logCursor{}, lastKey)
+		return page, err
+	}
+
+	// Rango de tiempo sin otro filtro: se recorren las particiones diarias hacia atrás
+	day := filter.To.UTC().Truncate(24 * time.Hour)
+	if start.Day != "" {
+		if day, err = time.Parse(logDateLayout, start.Day); err != nil {
+			return nil, domain.ErrInvalidCursor
+		}
+	}
+	firstDay := filter.From.UTC().Truncate(24 * time.Hour)
+
+	for !day.Before(firstDay) {
+		input := r.newQuery(filter)
+		input.ExpressionAttributeValues[":partition"] = &types.AttributeValueMemberS{Value: day.Format(logDateLayout)}
+		input.ExclusiveStartKey = startKey
+
+		lastKey, err := r.collect(ctx, input, limit, page)
+		if err != nil {
+			return nil, err
+		}
+		if lastKey != nil {
+			page.NextCursor, err = encodeLogCursor(logCursor{Day: day.Format(logDateLayout)}, lastKey)
+			return page, err
+		}
+
+		day, startKey = day.AddDate(0, 0, -1), nil
+		if len(page.Entries) >= limit {
+			if !day.Before(firstDay) {
+				page.NextCursor, err = encodeLogCursor(logCursor{Day: day.Format(logDateLayout)}, nil)
+			}
+			return page, err
+		}
+	}
+
+	return page, nil
+}
+
+// newQuery construye el Query sobre el índice que corresponde al filtro,
+// acotado por el rango de SortKey. En LogDateIndex el valor de :partition lo
+// completa el llamador con el día a consultar.
+func (r *DynamoDBLogRepository) newQuery(filter domain.LogFilter) *dynamodb.QueryInput {
+	input := &dynamodb.QueryInput{
+		TableName:        aws.String(r.tableName),
+		ScanIndexForward: aws.Bool(false),
+		ExpressionAttributeNames: map[string]string{
+			"#sortKey": sortKeyAttribute,
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			// "~" es mayor que cualquier carácter de un ID, así que incluye todo el instante To
+			":to": &types.AttributeValueMemberS{Value: filter.To.UTC().Format(sortKeyLayout) + "#~"},
+		},
+	}
+
+	switch {
+	case filter.EmployeeID != "":
+		input.IndexName = aws.String(employeeIndex)
+		input.ExpressionAttributeNames["#partition"] = "EmployeeID"
+		input.ExpressionAttributeValues[":partition"] = &types.AttributeValueMemberS{Value: filter.EmployeeID}
+		if filter.EventType != "" {
+			input.FilterExpression = aws.String("EventType = :eventType")
+			input.ExpressionAttributeValues[":eventType"] = &types.AttributeValueMemberS{Value: filter.EventType}
+		}
+	case filter.EventType != "":
+		input.IndexName = aws.String(eventTypeIndex)
+		input.ExpressionAttributeNames["#partition"] = "EventType"
+		input.ExpressionAttributeValues[":partition"] = &types.AttributeValueMemberS{Value: filter.EventType}
+	default:
+		input.IndexName = aws.String(logDateIndex)
+		input.ExpressionAttributeNames["#partition"] = logDateAttribute
+	}
+
+	if filter.From.IsZero() {
+		input.KeyConditionExpression = aws.String("#partition = :partition AND #sortKey <= :to")
+	} else {
+		input.KeyConditionExpression = aws.String("#partition = :partition AND #sortKey BETWEEN :from AND :to")
+		input.ExpressionAttributeValues[":from"] = &types.AttributeValueMemberS{Value: filter.From.UTC().Format(sortKeyLayout)}
+	}
+
+	return input
+}
+
+// collect ejecuta el Query hasta completar la página o agotar la partición y
+// devuelve la LastEvaluatedKey pendiente (nil si no quedan resultados). Con
+// FilterExpression DynamoDB puede devolver páginas incompletas, por eso se repite.
+func (r *DynamoDBLogRepository) collect(ctx context.Context, input *dynamodb.QueryInput, limit int, page *domain.LogPage) (map[string]types.AttributeValue, error) {
+	for {
+		input.Limit = aws.Int32(int32(limit - len(page.Entries)))
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range result.Items {
+			entry, err := unmarshalLogEntry(item)
+			if err != nil {
+				log.Printf("Error unmarshaling log entry: %v", err)
+				continue
+			}
+			page.Entries = append(page.Entries, entry)
+		}
+
+		if result.LastEvaluatedKey == nil || len(page.Entries) >= limit {
+			return result.LastEvaluatedKey, nil
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
+
+// encodeLogCursor serializa el cursor como JSON en base64 URL-safe
+func encodeLogCursor(cursor logCursor, lastKey map[string]types.AttributeValue) (string, error) {
+	if lastKey == nil && cursor.Day == "" {
+		return "", nil
+	}
+	if lastKey != nil {
+		if err := attributevalue.UnmarshalMap(lastKey, &cursor.Key); err != nil {
+			return "", err
+		}
+	}
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeLogCursor(value string) (logCursor, error) {
+	var cursor logCursor
+	if value == "" {
+		return cursor, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor, domain.ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return cursor, domain.ErrInvalidCursor
+	}
+	return cursor, nil
+}
@@ -0,0 +1,70 @@
+package infrastructure
+
+import (
+	"errors"
+	"logger-service/internal/domain"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestLogCursorRoundTrip(t *testing.T) {
+	lastKey := map[string]types.AttributeValue{
+		"ID":        &types.AttributeValueMemberS{Value: "entry-1"},
+		"LogDate":   &types.AttributeValueMemberS{Value: "2026-05-01"},
+		"SortKey":   &types.AttributeValueMemberS{Value: "2026-05-01T09:00:00.000000000Z#entry-1"},
+		"EventType": &types.AttributeValueMemberS{Value: "employee.updated"},
+	}
+
+	tests := []struct {
+		name      string
+		cursor    logCursor
+		lastKey   map[string]types.AttributeValue
+		wantEmpty bool
+	}{
+		{"sin más páginas", logCursor{}, nil, true},
+		{"clave de un índice", logCursor{}, lastKey, false},
+		{"día y clave", logCursor{Day: "2026-05-01"}, lastKey, false},
+		{"solo el día siguiente", logCursor{Day: "2026-04-30"}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := encodeLogCursor(tt.cursor, tt.lastKey)
+			if err != nil {
+				t.Fatalf("encodeLogCursor() = %v", err)
+			}
+			if (encoded == "") != tt.wantEmpty {
+				t.Fatalf("encodeLogCursor() = %q, want empty %t", encoded, tt.wantEmpty)
+			}
+
+			decoded, err := decodeLogCursor(encoded)
+			if err != nil {
+				t.Fatalf("decodeLogCursor(%q) = %v", encoded, err)
+			}
+			if decoded.Day != tt.cursor.Day {
+				t.Errorf("day = %q, want %q", decoded.Day, tt.cursor.Day)
+			}
+
+			// FindPage convierte la clave del cursor de vuelta en ExclusiveStartKey
+			var startKey map[string]types.AttributeValue
+			if len(decoded.Key) > 0 {
+				if startKey, err = attributevalue.MarshalMap(decoded.Key); err != nil {
+					t.Fatalf("MarshalMap() = %v", err)
+				}
+			}
+			if !reflect.DeepEqual(startKey, tt.lastKey) {
+				t.Errorf("start key = %v, want %v", startKey, tt.lastKey)
+			}
+		})
+	}
+}
+
+func TestDecodeLogCursorRejectsMalformed(t *testing.T) {
+	for _, value := range []string{"not base64!", "bm90LWpzb24", "WzEsMl0"} {
+		if _, err := decodeLogCursor(value); !errors.Is(err, domain.ErrInvalidCursor) {
+			t.Errorf("decodeLogCursor(%q) = %v, want %v", value, err, domain.ErrInvalidCursor)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package infrastructure
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// ErrNoSigningKey indica que el firmador solo tiene la clave pública
+var ErrNoSigningKey = errors.New("no private key configured for signing")
+
+// Ed25519Signer firma y verifica checkpoints con Ed25519. El KeyID son los
+// primeros 8 bytes (hex) del SHA-256 de la clave pública.
+type Ed25519Signer struct {
+	privateKey ed25519.PrivateKey
+	publicKey  ed25519.PublicKey
+	keyID      string
+}
+
+// NewEd25519SignerFromSeed crea un firmador a partir de la semilla privada de
+// 32 bytes codificada en base64 (p.ej. AUDIT_SIGNING_KEY)
+func NewEd25519SignerFromSeed(seedBase64 string) (*Ed25519Signer, error) {
+	seed, err := base64.StdEncoding.DecodeString(seedBase64)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("signing key must be a base64 %d-byte Ed25519 seed", ed25519.SeedSize)
+	}
+	privateKey := ed25519.NewKeyFromSeed(seed)
+	return newEd25519Signer(privateKey, privateKey.Public().(ed25519.PublicKey)), nil
+}
+
+// NewEd25519Verifier crea un verificador a partir de la clave pública en base64;
+// no puede firmar
+func NewEd25519Verifier(publicKeyBase64 string) (*Ed25519Signer, error) {
+	publicKey, err := base64.StdEncoding.DecodeString(publicKeyBase64)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("public key must be a base64 %d-byte Ed25519 key", ed25519.PublicKeySize)
+	}
+	return newEd25519Signer(nil, publicKey), nil
+}
+
+func newEd25519Signer(privateKey ed25519.PrivateKey, publicKey ed25519.PublicKey) *Ed25519Signer {
+	sum := sha256.Sum256(publicKey)
+	return &Ed25519Signer{
+		privateKey: privateKey,
+		publicKey:  publicKey,
+		keyID:      hex.EncodeToString(sum[:8]),
+	}
+}
+
+// KeyID identifica la clave con la que se firma
+func (s *Ed25519Signer) KeyID() string {
+	return s.keyID
+}
+
+// PublicKey devuelve la clave pública en base64, para distribuirla a quien verifica
+func (s *Ed25519Signer) PublicKey() string {
+	return base64.StdEncoding.EncodeToString(s.publicKey)
+}
+
+// Sign firma el payload
+func (s *Ed25519Signer) Sign(payload []byte) ([]byte, error) {
+	if s.privateKey == nil {
+		return nil, ErrNoSigningKey
+	}
+	return ed25519.Sign(s.privateKey, payload), nil
+}
+
+// Verify comprueba una firma hecha con esta clave
+func (s *Ed25519Signer) Verify(keyID string, payload, signature []byte) bool {
+	return keyID == s.keyID && ed25519.Verify(s.publicKey, payload, signature)
+}
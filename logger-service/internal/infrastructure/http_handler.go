@@ -0,0 +1,182 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"logger-service/internal/application"
+	"logger-service/internal/domain"
+	"logger-service/internal/ports"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+// HTTPHandler maneja las peticiones HTTP de consulta y stream en vivo de logs
+type HTTPHandler struct {
+	service        *application.LoggerService
+	hub            *LogStreamHub
+	verifier       ports.TokenVerifier
+	allowedOrigins map[string]bool
+	upgrader       websocket.Upgrader
+}
+
+// NewHTTPHandler crea un nuevo manejador HTTP. verifier identifica a quien
+// pide descifrar los datos personales o abre el stream en vivo; nil no permite
+// ninguna de las dos cosas. allowedOrigins son los orígenes de navegador que
+// pueden abrir el stream además del propio (vacío = solo el mismo origen).
+func NewHTTPHandler(service *application.LoggerService, hub *LogStreamHub, verifier ports.TokenVerifier, allowedOrigins []string) *HTTPHandler {
+	h := &HTTPHandler{
+		service:        service,
+		hub:            hub,
+		verifier:       verifier,
+		allowedOrigins: make(map[string]bool, len(allowedOrigins)),
+	}
+	for _, origin := range allowedOrigins {
+		h.allowedOrigins[strings.TrimSuffix(origin, "/")] = true
+	}
+	h.upgrader = websocket.Upgrader{CheckOrigin: h.checkOrigin}
+	return h
+}
+
+// ListLogs lista las entradas de log de la más reciente a la más antigua.
+// Filtros: event_type, employee_id, from y to (RFC3339 o YYYY-MM-DD); paginación
+// con limit (máx. 200) y el cursor next_cursor de la respuesta anterior. Con
+// decrypt=true y un token del auth-service (Authorization: Bearer) de un
+// usuario autorizado se descifran los datos personales.
+func (h *HTTPHandler) ListLogs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := domain.LogFilter{
+		EventType:  query.Get("event_type"),
+		EmployeeID: query.Get("employee_id"),
+	}
+
+	var err error
+	if filter.From, err = parseTime(query.Get("from"), false); err != nil {
+		http.Error(w, "Invalid from: use RFC3339 or YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+	if filter.To, err = parseTime(query.Get("to"), true); err != nil {
+		http.Error(w, "Invalid to: use RFC3339 or YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+			http.Error(w, domain.ErrInvalidPageSize.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	reader := ""
+	if query.Get("decrypt") == "true" {
+		if reader, err = h.authenticate(r); err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "A valid bearer token is required to decrypt personal data", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	page, err := h.service.ListLogs(r.Context(), filter, query.Get("cursor"), limit, reader)
+	if err != nil {
+		log.Printf("Error listing logs: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// ActivityStats devuelve la serie temporal de eventos por tipo. Parámetros:
+// event_type (uno o varios tipos exactos separados por comas), bucket (hour,
+// day, week o month; day por defecto) y from/to (RFC3339 o YYYY-MM-DD).
+func (h *HTTPHandler) ActivityStats(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	bucket, err := domain.ParseBucketSize(query.Get("bucket"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	activity := domain.ActivityQuery{
+		EventTypes: strings.Split(query.Get("event_type"), ","),
+		Bucket:     bucket,
+	}
+	if activity.From, err = parseTime(query.Get("from"), false); err != nil {
+		http.Error(w, "Invalid from: use RFC3339 or YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+	if activity.To, err = parseTime(query.Get("to"), true); err != nil {
+		http.Error(w, "Invalid to: use RFC3339 or YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+
+	stats, err := h.service.ActivityStats(r.Context(), activity)
+	if err != nil {
+		log.Printf("Error reading activity stats: %v", err)
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// authenticate devuelve el ID del usuario del token Bearer de la petición
+func (h *HTTPHandler) authenticate(r *http.Request) (string, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" || h.verifier == nil {
+		return "", errors.New("missing bearer token")
+	}
+	return h.verifier.VerifyToken(token)
+}
+
+// SetupRoutes configura las rutas del servidor
+func (h *HTTPHandler) SetupRoutes() *mux.Router {
+	router := mux.NewRouter()
+	router.HandleFunc("/logs", h.ListLogs).Methods("GET")
+	router.HandleFunc("/logs/stream", h.StreamLogsSSE).Methods("GET")
+	router.HandleFunc("/logs/ws", h.StreamLogsWebSocket).Methods("GET")
+	router.HandleFunc("/stats/activity", h.ActivityStats).Methods("GET")
+	return router
+}
+
+// parseTime interpreta una fecha en formato RFC3339 o YYYY-MM-DD. Con
+// endOfDay una fecha sin hora se toma hasta el final del día (límite inclusivo).
+func parseTime(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
+
+// writeError traduce los errores del dominio a códigos de estado HTTP
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case err == domain.ErrInvalidCursor, err == domain.ErrInvalidPageSize, err == domain.ErrInvalidTimeRange, err == domain.ErrTimeRangeTooWide,
+		err == domain.ErrTooManyBuckets, errors.Is(err, domain.ErrInvalidActivityQuery):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case err == domain.ErrPIIAccessDenied:
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
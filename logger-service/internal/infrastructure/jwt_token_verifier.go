@@ -0,0 +1,42 @@
+package infrastructure
+
+import (
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTTokenVerifier implementa el TokenVerifier con los tokens JWT (HS256) que
+// emite el auth-service, firmados con el mismo secreto
+type JWTTokenVerifier struct {
+	secretKey []byte
+}
+
+// NewJWTTokenVerifier crea el verificador con el secreto JWT_SECRET del auth-service
+func NewJWTTokenVerifier(secretKey string) *JWTTokenVerifier {
+	return &JWTTokenVerifier{secretKey: []byte(secretKey)}
+}
+
+// tokenClaims son los claims de los tokens del auth-service
+type tokenClaims struct {
+	UserID string `json:"user_id"`
+	jwt.RegisteredClaims
+}
+
+// VerifyToken valida el token (firma y vencimiento) y devuelve el ID del usuario
+func (v *JWTTokenVerifier) VerifyToken(tokenString string) (string, error) {
+	claims := &tokenClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("invalid signing method")
+		}
+		return v.secretKey, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if !token.Valid || claims.UserID == "" {
+		return "", errors.New("invalid token")
+	}
+	return claims.UserID, nil
+}
@@ -0,0 +1,36 @@
+package infrastructure
+
+import (
+	"fmt"
+	"logger-service/internal/ports"
+	"os"
+	"pkg/awsclient"
+)
+
+// NewKeyProviderFromEnv crea la clave maestra de los datos personales según
+// PII_KEY_PROVIDER: "local" lee las claves del archivo PII_KEY_FILE y "kms"
+// usa la clave de KMS PII_KMS_KEY_ID (ID, ARN o alias). Vacío desactiva el
+// cifrado y devuelve nil.
+func NewKeyProviderFromEnv(clients *awsclient.Factory) (ports.KeyProvider, error) {
+	switch kind := os.Getenv("PII_KEY_PROVIDER"); kind {
+	case "":
+		return nil, nil
+
+	case "local":
+		path := os.Getenv("PII_KEY_FILE")
+		if path == "" {
+			return nil, fmt.Errorf("PII_KEY_FILE is required when PII_KEY_PROVIDER=local")
+		}
+		return NewLocalKeyProvider(path)
+
+	case "kms":
+		keyID := os.Getenv("PII_KMS_KEY_ID")
+		if keyID == "" {
+			return nil, fmt.Errorf("PII_KMS_KEY_ID is required when PII_KEY_PROVIDER=kms")
+		}
+		return NewKMSKeyProvider(clients.KMS(), keyID), nil
+
+	default:
+		return nil, fmt.Errorf("unknown PII_KEY_PROVIDER %q (use local or kms)", kind)
+	}
+}
@@ -0,0 +1,56 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"logger-service/internal/domain"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/kms/types"
+)
+
+// KMSKeyProvider implementa el KeyProvider con una clave simétrica de KMS:
+// KMS genera las claves de datos y solo él puede descifrarlas. El KeyID de
+// cada clave de datos es el ARN de la clave con que se cifró, de modo que
+// cambiar el alias configurado no impide leer las entradas anteriores.
+type KMSKeyProvider struct {
+	client *kms.Client
+	keyID  string
+}
+
+// NewKMSKeyProvider crea el proveedor de la clave keyID (ID, ARN o alias)
+func NewKMSKeyProvider(client *kms.Client, keyID string) *KMSKeyProvider {
+	return &KMSKeyProvider{
+		client: client,
+		keyID:  keyID,
+	}
+}
+
+// GenerateDataKey pide a KMS una clave de datos AES-256
+func (p *KMSKeyProvider) GenerateDataKey(ctx context.Context) (*domain.DataKey, error) {
+	output, err := p.client.GenerateDataKey(ctx, &kms.GenerateDataKeyInput{
+		KeyId:   aws.String(p.keyID),
+		KeySpec: types.DataKeySpecAes256,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &domain.DataKey{
+		KeyID:     aws.ToString(output.KeyId),
+		Plaintext: output.Plaintext,
+		Encrypted: output.CiphertextBlob,
+	}, nil
+}
+
+// DecryptDataKey pide a KMS que descifre una clave de datos
+func (p *KMSKeyProvider) DecryptDataKey(ctx context.Context, keyID string, encrypted []byte) ([]byte, error) {
+	output, err := p.client.Decrypt(ctx, &kms.DecryptInput{
+		KeyId:          aws.String(keyID),
+		CiphertextBlob: encrypted,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: data key: %v", domain.ErrPIIDecryption, err)
+	}
+	return output.Plaintext, nil
+}
@@ -0,0 +1,112 @@
+package infrastructure
+
+import (
+	"bufio"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"logger-service/internal/domain"
+	"os"
+	"strings"
+)
+
+// dataKeySize es el tamaño de las claves de datos y de las claves maestras locales (AES-256)
+const dataKeySize = 32
+
+// LocalKeyProvider implementa el KeyProvider con claves maestras AES-256
+// leídas de un archivo, una por línea: "<key-id> <clave en base64>". La
+// primera es la activa, con la que se cifran las claves de datos nuevas; las
+// demás solo descifran, lo que permite rotar la clave agregando una línea al
+// principio. Las líneas vacías y las que empiezan por # se ignoran.
+type LocalKeyProvider struct {
+	activeID string
+	keys     map[string]cipher.AEAD
+}
+
+// NewLocalKeyProvider lee el archivo de claves maestras
+func NewLocalKeyProvider(path string) (*LocalKeyProvider, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	provider := &LocalKeyProvider{keys: make(map[string]cipher.AEAD)}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected \"<key-id> <base64 key>\"", path, line)
+		}
+		keyID := fields[0]
+		key, err := base64.StdEncoding.DecodeString(fields[1])
+		if err != nil || len(key) != dataKeySize {
+			return nil, fmt.Errorf("%s:%d: key must be %d bytes in base64", path, line, dataKeySize)
+		}
+		if _, ok := provider.keys[keyID]; ok {
+			return nil, fmt.Errorf("%s:%d: duplicate key id %q", path, line, keyID)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		if provider.keys[keyID], err = cipher.NewGCM(block); err != nil {
+			return nil, err
+		}
+		if provider.activeID == "" {
+			provider.activeID = keyID
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if provider.activeID == "" {
+		return nil, fmt.Errorf("%s: no keys", path)
+	}
+	return provider, nil
+}
+
+// GenerateDataKey genera una clave de datos y la cifra con la clave activa
+func (p *LocalKeyProvider) GenerateDataKey(ctx context.Context) (*domain.DataKey, error) {
+	plaintext := make([]byte, dataKeySize)
+	if _, err := rand.Read(plaintext); err != nil {
+		return nil, err
+	}
+
+	aead := p.keys[p.activeID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return &domain.DataKey{
+		KeyID:     p.activeID,
+		Plaintext: plaintext,
+		Encrypted: aead.Seal(nonce, nonce, plaintext, []byte(p.activeID)),
+	}, nil
+}
+
+// DecryptDataKey descifra una clave de datos con la clave maestra keyID
+func (p *LocalKeyProvider) DecryptDataKey(ctx context.Context, keyID string, encrypted []byte) ([]byte, error) {
+	aead, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnknownKey, keyID)
+	}
+	if len(encrypted) < aead.NonceSize() {
+		return nil, fmt.Errorf("%w: malformed data key", domain.ErrPIIDecryption)
+	}
+	nonce, ciphertext := encrypted[:aead.NonceSize()], encrypted[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("%w: data key: %v", domain.ErrPIIDecryption, err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,88 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"logger-service/internal/domain"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LocalObjectStore implementa el almacenamiento de archivos en un directorio
+// local; cada clave es una ruta relativa a dir
+type LocalObjectStore struct {
+	dir string
+}
+
+// NewLocalObjectStore crea una nueva instancia del almacenamiento
+func NewLocalObjectStore(dir string) *LocalObjectStore {
+	return &LocalObjectStore{dir: dir}
+}
+
+// Put escribe el objeto en un archivo temporal y lo renombra, de modo que
+// nunca queda un archivo a medio escribir con el nombre final
+func (s *LocalObjectStore) Put(ctx context.Context, key string, data []byte) error {
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get lee el objeto
+func (s *LocalObjectStore) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, domain.ErrArchiveNotFound
+	}
+	return data, err
+}
+
+// List devuelve las claves que empiezan por prefix, ordenadas. Un directorio
+// inexistente equivale a un almacenamiento vacío.
+func (s *LocalObjectStore) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.WalkDir(s.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".tmp-") {
+			return nil
+		}
+
+		relative, err := filepath.Rel(s.dir, path)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(relative); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	sort.Strings(keys)
+	return keys, err
+}
+
+func (s *LocalObjectStore) path(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(key))
+}
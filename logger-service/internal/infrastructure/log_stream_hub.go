@@ -0,0 +1,160 @@
+package infrastructure
+
+import (
+	"log"
+	"logger-service/internal/domain"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Valores por defecto del hub del stream en vivo
+const (
+	DefaultStreamBufferSize = 256
+	DefaultStreamReplaySize = 1000
+)
+
+// StreamEvent es una entrada de log difundida con su ID de stream
+// ("<epoch>-<secuencia>"), que el cliente devuelve como Last-Event-ID
+type StreamEvent struct {
+	ID    string
+	Entry *domain.LogEntry
+	seq   uint64
+}
+
+// LogStreamHub difunde en memoria las entradas guardadas a los suscriptores
+// del stream en vivo. Cada suscriptor tiene un buffer acotado: si se llena el
+// suscriptor se desconecta en lugar de frenar el procesamiento de eventos.
+// Las últimas entradas se conservan para reanudar desde un Last-Event-ID.
+type LogStreamHub struct {
+	mu          sync.Mutex
+	epoch       string
+	seq         uint64
+	replay      []StreamEvent
+	replaySize  int
+	bufferSize  int
+	subscribers map[*Subscription]struct{}
+}
+
+// NewLogStreamHub crea el hub. bufferSize es el máximo de entradas pendientes
+// por suscriptor y replaySize las entradas que se conservan para reanudar.
+func NewLogStreamHub(bufferSize, replaySize int) *LogStreamHub {
+	if bufferSize <= 0 {
+		bufferSize = DefaultStreamBufferSize
+	}
+	if replaySize <= 0 {
+		replaySize = DefaultStreamReplaySize
+	}
+	return &LogStreamHub{
+		// El epoch distingue los IDs de instancias (o reinicios) distintos
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		replaySize:  replaySize,
+		bufferSize:  bufferSize,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscription es la suscripción de un cliente al stream
+type Subscription struct {
+	// Backlog son las entradas posteriores al Last-Event-ID, a enviar antes que Events
+	Backlog []StreamEvent
+	// Gap indica que el Last-Event-ID no se pudo reanudar (otra instancia o
+	// demasiado antiguo): pueden faltar entradas y conviene consultar GET /logs
+	Gap bool
+
+	filter  domain.StreamFilter
+	events  chan StreamEvent
+	dropped chan struct{}
+	hub     *LogStreamHub
+}
+
+// Events devuelve las entradas nuevas que cumplen el filtro
+func (s *Subscription) Events() <-chan StreamEvent {
+	return s.events
+}
+
+// Dropped se cierra si el hub desconecta al suscriptor por no consumir a tiempo
+func (s *Subscription) Dropped() <-chan struct{} {
+	return s.dropped
+}
+
+// Close cancela la suscripción
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	delete(s.hub.subscribers, s)
+}
+
+// Subscribe registra un suscriptor con su filtro. Si lastEventID no está vacío
+// el Backlog contiene las entradas retenidas posteriores a ese ID.
+func (h *LogStreamHub) Subscribe(filter domain.StreamFilter, lastEventID string) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subscription := &Subscription{
+		filter:  filter,
+		events:  make(chan StreamEvent, h.bufferSize),
+		dropped: make(chan struct{}),
+		hub:     h,
+	}
+
+	if lastEventID != "" {
+		after, ok := h.resumePoint(lastEventID)
+		subscription.Gap = !ok
+		for _, event := range h.replay {
+			if event.seq > after && filter.Matches(event.Entry) {
+				subscription.Backlog = append(subscription.Backlog, event)
+			}
+		}
+	}
+
+	h.subscribers[subscription] = struct{}{}
+	return subscription
+}
+
+// resumePoint devuelve la secuencia desde la que reanudar y si es exacta: sin
+// huecos entre lastEventID y las entradas retenidas
+func (h *LogStreamHub) resumePoint(lastEventID string) (uint64, bool) {
+	epoch, value, found := strings.Cut(lastEventID, "-")
+	seq, err := strconv.ParseUint(value, 10, 64)
+	if !found || err != nil || epoch != h.epoch || seq > h.seq {
+		return 0, false
+	}
+	if len(h.replay) > 0 && seq+1 < h.replay[0].seq {
+		return 0, false
+	}
+	return seq, true
+}
+
+// Broadcast difunde la entrada a los suscriptores cuyo filtro la acepta
+func (h *LogStreamHub) Broadcast(entry *domain.LogEntry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.seq++
+	event := StreamEvent{
+		ID:    h.epoch + "-" + strconv.FormatUint(h.seq, 10),
+		Entry: entry,
+		seq:   h.seq,
+	}
+
+	h.replay = append(h.replay, event)
+	if len(h.replay) > h.replaySize {
+		h.replay = h.replay[len(h.replay)-h.replaySize:]
+	}
+
+	for subscription := range h.subscribers {
+		if !subscription.filter.Matches(entry) {
+			continue
+		}
+		select {
+		case subscription.events <- event:
+		default:
+			// Cliente lento: se desconecta y puede reanudar con Last-Event-ID
+			delete(h.subscribers, subscription)
+			close(subscription.dropped)
+			log.Printf("Dropping slow stream subscriber: buffer of %d entries full", h.bufferSize)
+		}
+	}
+}
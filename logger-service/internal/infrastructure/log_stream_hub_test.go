@@ -0,0 +1,161 @@
+package infrastructure
+
+import (
+	"fmt"
+	"logger-service/internal/domain"
+	"reflect"
+	"testing"
+)
+
+// broadcastEntries difunde n entradas alternando tipos de empleado y de mensaje
+// y devuelve los IDs de stream asignados
+func broadcastEntries(hub *LogStreamHub, n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		eventType := "employee.updated"
+		if i%2 == 1 {
+			eventType = "message.sent"
+		}
+		hub.Broadcast(&domain.LogEntry{ID: fmt.Sprintf("log-%d", i+1), EventType: eventType})
+		ids[i] = hub.replay[len(hub.replay)-1].ID
+	}
+	return ids
+}
+
+func backlogIDs(subscription *Subscription) []string {
+	var ids []string
+	for _, event := range subscription.Backlog {
+		ids = append(ids, event.Entry.ID)
+	}
+	return ids
+}
+
+func TestLogStreamHubResume(t *testing.T) {
+	// El hub retiene las 5 últimas de 8 entradas: log-4 a log-8
+	hub := NewLogStreamHub(10, 5)
+	ids := broadcastEntries(hub, 8)
+	other := NewLogStreamHub(10, 5)
+	otherIDs := broadcastEntries(other, 1)
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		filter      domain.StreamFilter
+		wantBacklog []string
+		wantGap     bool
+	}{
+		{
+			name:        "sin Last-Event-ID",
+			lastEventID: "",
+		},
+		{
+			name:        "dentro de la ventana",
+			lastEventID: ids[5],
+			wantBacklog: []string{"log-7", "log-8"},
+		},
+		{
+			name:        "justo antes de la primera entrada retenida",
+			lastEventID: ids[2],
+			wantBacklog: []string{"log-4", "log-5", "log-6", "log-7", "log-8"},
+		},
+		{
+			name:        "última entrada difundida",
+			lastEventID: ids[7],
+		},
+		{
+			name:        "dentro de la ventana con filtro",
+			lastEventID: ids[3],
+			filter:      domain.StreamFilter{EventTypes: []string{"message.*"}},
+			wantBacklog: []string{"log-6", "log-8"},
+		},
+		{
+			name:        "fuera de la ventana",
+			lastEventID: ids[1],
+			wantBacklog: []string{"log-4", "log-5", "log-6", "log-7", "log-8"},
+			wantGap:     true,
+		},
+		{
+			name:        "ID de otra instancia",
+			lastEventID: otherIDs[0],
+			wantBacklog: []string{"log-4", "log-5", "log-6", "log-7", "log-8"},
+			wantGap:     true,
+		},
+		{
+			name:        "secuencia posterior a la última difundida",
+			lastEventID: hub.epoch + "-9",
+			wantBacklog: []string{"log-4", "log-5", "log-6", "log-7", "log-8"},
+			wantGap:     true,
+		},
+		{
+			name:        "ID malformado",
+			lastEventID: "not-an-id",
+			wantBacklog: []string{"log-4", "log-5", "log-6", "log-7", "log-8"},
+			wantGap:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subscription := hub.Subscribe(tt.filter, tt.lastEventID)
+			defer subscription.Close()
+
+			if got := backlogIDs(subscription); !reflect.DeepEqual(got, tt.wantBacklog) {
+				t.Errorf("Backlog = %v, want %v", got, tt.wantBacklog)
+			}
+			if subscription.Gap != tt.wantGap {
+				t.Errorf("Gap = %t, want %t", subscription.Gap, tt.wantGap)
+			}
+		})
+	}
+}
+
+func TestLogStreamHubDropsSlowSubscriber(t *testing.T) {
+	hub := NewLogStreamHub(2, 10)
+	slow := hub.Subscribe(domain.StreamFilter{}, "")
+	fast := hub.Subscribe(domain.StreamFilter{}, "")
+	// Las entradas que el filtro descarta no ocupan el buffer
+	filtered := hub.Subscribe(domain.StreamFilter{EventTypes: []string{"message.*"}}, "")
+
+	var received []string
+	for i := 1; i <= 4; i++ {
+		hub.Broadcast(&domain.LogEntry{ID: fmt.Sprintf("log-%d", i), EventType: "employee.updated"})
+		received = append(received, (<-fast.Events()).Entry.ID)
+	}
+
+	select {
+	case <-slow.Dropped():
+	default:
+		t.Fatal("slow subscriber was not dropped with its buffer full")
+	}
+	// El cliente lento conserva lo que alcanzó a recibir y puede reanudar desde ahí
+	var pending []string
+	for len(slow.Events()) > 0 {
+		pending = append(pending, (<-slow.Events()).Entry.ID)
+	}
+	if want := []string{"log-1", "log-2"}; !reflect.DeepEqual(pending, want) {
+		t.Errorf("slow subscriber buffered %v, want %v", pending, want)
+	}
+	resumed := hub.Subscribe(domain.StreamFilter{}, hub.replay[1].ID)
+	if got := backlogIDs(resumed); resumed.Gap || !reflect.DeepEqual(got, []string{"log-3", "log-4"}) {
+		t.Errorf("resumed Backlog = %v (gap %t), want [log-3 log-4]", got, resumed.Gap)
+	}
+
+	if want := []string{"log-1", "log-2", "log-3", "log-4"}; !reflect.DeepEqual(received, want) {
+		t.Errorf("fast subscriber received %v, want %v", received, want)
+	}
+	for name, subscription := range map[string]*Subscription{"fast": fast, "filtered": filtered} {
+		select {
+		case <-subscription.Dropped():
+			t.Errorf("%s subscriber was dropped", name)
+		default:
+		}
+	}
+
+	// Un suscriptor desconectado no recibe más entradas
+	hub.Broadcast(&domain.LogEntry{ID: "log-5", EventType: "employee.updated"})
+	if len(slow.Events()) != 0 {
+		t.Error("dropped subscriber kept receiving entries")
+	}
+	if _, ok := hub.subscribers[slow]; ok {
+		t.Error("dropped subscriber is still registered")
+	}
+}
@@ -0,0 +1,60 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"logger-service/internal/domain"
+	"pkg/events"
+	"pkg/sqsqueue"
+)
+
+// errNoRecipients indica que la alerta no tiene a quién avisar por email
+var errNoRecipients = errors.New("no recipients for messaging alert")
+
+// MessagingAlertNotifier avisa de las alertas a través del messaging-service:
+// publica un evento alert.fired en su cola y el servicio envía un email a cada
+// destinatario
+type MessagingAlertNotifier struct {
+	publisher  *sqsqueue.Publisher
+	recipients []string
+}
+
+// NewMessagingAlertNotifier crea el canal messaging con los destinatarios por
+// defecto (una regla puede indicar los suyos)
+func NewMessagingAlertNotifier(publisher *sqsqueue.Publisher, recipients []string) *MessagingAlertNotifier {
+	return &MessagingAlertNotifier{
+		publisher:  publisher,
+		recipients: recipients,
+	}
+}
+
+// Notify publica el evento alert.fired. El ID del evento es el de la alerta,
+// así que los reintentos no repiten el aviso.
+func (n *MessagingAlertNotifier) Notify(ctx context.Context, alert *domain.Alert) error {
+	recipients := alert.Recipients
+	if len(recipients) == 0 {
+		recipients = n.recipients
+	}
+	if len(recipients) == 0 {
+		return errNoRecipients
+	}
+
+	event, err := events.New(alert.ID, events.SourceLoggerService, events.TypeAlertFired, "", alert.FiredAt, events.AlertFiredPayload{
+		AlertID:       alert.ID,
+		Rule:          alert.Rule,
+		Description:   alert.Description,
+		Severity:      alert.Severity,
+		Count:         alert.Count,
+		Threshold:     alert.Threshold,
+		WindowSeconds: alert.WindowSeconds,
+		Group:         alert.Group,
+		FirstEventAt:  alert.FirstEventAt.UTC(),
+		LastEventAt:   alert.LastEventAt.UTC(),
+		FiredAt:       alert.FiredAt.UTC(),
+		Recipients:    recipients,
+	})
+	if err != nil {
+		return err
+	}
+	return n.publisher.Publish(ctx, event)
+}
@@ -0,0 +1,83 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"logger-service/internal/domain"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3ObjectStore implementa el almacenamiento de archivos en un bucket de S3.
+// Las claves se guardan bajo prefix (p.ej. "employee-logs/").
+type S3ObjectStore struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// NewS3ObjectStore crea una nueva instancia del almacenamiento
+func NewS3ObjectStore(client *s3.Client, bucket, prefix string) *S3ObjectStore {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return &S3ObjectStore{
+		client: client,
+		bucket: bucket,
+		prefix: prefix,
+	}
+}
+
+// Put sube el objeto
+func (s *S3ObjectStore) Put(ctx context.Context, key string, data []byte) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(s.prefix + key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/gzip"),
+	})
+	return err
+}
+
+// Get descarga el objeto
+func (s *S3ObjectStore) Get(ctx context.Context, key string) ([]byte, error) {
+	result, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + key),
+	})
+	var noSuchKey *types.NoSuchKey
+	if errors.As(err, &noSuchKey) {
+		return nil, domain.ErrArchiveNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer result.Body.Close()
+
+	return io.ReadAll(result.Body)
+}
+
+// List devuelve las claves que empiezan por prefix (S3 las devuelve ordenadas)
+func (s *S3ObjectStore) List(ctx context.Context, prefix string) ([]string, error) {
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(s.prefix + prefix),
+	})
+
+	var keys []string
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range result.Contents {
+			keys = append(keys, strings.TrimPrefix(aws.ToString(object.Key), s.prefix))
+		}
+	}
+	return keys, nil
+}
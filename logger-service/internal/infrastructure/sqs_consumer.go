@@ -2,75 +2,60 @@ package infrastructure
 
 import (
 	"context"
-	"encoding/json"
-	"log"
+	"errors"
 	"logger-service/internal/domain"
-	"time"
+	"pkg/events"
+	"pkg/eventschema"
+	"pkg/sqsqueue"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 )
 
-// SQSEventConsumer implementa el consumidor de eventos usando SQS
+// SQSEventConsumer implementa el consumidor de eventos sobre el consumidor SQS compartido
 type SQSEventConsumer struct {
-	client   *sqs.Client
-	queueURL string
+	queue    *sqsqueue.Consumer
+	registry *eventschema.Registry
 }
 
 // NewSQSEventConsumer crea una nueva instancia del consumidor
-func NewSQSEventConsumer(client *sqs.Client, queueURL string) *SQSEventConsumer {
+func NewSQSEventConsumer(queue *sqsqueue.Consumer, registry *eventschema.Registry) *SQSEventConsumer {
 	return &SQSEventConsumer{
-		client:   client,
-		queueURL: queueURL,
+		queue:    queue,
+		registry: registry,
 	}
 }
 
-// ConsumeEvents consume eventos de SQS
-func (c *SQSEventConsumer) ConsumeEvents(ctx context.Context, handler func(*domain.EmployeeEvent) error) error {
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			messages, err := c.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
-				QueueUrl:            aws.String(c.queueURL),
-				MaxNumberOfMessages: 10,
-				WaitTimeSeconds:     20,
-				VisibilityTimeout:   30,
-			})
-
-			if err != nil {
-				log.Printf("Error receiving messages from SQS: %v", err)
-				time.Sleep(5 * time.Second)
-				continue
-			}
+// ConsumeEvents consume eventos de SQS con el pool de workers del consumidor;
+// el handler puede ejecutarse en paralelo para mensajes distintos
+func (c *SQSEventConsumer) ConsumeEvents(ctx context.Context, handler func(context.Context, *domain.Event) error) error {
+	return c.queue.Run(ctx, func(ctx context.Context, message types.Message) error {
+		return c.processMessage(ctx, message, handler)
+	})
+}
 
-			for _, message := range messages.Messages {
-				if err := c.processMessage(ctx, message, handler); err != nil {
-					log.Printf("Error processing message: %v", err)
-					continue
-				}
+func (c *SQSEventConsumer) processMessage(ctx context.Context, message types.Message, handler func(context.Context, *domain.Event) error) error {
+	body := []byte(*message.Body)
 
-				// Eliminar mensaje de la cola
-				_, err := c.client.DeleteMessage(ctx, &sqs.DeleteMessageInput{
-					QueueUrl:      aws.String(c.queueURL),
-					ReceiptHandle: message.ReceiptHandle,
-				})
+	event, err := domain.DecodeEvent(body)
+	if err != nil {
+		return sqsqueue.Permanent(err)
+	}
 
-				if err != nil {
-					log.Printf("Error deleting message from SQS: %v", err)
-				}
-			}
+	// Los eventos CloudEvents se validan contra su esquema; el formato anterior
+	// se acepta sin validar durante la migración. Los tipos sin ningún esquema
+	// registrado se guardan igualmente (el envelope ya es válido). Un evento
+	// inválido no se arregla reintentando: va directo a la dead-letter queue.
+	if events.IsCloudEvent(body) {
+		err := c.registry.ValidateEvent(body)
+		unregistered := errors.Is(err, eventschema.ErrUnknownSchema) && len(c.registry.Versions(event.Type)) == 0
+		if err != nil && !unregistered {
+			return sqsqueue.Permanent(err)
 		}
 	}
-}
 
-func (c *SQSEventConsumer) processMessage(ctx context.Context, message types.Message, handler func(*domain.EmployeeEvent) error) error {
-	var event domain.EmployeeEvent
-	if err := json.Unmarshal([]byte(*message.Body), &event); err != nil {
-		return err
+	err = handler(ctx, event)
+	if errors.Is(err, domain.ErrInvalidEventPayload) {
+		return sqsqueue.Permanent(err)
 	}
-
-	return handler(&event)
+	return err
 }
@@ -0,0 +1,219 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"logger-service/internal/domain"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Parámetros de las conexiones del stream en vivo
+const (
+	streamHeartbeat    = 15 * time.Second
+	streamWriteTimeout = 10 * time.Second
+	sseRetryMillis     = 3000
+)
+
+// streamTokenParam es el parámetro con el token para los clientes que no
+// pueden enviar el header Authorization (EventSource y WebSocket del navegador)
+const streamTokenParam = "access_token"
+
+// streamMessage es el formato de los mensajes WebSocket
+type streamMessage struct {
+	Type  string           `json:"type"` // log, gap o dropped
+	ID    string           `json:"id,omitempty"`
+	Entry *domain.LogEntry `json:"entry,omitempty"`
+}
+
+// StreamLogsSSE difunde las entradas nuevas como Server-Sent Events. Filtro:
+// ?event_type=a,b (admite "employee.*"). Reanuda desde el header Last-Event-ID
+// (o ?last_event_id=, para la primera conexión de EventSource).
+func (h *HTTPHandler) StreamLogsSSE(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeStream(w, r) {
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	subscription := h.hub.Subscribe(domain.ParseStreamFilter(r.URL.Query().Get("event_type")), lastEventID(r))
+	defer subscription.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", sseRetryMillis)
+
+	if subscription.Gap {
+		fmt.Fprint(w, "event: gap\ndata: {\"message\":\"Last-Event-ID not resumable: some entries may be missing, use GET /logs\"}\n\n")
+	}
+	for _, event := range subscription.Backlog {
+		if err := writeSSEEvent(w, event); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event := <-subscription.Events():
+			if err := writeSSEEvent(w, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case <-subscription.Dropped():
+			fmt.Fprint(w, "event: dropped\ndata: {\"message\":\"client too slow, reconnect with Last-Event-ID\"}\n\n")
+			flusher.Flush()
+			return
+		case <-r.Context().Done():
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// StreamLogsWebSocket difunde las entradas nuevas por WebSocket como mensajes
+// JSON {type, id, entry}. Mismo filtro que SSE; reanuda con ?last_event_id=.
+func (h *HTTPHandler) StreamLogsWebSocket(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeStream(w, r) {
+		return
+	}
+
+	filter := domain.ParseStreamFilter(r.URL.Query().Get("event_type"))
+	resumeFrom := lastEventID(r)
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("Error upgrading stream connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	subscription := h.hub.Subscribe(filter, resumeFrom)
+	defer subscription.Close()
+
+	// El cliente no envía datos: leer solo procesa pings/close y detecta la desconexión
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	write := func(message streamMessage) error {
+		conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		return conn.WriteJSON(message)
+	}
+
+	if subscription.Gap {
+		if err := write(streamMessage{Type: "gap"}); err != nil {
+			return
+		}
+	}
+	for _, event := range subscription.Backlog {
+		if err := write(streamMessage{Type: "log", ID: event.ID, Entry: event.Entry}); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event := <-subscription.Events():
+			if err := write(streamMessage{Type: "log", ID: event.ID, Entry: event.Entry}); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteTimeout)); err != nil {
+				return
+			}
+		case <-subscription.Dropped():
+			write(streamMessage{Type: "dropped"})
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "client too slow"),
+				time.Now().Add(streamWriteTimeout))
+			return
+		case <-closed:
+			return
+		}
+	}
+}
+
+// authorizeStream exige para el stream un origen permitido y un token válido del
+// auth-service (header Authorization: Bearer o parámetro access_token). Si no se
+// cumplen responde 403 o 401 y devuelve false.
+func (h *HTTPHandler) authorizeStream(w http.ResponseWriter, r *http.Request) bool {
+	if !h.checkOrigin(r) {
+		http.Error(w, "Origin not allowed", http.StatusForbidden)
+		return false
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		token = r.URL.Query().Get(streamTokenParam)
+	}
+	if token == "" || h.verifier == nil {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "A valid bearer token is required to open the log stream", http.StatusUnauthorized)
+		return false
+	}
+	if _, err := h.verifier.VerifyToken(token); err != nil {
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		http.Error(w, "A valid bearer token is required to open the log stream", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+// checkOrigin acepta las peticiones sin Origin (clientes que no son un
+// navegador), las del mismo origen y las de los orígenes permitidos
+func (h *HTTPHandler) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if h.allowedOrigins[origin] {
+		return true
+	}
+	parsed, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(parsed.Host, r.Host)
+}
+
+// writeSSEEvent escribe una entrada como evento SSE "log" con su ID de stream
+func writeSSEEvent(w http.ResponseWriter, event StreamEvent) error {
+	data, err := json.Marshal(event.Entry)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: log\ndata: %s\n\n", event.ID, data)
+	return err
+}
+
+// lastEventID devuelve el punto de reanudación del header Last-Event-ID o del
+// parámetro last_event_id (EventSource y WebSocket no permiten headers propios)
+func lastEventID(r *http.Request) string {
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		return id
+	}
+	return r.URL.Query().Get("last_event_id")
+}
@@ -0,0 +1,70 @@
+package infrastructure
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeVerifier acepta solo el token "valid"
+type fakeVerifier struct{}
+
+func (fakeVerifier) VerifyToken(token string) (string, error) {
+	if token != "valid" {
+		return "", errors.New("invalid token")
+	}
+	return "user-1", nil
+}
+
+func TestAuthorizeStream(t *testing.T) {
+	handler := NewHTTPHandler(nil, nil, fakeVerifier{}, []string{"https://dashboard.example.com/"})
+
+	tests := []struct {
+		name       string
+		url        string
+		origin     string
+		auth       string
+		wantStatus int // 0 = autorizado
+	}{
+		{"header Authorization", "/logs/stream", "", "Bearer valid", 0},
+		{"parámetro access_token", "/logs/stream?access_token=valid", "", "", 0},
+		{"origen permitido", "/logs/ws?access_token=valid", "https://dashboard.example.com", "", 0},
+		{"mismo origen", "/logs/ws?access_token=valid", "http://logs.internal:8084", "", 0},
+		{"sin token", "/logs/stream", "", "", http.StatusUnauthorized},
+		{"token inválido", "/logs/stream", "", "Bearer forged", http.StatusUnauthorized},
+		{"token inválido en el parámetro", "/logs/ws?access_token=forged", "", "", http.StatusUnauthorized},
+		{"origen no permitido", "/logs/ws?access_token=valid", "https://evil.example.com", "", http.StatusForbidden},
+		{"origen malformado", "/logs/stream", "://", "Bearer valid", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "http://logs.internal:8084"+tt.url, nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if tt.auth != "" {
+				r.Header.Set("Authorization", tt.auth)
+			}
+			w := httptest.NewRecorder()
+
+			authorized := handler.authorizeStream(w, r)
+			if authorized != (tt.wantStatus == 0) {
+				t.Fatalf("authorizeStream() = %t, response %d", authorized, w.Code)
+			}
+			if !authorized && w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+
+	t.Run("sin verificador", func(t *testing.T) {
+		handler := NewHTTPHandler(nil, nil, nil, nil)
+		r := httptest.NewRequest(http.MethodGet, "/logs/stream", nil)
+		r.Header.Set("Authorization", "Bearer valid")
+		w := httptest.NewRecorder()
+		if handler.authorizeStream(w, r) || w.Code != http.StatusUnauthorized {
+			t.Errorf("authorizeStream() without verifier answered %d, want %d", w.Code, http.StatusUnauthorized)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"logger-service/internal/domain"
+	"net/http"
+)
+
+// alertSignatureHeader lleva la firma HMAC-SHA256 del cuerpo ("sha256=<hex>")
+const alertSignatureHeader = "X-Alert-Signature"
+
+// WebhookAlertNotifier avisa de las alertas con un POST JSON a una URL
+type WebhookAlertNotifier struct {
+	client *http.Client
+	url    string
+	secret []byte
+}
+
+// NewWebhookAlertNotifier crea el canal webhook. Con secret cada petición
+// lleva la firma HMAC-SHA256 del cuerpo para que el receptor la verifique.
+func NewWebhookAlertNotifier(url, secret string) *WebhookAlertNotifier {
+	return &WebhookAlertNotifier{
+		client: &http.Client{},
+		url:    url,
+		secret: []byte(secret),
+	}
+}
+
+// Notify envía la alerta; cualquier respuesta que no sea 2xx es un error
+func (n *WebhookAlertNotifier) Notify(ctx context.Context, alert *domain.Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(n.secret) > 0 {
+		mac := hmac.New(sha256.New, n.secret)
+		mac.Write(body)
+		req.Header.Set(alertSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded %s", resp.Status)
+	}
+	return nil
+}
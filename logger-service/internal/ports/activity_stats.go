@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+	"logger-service/internal/domain"
+	"time"
+)
+
+// ActivityStatsRepository define el puerto de los contadores de actividad por
+// tipo de evento, por hora y por día (UTC)
+type ActivityStatsRepository interface {
+	// Increment suma un evento del tipo a los contadores de la hora y del día de at
+	Increment(ctx context.Context, eventType string, at time.Time) error
+	// FindCounters obtiene los contadores de un tipo y granularidad (BucketHour
+	// o BucketDay) que empiezan entre from y to, ambos inclusive
+	FindCounters(ctx context.Context, eventType string, size domain.BucketSize, from, to time.Time) ([]domain.ActivityCounter, error)
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+	"logger-service/internal/domain"
+)
+
+// AlertEvaluator define el puerto que evalúa las reglas de alerta con cada
+// entrada de log ya guardada
+type AlertEvaluator interface {
+	Evaluate(entry *domain.LogEntry)
+}
+
+// AlertNotifier define el puerto de un canal de aviso de alertas (webhook, messaging-service...)
+type AlertNotifier interface {
+	Notify(ctx context.Context, alert *domain.Alert) error
+}
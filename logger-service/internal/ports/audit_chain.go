@@ -0,0 +1,31 @@
+package ports
+
+import (
+	"context"
+	"logger-service/internal/domain"
+)
+
+// AuditChainStore define el puerto de lectura de las cadenas de auditoría y
+// de almacenamiento de sus checkpoints firmados
+type AuditChainStore interface {
+	// ListHeads recorre las cabezas de todas las cadenas
+	ListHeads(ctx context.Context, fn func(*domain.ChainHead) error) error
+	// GetHead obtiene la cabeza de una cadena (nil si no existe)
+	GetHead(ctx context.Context, chainID string) (*domain.ChainHead, error)
+	// HeadsPendingCheckpoint obtiene las cabezas que avanzaron desde su último checkpoint
+	HeadsPendingCheckpoint(ctx context.Context) ([]*domain.ChainHead, error)
+	// SaveCheckpoint guarda el checkpoint y lo registra en la cabeza de su cadena
+	SaveCheckpoint(ctx context.Context, checkpoint *domain.Checkpoint) error
+	// ListCheckpoints obtiene los checkpoints de una cadena ordenados por secuencia
+	ListCheckpoints(ctx context.Context, chainID string) ([]*domain.Checkpoint, error)
+	// ReadChain recorre las entradas de una cadena en orden de secuencia
+	ReadChain(ctx context.Context, chainID string, fn func(*domain.LogEntry) error) error
+}
+
+// CheckpointSigner define el puerto de firma y verificación de checkpoints
+type CheckpointSigner interface {
+	// KeyID identifica la clave con la que se firma
+	KeyID() string
+	Sign(payload []byte) ([]byte, error)
+	Verify(keyID string, payload, signature []byte) bool
+}
@@ -7,5 +7,5 @@ import (
 
 // EventConsumer define el puerto para consumir eventos
 type EventConsumer interface {
-	ConsumeEvents(ctx context.Context, handler func(*domain.EmployeeEvent) error) error
+	ConsumeEvents(ctx context.Context, handler func(context.Context, *domain.Event) error) error
 }
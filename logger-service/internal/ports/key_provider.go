@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+	"logger-service/internal/domain"
+)
+
+// KeyProvider define el puerto de la clave maestra que cifra las claves de
+// datos de los datos personales (archivo local, KMS...)
+type KeyProvider interface {
+	// GenerateDataKey genera una clave de datos AES-256 nueva, en claro y
+	// cifrada por la clave maestra activa
+	GenerateDataKey(ctx context.Context) (*domain.DataKey, error)
+	// DecryptDataKey descifra una clave de datos cifrada por la clave maestra keyID
+	DecryptDataKey(ctx context.Context, keyID string, encrypted []byte) ([]byte, error)
+}
@@ -0,0 +1,30 @@
+package ports
+
+import (
+	"context"
+	"logger-service/internal/domain"
+	"time"
+)
+
+// LogArchiveRepository define el puerto de la tabla de logs que usa el archivado
+type LogArchiveRepository interface {
+	// FindPendingArchive recorre, en páginas, las entradas cuya retención vence
+	// el día day (UTC) y que todavía no se archivaron
+	FindPendingArchive(ctx context.Context, day time.Time, fn func([]*domain.LogEntry) error) error
+	// MarkArchived registra el archivo de las entradas y les asigna el TTL
+	// (expiresAt) con el que DynamoDB las borra
+	MarkArchived(ctx context.Context, entries []*domain.LogEntry, archiveKey string, expiresAt time.Time) error
+	// Restore vuelve a insertar una entrada archivada sin tocar su cadena.
+	// Devuelve false si la entrada ya existe. expiresAt cero = sin TTL.
+	Restore(ctx context.Context, entry *domain.LogEntry, archiveKey string, expiresAt time.Time) (bool, error)
+}
+
+// ObjectStore define el puerto del almacenamiento de archivos (sistema de
+// archivos local o S3). Las claves usan "/" como separador.
+type ObjectStore interface {
+	Put(ctx context.Context, key string, data []byte) error
+	// Get devuelve domain.ErrArchiveNotFound si la clave no existe
+	Get(ctx context.Context, key string) ([]byte, error)
+	// List devuelve las claves que empiezan por prefix, ordenadas
+	List(ctx context.Context, prefix string) ([]string, error)
+}
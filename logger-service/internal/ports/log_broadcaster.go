@@ -0,0 +1,8 @@
+package ports
+
+import "logger-service/internal/domain"
+
+// LogBroadcaster define el puerto para difundir en vivo las entradas de log ya guardadas
+type LogBroadcaster interface {
+	Broadcast(entry *domain.LogEntry)
+}
@@ -9,4 +9,6 @@ import (
 type LogRepository interface {
 	Save(ctx context.Context, entry *domain.LogEntry) error
 	FindAll(ctx context.Context) ([]*domain.LogEntry, error)
+	// FindPage obtiene una página de entradas filtradas a partir de un cursor opaco ("" para la primera)
+	FindPage(ctx context.Context, filter domain.LogFilter, cursor string, limit int) (*domain.LogPage, error)
 }
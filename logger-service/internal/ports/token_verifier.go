@@ -0,0 +1,8 @@
+package ports
+
+// TokenVerifier define el puerto que identifica a quien hace una consulta a
+// partir de su token de acceso
+type TokenVerifier interface {
+	// VerifyToken valida el token y devuelve el ID del usuario
+	VerifyToken(token string) (string, error)
+}
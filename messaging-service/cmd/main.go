@@ -5,51 +5,30 @@ import (
 	"log"
 	"messaging-service/internal/application"
 	"messaging-service/internal/infrastructure"
+	"messaging-service/internal/ports"
 	"os"
 	"os/signal"
+	"pkg/awsclient"
+	"pkg/dedup"
+	"pkg/eventschema"
+	"pkg/health"
+	"pkg/sqsqueue"
+	"strconv"
 	"syscall"
-
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials"
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
-	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"time"
 )
 
 func main() {
 	ctx := context.Background()
 
-	// Configurar AWS SDK
-	awsEndpoint := os.Getenv("AWS_ENDPOINT")
-	awsRegion := os.Getenv("AWS_REGION")
-	if awsRegion == "" {
-		awsRegion = "us-east-1"
-	}
-
-	cfg, err := config.LoadDefaultConfig(ctx,
-		config.WithRegion(awsRegion),
-		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
-			os.Getenv("AWS_ACCESS_KEY_ID"),
-			os.Getenv("AWS_SECRET_ACCESS_KEY"),
-			"",
-		)),
-	)
+	// Configurar AWS SDK y crear clientes (endpoint de LocalStack vía AWS_ENDPOINT)
+	clients, err := awsclient.NewFactoryFromEnv(ctx)
 	if err != nil {
 		log.Fatalf("Error loading AWS config: %v", err)
 	}
 
-	// Crear clientes de AWS
-	dynamoClient := dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
-		if awsEndpoint != "" {
-			o.BaseEndpoint = aws.String(awsEndpoint)
-		}
-	})
-
-	sqsClient := sqs.NewFromConfig(cfg, func(o *sqs.Options) {
-		if awsEndpoint != "" {
-			o.BaseEndpoint = aws.String(awsEndpoint)
-		}
-	})
+	dynamoClient := clients.DynamoDB()
+	sqsClient := clients.SQS()
 
 	// Obtener variables de entorno
 	tableName := os.Getenv("DYNAMODB_TABLE")
@@ -62,19 +41,69 @@ func main() {
 		log.Fatal("EMPLOYEE_EVENTS_QUEUE_URL environment variable is required")
 	}
 
+	// Con EVENT_TOPIC_ARN los eventos message.* se publican en el bus (topic SNS) y llegan
+	// a cada suscriptor según su filter policy; sin él se envían directamente a LOG_QUEUE_URL
+	topicARN := os.Getenv("EVENT_TOPIC_ARN")
 	logQueueURL := os.Getenv("LOG_QUEUE_URL")
-	if logQueueURL == "" {
-		log.Fatal("LOG_QUEUE_URL environment variable is required")
+	if topicARN == "" && logQueueURL == "" {
+		log.Fatal("EVENT_TOPIC_ARN or LOG_QUEUE_URL environment variable is required")
+	}
+
+	// Registro de esquemas para validar los eventos
+	registry, err := eventschema.Default()
+	if err != nil {
+		log.Fatalf("Error loading event schemas: %v", err)
 	}
 
 	// Crear instancias de infraestructura (Dependency Injection)
 	repository := infrastructure.NewDynamoDBRepository(dynamoClient, tableName)
-	sender := infrastructure.NewSimulatedMessageSender()
-	publisher := infrastructure.NewSQSEventPublisher(sqsClient, logQueueURL)
-	consumer := infrastructure.NewSQSEventConsumer(sqsClient, employeeEventsQueueURL)
+	// SIMULATED_SEND_FAILURE_RATE (0 a 1) hace fallar esa proporción de envíos
+	failureRate := 0.0
+	if value := os.Getenv("SIMULATED_SEND_FAILURE_RATE"); value != "" {
+		if rate, err := strconv.ParseFloat(value, 64); err == nil && rate >= 0 && rate <= 1 {
+			failureRate = rate
+		}
+	}
+	sender := infrastructure.NewSimulatedMessageSender(failureRate)
+	// Los eventos message.* de los workers se agrupan en SendMessageBatch o PublishBatch
+	publishMaxLatency := 50 * time.Millisecond
+	if value := os.Getenv("SQS_PUBLISH_MAX_LATENCY_MS"); value != "" {
+		if ms, err := strconv.Atoi(value); err == nil && ms > 0 {
+			publishMaxLatency = time.Duration(ms) * time.Millisecond
+		}
+	}
+	var publisher *sqsqueue.BatchPublisher
+	if topicARN != "" {
+		publisher = sqsqueue.NewTopicBatchPublisher(clients.SNS(), topicARN, registry, publishMaxLatency)
+	} else {
+		publisher = sqsqueue.NewBatchPublisher(sqsClient, logQueueURL, registry, publishMaxLatency)
+	}
+	consumerOptions := sqsqueue.OptionsFromEnv()
+	consumer := infrastructure.NewSQSEventConsumer(sqsqueue.NewConsumer(sqsClient, employeeEventsQueueURL, consumerOptions), registry)
+
+	// Registro de eventos procesados para descartar entregas duplicadas
+	// (DEDUP_STORE=memory lo mantiene en memoria, sin DynamoDB)
+	dedupTableName := os.Getenv("DEDUP_TABLE")
+	if dedupTableName == "" {
+		dedupTableName = "processed-events"
+	}
+
+	dedupTTL := dedup.DefaultTTL
+	if value := os.Getenv("DEDUP_TTL_HOURS"); value != "" {
+		if hours, err := strconv.Atoi(value); err == nil && hours > 0 {
+			dedupTTL = time.Duration(hours) * time.Hour
+		}
+	}
+
+	var dedupStore ports.DeduplicationStore
+	if os.Getenv("DEDUP_STORE") == "memory" {
+		dedupStore = dedup.NewMemoryStore(dedupTTL)
+	} else {
+		dedupStore = dedup.NewDynamoDBStore(dynamoClient, dedupTableName, "messaging-service", dedupTTL)
+	}
 
 	// Crear servicio de aplicación
-	service := application.NewMessagingService(repository, sender, publisher)
+	service := application.NewMessagingService(repository, sender, publisher, dedupStore)
 
 	// Configurar manejo de señales para graceful shutdown
 	ctx, cancel := context.WithCancel(ctx)
@@ -89,16 +118,48 @@ func main() {
 		cancel()
 	}()
 
+	// Endpoints de salud (liveness y readiness) en un puerto propio
+	healthPort := os.Getenv("HEALTH_PORT")
+	if healthPort == "" {
+		healthPort = "8083"
+	}
+	healthHandler := health.New("messaging-service")
+	healthHandler.AddCheck("messages-table", awsclient.TableCheck(dynamoClient, tableName))
+	healthHandler.AddCheck("employee-events-queue", awsclient.QueueCheck(sqsClient, employeeEventsQueueURL))
+	if topicARN != "" {
+		healthHandler.AddCheck("event-topic", awsclient.TopicCheck(clients.SNS(), topicARN))
+	} else {
+		healthHandler.AddCheck("log-queue", awsclient.QueueCheck(sqsClient, logQueueURL))
+	}
+	if os.Getenv("DEDUP_STORE") != "memory" {
+		healthHandler.AddCheck("dedup-table", awsclient.TableCheck(dynamoClient, dedupTableName))
+	}
+	if consumerOptions.DeadLetterQueueURL != "" {
+		healthHandler.AddCheck("dead-letter-queue", awsclient.QueueCheck(sqsClient, consumerOptions.DeadLetterQueueURL))
+	}
+	go healthHandler.ListenAndServe(ctx, ":"+healthPort)
+
 	// Iniciar consumidor de eventos
 	log.Println("Messaging service starting...")
 	log.Printf("Consuming events from: %s", employeeEventsQueueURL)
-	log.Printf("Publishing logs to: %s", logQueueURL)
+	if topicARN != "" {
+		log.Printf("Publishing events to topic: %s", topicARN)
+	} else {
+		log.Printf("Publishing logs to: %s", logQueueURL)
+	}
 
-	if err := consumer.ConsumeEvents(ctx, service.HandleEmployeeEvent); err != nil {
+	if err := consumer.ConsumeEvents(ctx, service); err != nil {
 		if err != context.Canceled {
 			log.Fatalf("Error consuming events: %v", err)
 		}
 	}
 
+	// Enviar los eventos que quedaron en el último lote
+	closeCtx, cancelClose := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelClose()
+	if err := publisher.Close(closeCtx); err != nil {
+		log.Printf("Error flushing pending events: %v", err)
+	}
+
 	log.Println("Messaging service stopped gracefully")
 }
@@ -5,7 +5,7 @@ import (
 	"log"
 	"messaging-service/internal/domain"
 	"messaging-service/internal/ports"
-	"time"
+	"pkg/dedup"
 )
 
 // MessagingService implementa la lógica de negocio para el enví de mensajes
@@ -13,6 +13,7 @@ type MessagingService struct {
 	repository ports.MessageRepository
 	sender     ports.MessageSender
 	publisher  ports.EventPublisher
+	dedup      ports.DeduplicationStore
 }
 
 // NewMessagingService crea una nueva instancia del servicio de mensajería
@@ -20,11 +21,13 @@ func NewMessagingService(
 	repository ports.MessageRepository,
 	sender ports.MessageSender,
 	publisher ports.EventPublisher,
+	dedupStore ports.DeduplicationStore,
 ) *MessagingService {
 	return &MessagingService{
 		repository: repository,
 		sender:     sender,
 		publisher:  publisher,
+		dedup:      dedupStore,
 	}
 }
 
@@ -42,6 +45,40 @@ func (s *MessagingService) ProcessEmployeeCreatedEvent(ctx context.Context, even
 	message := domain.NewWelcomeEmail(event.Employee.ID, event.Employee.Name, event.Employee.Email)
 
 	// Enviar el mensaje según su tipo
+	if err := s.send(ctx, message); err != nil {
+		if err == domain.ErrInvalidMessage {
+			return err
+		}
+		// Cada intento fallido se publica como message.failed (el logger-service
+		// lo registra y sus reglas de alerta detectan los fallos repetidos)
+		s.publishMessageFailed(ctx, message, event.Employee.ID, err)
+		return domain.ErrMessageSendFailed
+	}
+
+	// Guardar el mensaje en el repositorio
+	if err := s.repository.Save(ctx, message); err != nil {
+		log.Printf("Error saving message to repository: %v", err)
+		// No retornamos error aquí porque el mensaje ya fue enviado
+	}
+
+	// Publicar evento message.sent para el logger-service
+	sentEvent, err := domain.NewMessageSentEvent(message, event.Employee.ID)
+	if err != nil {
+		log.Printf("Error building message.sent event: %v", err)
+		return nil
+	}
+
+	if err := s.publisher.Publish(ctx, sentEvent); err != nil {
+		log.Printf("Error publishing message.sent event: %v", err)
+		// No retornamos error aquí porque el mensaje ya fue enviado
+	}
+
+	log.Printf("Welcome message sent successfully to %s", message.To)
+	return nil
+}
+
+// send envía el mensaje por el canal de su tipo
+func (s *MessagingService) send(ctx context.Context, message *domain.Message) error {
 	var err error
 	switch message.Type {
 	case domain.MessageTypeEmail:
@@ -53,40 +90,68 @@ func (s *MessagingService) ProcessEmployeeCreatedEvent(ctx context.Context, even
 		return domain.ErrInvalidMessage
 	}
 
-	if err != nil {		log.Printf("Error sending message: %v", err)
+	if err != nil {
+		log.Printf("Error sending message: %v", err)
 		message.Status = "failed"
-		return domain.ErrMessageSendFailed
+		return err
 	}
+	return nil
+}
 
-	// Guardar el mensaje en el repositorio
-	if err := s.repository.Save(ctx, message); err != nil {
-		log.Printf("Error saving message to repository: %v", err)
-		// No retornamos error aquí porque el mensaje ya fue enviado
+// publishMessageFailed publica el evento message.failed de un intento fallido
+func (s *MessagingService) publishMessageFailed(ctx context.Context, message *domain.Message, employeeID string, sendErr error) {
+	failedEvent, err := domain.NewMessageFailedEvent(message, employeeID, sendErr)
+	if err != nil {
+		log.Printf("Error building message.failed event: %v", err)
+		return
 	}
-
-	// Publicar evento de log compatible con logger-service
-	logEvent := &domain.LogEvent{
-		EventType: "message.sent",
-		Employee: domain.Employee{
-			ID:        message.ID,
-			Name:      "Messaging Service - Welcome Email",
-			Email:     message.To,
-			CreatedAt: message.CreatedAt.Format(time.RFC3339),
-		},
-		Timestamp: time.Now().Format(time.RFC3339),
+	if err := s.publisher.Publish(ctx, failedEvent); err != nil {
+		log.Printf("Error publishing message.failed event: %v", err)
 	}
+}
 
-	if err := s.publisher.PublishLogEvent(ctx, logEvent); err != nil {
-		log.Printf("Error publishing log event: %v", err)
-		// No retornamos error aquí porque el mensaje ya fue enviado
+// ProcessAlertEvent avisa por email de una alerta a cada destinatario. Cada
+// destinatario se procesa una sola vez: si un envío falla, el reintento del
+// evento solo repite los pendientes. Los avisos no publican message.sent ni
+// message.failed para que una alerta no pueda disparar otra.
+func (s *MessagingService) ProcessAlertEvent(ctx context.Context, event *domain.AlertEvent) error {
+	var failed int
+	for _, recipient := range event.Alert.Recipients {
+		message := domain.NewAlertEmail(&event.Alert, recipient)
+
+		err := dedup.Once(ctx, s.dedup, event.EventID+"/"+recipient, func(ctx context.Context) error {
+			if err := s.send(ctx, message); err != nil {
+				return err
+			}
+			if err := s.repository.Save(ctx, message); err != nil {
+				log.Printf("Error saving message to repository: %v", err)
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("Error sending alert %s to %s: %v", event.Alert.AlertID, recipient, err)
+			failed++
+			continue
+		}
+		log.Printf("Alert %s (rule %s) sent to %s", event.Alert.AlertID, event.Alert.Rule, recipient)
 	}
 
-	log.Printf("Welcome message sent successfully to %s", message.To)
+	if failed > 0 {
+		return domain.ErrMessageSendFailed
+	}
 	return nil
 }
 
-// HandleEmployeeEvent es el handler para procesar eventos de empleado
-func (s *MessagingService) HandleEmployeeEvent(event *domain.EmployeeEvent) error {
-	ctx := context.Background()
-	return s.ProcessEmployeeCreatedEvent(ctx, event)
+// HandleAlertEvent es el handler de los eventos alert.fired
+func (s *MessagingService) HandleAlertEvent(ctx context.Context, event *domain.AlertEvent) error {
+	return s.ProcessAlertEvent(ctx, event)
+}
+
+// HandleEmployeeEvent es el handler para procesar eventos de empleado. Cada
+// evento se procesa una sola vez para no repetir el mensaje de bienvenida
+// cuando SQS entrega el mismo evento más de una vez.
+func (s *MessagingService) HandleEmployeeEvent(ctx context.Context, event *domain.EmployeeEvent) error {
+	return dedup.Once(ctx, s.dedup, event.EventID, func(ctx context.Context) error {
+		return s.ProcessEmployeeCreatedEvent(ctx, event)
+	})
 }
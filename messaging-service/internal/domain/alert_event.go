@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"fmt"
+	"pkg/events"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AlertEvent representa una alerta disparada por el logger-service que se
+// avisa por email a sus destinatarios
+type AlertEvent struct {
+	EventID string
+	Alert   events.AlertFiredPayload
+}
+
+// NewAlertEvent obtiene la alerta de un evento alert.fired
+func NewAlertEvent(envelope *events.CloudEvent) (*AlertEvent, error) {
+	var payload events.AlertFiredPayload
+	if err := envelope.DecodeData(&payload); err != nil {
+		return nil, err
+	}
+	if payload.AlertID == "" || payload.Rule == "" {
+		return nil, ErrInvalidEvent
+	}
+
+	return &AlertEvent{EventID: envelope.ID, Alert: payload}, nil
+}
+
+// NewAlertEmail crea el email de aviso de una alerta para un destinatario
+func NewAlertEmail(alert *events.AlertFiredPayload, recipient string) *Message {
+	return &Message{
+		ID:        "msg-alert-" + alert.AlertID + "-" + recipient,
+		Type:      MessageTypeEmail,
+		To:        recipient,
+		Subject:   fmt.Sprintf("[%s] Alerta: %s", strings.ToUpper(alert.Severity), alert.Rule),
+		Body:      buildAlertEmailBody(alert),
+		Status:    "pending",
+		CreatedAt: time.Now(),
+	}
+}
+
+// buildAlertEmailBody construye el cuerpo del email de aviso de una alerta
+func buildAlertEmailBody(alert *events.AlertFiredPayload) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Se disparó la regla de alerta %q (%s).\n\n", alert.Rule, alert.Severity)
+	if alert.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", alert.Description)
+	}
+	fmt.Fprintf(&b, "Eventos: %d en %s (umbral: %d)\n", alert.Count, time.Duration(alert.WindowSeconds)*time.Second, alert.Threshold)
+	fmt.Fprintf(&b, "Primer evento: %s\n", alert.FirstEventAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "Último evento: %s\n", alert.LastEventAt.Format(time.RFC3339))
+
+	keys := make([]string, 0, len(alert.Group))
+	for key := range alert.Group {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%s: %s\n", key, alert.Group[key])
+	}
+
+	fmt.Fprintf(&b, "\nID de la alerta: %s", alert.AlertID)
+	return b.String()
+}
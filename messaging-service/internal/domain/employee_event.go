@@ -1,17 +1,45 @@
 package domain
 
+import (
+	"pkg/events"
+	"time"
+)
+
 // EmployeeEvent representa un evento relacionado con un empleado
 type EmployeeEvent struct {
-	EventType string    `json:"event_type"`
-	Employee  *Employee `json:"employee"`
-	Timestamp string    `json:"timestamp"`
+	EventID   string
+	EventType string
+	Employee  *Employee
+	Timestamp string
 }
 
-// Employee representa los datos básicos de un empleado en el evento
-type Employee struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	Email     string `json:"email"`
-	Password  string `json:"password,omitempty"`
-	CreatedAt string `json:"created_at,omitempty"`
+// Employee representa los datos básicos de un empleado en el evento (contrato compartido)
+type Employee = events.EmployeeData
+
+// DecodeEmployeeEvent decodifica un evento de empleado en formato CloudEvents
+// o en el formato anterior durante la migración
+func DecodeEmployeeEvent(body []byte) (*EmployeeEvent, error) {
+	envelope, err := events.Decode(body)
+	if err != nil {
+		return nil, err
+	}
+	return NewEmployeeEvent(envelope)
+}
+
+// NewEmployeeEvent obtiene el evento de empleado de un envelope ya decodificado
+func NewEmployeeEvent(envelope *events.CloudEvent) (*EmployeeEvent, error) {
+	var payload events.EmployeePayload
+	if err := envelope.DecodeData(&payload); err != nil {
+		return nil, err
+	}
+	if payload.Employee == nil {
+		return nil, ErrInvalidEvent
+	}
+
+	return &EmployeeEvent{
+		EventID:   envelope.ID,
+		EventType: envelope.Type,
+		Employee:  payload.Employee,
+		Timestamp: envelope.Time.Format(time.RFC3339),
+	}, nil
 }
@@ -9,6 +9,9 @@ var (
 	// ErrInvalidMessage indica que el mensaje no es válido
 	ErrInvalidMessage = errors.New("invalid message")
 
+	// ErrInvalidEvent indica que el evento recibido no tiene el formato esperado
+	ErrInvalidEvent = errors.New("invalid event")
+
 	// ErrEventProcessing indica que hubo un error procesando el evento
 	ErrEventProcessing = errors.New("failed to process event")
 )
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"pkg/events"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NewMessageFailedEvent crea el evento message.failed de un intento de envío
+// fallido a un empleado. Cada intento es un evento distinto.
+func NewMessageFailedEvent(message *Message, employeeID string, sendErr error) (*events.CloudEvent, error) {
+	failedAt := time.Now().UTC()
+
+	return events.New(uuid.New().String(), events.SourceMessagingService, events.TypeMessageFailed, employeeID, failedAt, events.MessageFailedPayload{
+		MessageID:  message.ID,
+		EmployeeID: employeeID,
+		Channel:    string(message.Type),
+		To:         message.To,
+		Subject:    message.Subject,
+		Error:      sendErr.Error(),
+		FailedAt:   failedAt,
+	})
+}
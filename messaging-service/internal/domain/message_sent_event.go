@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"pkg/events"
+	"time"
+)
+
+// NewMessageSentEvent crea el evento message.sent de un mensaje enviado a un empleado
+func NewMessageSentEvent(message *Message, employeeID string) (*events.CloudEvent, error) {
+	sentAt := time.Now().UTC()
+	if message.SentAt != nil {
+		sentAt = message.SentAt.UTC()
+	}
+
+	return events.New(message.ID, events.SourceMessagingService, events.TypeMessageSent, employeeID, sentAt, events.MessageSentPayload{
+		MessageID:  message.ID,
+		EmployeeID: employeeID,
+		Channel:    string(message.Type),
+		To:         message.To,
+		Subject:    message.Subject,
+		SentAt:     sentAt,
+	})
+}
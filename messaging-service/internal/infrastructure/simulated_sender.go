@@ -2,17 +2,29 @@ package infrastructure
 
 import (
 	"context"
+	"errors"
 	"log"
+	"math/rand"
 	"messaging-service/internal/domain"
 	"time"
 )
 
+// sendDelay simula la latencia de un proveedor real de email/SMS
+const sendDelay = 100 * time.Millisecond
+
+// errSimulatedFailure es el error de los envíos que fallan a propósito
+var errSimulatedFailure = errors.New("simulated provider failure")
+
 // SimulatedMessageSender implementa el envío simulado de mensajes
-type SimulatedMessageSender struct{}
+type SimulatedMessageSender struct {
+	failureRate float64
+}
 
-// NewSimulatedMessageSender crea una nueva instancia del sender simulado
-func NewSimulatedMessageSender() *SimulatedMessageSender {
-	return &SimulatedMessageSender{}
+// NewSimulatedMessageSender crea una nueva instancia del sender simulado.
+// failureRate (0 a 1) es la proporción de envíos que fallan, para probar los
+// reintentos y las alertas de fallos repetidos.
+func NewSimulatedMessageSender(failureRate float64) *SimulatedMessageSender {
+	return &SimulatedMessageSender{failureRate: failureRate}
 }
 
 // SendEmail simula el envío de un email
@@ -24,7 +36,9 @@ func (s *SimulatedMessageSender) SendEmail(ctx context.Context, message *domain.
 	log.Printf("===================================")
 
 	// Simular un pequeño delay como si estuviera enviando realmente
-	time.Sleep(100 * time.Millisecond)
+	if err := s.simulateSend(ctx); err != nil {
+		return err
+	}
 
 	log.Printf("✓ Email enviado exitosamente a %s", message.To)
 	return nil
@@ -38,8 +52,32 @@ func (s *SimulatedMessageSender) SendSMS(ctx context.Context, message *domain.Me
 	log.Printf("==================================")
 
 	// Simular un pequeño delay como si estuviera enviando realmente
-	time.Sleep(100 * time.Millisecond)
+	if err := s.simulateSend(ctx); err != nil {
+		return err
+	}
 
 	log.Printf("✓ SMS enviado exitosamente a %s", message.To)
 	return nil
 }
+
+// simulateSend simula la latencia del proveedor y, con probabilidad
+// failureRate, un fallo del envío
+func (s *SimulatedMessageSender) simulateSend(ctx context.Context) error {
+	if err := simulateDelay(ctx); err != nil {
+		return err
+	}
+	if s.failureRate > 0 && rand.Float64() < s.failureRate {
+		return errSimulatedFailure
+	}
+	return nil
+}
+
+// simulateDelay espera sendDelay o hasta que se cancele el contexto
+func simulateDelay(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(sendDelay):
+		return nil
+	}
+}
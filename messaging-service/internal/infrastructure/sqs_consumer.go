@@ -2,80 +2,75 @@ package infrastructure
 
 import (
 	"context"
-	"encoding/json"
 	"log"
 	"messaging-service/internal/domain"
-	"time"
+	"messaging-service/internal/ports"
+	"pkg/events"
+	"pkg/eventschema"
+	"pkg/sqsqueue"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 )
 
-// SQSEventConsumer implementa el consumidor de eventos usando SQS
+// SQSEventConsumer implementa el consumidor de eventos de empleado sobre el consumidor SQS compartido
 type SQSEventConsumer struct {
-	client   *sqs.Client
-	queueURL string
+	queue    *sqsqueue.Consumer
+	registry *eventschema.Registry
 }
 
 // NewSQSEventConsumer crea una nueva instancia del consumidor
-func NewSQSEventConsumer(client *sqs.Client, queueURL string) *SQSEventConsumer {
+func NewSQSEventConsumer(queue *sqsqueue.Consumer, registry *eventschema.Registry) *SQSEventConsumer {
 	return &SQSEventConsumer{
-		client:   client,
-		queueURL: queueURL,
+		queue:    queue,
+		registry: registry,
 	}
 }
 
-// ConsumeEvents consume eventos de SQS
-func (c *SQSEventConsumer) ConsumeEvents(ctx context.Context, handler func(*domain.EmployeeEvent) error) error {
-	log.Printf("Starting to consume events from queue: %s", c.queueURL)
-
-	for {
-		select {
-		case <-ctx.Done():
-			log.Println("Event consumer stopped")
-			return ctx.Err()
-		default:
-			messages, err := c.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
-				QueueUrl:            aws.String(c.queueURL),
-				MaxNumberOfMessages: 10,
-				WaitTimeSeconds:     20,
-				VisibilityTimeout:   30,
-			})
+// ConsumeEvents consume eventos de SQS con el pool de workers del consumidor;
+// el handler puede ejecutarse en paralelo para mensajes distintos
+func (c *SQSEventConsumer) ConsumeEvents(ctx context.Context, handler ports.EventHandler) error {
+	return c.queue.Run(ctx, func(ctx context.Context, message types.Message) error {
+		return c.processMessage(ctx, message, handler)
+	})
+}
 
-			if err != nil {
-				log.Printf("Error receiving messages from SQS: %v", err)
-				time.Sleep(5 * time.Second)
-				continue
-			}
+func (c *SQSEventConsumer) processMessage(ctx context.Context, message types.Message, handler ports.EventHandler) error {
+	body := []byte(*message.Body)
 
-			for _, message := range messages.Messages {
-				if err := c.processMessage(ctx, message, handler); err != nil {
-					log.Printf("Error processing message: %v", err)
-					continue
-				}
+	// Los eventos CloudEvents se validan contra su esquema; el formato anterior
+	// se acepta sin validar durante la migración. Un evento inválido no se
+	// arregla reintentando: va directo a la dead-letter queue.
+	if events.IsCloudEvent(body) {
+		if err := c.registry.ValidateEvent(body); err != nil {
+			log.Printf("Invalid event: %v", err)
+			return sqsqueue.Permanent(err)
+		}
+	}
 
-				// Eliminar mensaje de la cola
-				_, err := c.client.DeleteMessage(ctx, &sqs.DeleteMessageInput{
-					QueueUrl:      aws.String(c.queueURL),
-					ReceiptHandle: message.ReceiptHandle,
-				})
+	envelope, err := events.Decode(body)
+	if err != nil {
+		log.Printf("Error decoding message: %v", err)
+		return sqsqueue.Permanent(err)
+	}
 
-				if err != nil {
-					log.Printf("Error deleting message from SQS: %v", err)
-				}
-			}
+	// Las alertas del logger-service llegan por la misma cola que los eventos de empleado
+	if envelope.Type == events.TypeAlertFired {
+		alert, err := domain.NewAlertEvent(envelope)
+		if err != nil {
+			log.Printf("Error decoding alert: %v", err)
+			return sqsqueue.Permanent(err)
 		}
+
+		log.Printf("Processing alert: %s (rule %s)", alert.Alert.AlertID, alert.Alert.Rule)
+		return handler.HandleAlertEvent(ctx, alert)
 	}
-}
 
-func (c *SQSEventConsumer) processMessage(ctx context.Context, message types.Message, handler func(*domain.EmployeeEvent) error) error {
-	var event domain.EmployeeEvent
-	if err := json.Unmarshal([]byte(*message.Body), &event); err != nil {
-		log.Printf("Error unmarshalling message: %v", err)
-		return err
+	event, err := domain.NewEmployeeEvent(envelope)
+	if err != nil {
+		log.Printf("Error decoding message: %v", err)
+		return sqsqueue.Permanent(err)
 	}
 
 	log.Printf("Processing event: %s for employee: %s", event.EventType, event.Employee.Email)
-	return handler(&event)
+	return handler.HandleEmployeeEvent(ctx, event)
 }
@@ -0,0 +1,17 @@
+package ports
+
+import "context"
+
+// DeduplicationStore define el puerto para registrar los eventos ya procesados y
+// descartar las entregas duplicadas de SQS (implementaciones en pkg/dedup)
+type DeduplicationStore interface {
+	// Claim reserva el evento; devuelve dedup.ErrAlreadyProcessed o dedup.ErrInProgress
+	// si ya se procesó o si otro worker lo está procesando
+	Claim(ctx context.Context, eventID string) error
+
+	// Complete marca el evento como procesado
+	Complete(ctx context.Context, eventID string) error
+
+	// Release libera la reserva para que el evento se pueda reintentar
+	Release(ctx context.Context, eventID string) error
+}
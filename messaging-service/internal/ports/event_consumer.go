@@ -5,7 +5,15 @@ import (
 	"messaging-service/internal/domain"
 )
 
+// EventHandler procesa los eventos consumidos según su tipo
+type EventHandler interface {
+	// HandleEmployeeEvent procesa los eventos employee.*
+	HandleEmployeeEvent(ctx context.Context, event *domain.EmployeeEvent) error
+	// HandleAlertEvent procesa los eventos alert.fired del logger-service
+	HandleAlertEvent(ctx context.Context, event *domain.AlertEvent) error
+}
+
 // EventConsumer define el puerto para consumir eventos
 type EventConsumer interface {
-	ConsumeEvents(ctx context.Context, handler func(*domain.EmployeeEvent) error) error
+	ConsumeEvents(ctx context.Context, handler EventHandler) error
 }
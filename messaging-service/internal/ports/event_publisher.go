@@ -2,10 +2,10 @@ package ports
 
 import (
 	"context"
-	"messaging-service/internal/domain"
+	"pkg/events"
 )
 
 // EventPublisher define el puerto para publicar eventos
 type EventPublisher interface {
-	PublishLogEvent(ctx context.Context, event *domain.LogEvent) error
+	Publish(ctx context.Context, event *events.CloudEvent) error
 }
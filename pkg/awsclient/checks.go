@@ -0,0 +1,42 @@
+package awsclient
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// TableCheck devuelve un chequeo de readiness que verifica que la tabla exista
+func TableCheck(client *dynamodb.Client, tableName string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+			TableName: aws.String(tableName),
+		})
+		return err
+	}
+}
+
+// QueueCheck devuelve un chequeo de readiness que verifica que la cola exista
+func QueueCheck(client *sqs.Client, queueURL string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+			QueueUrl:       aws.String(queueURL),
+			AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameQueueArn},
+		})
+		return err
+	}
+}
+
+// TopicCheck devuelve un chequeo de readiness que verifica que el topic SNS exista
+func TopicCheck(client *sns.Client, topicARN string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := client.GetTopicAttributes(ctx, &sns.GetTopicAttributesInput{
+			TopicArn: aws.String(topicARN),
+		})
+		return err
+	}
+}
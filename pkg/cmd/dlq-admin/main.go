@@ -0,0 +1,401 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"pkg/awsclient"
+	"pkg/events"
+	"pkg/eventschema"
+	"pkg/sqsqueue"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// dlq-admin inspecciona y reprocesa los mensajes de una dead-letter queue.
+// Usa la configuración de AWS del entorno (AWS_REGION, AWS_ENDPOINT, ...). Uso:
+//
+//	dlq-admin list    -queue <dlq-url> [-max 20] [-body]
+//	dlq-admin discard -queue <dlq-url> -id <message-id>[,<message-id>...]
+//	dlq-admin edit    -queue <dlq-url> -id <message-id> -file evento.json [-force]
+//	dlq-admin redrive -queue <dlq-url> (-id <message-id>,... | -all) [-to <queue-url>] [-rate 5]
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	var err error
+	switch command, args := os.Args[1], os.Args[2:]; command {
+	case "list":
+		err = runList(ctx, args)
+	case "discard":
+		err = runDiscard(ctx, args)
+	case "edit":
+		err = runEdit(ctx, args)
+	case "redrive":
+		err = runRedrive(ctx, args)
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `Uso: dlq-admin <comando> [opciones]
+
+Comandos:
+  list     Lista los mensajes con el evento decodificado y el motivo del fallo
+  discard  Elimina definitivamente mensajes de la DLQ
+  edit     Reemplaza el cuerpo de un mensaje (p.ej. para corregir un evento inválido)
+  redrive  Devuelve mensajes a su cola de origen con rate limiting
+
+Ejecuta "dlq-admin <comando> -h" para ver sus opciones.`)
+}
+
+// commonFlags son las opciones compartidas por todos los comandos
+type commonFlags struct {
+	queueURL string
+	lease    time.Duration
+}
+
+func newFlagSet(name string, common *commonFlags) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.StringVar(&common.queueURL, "queue", os.Getenv("SQS_DLQ_URL"), "URL de la dead-letter queue (por defecto SQS_DLQ_URL)")
+	fs.DurationVar(&common.lease, "lease", 2*time.Minute, "Tiempo que los mensajes leídos quedan reservados mientras se procesan")
+	return fs
+}
+
+func openQueue(ctx context.Context, common commonFlags) (*sqsqueue.DeadLetterQueue, error) {
+	if common.queueURL == "" {
+		return nil, fmt.Errorf("-queue is required")
+	}
+	clients, err := awsclient.NewFactoryFromEnv(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("loading AWS config: %w", err)
+	}
+	return sqsqueue.NewDeadLetterQueue(clients.SQS(), common.queueURL, common.lease), nil
+}
+
+// runList muestra los mensajes de la DLQ y los libera sin modificarlos
+func runList(ctx context.Context, args []string) error {
+	var common commonFlags
+	fs := newFlagSet("list", &common)
+	max := fs.Int("max", 20, "Número máximo de mensajes a mostrar (0 = todos)")
+	showBody := fs.Bool("body", false, "Mostrar el cuerpo completo del evento")
+	fs.Parse(args)
+
+	queue, err := openQueue(ctx, common)
+	if err != nil {
+		return err
+	}
+
+	messages, err := queue.Receive(ctx, *max)
+	defer func() {
+		if err := queue.Release(context.Background(), messages...); err != nil {
+			log.Printf("Error releasing messages: %v", err)
+		}
+	}()
+	if err != nil {
+		return err
+	}
+
+	if len(messages) == 0 {
+		fmt.Println("La dead-letter queue está vacía")
+		return nil
+	}
+
+	for _, message := range messages {
+		printMessage(message, *showBody)
+	}
+	fmt.Printf("%d mensaje(s)\n", len(messages))
+	return nil
+}
+
+// runDiscard elimina los mensajes indicados
+func runDiscard(ctx context.Context, args []string) error {
+	var common commonFlags
+	fs := newFlagSet("discard", &common)
+	ids := fs.String("id", "", "IDs de mensaje separados por coma")
+	fs.Parse(args)
+
+	messageIDs := splitIDs(*ids)
+	if len(messageIDs) == 0 {
+		return fmt.Errorf("-id is required")
+	}
+
+	queue, err := openQueue(ctx, common)
+	if err != nil {
+		return err
+	}
+
+	messages, err := queue.Find(ctx, messageIDs)
+	if err != nil {
+		return err
+	}
+	reportMissing(messageIDs, messages)
+
+	for _, message := range messages {
+		if err := queue.Discard(ctx, message); err != nil {
+			return fmt.Errorf("discarding %s: %w", message.MessageID, err)
+		}
+		fmt.Printf("✓ %s descartado\n", message.MessageID)
+	}
+	return nil
+}
+
+// runEdit reemplaza el cuerpo de un mensaje por el contenido de un archivo
+func runEdit(ctx context.Context, args []string) error {
+	var common commonFlags
+	fs := newFlagSet("edit", &common)
+	id := fs.String("id", "", "ID del mensaje a editar")
+	file := fs.String("file", "", "Archivo con el nuevo cuerpo del mensaje (- para stdin)")
+	force := fs.Bool("force", false, "Guardar aunque el evento no cumpla su esquema")
+	fs.Parse(args)
+
+	if *id == "" || *file == "" {
+		return fmt.Errorf("-id and -file are required")
+	}
+
+	body, err := readBody(*file)
+	if err != nil {
+		return err
+	}
+	if err := validateBody(body); err != nil {
+		if !*force {
+			return fmt.Errorf("new body is not a valid event (use -force to save anyway): %w", err)
+		}
+		log.Printf("Warning: saving invalid event: %v", err)
+	}
+
+	queue, err := openQueue(ctx, common)
+	if err != nil {
+		return err
+	}
+
+	messages, err := queue.Find(ctx, []string{*id})
+	if err != nil {
+		return err
+	}
+	if len(messages) == 0 {
+		return fmt.Errorf("message %s not found in %s", *id, queue.QueueURL())
+	}
+
+	newID, err := queue.Replace(ctx, messages[0], string(body))
+	if err != nil {
+		return fmt.Errorf("replacing %s: %w", *id, err)
+	}
+	fmt.Printf("✓ %s reemplazado por %s\n", *id, newID)
+	return nil
+}
+
+// runRedrive devuelve mensajes a su cola de origen respetando el rate limit
+func runRedrive(ctx context.Context, args []string) error {
+	var common commonFlags
+	fs := newFlagSet("redrive", &common)
+	ids := fs.String("id", "", "IDs de mensaje separados por coma")
+	all := fs.Bool("all", false, "Reprocesar todos los mensajes de la DLQ")
+	target := fs.String("to", "", "Cola destino (por defecto la de origen, atributo dlq_source_queue)")
+	rate := fs.Float64("rate", 5, "Mensajes por segundo")
+	fs.Parse(args)
+
+	messageIDs := splitIDs(*ids)
+	if *all == (len(messageIDs) > 0) {
+		return fmt.Errorf("use either -id or -all")
+	}
+	if *rate <= 0 {
+		return fmt.Errorf("-rate must be greater than 0")
+	}
+
+	queue, err := openQueue(ctx, common)
+	if err != nil {
+		return err
+	}
+
+	limiter := time.NewTicker(time.Duration(float64(time.Second) / *rate))
+	defer limiter.Stop()
+
+	redriver := &redriver{queue: queue, target: *target, limiter: limiter}
+	defer redriver.releaseSkipped()
+
+	if !*all {
+		messages, err := queue.Find(ctx, messageIDs)
+		if err != nil {
+			return err
+		}
+		reportMissing(messageIDs, messages)
+		for _, message := range messages {
+			if err := redriver.redrive(ctx, message); err != nil {
+				return err
+			}
+		}
+		redriver.summary()
+		return nil
+	}
+
+	// Con -all se recibe por lotes hasta vaciar la cola; los mensajes omitidos
+	// quedan reservados hasta el final para no volver a recibirlos. Si vence su
+	// reserva (o falló un borrado) un mensaje puede volver a recibirse: se ignora,
+	// y cuando un lote solo trae mensajes ya vistos se da la cola por recorrida
+	seen := make(map[string]bool)
+	for {
+		batch, err := queue.ReceiveBatch(ctx)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			break
+		}
+		unseen := 0
+		for _, message := range batch {
+			if seen[message.MessageID] {
+				continue
+			}
+			seen[message.MessageID] = true
+			unseen++
+			if err := redriver.redrive(ctx, message); err != nil {
+				return err
+			}
+		}
+		if unseen == 0 {
+			log.Printf("Received only messages already processed in this run, stopping")
+			break
+		}
+	}
+	redriver.summary()
+	return nil
+}
+
+// redriver aplica el rate limit y lleva la cuenta de los mensajes reprocesados
+type redriver struct {
+	queue   *sqsqueue.DeadLetterQueue
+	target  string
+	limiter *time.Ticker
+	moved   int
+	skipped []*sqsqueue.DeadLetterMessage
+}
+
+func (r *redriver) redrive(ctx context.Context, message *sqsqueue.DeadLetterMessage) error {
+	target := r.target
+	if target == "" {
+		target = message.SourceQueue
+	}
+	if target == "" {
+		log.Printf("Skipping %s: no dlq_source_queue attribute (use -to)", message.MessageID)
+		r.skipped = append(r.skipped, message)
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		r.skipped = append(r.skipped, message)
+		return ctx.Err()
+	case <-r.limiter.C:
+	}
+
+	if err := r.queue.Redrive(ctx, message, target); err != nil {
+		r.skipped = append(r.skipped, message)
+		return fmt.Errorf("redriving %s: %w", message.MessageID, err)
+	}
+	r.moved++
+	fmt.Printf("✓ %s → %s\n", message.MessageID, target)
+	return nil
+}
+
+func (r *redriver) releaseSkipped() {
+	if err := r.queue.Release(context.Background(), r.skipped...); err != nil {
+		log.Printf("Error releasing skipped messages: %v", err)
+	}
+}
+
+func (r *redriver) summary() {
+	fmt.Printf("%d mensaje(s) reprocesado(s), %d omitido(s)\n", r.moved, len(r.skipped))
+}
+
+func printMessage(message *sqsqueue.DeadLetterMessage, showBody bool) {
+	fmt.Println("----------------------------------------")
+	fmt.Printf("Mensaje:   %s\n", message.MessageID)
+	fmt.Printf("Origen:    %s\n", valueOr(message.SourceQueue, "(desconocido)"))
+	fmt.Printf("Intentos:  %d\n", message.Attempts)
+	fmt.Printf("Falló el:  %s\n", valueOr(message.FailedAt, "(desconocido)"))
+	fmt.Printf("Motivo:    %s\n", valueOr(message.FailureReason, "(sin motivo: movido por la redrive policy)"))
+
+	event, err := events.Decode([]byte(message.Body))
+	if err != nil {
+		fmt.Printf("Evento:    no se pudo decodificar (%v)\n", err)
+		fmt.Printf("Cuerpo:    %s\n", message.Body)
+		return
+	}
+
+	fmt.Printf("Evento:    %s %s (subject %s, %s)\n", event.Type, event.ID, event.Subject, event.Time.Format(time.RFC3339))
+	if showBody {
+		if indented, err := json.MarshalIndent(event.Data, "           ", "  "); err == nil {
+			fmt.Printf("Data:      %s\n", indented)
+		}
+	}
+}
+
+// validateBody valida un CloudEvent contra su esquema; un evento en el formato
+// anterior solo se comprueba que se pueda decodificar
+func validateBody(body []byte) error {
+	if !events.IsCloudEvent(body) {
+		_, err := events.Decode(body)
+		return err
+	}
+	registry, err := eventschema.Default()
+	if err != nil {
+		return err
+	}
+	return registry.ValidateEvent(body)
+}
+
+func readBody(file string) ([]byte, error) {
+	if file == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(file)
+}
+
+func splitIDs(value string) []string {
+	var ids []string
+	for _, id := range strings.Split(value, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func reportMissing(messageIDs []string, found []*sqsqueue.DeadLetterMessage) {
+	present := make(map[string]bool, len(found))
+	for _, message := range found {
+		present[message.MessageID] = true
+	}
+	for _, id := range messageIDs {
+		if !present[id] {
+			log.Printf("Message %s not found (already processed or reserved by another reader)", id)
+		}
+	}
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
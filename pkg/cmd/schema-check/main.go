@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"pkg/eventschema"
+)
+
+// schema-check verifica que cada versión de un esquema de evento sea compatible
+// con la anterior. Uso:
+//
+//	go run ./cmd/schema-check                          # esquemas incluidos en el módulo
+//	go run ./cmd/schema-check -dir eventschema/schemas # esquemas de un directorio
+//	go run ./cmd/schema-check -old v1.json -new v2.json
+func main() {
+	dir := flag.String("dir", "", "Directorio de esquemas (<tipo>/<versión>.json); por defecto los incluidos en el módulo")
+	oldFile := flag.String("old", "", "Esquema de la versión anterior")
+	newFile := flag.String("new", "", "Esquema de la versión nueva")
+	flag.Parse()
+
+	if *oldFile != "" || *newFile != "" {
+		if *oldFile == "" || *newFile == "" {
+			log.Fatal("-old and -new must be used together")
+		}
+		issues, err := compareFiles(*oldFile, *newFile)
+		if err != nil {
+			log.Fatalf("Error reading schemas: %v", err)
+		}
+		report(fmt.Sprintf("%s → %s", *oldFile, *newFile), issues)
+		if len(issues) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	registry, err := loadRegistry(*dir)
+	if err != nil {
+		log.Fatalf("Error loading schemas: %v", err)
+	}
+
+	incompatible := false
+	for _, eventType := range registry.Types() {
+		versions := registry.Versions(eventType)
+		for i := 1; i < len(versions); i++ {
+			previous, _ := registry.Schema(eventType, versions[i-1])
+			next, _ := registry.Schema(eventType, versions[i])
+
+			issues := eventschema.CheckCompatibility(previous, next)
+			report(fmt.Sprintf("%s %s → %s", eventType, versions[i-1], versions[i]), issues)
+			if len(issues) > 0 {
+				incompatible = true
+			}
+		}
+		if len(versions) == 1 {
+			fmt.Printf("✓ %s %s (única versión)\n", eventType, versions[0])
+		}
+	}
+
+	if incompatible {
+		os.Exit(1)
+	}
+}
+
+func loadRegistry(dir string) (*eventschema.Registry, error) {
+	if dir == "" {
+		return eventschema.Default()
+	}
+	return eventschema.Load(os.DirFS(dir))
+}
+
+func compareFiles(oldFile, newFile string) ([]string, error) {
+	previous, err := readSchema(oldFile)
+	if err != nil {
+		return nil, err
+	}
+	next, err := readSchema(newFile)
+	if err != nil {
+		return nil, err
+	}
+	return eventschema.CheckCompatibility(previous, next), nil
+}
+
+func readSchema(file string) (*eventschema.Schema, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return eventschema.ParseSchema(data)
+}
+
+func report(title string, issues []string) {
+	if len(issues) == 0 {
+		fmt.Printf("✓ %s compatible\n", title)
+		return
+	}
+
+	fmt.Printf("✗ %s incompatible:\n", title)
+	for _, issue := range issues {
+		fmt.Printf("  - %s\n", issue)
+	}
+}
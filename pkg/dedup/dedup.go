@@ -0,0 +1,71 @@
+// Package dedup evita procesar dos veces el mismo evento cuando SQS lo entrega
+// más de una vez (entrega at-least-once).
+package dedup
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+var (
+	// ErrAlreadyProcessed indica que el evento ya se procesó correctamente
+	ErrAlreadyProcessed = errors.New("event already processed")
+	// ErrInProgress indica que otro worker está procesando el mismo evento; el
+	// mensaje debe reintentarse más tarde
+	ErrInProgress = errors.New("event is being processed by another worker")
+)
+
+// Valores por defecto de los plazos
+const (
+	// DefaultTTL cubre la retención máxima de SQS (14 días), incluida la DLQ
+	DefaultTTL = 14 * 24 * time.Hour
+	// DefaultProcessingLease es el tiempo tras el cual una reserva sin completar
+	// (p.ej. el worker se cayó) se puede volver a tomar
+	DefaultProcessingLease = 5 * time.Minute
+)
+
+// Store registra los eventos procesados por un consumidor
+type Store interface {
+	// Claim reserva key para procesarla con una escritura condicional. Devuelve
+	// ErrAlreadyProcessed si ya se completó o ErrInProgress si otra reserva vigente la tiene.
+	Claim(ctx context.Context, key string) error
+
+	// Complete marca key como procesada; se conserva hasta que venza el TTL
+	Complete(ctx context.Context, key string) error
+
+	// Release elimina la reserva para permitir reintentar (el handler falló)
+	Release(ctx context.Context, key string) error
+}
+
+// Once ejecuta fn solo si key no se procesó antes. Un duplicado de un evento
+// ya completado se ignora (devuelve nil para que el mensaje se elimine); si
+// otro worker lo está procesando devuelve ErrInProgress. Con key vacía (eventos
+// sin ID) fn se ejecuta siempre.
+func Once(ctx context.Context, store Store, key string, fn func(ctx context.Context) error) error {
+	if key == "" {
+		return fn(ctx)
+	}
+
+	if err := store.Claim(ctx, key); err != nil {
+		if errors.Is(err, ErrAlreadyProcessed) {
+			log.Printf("Skipping duplicate event %s", key)
+			return nil
+		}
+		return err
+	}
+
+	if err := fn(ctx); err != nil {
+		if releaseErr := store.Release(ctx, key); releaseErr != nil {
+			log.Printf("Error releasing dedup key %s: %v", key, releaseErr)
+		}
+		return err
+	}
+
+	if err := store.Complete(ctx, key); err != nil {
+		// El evento ya se procesó: la reserva vence sola tras el lease
+		log.Printf("Error completing dedup key %s: %v", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package dedup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOnceWithMemoryStore(t *testing.T) {
+	errHandler := errors.New("handler failed")
+
+	tests := []struct {
+		name string
+		// setup prepara el almacén; advance mueve el reloj antes de llamar a Once
+		setup   func(t *testing.T, store *MemoryStore)
+		advance time.Duration
+		key     string
+		fnErr   error
+		wantErr error
+		wantRun bool
+	}{
+		{
+			name:    "primera entrega",
+			key:     "evt-1",
+			wantRun: true,
+		},
+		{
+			name: "duplicado de un evento completado",
+			setup: func(t *testing.T, store *MemoryStore) {
+				mustOnce(t, store, "evt-1")
+			},
+			key:     "evt-1",
+			wantRun: false,
+		},
+		{
+			name: "otra clave no es un duplicado",
+			setup: func(t *testing.T, store *MemoryStore) {
+				mustOnce(t, store, "evt-1")
+			},
+			key:     "evt-2",
+			wantRun: true,
+		},
+		{
+			name: "reserva vigente de otro worker",
+			setup: func(t *testing.T, store *MemoryStore) {
+				mustClaim(t, store, "evt-1")
+			},
+			advance: DefaultProcessingLease - time.Second,
+			key:     "evt-1",
+			wantErr: ErrInProgress,
+			wantRun: false,
+		},
+		{
+			name: "reserva vencida se vuelve a tomar",
+			setup: func(t *testing.T, store *MemoryStore) {
+				mustClaim(t, store, "evt-1")
+			},
+			advance: DefaultProcessingLease + time.Second,
+			key:     "evt-1",
+			wantRun: true,
+		},
+		{
+			name: "un fallo libera la reserva",
+			setup: func(t *testing.T, store *MemoryStore) {
+				err := Once(context.Background(), store, "evt-1", func(context.Context) error { return errHandler })
+				if !errors.Is(err, errHandler) {
+					t.Fatalf("Once() = %v, want %v", err, errHandler)
+				}
+			},
+			key:     "evt-1",
+			wantRun: true,
+		},
+		{
+			name:    "el error del handler se propaga",
+			key:     "evt-1",
+			fnErr:   errHandler,
+			wantErr: errHandler,
+			wantRun: true,
+		},
+		{
+			name: "clave completada tras vencer el TTL",
+			setup: func(t *testing.T, store *MemoryStore) {
+				mustOnce(t, store, "evt-1")
+			},
+			advance: time.Hour + time.Second,
+			key:     "evt-1",
+			wantRun: true,
+		},
+		{
+			name: "sin clave siempre se ejecuta",
+			setup: func(t *testing.T, store *MemoryStore) {
+				mustOnce(t, store, "")
+			},
+			key:     "",
+			wantRun: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+			store := NewMemoryStore(time.Hour)
+			store.now = func() time.Time { return now }
+
+			if tt.setup != nil {
+				tt.setup(t, store)
+			}
+			now = now.Add(tt.advance)
+
+			ran := false
+			err := Once(context.Background(), store, tt.key, func(context.Context) error {
+				ran = true
+				return tt.fnErr
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Once() = %v, want %v", err, tt.wantErr)
+			}
+			if ran != tt.wantRun {
+				t.Errorf("handler ran = %t, want %t", ran, tt.wantRun)
+			}
+		})
+	}
+}
+
+func TestOnceCompletesAfterFailedAttempt(t *testing.T) {
+	store := NewMemoryStore(time.Hour)
+	ctx := context.Background()
+
+	calls := 0
+	handler := func(context.Context) error {
+		calls++
+		if calls == 1 {
+			return errors.New("transient failure")
+		}
+		return nil
+	}
+
+	if err := Once(ctx, store, "evt-1", handler); err == nil {
+		t.Fatal("first attempt succeeded, want the handler error")
+	}
+	if err := Once(ctx, store, "evt-1", handler); err != nil {
+		t.Fatalf("retry = %v", err)
+	}
+	if err := Once(ctx, store, "evt-1", handler); err != nil {
+		t.Fatalf("duplicate = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("handler called %d times, want 2", calls)
+	}
+}
+
+func mustOnce(t *testing.T, store *MemoryStore, key string) {
+	t.Helper()
+	if err := Once(context.Background(), store, key, func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("Once(%q) = %v", key, err)
+	}
+}
+
+func mustClaim(t *testing.T, store *MemoryStore, key string) {
+	t.Helper()
+	if err := store.Claim(context.Background(), key); err != nil {
+		t.Fatalf("Claim(%q) = %v", key, err)
+	}
+}
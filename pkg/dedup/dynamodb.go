@@ -0,0 +1,138 @@
+package dedup
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Estados de un registro
+const (
+	statusProcessing = "processing"
+	statusCompleted  = "completed"
+)
+
+// DynamoDBStore implementa Store con DynamoDB. La tabla usa "Key" como clave de
+// partición y "ExpiresAt" como atributo TTL; las claves se prefijan con el nombre
+// del consumidor para que varios servicios compartan la tabla.
+type DynamoDBStore struct {
+	client    *dynamodb.Client
+	tableName string
+	consumer  string
+	ttl       time.Duration
+	lease     time.Duration
+}
+
+// NewDynamoDBStore crea una nueva instancia del almacén para consumer
+func NewDynamoDBStore(client *dynamodb.Client, tableName, consumer string, ttl time.Duration) *DynamoDBStore {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &DynamoDBStore{
+		client:    client,
+		tableName: tableName,
+		consumer:  consumer,
+		ttl:       ttl,
+		lease:     DefaultProcessingLease,
+	}
+}
+
+// Claim reserva la clave si no existe, si venció su TTL o si su reserva en
+// curso superó el lease
+func (s *DynamoDBStore) Claim(ctx context.Context, key string) error {
+	now := time.Now()
+	_, err := s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item: map[string]types.AttributeValue{
+			"Key":        &types.AttributeValueMemberS{Value: s.key(key)},
+			"Status":     &types.AttributeValueMemberS{Value: statusProcessing},
+			"LeaseUntil": &types.AttributeValueMemberN{Value: unix(now.Add(s.lease))},
+			"ExpiresAt":  &types.AttributeValueMemberN{Value: unix(now.Add(s.ttl))},
+		},
+		// El TTL de DynamoDB borra los items con retraso: se comprueba también aquí
+		ConditionExpression: aws.String("attribute_not_exists(#key) OR #expires < :now OR (#status = :processing AND LeaseUntil < :now)"),
+		ExpressionAttributeNames: map[string]string{
+			"#key":     "Key",
+			"#expires": "ExpiresAt",
+			"#status":  "Status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now":        &types.AttributeValueMemberN{Value: unix(now)},
+			":processing": &types.AttributeValueMemberS{Value: statusProcessing},
+		},
+		ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+	})
+	if err == nil {
+		return nil
+	}
+
+	var conditionFailed *types.ConditionalCheckFailedException
+	if !errors.As(err, &conditionFailed) {
+		log.Printf("Error claiming dedup key: %v", err)
+		return err
+	}
+
+	item := conditionFailed.Item
+	if item == nil {
+		// Algunos emuladores no devuelven el item en el error: leerlo
+		result, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+			TableName:      aws.String(s.tableName),
+			ConsistentRead: aws.Bool(true),
+			Key: map[string]types.AttributeValue{
+				"Key": &types.AttributeValueMemberS{Value: s.key(key)},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		item = result.Item
+	}
+
+	if status, ok := item["Status"].(*types.AttributeValueMemberS); ok && status.Value == statusCompleted {
+		return ErrAlreadyProcessed
+	}
+	return ErrInProgress
+}
+
+// Complete marca la clave como procesada
+func (s *DynamoDBStore) Complete(ctx context.Context, key string) error {
+	_, err := s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"Key": &types.AttributeValueMemberS{Value: s.key(key)},
+		},
+		UpdateExpression: aws.String("SET #status = :completed REMOVE LeaseUntil"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "Status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":completed": &types.AttributeValueMemberS{Value: statusCompleted},
+		},
+	})
+	return err
+}
+
+// Release elimina la reserva
+func (s *DynamoDBStore) Release(ctx context.Context, key string) error {
+	_, err := s.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"Key": &types.AttributeValueMemberS{Value: s.key(key)},
+		},
+	})
+	return err
+}
+
+func (s *DynamoDBStore) key(key string) string {
+	return s.consumer + "#" + key
+}
+
+func unix(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
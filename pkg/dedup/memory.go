@@ -0,0 +1,82 @@
+package dedup
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryRecord es el estado de una clave en MemoryStore
+type memoryRecord struct {
+	completed  bool
+	leaseUntil time.Time
+	expiresAt  time.Time
+}
+
+// MemoryStore implementa Store en memoria (tests y desarrollo local sin DynamoDB).
+// Sigue las mismas reglas de TTL y lease que DynamoDBStore.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]memoryRecord
+	ttl     time.Duration
+	lease   time.Duration
+	now     func() time.Time
+}
+
+// NewMemoryStore crea un almacén en memoria vacío
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &MemoryStore{
+		records: make(map[string]memoryRecord),
+		ttl:     ttl,
+		lease:   DefaultProcessingLease,
+		now:     time.Now,
+	}
+}
+
+// Claim reserva la clave si no existe, si venció su TTL o si su reserva superó el lease
+func (s *MemoryStore) Claim(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if record, ok := s.records[key]; ok && now.Before(record.expiresAt) {
+		if record.completed {
+			return ErrAlreadyProcessed
+		}
+		if now.Before(record.leaseUntil) {
+			return ErrInProgress
+		}
+	}
+
+	s.records[key] = memoryRecord{
+		leaseUntil: now.Add(s.lease),
+		expiresAt:  now.Add(s.ttl),
+	}
+	return nil
+}
+
+// Complete marca la clave como procesada
+func (s *MemoryStore) Complete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record := s.records[key]
+	record.completed = true
+	if record.expiresAt.IsZero() {
+		record.expiresAt = s.now().Add(s.ttl)
+	}
+	s.records[key] = record
+	return nil
+}
+
+// Release elimina la reserva
+func (s *MemoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
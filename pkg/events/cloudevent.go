@@ -0,0 +1,118 @@
+// Package events contiene los contratos de eventos compartidos por todos los
+// servicios: el envelope CloudEvents 1.0 y los payloads de cada tipo de evento.
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Valores del envelope CloudEvents 1.0
+const (
+	SpecVersion      = "1.0"
+	ContentType      = "application/json"
+	SchemaVersion    = "v1"
+	DataSchemaPrefix = "urn:go-aws-template:events:"
+)
+
+// Orígenes (source) de los eventos
+const (
+	SourceEmployeeService  = "/employee-service"
+	SourceMessagingService = "/messaging-service"
+	SourceAuthService      = "/auth-service"
+	SourceLoggerService    = "/logger-service"
+)
+
+// Tipos de evento
+const (
+	TypeEmployeeCreated       = "employee.created"
+	TypeEmployeeUpdated       = "employee.updated"
+	TypeEmployeeDeleted       = "employee.deleted"
+	TypeEmployeeStatusChanged = "employee.status_changed"
+	TypeMessageSent           = "message.sent"
+	TypeMessageFailed         = "message.failed"
+	TypeAuthLogin             = "auth.login"
+	TypeAuthLoginFailed       = "auth.login_failed"
+	TypeAlertFired            = "alert.fired"
+)
+
+var (
+	// ErrInvalidEvent indica que el mensaje no tiene el formato de un evento
+	ErrInvalidEvent = errors.New("invalid event")
+
+	// ErrInvalidDataSchema indica que el atributo dataschema no tiene el formato esperado
+	ErrInvalidDataSchema = errors.New("invalid dataschema")
+)
+
+// CloudEvent es el envelope versionado (CloudEvents 1.0, modo structured JSON)
+// con el que se publican todos los eventos. El formato de Data lo identifica
+// DataSchema, que incluye el tipo de evento y la versión del esquema.
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	DataSchema      string          `json:"dataschema"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// New crea un evento con la versión actual del esquema de su tipo
+func New(id, source, eventType, subject string, at time.Time, data interface{}) (*CloudEvent, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CloudEvent{
+		SpecVersion:     SpecVersion,
+		ID:              id,
+		Source:          source,
+		Type:            eventType,
+		Subject:         subject,
+		Time:            at.UTC(),
+		DataContentType: ContentType,
+		DataSchema:      DataSchemaURI(eventType, SchemaVersion),
+		Data:            payload,
+	}, nil
+}
+
+// DecodeData decodifica el payload del evento
+func (e *CloudEvent) DecodeData(v interface{}) error {
+	if len(e.Data) == 0 {
+		return ErrInvalidEvent
+	}
+	return json.Unmarshal(e.Data, v)
+}
+
+// DataSchemaURI devuelve el identificador dataschema de un tipo de evento y versión
+func DataSchemaURI(eventType, version string) string {
+	return DataSchemaPrefix + eventType + ":" + version
+}
+
+// ParseDataSchema obtiene el tipo de evento y la versión de un identificador dataschema
+func ParseDataSchema(uri string) (string, string, error) {
+	if !strings.HasPrefix(uri, DataSchemaPrefix) {
+		return "", "", fmt.Errorf("%w: %s", ErrInvalidDataSchema, uri)
+	}
+
+	reference := strings.TrimPrefix(uri, DataSchemaPrefix)
+	separator := strings.LastIndex(reference, ":")
+	if separator <= 0 || separator == len(reference)-1 {
+		return "", "", fmt.Errorf("%w: %s", ErrInvalidDataSchema, uri)
+	}
+	return reference[:separator], reference[separator+1:], nil
+}
+
+// IsCloudEvent indica si el mensaje usa el envelope CloudEvents (tiene specversion)
+func IsCloudEvent(body []byte) bool {
+	var envelope struct {
+		SpecVersion string `json:"specversion"`
+	}
+	return json.Unmarshal(body, &envelope) == nil && envelope.SpecVersion != ""
+}
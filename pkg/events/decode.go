@@ -0,0 +1,60 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// legacyEvent es el formato anterior al envelope CloudEvents
+// ({event_id, event_type, employee, transition, timestamp})
+type legacyEvent struct {
+	EventID    string                `json:"event_id"`
+	EventType  string                `json:"event_type"`
+	Employee   *EmployeeData         `json:"employee"`
+	Transition *StatusTransitionData `json:"transition,omitempty"`
+	Timestamp  string                `json:"timestamp"`
+}
+
+// Decode decodifica un mensaje en formato CloudEvents. Durante la migración los
+// mensajes en el formato anterior se convierten a un CloudEvent equivalente
+// (sin source ni dataschema) con un EmployeePayload como data.
+func Decode(body []byte) (*CloudEvent, error) {
+	if IsCloudEvent(body) {
+		var event CloudEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			return nil, err
+		}
+		return &event, nil
+	}
+
+	var legacy legacyEvent
+	if err := json.Unmarshal(body, &legacy); err != nil {
+		return nil, err
+	}
+	if legacy.EventType == "" || legacy.Employee == nil {
+		return nil, ErrInvalidEvent
+	}
+
+	data, err := json.Marshal(EmployeePayload{
+		Employee:   legacy.Employee,
+		Transition: legacy.Transition,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	eventTime, err := time.Parse(time.RFC3339, legacy.Timestamp)
+	if err != nil {
+		eventTime = time.Now().UTC()
+	}
+
+	return &CloudEvent{
+		SpecVersion:     SpecVersion,
+		ID:              legacy.EventID,
+		Type:            legacy.EventType,
+		Subject:         legacy.Employee.ID,
+		Time:            eventTime.UTC(),
+		DataContentType: ContentType,
+		Data:            data,
+	}, nil
+}
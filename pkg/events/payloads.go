@@ -0,0 +1,87 @@
+package events
+
+import "time"
+
+// EmployeeData representa los datos del empleado en un evento (sin información sensible)
+type EmployeeData struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	CreatedAt string `json:"created_at"`
+}
+
+// StatusTransitionData representa los datos de una transición de estado laboral
+type StatusTransitionData struct {
+	From          string `json:"from"`
+	To            string `json:"to"`
+	ReasonCode    string `json:"reason_code"`
+	EffectiveDate string `json:"effective_date"`
+}
+
+// EmployeePayload es el payload (data) de los eventos employee.*
+type EmployeePayload struct {
+	Employee   *EmployeeData         `json:"employee"`
+	Transition *StatusTransitionData `json:"transition,omitempty"`
+}
+
+// MessageSentPayload es el payload (data) del evento message.sent
+type MessageSentPayload struct {
+	MessageID  string    `json:"message_id"`
+	EmployeeID string    `json:"employee_id"`
+	Channel    string    `json:"channel"`
+	To         string    `json:"to"`
+	Subject    string    `json:"subject,omitempty"`
+	SentAt     time.Time `json:"sent_at"`
+}
+
+// MessageFailedPayload es el payload (data) del evento message.failed (un
+// intento de envío fallido; cada reintento publica el suyo)
+type MessageFailedPayload struct {
+	MessageID  string    `json:"message_id"`
+	EmployeeID string    `json:"employee_id"`
+	Channel    string    `json:"channel"`
+	To         string    `json:"to"`
+	Subject    string    `json:"subject,omitempty"`
+	Error      string    `json:"error"`
+	FailedAt   time.Time `json:"failed_at"`
+}
+
+// AuthLoginPayload es el payload (data) del evento auth.login (inicio de sesión correcto)
+type AuthLoginPayload struct {
+	UserID     string    `json:"user_id"`
+	Email      string    `json:"email"`
+	LoggedInAt time.Time `json:"logged_in_at"`
+}
+
+// Motivos de un inicio de sesión fallido
+const (
+	LoginFailureUnknownUser       = "unknown_user"
+	LoginFailureInvalidPassword   = "invalid_password"
+	LoginFailureAccountTerminated = "account_terminated"
+)
+
+// AuthLoginFailedPayload es el payload (data) del evento auth.login_failed.
+// UserID está vacío cuando el email no corresponde a ningún usuario.
+type AuthLoginFailedPayload struct {
+	UserID   string    `json:"user_id,omitempty"`
+	Email    string    `json:"email"`
+	Reason   string    `json:"reason"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// AlertFiredPayload es el payload (data) del evento alert.fired: una regla de
+// alerta superó su umbral. Recipients son los destinatarios del aviso.
+type AlertFiredPayload struct {
+	AlertID       string            `json:"alert_id"`
+	Rule          string            `json:"rule"`
+	Description   string            `json:"description,omitempty"`
+	Severity      string            `json:"severity"`
+	Count         int               `json:"count"`
+	Threshold     int               `json:"threshold"`
+	WindowSeconds int               `json:"window_seconds"`
+	Group         map[string]string `json:"group,omitempty"`
+	FirstEventAt  time.Time         `json:"first_event_at"`
+	LastEventAt   time.Time         `json:"last_event_at"`
+	FiredAt       time.Time         `json:"fired_at"`
+	Recipients    []string          `json:"recipients"`
+}
@@ -0,0 +1,72 @@
+package eventschema
+
+import "fmt"
+
+// CheckCompatibility compara una nueva versión de un esquema con la anterior y
+// devuelve los cambios incompatibles. Una versión es compatible cuando los
+// consumidores actuales pueden seguir leyendo los eventos nuevos y los
+// consumidores actualizados pueden leer los eventos ya publicados:
+//   - no se eliminan ni renombran campos requeridos
+//   - no se agregan campos requeridos (los eventos anteriores no los tienen)
+//   - no cambia el tipo de un campo existente
+//   - no se eliminan valores de un enum ni se cambia un const
+//   - no se restringe additionalProperties
+func CheckCompatibility(previous, next *Schema) []string {
+	var issues []string
+	checkCompatibility("$", previous, next, &issues)
+	return issues
+}
+
+func checkCompatibility(path string, previous, next *Schema, issues *[]string) {
+	if previous.Type != next.Type {
+		*issues = append(*issues, fmt.Sprintf("%s: type changed from %q to %q", path, previous.Type, next.Type))
+		return
+	}
+
+	for _, name := range previous.Required {
+		if !contains(next.Required, name) {
+			*issues = append(*issues, fmt.Sprintf("%s.%s: required field removed", path, name))
+		}
+	}
+	for _, name := range next.Required {
+		if !contains(previous.Required, name) {
+			*issues = append(*issues, fmt.Sprintf("%s.%s: new required field", path, name))
+		}
+	}
+
+	for name, property := range previous.Properties {
+		nextProperty, ok := next.Properties[name]
+		if !ok {
+			if next.AdditionalProperties != nil && !*next.AdditionalProperties {
+				*issues = append(*issues, fmt.Sprintf("%s.%s: field removed while additional properties are not allowed", path, name))
+			}
+			continue
+		}
+		checkCompatibility(path+"."+name, property, nextProperty, issues)
+	}
+
+	previousOpen := previous.AdditionalProperties == nil || *previous.AdditionalProperties
+	nextOpen := next.AdditionalProperties == nil || *next.AdditionalProperties
+	if previousOpen && !nextOpen {
+		*issues = append(*issues, fmt.Sprintf("%s: additional properties are no longer allowed", path))
+	}
+
+	if len(next.Enum) > 0 {
+		for _, value := range previous.Enum {
+			if !contains(next.Enum, value) {
+				*issues = append(*issues, fmt.Sprintf("%s: enum value %q removed", path, value))
+			}
+		}
+		if len(previous.Enum) == 0 {
+			*issues = append(*issues, fmt.Sprintf("%s: values restricted to an enum", path))
+		}
+	}
+
+	if previous.Const != next.Const {
+		*issues = append(*issues, fmt.Sprintf("%s: const changed from %q to %q", path, previous.Const, next.Const))
+	}
+
+	if previous.Items != nil && next.Items != nil {
+		checkCompatibility(path+"[]", previous.Items, next.Items, issues)
+	}
+}
@@ -0,0 +1,191 @@
+package eventschema
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	base := `{
+		"type": "object",
+		"required": ["id", "status"],
+		"properties": {
+			"id": {"type": "string"},
+			"status": {"type": "string", "enum": ["active", "on_leave"]},
+			"kind": {"type": "string", "const": "employee"},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"note": {"type": "string"}
+		}
+	}`
+
+	tests := []struct {
+		name       string
+		previous   string
+		next       string
+		wantIssues []string
+	}{
+		{
+			name:     "mismo esquema",
+			previous: base,
+			next:     base,
+		},
+		{
+			name:     "nuevo campo opcional",
+			previous: `{"type": "object", "properties": {"id": {"type": "string"}}}`,
+			next:     `{"type": "object", "properties": {"id": {"type": "string"}, "email": {"type": "string"}}}`,
+		},
+		{
+			name:     "nuevo valor de enum",
+			previous: `{"type": "string", "enum": ["a", "b"]}`,
+			next:     `{"type": "string", "enum": ["a", "b", "c"]}`,
+		},
+		{
+			name:     "se elimina el enum",
+			previous: `{"type": "string", "enum": ["a", "b"]}`,
+			next:     `{"type": "string"}`,
+		},
+		{
+			name:     "se elimina un campo opcional de un objeto abierto",
+			previous: `{"type": "object", "properties": {"note": {"type": "string"}}}`,
+			next:     `{"type": "object"}`,
+		},
+		{
+			name:     "se abre additionalProperties",
+			previous: `{"type": "object", "additionalProperties": false}`,
+			next:     `{"type": "object", "additionalProperties": true}`,
+		},
+
+		{
+			name:       "cambia el tipo raíz",
+			previous:   `{"type": "object"}`,
+			next:       `{"type": "array"}`,
+			wantIssues: []string{`$: type changed from "object" to "array"`},
+		},
+		{
+			name:       "cambia el tipo de un campo",
+			previous:   `{"type": "object", "properties": {"age": {"type": "integer"}}}`,
+			next:       `{"type": "object", "properties": {"age": {"type": "string"}}}`,
+			wantIssues: []string{`$.age: type changed from "integer" to "string"`},
+		},
+		{
+			name:       "se elimina un campo requerido",
+			previous:   `{"type": "object", "required": ["id", "name"]}`,
+			next:       `{"type": "object", "required": ["id"]}`,
+			wantIssues: []string{"$.name: required field removed"},
+		},
+		{
+			name:       "nuevo campo requerido",
+			previous:   `{"type": "object", "required": ["id"]}`,
+			next:       `{"type": "object", "required": ["id", "email"]}`,
+			wantIssues: []string{"$.email: new required field"},
+		},
+		{
+			name:     "campo renombrado",
+			previous: `{"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}`,
+			next:     `{"type": "object", "required": ["full_name"], "properties": {"full_name": {"type": "string"}}}`,
+			wantIssues: []string{
+				"$.full_name: new required field",
+				"$.name: required field removed",
+			},
+		},
+		{
+			name:       "se elimina un campo de un objeto cerrado",
+			previous:   `{"type": "object", "additionalProperties": false, "properties": {"note": {"type": "string"}}}`,
+			next:       `{"type": "object", "additionalProperties": false}`,
+			wantIssues: []string{"$.note: field removed while additional properties are not allowed"},
+		},
+		{
+			name:       "se cierra additionalProperties",
+			previous:   `{"type": "object"}`,
+			next:       `{"type": "object", "additionalProperties": false}`,
+			wantIssues: []string{"$: additional properties are no longer allowed"},
+		},
+		{
+			name:       "se elimina un valor de enum",
+			previous:   `{"type": "string", "enum": ["a", "b"]}`,
+			next:       `{"type": "string", "enum": ["a"]}`,
+			wantIssues: []string{`$: enum value "b" removed`},
+		},
+		{
+			name:       "se restringe a un enum",
+			previous:   `{"type": "string"}`,
+			next:       `{"type": "string", "enum": ["a"]}`,
+			wantIssues: []string{"$: values restricted to an enum"},
+		},
+		{
+			name:       "cambia el const",
+			previous:   `{"type": "string", "const": "1.0"}`,
+			next:       `{"type": "string", "const": "2.0"}`,
+			wantIssues: []string{`$: const changed from "1.0" to "2.0"`},
+		},
+		{
+			name:       "cambia el tipo de los items",
+			previous:   `{"type": "array", "items": {"type": "string"}}`,
+			next:       `{"type": "array", "items": {"type": "integer"}}`,
+			wantIssues: []string{`$[]: type changed from "string" to "integer"`},
+		},
+		{
+			name:       "cambio anidado",
+			previous:   `{"type": "object", "properties": {"employee": {"type": "object", "properties": {"status": {"type": "string", "enum": ["active", "terminated"]}}}}}`,
+			next:       `{"type": "object", "properties": {"employee": {"type": "object", "properties": {"status": {"type": "string", "enum": ["active"]}}}}}`,
+			wantIssues: []string{`$.employee.status: enum value "terminated" removed`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			previous := mustParseSchema(t, tt.previous)
+			next := mustParseSchema(t, tt.next)
+
+			issues := CheckCompatibility(previous, next)
+			sort.Strings(issues)
+			if !equalIssues(issues, tt.wantIssues) {
+				t.Errorf("CheckCompatibility() = %q, want %q", issues, tt.wantIssues)
+			}
+		})
+	}
+}
+
+func TestRegisteredVersionsAreCompatible(t *testing.T) {
+	registry, err := Default()
+	if err != nil {
+		t.Fatalf("Default() = %v", err)
+	}
+
+	for _, eventType := range registry.Types() {
+		versions := registry.Versions(eventType)
+		for i := 1; i < len(versions); i++ {
+			previous, err := registry.Schema(eventType, versions[i-1])
+			if err != nil {
+				t.Fatalf("Schema(%s, %s) = %v", eventType, versions[i-1], err)
+			}
+			next, err := registry.Schema(eventType, versions[i])
+			if err != nil {
+				t.Fatalf("Schema(%s, %s) = %v", eventType, versions[i], err)
+			}
+			if issues := CheckCompatibility(previous, next); len(issues) > 0 {
+				t.Errorf("%s %s → %s is incompatible: %q", eventType, versions[i-1], versions[i], issues)
+			}
+		}
+	}
+}
+
+func mustParseSchema(t *testing.T, data string) *Schema {
+	t.Helper()
+	schema, err := ParseSchema([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseSchema(%s) = %v", data, err)
+	}
+	return schema
+}
+
+func equalIssues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,166 @@
+package eventschema
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"pkg/events"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// envelopeSchemaFile es el esquema del envelope CloudEvents
+const envelopeSchemaFile = "cloudevent.json"
+
+//go:embed schemas
+var embeddedSchemas embed.FS
+
+// ErrUnknownSchema indica que no hay esquema para el tipo y versión del evento
+var ErrUnknownSchema = errors.New("unknown event schema")
+
+// Registry contiene los esquemas de cada tipo de evento por versión
+type Registry struct {
+	envelope *Schema
+	schemas  map[string]map[string]*Schema // tipo → versión → esquema
+}
+
+var (
+	defaultRegistry    *Registry
+	defaultRegistryErr error
+	defaultOnce        sync.Once
+)
+
+// Default devuelve el registro con los esquemas incluidos en el módulo
+func Default() (*Registry, error) {
+	defaultOnce.Do(func() {
+		schemas, err := fs.Sub(embeddedSchemas, "schemas")
+		if err != nil {
+			defaultRegistryErr = err
+			return
+		}
+		defaultRegistry, defaultRegistryErr = Load(schemas)
+	})
+	return defaultRegistry, defaultRegistryErr
+}
+
+// Load carga los esquemas de un directorio con la estructura
+// cloudevent.json y <tipo>/<versión>.json (por ejemplo employee.created/v1.json)
+func Load(fsys fs.FS) (*Registry, error) {
+	registry := &Registry{schemas: make(map[string]map[string]*Schema)}
+
+	err := fs.WalkDir(fsys, ".", func(file string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || path.Ext(file) != ".json" {
+			return err
+		}
+
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		schema, err := ParseSchema(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+
+		if file == envelopeSchemaFile {
+			registry.envelope = schema
+			return nil
+		}
+
+		eventType, version := path.Dir(file), strings.TrimSuffix(path.Base(file), ".json")
+		if registry.schemas[eventType] == nil {
+			registry.schemas[eventType] = make(map[string]*Schema)
+		}
+		registry.schemas[eventType][version] = schema
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if registry.envelope == nil {
+		return nil, fmt.Errorf("%s not found", envelopeSchemaFile)
+	}
+	return registry, nil
+}
+
+// Schema devuelve el esquema de un tipo de evento y versión
+func (r *Registry) Schema(eventType, version string) (*Schema, error) {
+	schema, ok := r.schemas[eventType][version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s %s", ErrUnknownSchema, eventType, version)
+	}
+	return schema, nil
+}
+
+// Types devuelve los tipos de evento registrados ordenados alfabéticamente
+func (r *Registry) Types() []string {
+	types := make([]string, 0, len(r.schemas))
+	for eventType := range r.schemas {
+		types = append(types, eventType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Versions devuelve las versiones de un tipo de evento en orden (v1, v2, ..., v10)
+func (r *Registry) Versions(eventType string) []string {
+	versions := make([]string, 0, len(r.schemas[eventType]))
+	for version := range r.schemas[eventType] {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		if len(versions[i]) != len(versions[j]) {
+			return len(versions[i]) < len(versions[j])
+		}
+		return versions[i] < versions[j]
+	})
+	return versions
+}
+
+// ValidateEvent valida un evento serializado (envelope CloudEvents) contra el
+// esquema del envelope y el esquema de su payload indicado en dataschema
+func (r *Registry) ValidateEvent(body []byte) error {
+	if err := r.envelope.ValidateJSON(body); err != nil {
+		return err
+	}
+
+	var envelope struct {
+		Type       string          `json:"type"`
+		DataSchema string          `json:"dataschema"`
+		Data       json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return err
+	}
+
+	eventType, version, err := events.ParseDataSchema(envelope.DataSchema)
+	if err != nil {
+		return err
+	}
+	if eventType != envelope.Type {
+		return fmt.Errorf("%w: %s does not describe events of type %s", events.ErrInvalidDataSchema, envelope.DataSchema, envelope.Type)
+	}
+
+	schema, err := r.Schema(eventType, version)
+	if err != nil {
+		return err
+	}
+	return schema.ValidateJSON(envelope.Data)
+}
+
+// Marshal serializa el evento y lo valida antes de publicarlo
+func (r *Registry) Marshal(event *events.CloudEvent) ([]byte, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.ValidateEvent(body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
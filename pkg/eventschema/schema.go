@@ -0,0 +1,155 @@
+package eventschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Schema es el subconjunto de JSON Schema usado para describir los eventos:
+// type, properties, required, additionalProperties, enum, format (date-time),
+// minLength e items
+type Schema struct {
+	SchemaURI            string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Const                string             `json:"const,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	MinLength            int                `json:"minLength,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+}
+
+// ParseSchema decodifica un documento JSON Schema
+func ParseSchema(data []byte) (*Schema, error) {
+	var schema Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// ValidationError describe los campos que no cumplen el esquema
+type ValidationError struct {
+	Schema   string
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("event does not match schema %s: %s", e.Schema, strings.Join(e.Problems, "; "))
+}
+
+// ValidateJSON valida un documento JSON contra el esquema
+func (s *Schema) ValidateJSON(data []byte) error {
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return &ValidationError{Schema: s.ID, Problems: []string{"invalid JSON: " + err.Error()}}
+	}
+
+	var problems []string
+	s.validate("$", document, &problems)
+	if len(problems) > 0 {
+		return &ValidationError{Schema: s.ID, Problems: problems}
+	}
+	return nil
+}
+
+// validate acumula en problems las diferencias entre value y el esquema
+func (s *Schema) validate(path string, value interface{}, problems *[]string) {
+	if s.Type != "" && !matchesType(s.Type, value) {
+		*problems = append(*problems, fmt.Sprintf("%s: expected %s", path, s.Type))
+		return
+	}
+
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := typed[name]; !ok {
+				*problems = append(*problems, fmt.Sprintf("%s.%s: is required", path, name))
+			}
+		}
+
+		names := make([]string, 0, len(typed))
+		for name := range typed {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			property, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					*problems = append(*problems, fmt.Sprintf("%s.%s: is not allowed", path, name))
+				}
+				continue
+			}
+			property.validate(path+"."+name, typed[name], problems)
+		}
+
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range typed {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, problems)
+			}
+		}
+
+	case string:
+		if len(s.Enum) > 0 && !contains(s.Enum, typed) {
+			*problems = append(*problems, fmt.Sprintf("%s: must be one of %s", path, strings.Join(s.Enum, ", ")))
+		}
+		if s.Const != "" && typed != s.Const {
+			*problems = append(*problems, fmt.Sprintf("%s: must be %q", path, s.Const))
+		}
+		if len(typed) < s.MinLength {
+			*problems = append(*problems, fmt.Sprintf("%s: must have at least %d characters", path, s.MinLength))
+		}
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, typed); err != nil {
+				*problems = append(*problems, fmt.Sprintf("%s: must be an RFC 3339 date-time", path))
+			}
+		}
+	}
+}
+
+// matchesType indica si el valor decodificado corresponde al tipo JSON Schema
+func matchesType(schemaType string, value interface{}) bool {
+	switch schemaType {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == float64(int64(number))
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
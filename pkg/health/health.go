@@ -0,0 +1,144 @@
+// Package health expone los endpoints de liveness (/health) y readiness
+// (/health/ready) comunes a todos los servicios.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// checkTimeout es el tiempo máximo de cada chequeo de readiness
+const checkTimeout = 2 * time.Second
+
+// Check verifica una dependencia del servicio (tabla, cola, otro servicio...)
+type Check func(ctx context.Context) error
+
+// Handler responde los endpoints de salud de un servicio
+type Handler struct {
+	service string
+
+	mu     sync.RWMutex
+	checks map[string]Check
+}
+
+// New crea un handler de salud para el servicio indicado
+func New(service string) *Handler {
+	return &Handler{
+		service: service,
+		checks:  make(map[string]Check),
+	}
+}
+
+// AddCheck registra un chequeo de readiness
+func (h *Handler) AddCheck(name string, check Check) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks[name] = check
+}
+
+// Live responde 200 mientras el proceso esté en ejecución
+func (h *Handler) Live(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"status":  "ok",
+		"service": h.service,
+	})
+}
+
+// Ready ejecuta los chequeos registrados y responde 503 si alguno falla
+func (h *Handler) Ready(w http.ResponseWriter, r *http.Request) {
+	h.mu.RLock()
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+	h.mu.RUnlock()
+	sort.Strings(names)
+
+	status := http.StatusOK
+	results := make(map[string]string, len(names))
+	for _, name := range names {
+		h.mu.RLock()
+		check := h.checks[name]
+		h.mu.RUnlock()
+
+		ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+		err := check(ctx)
+		cancel()
+
+		if err != nil {
+			status = http.StatusServiceUnavailable
+			results[name] = err.Error()
+			continue
+		}
+		results[name] = "ok"
+	}
+
+	overall := "ok"
+	if status != http.StatusOK {
+		overall = "unavailable"
+	}
+	writeJSON(w, status, map[string]interface{}{
+		"status":  overall,
+		"service": h.service,
+		"checks":  results,
+	})
+}
+
+// ServeMux devuelve un mux con /health y /health/ready, para servicios sin API HTTP
+func (h *Handler) ServeMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", h.Live)
+	mux.HandleFunc("/health/ready", h.Ready)
+	return mux
+}
+
+// ListenAndServe expone los endpoints de salud en addr hasta que se cancele el contexto
+func (h *Handler) ListenAndServe(ctx context.Context, addr string) {
+	server := &http.Server{Addr: addr, Handler: h.ServeMux()}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("Health endpoints listening on %s", addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Printf("Health server error: %v", err)
+	}
+}
+
+// HTTPCheck devuelve un chequeo que verifica que url responda con 2xx
+func HTTPCheck(url string) Check {
+	return func(ctx context.Context) error {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			return err
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode < 200 || response.StatusCode > 299 {
+			return fmt.Errorf("unexpected status %d", response.StatusCode)
+		}
+		return nil
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,32 @@
+// Package password contiene el hasher de passwords compartido por los servicios
+package password
+
+import (
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BcryptHasher implementa los puertos PasswordHasher de los servicios usando bcrypt
+type BcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher crea una nueva instancia del hasher
+func NewBcryptHasher() *BcryptHasher {
+	return &BcryptHasher{
+		cost: bcrypt.DefaultCost, // Cost factor de 10
+	}
+}
+
+// Hash genera un hash bcrypt del password
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashedBytes), nil
+}
+
+// Compare verifica si un password coincide con su hash
+func (h *BcryptHasher) Compare(hashedPassword, password string) error {
+	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+}
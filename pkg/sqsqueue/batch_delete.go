@@ -0,0 +1,139 @@
+package sqsqueue
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// Parámetros del borrado en lotes
+const (
+	maxDeleteBatchSize  = 10
+	deleteFlushInterval = 100 * time.Millisecond
+)
+
+// deleter acumula los mensajes procesados sin error y los elimina con
+// DeleteMessageBatch al juntar 10 o cuando el más antiguo lleva
+// deleteFlushInterval esperando, sin esperar al resto de su recepción. Los
+// mensajes mantienen su heartbeat hasta que se eliminan.
+type deleter struct {
+	consumer   *Consumer
+	ctx        context.Context
+	deliveries chan delivery
+	done       chan struct{}
+}
+
+// startDeleter arranca el deleter; bufferSize es la cantidad de mensajes que
+// los workers pueden encolar sin esperar a que termine un borrado en curso
+func (c *Consumer) startDeleter(ctx context.Context, bufferSize int) *deleter {
+	d := &deleter{
+		consumer:   c,
+		ctx:        ctx,
+		deliveries: make(chan delivery, bufferSize),
+		done:       make(chan struct{}),
+	}
+	go d.run()
+	return d
+}
+
+// add encola un mensaje procesado para eliminarlo
+func (d *deleter) add(delivery delivery) {
+	d.deliveries <- delivery
+}
+
+// close elimina los mensajes pendientes y espera a que termine el deleter
+func (d *deleter) close() {
+	close(d.deliveries)
+	<-d.done
+}
+
+func (d *deleter) run() {
+	defer close(d.done)
+
+	var pending []delivery
+	var flushTimer <-chan time.Time
+	flush := func() {
+		d.consumer.deleteBatch(d.ctx, pending)
+		pending = nil
+		flushTimer = nil
+	}
+
+	for {
+		select {
+		case delivery, ok := <-d.deliveries:
+			if !ok {
+				flush()
+				return
+			}
+			pending = append(pending, delivery)
+			if len(pending) == 1 {
+				flushTimer = time.After(deleteFlushInterval)
+			}
+			if len(pending) == maxDeleteBatchSize {
+				flush()
+			}
+		case <-flushTimer:
+			flush()
+		}
+	}
+}
+
+// deleteBatch elimina los mensajes con DeleteMessageBatch y reintenta de forma
+// individual los que fallen (p.ej. por un receipt handle vencido)
+func (c *Consumer) deleteBatch(ctx context.Context, deliveries []delivery) {
+	defer func() {
+		for _, delivery := range deliveries {
+			delivery.stopHeartbeat()
+		}
+	}()
+
+	if len(deliveries) == 0 {
+		return
+	}
+	if len(deliveries) == 1 {
+		if err := c.delete(ctx, deliveries[0].message); err != nil {
+			log.Printf("Error deleting message from SQS: %v", err)
+		}
+		return
+	}
+
+	entries := make([]types.DeleteMessageBatchRequestEntry, len(deliveries))
+	for i, delivery := range deliveries {
+		entries[i] = types.DeleteMessageBatchRequestEntry{
+			Id:            aws.String(strconv.Itoa(i)),
+			ReceiptHandle: delivery.message.ReceiptHandle,
+		}
+	}
+
+	output, err := c.client.DeleteMessageBatch(ctx, &sqs.DeleteMessageBatchInput{
+		QueueUrl: aws.String(c.queueURL),
+		Entries:  entries,
+	})
+
+	var retry []delivery
+	if err != nil {
+		log.Printf("Error deleting batch of %d messages from SQS, retrying individually: %v", len(deliveries), err)
+		retry = deliveries
+	} else {
+		for _, failed := range output.Failed {
+			index, convErr := strconv.Atoi(aws.ToString(failed.Id))
+			if convErr != nil || index < 0 || index >= len(deliveries) {
+				continue
+			}
+			log.Printf("Error deleting message %s in batch (%s: %s), retrying individually",
+				aws.ToString(deliveries[index].message.MessageId), aws.ToString(failed.Code), aws.ToString(failed.Message))
+			retry = append(retry, deliveries[index])
+		}
+	}
+
+	for _, delivery := range retry {
+		if err := c.delete(ctx, delivery.message); err != nil {
+			log.Printf("Error deleting message %s from SQS: %v", aws.ToString(delivery.message.MessageId), err)
+		}
+	}
+}
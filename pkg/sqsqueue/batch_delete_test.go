@@ -0,0 +1,85 @@
+package sqsqueue
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+func TestConsumerDeletesWithoutWaitingForTheReceive(t *testing.T) {
+	client := newFakeSQS(testMessage("slow", nil), testMessage("fast", nil))
+	consumer := NewConsumer(client, testQueueURL, Options{Workers: 2})
+
+	release := make(chan struct{})
+	stop := runConsumer(consumer, func(ctx context.Context, message types.Message) error {
+		if aws.ToString(message.MessageId) == "slow" {
+			<-release
+		}
+		return nil
+	})
+	defer stop()
+
+	// El mensaje rápido se elimina al vencer el intervalo de flush aunque el
+	// lento de la misma recepción siga procesándose
+	if !eventually(10*deleteFlushInterval, func() bool { return len(client.deletedIDs()) == 1 }) {
+		t.Fatalf("deleted %v while the slow message was running, want [fast]", client.deletedIDs())
+	}
+	if deleted := client.deletedIDs(); deleted[0] != "fast" {
+		t.Errorf("deleted %v, want [fast]", deleted)
+	}
+
+	close(release)
+	if !eventually(10*deleteFlushInterval, func() bool { return len(client.deletedIDs()) == 2 }) {
+		t.Fatalf("deleted %v, want both messages", client.deletedIDs())
+	}
+}
+
+func TestConsumerFlushesFullDeleteBatches(t *testing.T) {
+	client := newFakeSQS(testMessages(maxDeleteBatchSize)...)
+	consumer := NewConsumer(client, testQueueURL, Options{Workers: 4, MaxInFlight: maxDeleteBatchSize})
+
+	start := time.Now()
+	stop := runConsumer(consumer, func(ctx context.Context, message types.Message) error { return nil })
+	defer stop()
+
+	if !eventually(time.Second, func() bool { return len(client.deletedIDs()) == maxDeleteBatchSize }) {
+		t.Fatalf("deleted %d messages, want %d", len(client.deletedIDs()), maxDeleteBatchSize)
+	}
+	// Un lote completo se elimina sin esperar al intervalo de flush
+	if elapsed := time.Since(start); elapsed >= deleteFlushInterval {
+		t.Errorf("full batch deleted after %s, want it flushed before %s", elapsed, deleteFlushInterval)
+	}
+	if calls := client.deleteCalls(); !reflect.DeepEqual(calls, []int{maxDeleteBatchSize}) {
+		t.Errorf("delete calls = %v, want a single batch of %d", calls, maxDeleteBatchSize)
+	}
+}
+
+func TestDeleteBatchRetriesFailedEntriesIndividually(t *testing.T) {
+	client := newFakeSQS()
+	client.batchFails = map[string]bool{"msg-1": true}
+	consumer := NewConsumer(client, testQueueURL, Options{})
+
+	var deliveries []delivery
+	stopped := 0
+	for _, message := range testMessages(3) {
+		deliveries = append(deliveries, delivery{message: message, stopHeartbeat: func() { stopped++ }})
+	}
+	consumer.deleteBatch(context.Background(), deliveries)
+
+	deleted := client.deletedIDs()
+	sort.Strings(deleted)
+	if want := []string{"msg-0", "msg-1", "msg-2"}; !reflect.DeepEqual(deleted, want) {
+		t.Errorf("deleted %v, want %v", deleted, want)
+	}
+	if calls := client.deleteCalls(); !reflect.DeepEqual(calls, []int{3, 1}) {
+		t.Errorf("delete calls = %v, want the batch and one individual retry", calls)
+	}
+	if stopped != 3 {
+		t.Errorf("stopped %d heartbeats, want 3", stopped)
+	}
+}
@@ -0,0 +1,275 @@
+package sqsqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"pkg/events"
+	"pkg/eventschema"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// Límites de SendMessageBatch
+const (
+	maxBatchEntries = 10
+	maxBatchBytes   = 256 * 1024
+)
+
+// defaultMaxLatency es la espera máxima de un evento antes de enviar su lote
+const defaultMaxLatency = 50 * time.Millisecond
+
+// ErrPublisherClosed indica que se publicó después de cerrar el publicador
+var ErrPublisherClosed = errors.New("publisher closed")
+
+// BatchPublisher agrupa los eventos publicados en llamadas SendMessageBatch (o
+// PublishBatch de SNS si se crea con NewTopicBatchPublisher). Un lote se envía al
+// llegar a 10 eventos o 256 KB, o cuando su primer evento lleva maxLatency
+// esperando. Publish espera el resultado de su evento, por lo que agrupa las
+// publicaciones concurrentes (p.ej. de varios workers). En colas y topics FIFO
+// cada evento lleva su MessageGroupId y el orden de llegada se conserva.
+type BatchPublisher struct {
+	destination string // URL de la cola o ARN del topic
+	sendBatch   func(ctx context.Context, batch []*publishRequest) []error
+	registry    *eventschema.Registry
+	maxLatency  time.Duration
+
+	requests chan *publishRequest
+	closing  chan struct{}
+	closed   chan struct{}
+}
+
+// publishRequest es un evento serializado a la espera de su lote
+type publishRequest struct {
+	body      string
+	eventType string
+	groupID   string // solo en colas y topics FIFO
+	dedupID   string // solo en colas y topics FIFO
+	result    chan error
+}
+
+// NewBatchPublisher crea el publicador sobre una cola e inicia el envío de lotes.
+// Los eventos se validan contra registry antes de encolarse. Close envía los pendientes.
+func NewBatchPublisher(client *sqs.Client, queueURL string, registry *eventschema.Registry, maxLatency time.Duration) *BatchPublisher {
+	return newBatchPublisher(queueURL, func(ctx context.Context, batch []*publishRequest) []error {
+		return sendBatch(ctx, client, queueURL, batch)
+	}, registry, maxLatency)
+}
+
+// NewTopicBatchPublisher crea el publicador sobre un topic SNS: cada suscriptor recibe
+// los eventos que acepta su filter policy por el atributo event_type
+func NewTopicBatchPublisher(client *sns.Client, topicARN string, registry *eventschema.Registry, maxLatency time.Duration) *BatchPublisher {
+	return newBatchPublisher(topicARN, func(ctx context.Context, batch []*publishRequest) []error {
+		return publishTopicBatch(ctx, client, topicARN, batch)
+	}, registry, maxLatency)
+}
+
+func newBatchPublisher(destination string, sendBatch func(ctx context.Context, batch []*publishRequest) []error, registry *eventschema.Registry, maxLatency time.Duration) *BatchPublisher {
+	if maxLatency <= 0 {
+		maxLatency = defaultMaxLatency
+	}
+
+	p := &BatchPublisher{
+		destination: destination,
+		sendBatch:   sendBatch,
+		registry:    registry,
+		maxLatency:  maxLatency,
+		requests:    make(chan *publishRequest),
+		closing:     make(chan struct{}),
+		closed:      make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Publish valida el evento, lo agrega al lote en curso y espera a que se envíe
+func (p *BatchPublisher) Publish(ctx context.Context, event *events.CloudEvent) error {
+	request, err := newPublishRequest(p.registry, p.destination, event)
+	if err != nil {
+		return err
+	}
+	request.result = make(chan error, 1)
+
+	select {
+	case p.requests <- request:
+	case <-p.closing:
+		return ErrPublisherClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-request.result:
+		if err != nil {
+			log.Printf("Error publishing event to %s: %v", p.destination, err)
+		}
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close deja de aceptar eventos y espera a que se envíe el último lote
+func (p *BatchPublisher) Close(ctx context.Context) error {
+	select {
+	case <-p.closing:
+	default:
+		close(p.closing)
+	}
+
+	select {
+	case <-p.closed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run acumula los eventos y envía cada lote al llenarse o al vencer maxLatency
+func (p *BatchPublisher) run() {
+	defer close(p.closed)
+
+	var batch []*publishRequest
+	var batchBytes int
+	timer := time.NewTimer(p.maxLatency)
+	timer.Stop()
+
+	flush := func() {
+		timer.Stop()
+		if len(batch) > 0 {
+			p.send(batch)
+		}
+		batch, batchBytes = nil, 0
+	}
+
+	for {
+		select {
+		case request := <-p.requests:
+			if len(batch) > 0 && batchBytes+len(request.body) > maxBatchBytes {
+				flush()
+			}
+			if len(batch) == 0 {
+				timer.Reset(p.maxLatency)
+			}
+			batch = append(batch, request)
+			batchBytes += len(request.body)
+			if len(batch) == maxBatchEntries {
+				flush()
+			}
+
+		case <-timer.C:
+			flush()
+
+		case <-p.closing:
+			flush()
+			return
+		}
+	}
+}
+
+// send envía un lote y entrega a cada evento su resultado
+func (p *BatchPublisher) send(batch []*publishRequest) {
+	// El lote se envía aunque los publicadores hayan cancelado su contexto
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	results := p.sendBatch(ctx, batch)
+	failed := 0
+	for i, request := range batch {
+		if results[i] != nil {
+			failed++
+		}
+		request.result <- results[i]
+	}
+
+	log.Printf("Published batch of %d events (%d failed)", len(batch), failed)
+}
+
+// newPublishRequest valida y serializa un evento para enviarlo en un lote a la
+// cola o topic destination
+func newPublishRequest(registry *eventschema.Registry, destination string, event *events.CloudEvent) (*publishRequest, error) {
+	body, err := registry.Marshal(event)
+	if err != nil {
+		log.Printf("Refusing to publish invalid %s event: %v", event.Type, err)
+		return nil, err
+	}
+
+	request := &publishRequest{
+		body:      string(body),
+		eventType: event.Type,
+	}
+	if IsFIFO(destination) {
+		request.groupID = MessageGroupID(event)
+		request.dedupID = event.ID
+	}
+	return request, nil
+}
+
+// splitBatches reparte los eventos en lotes de hasta 10 eventos y 256 KB sin alterar su orden
+func splitBatches(requests []*publishRequest) [][]*publishRequest {
+	var batches [][]*publishRequest
+	var batch []*publishRequest
+	var batchBytes int
+	for _, request := range requests {
+		if len(batch) == maxBatchEntries || (len(batch) > 0 && batchBytes+len(request.body) > maxBatchBytes) {
+			batches = append(batches, batch)
+			batch, batchBytes = nil, 0
+		}
+		batch = append(batch, request)
+		batchBytes += len(request.body)
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// sendBatch envía un lote con una llamada SendMessageBatch y devuelve el resultado
+// de cada evento, en el orden del lote
+func sendBatch(ctx context.Context, client *sqs.Client, queueURL string, batch []*publishRequest) []error {
+	entries := make([]types.SendMessageBatchRequestEntry, len(batch))
+	for i, request := range batch {
+		entries[i] = types.SendMessageBatchRequestEntry{
+			Id:          aws.String(strconv.Itoa(i)),
+			MessageBody: aws.String(request.body),
+			MessageAttributes: map[string]types.MessageAttributeValue{
+				EventTypeAttribute: {
+					DataType:    aws.String("String"),
+					StringValue: aws.String(request.eventType),
+				},
+			},
+		}
+		// SQS respeta el orden de las entradas del lote dentro de cada grupo FIFO
+		if request.groupID != "" {
+			entries[i].MessageGroupId = aws.String(request.groupID)
+			entries[i].MessageDeduplicationId = aws.String(request.dedupID)
+		}
+	}
+
+	results := make([]error, len(batch))
+	output, err := client.SendMessageBatch(ctx, &sqs.SendMessageBatchInput{
+		QueueUrl: aws.String(queueURL),
+		Entries:  entries,
+	})
+	if err != nil {
+		for i := range results {
+			results[i] = err
+		}
+		return results
+	}
+
+	for _, entry := range output.Failed {
+		index, convErr := strconv.Atoi(aws.ToString(entry.Id))
+		if convErr != nil || index < 0 || index >= len(batch) {
+			continue
+		}
+		results[index] = fmt.Errorf("send %s event: %s: %s", batch[index].eventType, aws.ToString(entry.Code), aws.ToString(entry.Message))
+	}
+	return results
+}
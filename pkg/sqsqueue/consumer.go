@@ -0,0 +1,382 @@
+// Package sqsqueue contiene el consumidor y el publicador genéricos de SQS
+// que usan todos los servicios.
+package sqsqueue
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// Parámetros de recepción por defecto
+const (
+	maxMessagesPerReceive = 10
+	waitTimeSeconds       = 20
+	receiveErrorBackoff   = 5 * time.Second
+
+	defaultWorkers           = 4
+	defaultVisibilityTimeout = 30 * time.Second
+	defaultDrainTimeout      = 30 * time.Second
+)
+
+// Handler procesa un mensaje; si devuelve error el mensaje no se elimina y
+// vuelve a estar visible al vencer el visibility timeout
+type Handler func(ctx context.Context, message types.Message) error
+
+// Options configura el pool de workers del consumidor. Los valores cero se
+// reemplazan por los valores por defecto.
+type Options struct {
+	// Workers es el número de mensajes que se procesan en paralelo
+	Workers int
+	// MaxInFlight limita los mensajes recibidos y aún no procesados (procesándose
+	// o esperando un worker); no se piden más mensajes a SQS mientras se alcance
+	MaxInFlight int
+	// VisibilityTimeout es el visibility timeout con el que se reciben los mensajes;
+	// mientras el handler siga trabajando se extiende cada VisibilityTimeout/2
+	VisibilityTimeout time.Duration
+	// DrainTimeout es el tiempo máximo para terminar los mensajes en curso al
+	// cancelar el contexto; al vencer se cancela el contexto de los handlers
+	DrainTimeout time.Duration
+	// DeadLetterQueueURL es la cola a la que se mueven los mensajes que fallan
+	// MaxReceiveCount veces o con un error Permanent; vacía desactiva la cuarentena
+	DeadLetterQueueURL string
+	// MaxReceiveCount es el número de intentos (ApproximateReceiveCount) antes
+	// de mover un mensaje a la dead-letter queue
+	MaxReceiveCount int
+}
+
+// OptionsFromEnv lee las opciones de SQS_WORKERS, SQS_MAX_IN_FLIGHT,
+// SQS_VISIBILITY_TIMEOUT_SECONDS, SQS_DRAIN_TIMEOUT_SECONDS, SQS_DLQ_URL y
+// SQS_MAX_RECEIVE_COUNT
+func OptionsFromEnv() Options {
+	return Options{
+		Workers:            envInt("SQS_WORKERS"),
+		MaxInFlight:        envInt("SQS_MAX_IN_FLIGHT"),
+		VisibilityTimeout:  time.Duration(envInt("SQS_VISIBILITY_TIMEOUT_SECONDS")) * time.Second,
+		DrainTimeout:       time.Duration(envInt("SQS_DRAIN_TIMEOUT_SECONDS")) * time.Second,
+		DeadLetterQueueURL: os.Getenv("SQS_DLQ_URL"),
+		MaxReceiveCount:    envInt("SQS_MAX_RECEIVE_COUNT"),
+	}
+}
+
+func envInt(name string) int {
+	value, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || value < 0 {
+		return 0
+	}
+	return value
+}
+
+func (o Options) withDefaults() Options {
+	if o.Workers <= 0 {
+		o.Workers = defaultWorkers
+	}
+	if o.MaxInFlight < o.Workers {
+		o.MaxInFlight = o.Workers
+	}
+	if o.VisibilityTimeout <= 0 {
+		o.VisibilityTimeout = defaultVisibilityTimeout
+	}
+	// SQS trabaja en segundos: un mínimo de 2s permite extenderlo a la mitad
+	if o.VisibilityTimeout < 2*time.Second {
+		o.VisibilityTimeout = 2 * time.Second
+	}
+	if o.DrainTimeout <= 0 {
+		o.DrainTimeout = defaultDrainTimeout
+	}
+	if o.MaxReceiveCount <= 0 {
+		o.MaxReceiveCount = defaultMaxReceiveCount
+	}
+	return o
+}
+
+// ConsumerAPI es el subconjunto del cliente de SQS que usa el consumidor;
+// *sqs.Client lo implementa
+type ConsumerAPI interface {
+	ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
+	DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error)
+	DeleteMessageBatch(ctx context.Context, params *sqs.DeleteMessageBatchInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageBatchOutput, error)
+	ChangeMessageVisibility(ctx context.Context, params *sqs.ChangeMessageVisibilityInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error)
+	SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+}
+
+// Consumer recibe mensajes de una cola SQS con long polling y los procesa con
+// un pool de workers. En colas FIFO los mensajes de un mismo grupo se procesan
+// en orden y los de grupos distintos en paralelo.
+type Consumer struct {
+	client   ConsumerAPI
+	queueURL string
+	options  Options
+}
+
+// NewConsumer crea una nueva instancia del consumidor
+func NewConsumer(client ConsumerAPI, queueURL string, options Options) *Consumer {
+	return &Consumer{
+		client:   client,
+		queueURL: queueURL,
+		options:  options.withDefaults(),
+	}
+}
+
+// QueueURL devuelve la URL de la cola que se consume
+func (c *Consumer) QueueURL() string {
+	return c.queueURL
+}
+
+// Run consume mensajes hasta que se cancele el contexto y elimina de la cola
+// los que el handler procesa sin error, agrupándolos en DeleteMessageBatch.
+// Al cancelar ctx deja de recibir, termina los mensajes ya recibidos (hasta
+// DrainTimeout), elimina los procesados y luego retorna.
+func (c *Consumer) Run(ctx context.Context, handler Handler) error {
+	log.Printf("Starting to consume messages from queue: %s (workers: %d, max in flight: %d)",
+		c.queueURL, c.options.Workers, c.options.MaxInFlight)
+	if c.options.DeadLetterQueueURL != "" {
+		log.Printf("Messages failing %d times are moved to: %s", c.options.MaxReceiveCount, c.options.DeadLetterQueueURL)
+	}
+
+	// Los handlers, heartbeats y borrados usan un contexto propio para que
+	// el trabajo en curso no se interrumpa al empezar el apagado
+	workCtx, cancelWork := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelWork()
+
+	// Los borrados tampoco se cancelan al vencer el drain timeout: los
+	// mensajes ya procesados se eliminan aunque se corten los handlers
+	deleter := c.startDeleter(context.WithoutCancel(ctx), c.options.MaxInFlight)
+
+	// Cada slot es un mensaje en vuelo; se libera al terminar de procesarlo
+	slots := make(chan struct{}, c.options.MaxInFlight)
+	jobs := make(chan job)
+
+	var workers sync.WaitGroup
+	for i := 0; i < c.options.Workers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for job := range jobs {
+				c.processJob(workCtx, job, handler, slots, deleter)
+			}
+		}()
+	}
+
+	c.receiveLoop(ctx, workCtx, slots, jobs)
+	close(jobs)
+
+	// Drenar los mensajes en curso antes de retornar
+	log.Println("Consumer stopping, draining in-flight messages...")
+	drained := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-time.After(c.options.DrainTimeout):
+		log.Printf("Drain timeout (%s) reached, cancelling in-flight handlers", c.options.DrainTimeout)
+		cancelWork()
+		<-drained
+	}
+	deleter.close()
+
+	log.Println("Consumer stopped")
+	return ctx.Err()
+}
+
+// delivery es un mensaje recibido junto con la función que detiene su heartbeat
+type delivery struct {
+	message       types.Message
+	stopHeartbeat func()
+}
+
+// job es la unidad de trabajo de un worker: un mensaje o, en colas FIFO, los
+// mensajes de un mismo MessageGroupId en el orden recibido
+type job struct {
+	deliveries []delivery
+}
+
+// receiveLoop pide mensajes solo cuando hay slots libres (backpressure) y
+// los entrega a los workers hasta que se cancele ctx. El heartbeat de cada
+// mensaje empieza al recibirlo, aunque todavía espere un worker libre.
+func (c *Consumer) receiveLoop(ctx, workCtx context.Context, slots chan struct{}, jobs chan<- job) {
+	for {
+		// Esperar al menos un slot libre
+		select {
+		case <-ctx.Done():
+			return
+		case slots <- struct{}{}:
+		}
+
+		// Reservar los slots libres adicionales, hasta el máximo por recepción
+		reserved := 1
+	reserve:
+		for reserved < maxMessagesPerReceive {
+			select {
+			case slots <- struct{}{}:
+				reserved++
+			default:
+				break reserve
+			}
+		}
+
+		output, err := c.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(c.queueURL),
+			MaxNumberOfMessages: int32(reserved),
+			WaitTimeSeconds:     waitTimeSeconds,
+			VisibilityTimeout:   int32(c.options.VisibilityTimeout / time.Second),
+			MessageSystemAttributeNames: []types.MessageSystemAttributeName{
+				types.MessageSystemAttributeNameApproximateReceiveCount,
+				types.MessageSystemAttributeNameMessageGroupId,
+			},
+			MessageAttributeNames: []string{"All"},
+		})
+
+		var messages []types.Message
+		if err == nil {
+			messages = output.Messages
+		}
+
+		// Liberar los slots que no se usaron
+		for i := len(messages); i < reserved; i++ {
+			<-slots
+		}
+
+		for _, group := range c.groupMessages(messages) {
+			deliveries := make([]delivery, len(group))
+			for i, message := range group {
+				deliveries[i] = delivery{message: message, stopHeartbeat: c.startHeartbeat(workCtx, message)}
+			}
+			jobs <- job{deliveries: deliveries}
+		}
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Error receiving messages from SQS: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(receiveErrorBackoff):
+			}
+		}
+	}
+}
+
+// processJob procesa en orden los mensajes del job, entrega al deleter los que
+// terminan sin error y libera un slot por cada uno. Si un mensaje falla, los
+// siguientes de su grupo no se procesan: vuelven a la cola junto con él para
+// no alterar el orden del grupo.
+func (c *Consumer) processJob(ctx context.Context, job job, handler Handler, slots <-chan struct{}, deleter *deleter) {
+	failed := false
+	for _, delivery := range job.deliveries {
+		if failed {
+			log.Printf("Skipping message %s: an earlier message of its group failed", aws.ToString(delivery.message.MessageId))
+		} else {
+			failed = !c.process(ctx, delivery.message, handler)
+		}
+		if failed {
+			delivery.stopHeartbeat()
+		} else {
+			deleter.add(delivery)
+		}
+		<-slots
+	}
+}
+
+// process ejecuta el handler y pone el mensaje en cuarentena en la dead-letter
+// queue si agotó sus intentos. Los mensajes procesados sin error los elimina el deleter.
+func (c *Consumer) process(ctx context.Context, message types.Message, handler Handler) bool {
+	err := handler(ctx, message)
+	if err == nil {
+		return true
+	}
+
+	log.Printf("Error processing message %s (attempt %d/%d): %v",
+		aws.ToString(message.MessageId), ReceiveCount(message), c.options.MaxReceiveCount, err)
+	if c.shouldDeadLetter(message, err) {
+		c.moveToDeadLetter(ctx, message, err)
+	}
+	return false
+}
+
+// groupMessages agrupa los mensajes en unidades de trabajo. En colas estándar
+// cada mensaje es independiente; en colas FIFO los mensajes con el mismo
+// MessageGroupId se procesan secuencialmente en el orden recibido y los de
+// grupos distintos en paralelo.
+func (c *Consumer) groupMessages(messages []types.Message) [][]types.Message {
+	if !IsFIFO(c.queueURL) {
+		groups := make([][]types.Message, len(messages))
+		for i, message := range messages {
+			groups[i] = []types.Message{message}
+		}
+		return groups
+	}
+
+	var groups [][]types.Message
+	index := make(map[string]int)
+	for _, message := range messages {
+		groupID := message.Attributes[string(types.MessageSystemAttributeNameMessageGroupId)]
+		i, ok := index[groupID]
+		if !ok {
+			i = len(groups)
+			index[groupID] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], message)
+	}
+	return groups
+}
+
+// delete elimina el mensaje de la cola de origen
+func (c *Consumer) delete(ctx context.Context, message types.Message) error {
+	_, err := c.client.DeleteMessage(ctx, &sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(c.queueURL),
+		ReceiptHandle: message.ReceiptHandle,
+	})
+	return err
+}
+
+// startHeartbeat extiende periódicamente el visibility timeout del mensaje para
+// que no vuelva a la cola mientras el handler sigue trabajando; devuelve la
+// función que lo detiene
+func (c *Consumer) startHeartbeat(ctx context.Context, message types.Message) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(c.options.VisibilityTimeout / 2)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_, err := c.client.ChangeMessageVisibility(ctx, &sqs.ChangeMessageVisibilityInput{
+					QueueUrl:          aws.String(c.queueURL),
+					ReceiptHandle:     message.ReceiptHandle,
+					VisibilityTimeout: int32(c.options.VisibilityTimeout / time.Second),
+				})
+				if err != nil {
+					log.Printf("Error extending visibility of message %s: %v", aws.ToString(message.MessageId), err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
@@ -0,0 +1,145 @@
+package sqsqueue
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+func TestConsumerRespectsMaxInFlight(t *testing.T) {
+	client := newFakeSQS(testMessages(10)...)
+	consumer := NewConsumer(client, "https://sqs/queue", Options{Workers: 2, MaxInFlight: 3})
+
+	release := make(chan struct{})
+	var running, maxRunning atomic.Int32
+	stop := runConsumer(consumer, func(ctx context.Context, message types.Message) error {
+		current := running.Add(1)
+		defer running.Add(-1)
+		for {
+			previous := maxRunning.Load()
+			if current <= previous || maxRunning.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+		<-release
+		return nil
+	})
+	defer stop()
+
+	// Con los handlers bloqueados solo se reciben MaxInFlight mensajes: dos
+	// procesándose y uno esperando un worker
+	if !eventually(time.Second, func() bool { return running.Load() == 2 }) {
+		t.Fatalf("running handlers = %d, want 2", running.Load())
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := client.receivedCount(); got != 3 {
+		t.Errorf("received %d messages while blocked, want 3", got)
+	}
+
+	close(release)
+	if !eventually(time.Second, func() bool { return len(client.deletedIDs()) == 10 }) {
+		t.Fatalf("deleted %d messages, want 10", len(client.deletedIDs()))
+	}
+	if got := maxRunning.Load(); got != 2 {
+		t.Errorf("max concurrent handlers = %d, want 2", got)
+	}
+}
+
+func TestConsumerHeartbeatExtendsSlowHandler(t *testing.T) {
+	client := newFakeSQS(testMessage("slow", nil), testMessage("fast", nil))
+	// El mínimo de 2s extiende la visibilidad cada segundo
+	consumer := NewConsumer(client, "https://sqs/queue", Options{Workers: 2, VisibilityTimeout: 2 * time.Second})
+
+	stop := runConsumer(consumer, func(ctx context.Context, message types.Message) error {
+		if *message.MessageId == "slow" {
+			time.Sleep(2500 * time.Millisecond)
+		}
+		return nil
+	})
+	defer stop()
+
+	if !eventually(5*time.Second, func() bool { return len(client.deletedIDs()) == 2 }) {
+		t.Fatalf("deleted %v, want both messages", client.deletedIDs())
+	}
+	if got := client.extensions("slow"); got < 2 {
+		t.Errorf("slow message extended %d times, want at least 2", got)
+	}
+
+	// Tras eliminarse el mensaje el heartbeat se detiene
+	extended := client.extensions("slow")
+	time.Sleep(1200 * time.Millisecond)
+	if got := client.extensions("slow"); got != extended {
+		t.Errorf("slow message extended %d times after deletion", got-extended)
+	}
+}
+
+func TestConsumerDrainsInFlightMessagesOnCancel(t *testing.T) {
+	tests := []struct {
+		name         string
+		drainTimeout time.Duration
+		handlerTime  time.Duration
+		wantDeleted  []string
+		wantCanceled bool
+	}{
+		{
+			name:         "el mensaje en curso termina y se elimina",
+			drainTimeout: time.Second,
+			handlerTime:  100 * time.Millisecond,
+			wantDeleted:  []string{"msg-0", "msg-1"},
+		},
+		{
+			name:         "al vencer el drain timeout se cancelan los handlers",
+			drainTimeout: 50 * time.Millisecond,
+			handlerTime:  time.Minute,
+			wantCanceled: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFakeSQS(testMessages(2)...)
+			consumer := NewConsumer(client, "https://sqs/queue", Options{Workers: 2, DrainTimeout: tt.drainTimeout})
+
+			var started sync.WaitGroup
+			started.Add(2)
+			var canceled atomic.Int32
+			stop := runConsumer(consumer, func(ctx context.Context, message types.Message) error {
+				started.Done()
+				select {
+				case <-time.After(tt.handlerTime):
+					return nil
+				case <-ctx.Done():
+					canceled.Add(1)
+					return ctx.Err()
+				}
+			})
+
+			started.Wait()
+			stopped := make(chan struct{})
+			go func() {
+				stop()
+				close(stopped)
+			}()
+
+			select {
+			case <-stopped:
+			case <-time.After(5 * time.Second):
+				t.Fatal("Run did not return after the drain")
+			}
+
+			deleted := client.deletedIDs()
+			sort.Strings(deleted)
+			if !reflect.DeepEqual(deleted, tt.wantDeleted) {
+				t.Errorf("deleted %v, want %v", deleted, tt.wantDeleted)
+			}
+			if got := canceled.Load() > 0; got != tt.wantCanceled {
+				t.Errorf("handlers canceled = %t, want %t", got, tt.wantCanceled)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package sqsqueue
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// Atributos de mensaje que se agregan al mover un mensaje a la dead-letter queue
+const (
+	FailureReasonAttribute = "dlq_failure_reason"
+	SourceQueueAttribute   = "dlq_source_queue"
+	ReceiveCountAttribute  = "dlq_receive_count"
+	FailedAtAttribute      = "dlq_failed_at"
+)
+
+// Límites de SQS para los atributos de mensaje
+const (
+	maxMessageAttributes  = 10
+	maxFailureReasonBytes = 1024
+)
+
+// defaultMaxReceiveCount es el número de intentos antes de mover un mensaje a la DLQ
+const defaultMaxReceiveCount = 5
+
+// permanentError marca un error que no se resuelve reintentando
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marca err como no reintentable (p.ej. JSON inválido o evento que no
+// cumple su esquema): el mensaje se mueve a la dead-letter queue en el primer
+// intento en lugar de esperar a MaxReceiveCount
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent indica si err fue marcado con Permanent
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// ReceiveCount devuelve el atributo ApproximateReceiveCount del mensaje (0 si no se pidió)
+func ReceiveCount(message types.Message) int {
+	count, err := strconv.Atoi(message.Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)])
+	if err != nil {
+		return 0
+	}
+	return count
+}
+
+// groupIDOf devuelve el MessageGroupId de un mensaje FIFO o un grupo por
+// defecto si la cola de origen no es FIFO
+func groupIDOf(message types.Message) string {
+	if groupID := message.Attributes[string(types.MessageSystemAttributeNameMessageGroupId)]; groupID != "" {
+		return groupID
+	}
+	return deadLetterGroupID
+}
+
+// shouldDeadLetter decide si un mensaje fallido se pone en cuarentena
+func (c *Consumer) shouldDeadLetter(message types.Message, err error) bool {
+	if c.options.DeadLetterQueueURL == "" {
+		return false
+	}
+	return IsPermanent(err) || ReceiveCount(message) >= c.options.MaxReceiveCount
+}
+
+// moveToDeadLetter copia el mensaje a la dead-letter queue con el motivo del
+// fallo como atributos y lo elimina de la cola de origen. Si el envío falla el
+// mensaje se queda en la cola y la redrive policy de SQS actúa como respaldo.
+func (c *Consumer) moveToDeadLetter(ctx context.Context, message types.Message, cause error) {
+	attributes := make(map[string]types.MessageAttributeValue, maxMessageAttributes)
+	failure := map[string]string{
+		FailureReasonAttribute: truncate(cause.Error(), maxFailureReasonBytes),
+		SourceQueueAttribute:   c.queueURL,
+		ReceiveCountAttribute:  strconv.Itoa(ReceiveCount(message)),
+		FailedAtAttribute:      time.Now().UTC().Format(time.RFC3339),
+	}
+	for name, value := range failure {
+		attributes[name] = types.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(value),
+		}
+	}
+
+	// Conservar los atributos originales (p.ej. event_type) dentro del límite de SQS
+	for name, value := range message.MessageAttributes {
+		if len(attributes) >= maxMessageAttributes {
+			log.Printf("Dropping attribute %s of message %s: attribute limit reached", name, aws.ToString(message.MessageId))
+			continue
+		}
+		if _, reserved := attributes[name]; !reserved {
+			attributes[name] = value
+		}
+	}
+
+	input := &sqs.SendMessageInput{
+		QueueUrl:          aws.String(c.options.DeadLetterQueueURL),
+		MessageBody:       message.Body,
+		MessageAttributes: attributes,
+	}
+	// Una DLQ FIFO conserva el grupo original; el ID del mensaje evita duplicarlo
+	// si la cuarentena se reintenta
+	if IsFIFO(c.options.DeadLetterQueueURL) {
+		input.MessageGroupId = aws.String(groupIDOf(message))
+		input.MessageDeduplicationId = message.MessageId
+	}
+
+	_, err := c.client.SendMessage(ctx, input)
+	if err != nil {
+		log.Printf("Error moving message %s to dead-letter queue: %v", aws.ToString(message.MessageId), err)
+		return
+	}
+
+	if err := c.delete(ctx, message); err != nil {
+		log.Printf("Error deleting dead-lettered message %s from SQS: %v", aws.ToString(message.MessageId), err)
+		return
+	}
+
+	log.Printf("Message %s moved to dead-letter queue after %d attempts: %v",
+		aws.ToString(message.MessageId), ReceiveCount(message), cause)
+}
+
+// truncate recorta s a max bytes sin partir caracteres UTF-8
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "…"
+}
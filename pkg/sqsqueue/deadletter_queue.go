@@ -0,0 +1,254 @@
+package sqsqueue
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// deadLetterAttributePrefix es el prefijo de los atributos que agrega el consumidor al poner un mensaje en cuarentena
+const deadLetterAttributePrefix = "dlq_"
+
+// emptyReceiveWaitSeconds es la espera de long polling para concluir que no quedan mensajes visibles
+const emptyReceiveWaitSeconds = 1
+
+// DeadLetterMessage es un mensaje recibido de una dead-letter queue con sus
+// metadatos de fallo. Mientras dura el lease el mensaje no es visible para
+// otros consumidores.
+type DeadLetterMessage struct {
+	MessageID     string
+	ReceiptHandle string
+	Body          string
+	Attributes    map[string]types.MessageAttributeValue
+	Attempts      int // intentos en la cola de origen antes de la cuarentena
+	FailureReason string
+	SourceQueue   string
+	FailedAt      string
+	GroupID       string // MessageGroupId en colas FIFO
+}
+
+// OriginalAttributes devuelve los atributos del mensaje sin los de cuarentena (dlq_*)
+func (m *DeadLetterMessage) OriginalAttributes() map[string]types.MessageAttributeValue {
+	attributes := make(map[string]types.MessageAttributeValue, len(m.Attributes))
+	for name, value := range m.Attributes {
+		if !strings.HasPrefix(name, deadLetterAttributePrefix) {
+			attributes[name] = value
+		}
+	}
+	return attributes
+}
+
+// DeadLetterQueue permite inspeccionar y reprocesar los mensajes de una dead-letter queue
+type DeadLetterQueue struct {
+	client   *sqs.Client
+	queueURL string
+	lease    time.Duration
+}
+
+// NewDeadLetterQueue crea el acceso a la dead-letter queue; lease es el
+// visibility timeout con el que se reservan los mensajes recibidos
+func NewDeadLetterQueue(client *sqs.Client, queueURL string, lease time.Duration) *DeadLetterQueue {
+	if lease < time.Second {
+		lease = time.Second
+	}
+	return &DeadLetterQueue{
+		client:   client,
+		queueURL: queueURL,
+		lease:    lease,
+	}
+}
+
+// QueueURL devuelve la URL de la dead-letter queue
+func (q *DeadLetterQueue) QueueURL() string {
+	return q.queueURL
+}
+
+// ReceiveBatch recibe hasta 10 mensajes reservándolos durante el lease; un
+// resultado vacío indica que no quedan mensajes visibles
+func (q *DeadLetterQueue) ReceiveBatch(ctx context.Context) ([]*DeadLetterMessage, error) {
+	output, err := q.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:              aws.String(q.queueURL),
+		MaxNumberOfMessages:   maxMessagesPerReceive,
+		WaitTimeSeconds:       emptyReceiveWaitSeconds,
+		VisibilityTimeout:     int32(q.lease / time.Second),
+		MessageAttributeNames: []string{"All"},
+		MessageSystemAttributeNames: []types.MessageSystemAttributeName{
+			types.MessageSystemAttributeNameMessageGroupId,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*DeadLetterMessage, 0, len(output.Messages))
+	for _, message := range output.Messages {
+		messages = append(messages, newDeadLetterMessage(message))
+	}
+	return messages, nil
+}
+
+// Receive recibe hasta max mensajes (0 = todos los visibles). Los mensajes
+// quedan reservados hasta que se liberen, eliminen o venza el lease.
+func (q *DeadLetterQueue) Receive(ctx context.Context, max int) ([]*DeadLetterMessage, error) {
+	var messages []*DeadLetterMessage
+	for max == 0 || len(messages) < max {
+		batch, err := q.ReceiveBatch(ctx)
+		if err != nil {
+			return messages, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+		messages = append(messages, batch...)
+	}
+
+	if max > 0 && len(messages) > max {
+		if err := q.Release(ctx, messages[max:]...); err != nil {
+			return messages[:max], err
+		}
+		messages = messages[:max]
+	}
+	return messages, nil
+}
+
+// Find recibe mensajes hasta encontrar todos los IDs indicados o vaciar la
+// cola; los mensajes que no coinciden se liberan antes de retornar
+func (q *DeadLetterQueue) Find(ctx context.Context, messageIDs []string) ([]*DeadLetterMessage, error) {
+	pending := make(map[string]bool, len(messageIDs))
+	for _, id := range messageIDs {
+		pending[id] = true
+	}
+
+	var found, others []*DeadLetterMessage
+	var err error
+	for len(pending) > 0 {
+		var batch []*DeadLetterMessage
+		batch, err = q.ReceiveBatch(ctx)
+		if err != nil || len(batch) == 0 {
+			break
+		}
+		for _, message := range batch {
+			if pending[message.MessageID] {
+				delete(pending, message.MessageID)
+				found = append(found, message)
+			} else {
+				others = append(others, message)
+			}
+		}
+	}
+
+	if releaseErr := q.Release(ctx, others...); err == nil {
+		err = releaseErr
+	}
+	return found, err
+}
+
+// Release devuelve los mensajes a la cola para que vuelvan a estar visibles
+func (q *DeadLetterQueue) Release(ctx context.Context, messages ...*DeadLetterMessage) error {
+	for _, message := range messages {
+		_, err := q.client.ChangeMessageVisibility(ctx, &sqs.ChangeMessageVisibilityInput{
+			QueueUrl:          aws.String(q.queueURL),
+			ReceiptHandle:     aws.String(message.ReceiptHandle),
+			VisibilityTimeout: 0,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Discard elimina definitivamente el mensaje de la dead-letter queue
+func (q *DeadLetterQueue) Discard(ctx context.Context, message *DeadLetterMessage) error {
+	_, err := q.client.DeleteMessage(ctx, &sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(q.queueURL),
+		ReceiptHandle: aws.String(message.ReceiptHandle),
+	})
+	return err
+}
+
+// Replace reemplaza el cuerpo del mensaje conservando sus atributos: envía la
+// versión corregida a la dead-letter queue y elimina la original
+func (q *DeadLetterQueue) Replace(ctx context.Context, message *DeadLetterMessage, body string) (string, error) {
+	input := &sqs.SendMessageInput{
+		QueueUrl:          aws.String(q.queueURL),
+		MessageBody:       aws.String(body),
+		MessageAttributes: message.Attributes,
+	}
+	setFIFOFields(input, message, body)
+
+	output, err := q.client.SendMessage(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(output.MessageId), q.Discard(ctx, message)
+}
+
+// Redrive devuelve el mensaje a targetQueueURL con sus atributos originales
+// (sin los de cuarentena) y lo elimina de la dead-letter queue
+func (q *DeadLetterQueue) Redrive(ctx context.Context, message *DeadLetterMessage, targetQueueURL string) error {
+	input := &sqs.SendMessageInput{
+		QueueUrl:          aws.String(targetQueueURL),
+		MessageBody:       aws.String(message.Body),
+		MessageAttributes: message.OriginalAttributes(),
+	}
+	setFIFOFields(input, message, message.MessageID)
+
+	_, err := q.client.SendMessage(ctx, input)
+	if err != nil {
+		return err
+	}
+	return q.Discard(ctx, message)
+}
+
+func newDeadLetterMessage(message types.Message) *DeadLetterMessage {
+	return &DeadLetterMessage{
+		MessageID:     aws.ToString(message.MessageId),
+		ReceiptHandle: aws.ToString(message.ReceiptHandle),
+		Body:          aws.ToString(message.Body),
+		Attributes:    message.MessageAttributes,
+		Attempts:      intAttribute(message, ReceiveCountAttribute),
+		FailureReason: stringAttribute(message, FailureReasonAttribute),
+		SourceQueue:   stringAttribute(message, SourceQueueAttribute),
+		FailedAt:      stringAttribute(message, FailedAtAttribute),
+		GroupID:       message.Attributes[string(types.MessageSystemAttributeNameMessageGroupId)],
+	}
+}
+
+// setFIFOFields completa el grupo y el ID de deduplicación si el destino es una
+// cola FIFO. El ID de deduplicación se deriva de dedupSeed para que reintentar
+// la misma operación no duplique el mensaje.
+func setFIFOFields(input *sqs.SendMessageInput, message *DeadLetterMessage, dedupSeed string) {
+	if !IsFIFO(aws.ToString(input.QueueUrl)) {
+		return
+	}
+	groupID := message.GroupID
+	if groupID == "" {
+		groupID = deadLetterGroupID
+	}
+	sum := sha256.Sum256([]byte(dedupSeed))
+	input.MessageGroupId = aws.String(groupID)
+	input.MessageDeduplicationId = aws.String(hex.EncodeToString(sum[:]))
+}
+
+func stringAttribute(message types.Message, name string) string {
+	if value, ok := message.MessageAttributes[name]; ok {
+		return aws.ToString(value.StringValue)
+	}
+	return ""
+}
+
+func intAttribute(message types.Message, name string) int {
+	value, err := strconv.Atoi(stringAttribute(message, name))
+	if err != nil {
+		return 0
+	}
+	return value
+}
@@ -0,0 +1,206 @@
+package sqsqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+const (
+	testQueueURL = "https://sqs/employee-events-queue"
+	testDLQURL   = "https://sqs/employee-events-dlq"
+)
+
+func receivedMessage(id string, receiveCount int) types.Message {
+	return testMessage(id, map[string]string{
+		string(types.MessageSystemAttributeNameApproximateReceiveCount): fmt.Sprint(receiveCount),
+	})
+}
+
+func TestPermanent(t *testing.T) {
+	cause := errors.New("invalid JSON")
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"error marcado", Permanent(cause), true},
+		{"error marcado y envuelto", fmt.Errorf("decode event: %w", Permanent(cause)), true},
+		{"error sin marcar", cause, false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPermanent(tt.err); got != tt.want {
+				t.Errorf("IsPermanent(%v) = %t, want %t", tt.err, got, tt.want)
+			}
+		})
+	}
+
+	if Permanent(nil) != nil {
+		t.Error("Permanent(nil) != nil")
+	}
+	if err := Permanent(cause); !errors.Is(err, cause) || err.Error() != cause.Error() {
+		t.Errorf("Permanent(cause) = %v, want it to wrap %v", err, cause)
+	}
+}
+
+func TestShouldDeadLetter(t *testing.T) {
+	transient := errors.New("timeout")
+
+	tests := []struct {
+		name         string
+		dlqURL       string
+		receiveCount int
+		err          error
+		want         bool
+	}{
+		{"error transitorio con intentos restantes", testDLQURL, 2, transient, false},
+		{"error transitorio en el último intento", testDLQURL, 5, transient, true},
+		{"error transitorio superando los intentos", testDLQURL, 7, transient, true},
+		{"error permanente en el primer intento", testDLQURL, 1, Permanent(transient), true},
+		{"sin dead-letter queue", "", 5, Permanent(transient), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			consumer := NewConsumer(newFakeSQS(), testQueueURL, Options{DeadLetterQueueURL: tt.dlqURL, MaxReceiveCount: 5})
+			if got := consumer.shouldDeadLetter(receivedMessage("msg-1", tt.receiveCount), tt.err); got != tt.want {
+				t.Errorf("shouldDeadLetter() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoveToDeadLetterWritesFailureAttributes(t *testing.T) {
+	message := receivedMessage("msg-1", 3)
+	message.MessageAttributes = map[string]types.MessageAttributeValue{
+		"event_type": {DataType: aws.String("String"), StringValue: aws.String("employee.created")},
+		// Un atributo con el nombre de uno de cuarentena no lo pisa
+		FailureReasonAttribute: {DataType: aws.String("String"), StringValue: aws.String("old reason")},
+	}
+
+	client := newFakeSQS()
+	consumer := NewConsumer(client, testQueueURL, Options{DeadLetterQueueURL: testDLQURL})
+	before := time.Now().UTC().Truncate(time.Second)
+	consumer.moveToDeadLetter(context.Background(), message, Permanent(errors.New("schema mismatch")))
+
+	if len(client.sent) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(client.sent))
+	}
+	sent := client.sent[0]
+	if aws.ToString(sent.QueueUrl) != testDLQURL || aws.ToString(sent.MessageBody) != aws.ToString(message.Body) {
+		t.Errorf("sent %s to %s, want the original body to %s", aws.ToString(sent.MessageBody), aws.ToString(sent.QueueUrl), testDLQURL)
+	}
+	if sent.MessageGroupId != nil || sent.MessageDeduplicationId != nil {
+		t.Error("standard dead-letter queue got FIFO fields")
+	}
+
+	attributes := make(map[string]string)
+	for name, value := range sent.MessageAttributes {
+		attributes[name] = aws.ToString(value.StringValue)
+	}
+	failedAt, err := time.Parse(time.RFC3339, attributes[FailedAtAttribute])
+	if err != nil || failedAt.Before(before) {
+		t.Errorf("%s = %q, want the time of the failure", FailedAtAttribute, attributes[FailedAtAttribute])
+	}
+	delete(attributes, FailedAtAttribute)
+	want := map[string]string{
+		FailureReasonAttribute: "schema mismatch",
+		SourceQueueAttribute:   testQueueURL,
+		ReceiveCountAttribute:  "3",
+		"event_type":           "employee.created",
+	}
+	if !reflect.DeepEqual(attributes, want) {
+		t.Errorf("attributes = %v, want %v", attributes, want)
+	}
+
+	if deleted := client.deletedIDs(); !reflect.DeepEqual(deleted, []string{"msg-1"}) {
+		t.Errorf("deleted %v, want the message removed from the source queue", deleted)
+	}
+}
+
+func TestMoveToDeadLetterLimits(t *testing.T) {
+	t.Run("motivo truncado y atributos dentro del límite de SQS", func(t *testing.T) {
+		message := receivedMessage("msg-1", 5)
+		message.MessageAttributes = make(map[string]types.MessageAttributeValue)
+		for i := 0; i < maxMessageAttributes; i++ {
+			message.MessageAttributes[fmt.Sprintf("attr_%d", i)] = types.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String("v")}
+		}
+
+		client := newFakeSQS()
+		consumer := NewConsumer(client, testQueueURL, Options{DeadLetterQueueURL: testDLQURL})
+		consumer.moveToDeadLetter(context.Background(), message, errors.New(strings.Repeat("é", maxFailureReasonBytes)))
+
+		sent := client.sent[0]
+		if len(sent.MessageAttributes) != maxMessageAttributes {
+			t.Errorf("sent %d attributes, want %d", len(sent.MessageAttributes), maxMessageAttributes)
+		}
+		reason := aws.ToString(sent.MessageAttributes[FailureReasonAttribute].StringValue)
+		if !strings.HasSuffix(reason, "…") || len(reason) > maxFailureReasonBytes+len("…") || !strings.HasPrefix(reason, "é") {
+			t.Errorf("failure reason of %d bytes not truncated on a rune boundary", len(reason))
+		}
+	})
+
+	t.Run("dead-letter queue FIFO conserva el grupo", func(t *testing.T) {
+		message := testMessage("msg-1", map[string]string{
+			string(types.MessageSystemAttributeNameMessageGroupId): "emp-1",
+		})
+
+		client := newFakeSQS()
+		consumer := NewConsumer(client, testQueueURL+".fifo", Options{DeadLetterQueueURL: testDLQURL + ".fifo"})
+		consumer.moveToDeadLetter(context.Background(), message, errors.New("boom"))
+
+		sent := client.sent[0]
+		if aws.ToString(sent.MessageGroupId) != "emp-1" || aws.ToString(sent.MessageDeduplicationId) != "msg-1" {
+			t.Errorf("group = %q, dedup = %q, want emp-1 and msg-1", aws.ToString(sent.MessageGroupId), aws.ToString(sent.MessageDeduplicationId))
+		}
+	})
+
+	t.Run("si el envío falla el mensaje se queda en la cola", func(t *testing.T) {
+		client := newFakeSQS()
+		client.sendErr = errors.New("dlq unavailable")
+		consumer := NewConsumer(client, testQueueURL, Options{DeadLetterQueueURL: testDLQURL})
+		consumer.moveToDeadLetter(context.Background(), receivedMessage("msg-1", 5), errors.New("boom"))
+
+		if deleted := client.deletedIDs(); len(deleted) != 0 {
+			t.Errorf("deleted %v after a failed send", deleted)
+		}
+	})
+}
+
+func TestConsumerQuarantinesPermanentFailures(t *testing.T) {
+	client := newFakeSQS(receivedMessage("invalid", 1), receivedMessage("transient", 1), receivedMessage("valid", 1))
+	consumer := NewConsumer(client, testQueueURL, Options{DeadLetterQueueURL: testDLQURL})
+
+	stop := runConsumer(consumer, func(ctx context.Context, message types.Message) error {
+		switch aws.ToString(message.MessageId) {
+		case "invalid":
+			return Permanent(errors.New("invalid JSON"))
+		case "transient":
+			return errors.New("timeout")
+		}
+		return nil
+	})
+	if !eventually(time.Second, func() bool { return len(client.deletedIDs()) == 2 }) {
+		t.Fatalf("deleted %v, want the invalid and valid messages", client.deletedIDs())
+	}
+	stop()
+
+	// El error transitorio espera a que SQS lo vuelva a entregar
+	if len(client.sent) != 1 || aws.ToString(client.sent[0].MessageBody) != `{"id":"invalid"}` {
+		t.Errorf("dead-lettered %d messages, want only the invalid one", len(client.sent))
+	}
+	for _, id := range client.deletedIDs() {
+		if id == "transient" {
+			t.Error("transient failure was deleted")
+		}
+	}
+}
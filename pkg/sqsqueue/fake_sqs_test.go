@@ -0,0 +1,192 @@
+package sqsqueue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// emptyReceiveDelay simula el long polling de una cola vacía sin demorar los tests
+const emptyReceiveDelay = 5 * time.Millisecond
+
+// fakeSQS es una cola en memoria que implementa ConsumerAPI. Los mensajes
+// recibidos dejan de estar disponibles aunque no se eliminen: los tests no
+// simulan el vencimiento del visibility timeout. Igual que SQS, una cola FIFO
+// no entrega mensajes de un grupo mientras otro del mismo grupo esté en vuelo.
+type fakeSQS struct {
+	mu         sync.Mutex
+	available  []types.Message
+	received   int
+	inFlight   map[string]string // receipt handle → grupo de los mensajes FIFO en vuelo
+	deleted    []string          // IDs de los mensajes eliminados, en orden
+	deleteCall []int             // cantidad de mensajes de cada llamada de borrado
+	extended   map[string]int
+	sent       []*sqs.SendMessageInput
+	sendErr    error
+	// batchFails son los receipt handles que fallan en DeleteMessageBatch
+	batchFails map[string]bool
+}
+
+func newFakeSQS(messages ...types.Message) *fakeSQS {
+	return &fakeSQS{available: messages, inFlight: make(map[string]string), extended: make(map[string]int)}
+}
+
+// testMessage crea un mensaje cuyo receipt handle es su ID
+func testMessage(id string, attributes map[string]string) types.Message {
+	return types.Message{
+		MessageId:     aws.String(id),
+		ReceiptHandle: aws.String(id),
+		Body:          aws.String(fmt.Sprintf(`{"id":%q}`, id)),
+		Attributes:    attributes,
+	}
+}
+
+func testMessages(n int) []types.Message {
+	messages := make([]types.Message, n)
+	for i := range messages {
+		messages[i] = testMessage(fmt.Sprintf("msg-%d", i), nil)
+	}
+	return messages
+}
+
+func (f *fakeSQS) ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	f.mu.Lock()
+	fifo := IsFIFO(aws.ToString(params.QueueUrl))
+	locked := make(map[string]bool)
+	for _, group := range f.inFlight {
+		locked[group] = true
+	}
+	var messages, remaining []types.Message
+	for _, message := range f.available {
+		group := message.Attributes[string(types.MessageSystemAttributeNameMessageGroupId)]
+		if len(messages) == int(params.MaxNumberOfMessages) || (fifo && locked[group]) {
+			// Los mensajes siguientes de un grupo bloqueado tampoco se entregan
+			locked[group] = fifo
+			remaining = append(remaining, message)
+			continue
+		}
+		if fifo {
+			f.inFlight[aws.ToString(message.ReceiptHandle)] = group
+		}
+		messages = append(messages, message)
+	}
+	f.available = remaining
+	f.received += len(messages)
+	f.mu.Unlock()
+
+	if len(messages) == 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(emptyReceiveDelay):
+		}
+	}
+	return &sqs.ReceiveMessageOutput{Messages: messages}, nil
+}
+
+func (f *fakeSQS) DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deleted = append(f.deleted, aws.ToString(params.ReceiptHandle))
+	delete(f.inFlight, aws.ToString(params.ReceiptHandle))
+	f.deleteCall = append(f.deleteCall, 1)
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+func (f *fakeSQS) DeleteMessageBatch(ctx context.Context, params *sqs.DeleteMessageBatchInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageBatchOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	output := &sqs.DeleteMessageBatchOutput{}
+	for _, entry := range params.Entries {
+		if f.batchFails[aws.ToString(entry.ReceiptHandle)] {
+			output.Failed = append(output.Failed, types.BatchResultErrorEntry{
+				Id:      entry.Id,
+				Code:    aws.String("ReceiptHandleIsInvalid"),
+				Message: aws.String("expired"),
+			})
+			continue
+		}
+		f.deleted = append(f.deleted, aws.ToString(entry.ReceiptHandle))
+		delete(f.inFlight, aws.ToString(entry.ReceiptHandle))
+		output.Successful = append(output.Successful, types.DeleteMessageBatchResultEntry{Id: entry.Id})
+	}
+	f.deleteCall = append(f.deleteCall, len(params.Entries))
+	return output, nil
+}
+
+func (f *fakeSQS) ChangeMessageVisibility(ctx context.Context, params *sqs.ChangeMessageVisibilityInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.extended[aws.ToString(params.ReceiptHandle)]++
+	return &sqs.ChangeMessageVisibilityOutput{}, nil
+}
+
+func (f *fakeSQS) SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.sendErr != nil {
+		return nil, f.sendErr
+	}
+	f.sent = append(f.sent, params)
+	return &sqs.SendMessageOutput{MessageId: aws.String(fmt.Sprintf("sent-%d", len(f.sent)))}, nil
+}
+
+// receivedCount devuelve cuántos mensajes se entregaron al consumidor
+func (f *fakeSQS) receivedCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.received
+}
+
+// deletedIDs devuelve una copia de los IDs eliminados
+func (f *fakeSQS) deletedIDs() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.deleted...)
+}
+
+// deleteCalls devuelve la cantidad de mensajes de cada llamada de borrado
+func (f *fakeSQS) deleteCalls() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]int(nil), f.deleteCall...)
+}
+
+// extensions devuelve cuántas veces se extendió la visibilidad del mensaje
+func (f *fakeSQS) extensions(id string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.extended[id]
+}
+
+// runConsumer ejecuta el consumidor en segundo plano; la función devuelta
+// cancela el contexto y espera a que Run retorne
+func runConsumer(consumer *Consumer, handler Handler) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		consumer.Run(ctx, handler)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// eventually espera hasta que cond se cumpla o venza el plazo
+func eventually(timeout time.Duration, cond func() bool) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return cond()
+}
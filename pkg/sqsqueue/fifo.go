@@ -0,0 +1,27 @@
+package sqsqueue
+
+import (
+	"pkg/events"
+	"strings"
+)
+
+// fifoSuffix es el sufijo obligatorio del nombre de colas y topics FIFO
+const fifoSuffix = ".fifo"
+
+// deadLetterGroupID es el grupo de los mensajes que llegan a una cola FIFO sin grupo propio
+const deadLetterGroupID = "dead-letter"
+
+// IsFIFO indica si la URL de la cola (o el ARN del topic) corresponde a un recurso FIFO
+func IsFIFO(queueURLOrARN string) bool {
+	return strings.HasSuffix(queueURLOrARN, fifoSuffix)
+}
+
+// MessageGroupID devuelve el grupo de ordenamiento de un evento: el subject
+// (ID del empleado), de modo que los eventos de un empleado se entregan en
+// orden y los de empleados distintos en paralelo. Sin subject se agrupa por tipo.
+func MessageGroupID(event *events.CloudEvent) string {
+	if event.Subject != "" {
+		return event.Subject
+	}
+	return event.Type
+}
@@ -0,0 +1,138 @@
+package sqsqueue
+
+import (
+	"context"
+	"errors"
+	"pkg/events"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+func TestMessageGroupID(t *testing.T) {
+	tests := []struct {
+		name  string
+		event *events.CloudEvent
+		want  string
+	}{
+		{"agrupa por empleado", &events.CloudEvent{Type: "employee.updated", Subject: "emp-1"}, "emp-1"},
+		{"sin subject agrupa por tipo", &events.CloudEvent{Type: "department.created"}, "department.created"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MessageGroupID(tt.event); got != tt.want {
+				t.Errorf("MessageGroupID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if !IsFIFO("https://sqs/employee-events-queue.fifo") || !IsFIFO("arn:aws:sns:us-east-1:000000000000:employee-events-topic.fifo") {
+		t.Error("IsFIFO() = false for a .fifo queue or topic")
+	}
+	if IsFIFO("https://sqs/employee-events-queue") {
+		t.Error("IsFIFO() = true for a standard queue")
+	}
+}
+
+func groupedMessage(id, groupID string) types.Message {
+	return testMessage(id, map[string]string{string(types.MessageSystemAttributeNameMessageGroupId): groupID})
+}
+
+func TestGroupMessages(t *testing.T) {
+	messages := []types.Message{
+		groupedMessage("a1", "a"),
+		groupedMessage("b1", "b"),
+		groupedMessage("a2", "a"),
+		groupedMessage("c1", "c"),
+		groupedMessage("b2", "b"),
+	}
+
+	tests := []struct {
+		name     string
+		queueURL string
+		want     [][]string
+	}{
+		{
+			name:     "cola FIFO agrupa por MessageGroupId en el orden recibido",
+			queueURL: testQueueURL + ".fifo",
+			want:     [][]string{{"a1", "a2"}, {"b1", "b2"}, {"c1"}},
+		},
+		{
+			name:     "cola estándar procesa cada mensaje por separado",
+			queueURL: testQueueURL,
+			want:     [][]string{{"a1"}, {"b1"}, {"a2"}, {"c1"}, {"b2"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			consumer := NewConsumer(newFakeSQS(), tt.queueURL, Options{})
+			var got [][]string
+			for _, group := range consumer.groupMessages(messages) {
+				var ids []string
+				for _, message := range group {
+					ids = append(ids, aws.ToString(message.MessageId))
+				}
+				got = append(got, ids)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("groupMessages() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsumerProcessesFIFOGroupsInOrder(t *testing.T) {
+	client := newFakeSQS(
+		groupedMessage("a1", "a"),
+		groupedMessage("b1", "b"),
+		groupedMessage("a2", "a"),
+		groupedMessage("b2", "b"),
+		groupedMessage("a3", "a"),
+		groupedMessage("b3", "b"),
+	)
+	consumer := NewConsumer(client, testQueueURL+".fifo", Options{Workers: 4})
+
+	// Los dos grupos avanzan en paralelo: el primer mensaje de cada uno espera
+	// a que el otro grupo también haya empezado
+	var started sync.WaitGroup
+	started.Add(2)
+	var mu sync.Mutex
+	processed := make(map[string][]string)
+	stop := runConsumer(consumer, func(ctx context.Context, message types.Message) error {
+		id := aws.ToString(message.MessageId)
+		if id == "a1" || id == "b1" {
+			started.Done()
+			started.Wait()
+		}
+		group := message.Attributes[string(types.MessageSystemAttributeNameMessageGroupId)]
+		mu.Lock()
+		processed[group] = append(processed[group], id)
+		mu.Unlock()
+		if id == "b2" {
+			return errors.New("timeout")
+		}
+		return nil
+	})
+
+	if !eventually(time.Second, func() bool { return len(client.deletedIDs()) == 4 }) {
+		t.Fatalf("deleted %v, want a1, a2, a3 and b1", client.deletedIDs())
+	}
+	time.Sleep(20 * time.Millisecond)
+	stop()
+
+	// Al fallar b2, b3 no se procesa: vuelve a la cola con él para no alterar el orden
+	want := map[string][]string{"a": {"a1", "a2", "a3"}, "b": {"b1", "b2"}}
+	if !reflect.DeepEqual(processed, want) {
+		t.Errorf("processed %v, want %v", processed, want)
+	}
+	deleted := client.deletedIDs()
+	sort.Strings(deleted)
+	if want := []string{"a1", "a2", "a3", "b1"}; !reflect.DeepEqual(deleted, want) {
+		t.Errorf("deleted %v, want %v", deleted, want)
+	}
+}
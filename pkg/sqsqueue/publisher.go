@@ -0,0 +1,102 @@
+package sqsqueue
+
+import (
+	"context"
+	"log"
+	"pkg/events"
+	"pkg/eventschema"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// EventTypeAttribute es el atributo de mensaje con el tipo de evento
+const EventTypeAttribute = "event_type"
+
+// Publisher envía eventos a una cola SQS
+type Publisher struct {
+	client   *sqs.Client
+	queueURL string
+	registry *eventschema.Registry
+}
+
+// NewPublisher crea una nueva instancia del publicador.
+// Los eventos se validan contra registry antes de enviarse.
+func NewPublisher(client *sqs.Client, queueURL string, registry *eventschema.Registry) *Publisher {
+	return &Publisher{
+		client:   client,
+		queueURL: queueURL,
+		registry: registry,
+	}
+}
+
+// Publish valida y envía un evento CloudEvents con su tipo como atributo de mensaje.
+// Si la cola es FIFO el evento se agrupa por MessageGroupID.
+func (p *Publisher) Publish(ctx context.Context, event *events.CloudEvent) error {
+	body, err := p.registry.Marshal(event)
+	if err != nil {
+		log.Printf("Refusing to publish invalid %s event: %v", event.Type, err)
+		return err
+	}
+
+	input := &sqs.SendMessageInput{
+		QueueUrl:    aws.String(p.queueURL),
+		MessageBody: aws.String(string(body)),
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			EventTypeAttribute: {
+				DataType:    aws.String("String"),
+				StringValue: aws.String(event.Type),
+			},
+		},
+	}
+	// En colas FIFO el ID del evento deduplica los reenvíos dentro de la ventana de 5 minutos
+	if IsFIFO(p.queueURL) {
+		input.MessageGroupId = aws.String(MessageGroupID(event))
+		input.MessageDeduplicationId = aws.String(event.ID)
+	}
+
+	_, err = p.client.SendMessage(ctx, input)
+
+	if err != nil {
+		log.Printf("Error publishing event to SQS: %v", err)
+		return err
+	}
+
+	log.Printf("Event published successfully: %s", event.Type)
+	return nil
+}
+
+// PublishBatch valida y envía los eventos con llamadas SendMessageBatch de hasta 10
+// eventos y 256 KB, en el orden recibido. Devuelve el resultado de cada evento en el
+// mismo orden (nil si se publicó).
+func (p *Publisher) PublishBatch(ctx context.Context, batch []*events.CloudEvent) []error {
+	results := make([]error, len(batch))
+
+	requests := make([]*publishRequest, 0, len(batch))
+	indexes := make([]int, 0, len(batch))
+	for i, event := range batch {
+		request, err := newPublishRequest(p.registry, p.queueURL, event)
+		if err != nil {
+			results[i] = err
+			continue
+		}
+		requests = append(requests, request)
+		indexes = append(indexes, i)
+	}
+
+	batches := splitBatches(requests)
+	sent := 0
+	for _, chunk := range batches {
+		for i, err := range sendBatch(ctx, p.client, p.queueURL, chunk) {
+			results[indexes[sent+i]] = err
+			if err != nil {
+				log.Printf("Error publishing event to SQS: %v", err)
+			}
+		}
+		sent += len(chunk)
+	}
+
+	log.Printf("Published %d events in %d batches", len(requests), len(batches))
+	return results
+}
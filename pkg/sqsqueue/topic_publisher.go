@@ -0,0 +1,56 @@
+package sqsqueue
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+)
+
+// publishTopicBatch publica un lote en el topic con una llamada PublishBatch y devuelve
+// el resultado de cada evento, en el orden del lote. PublishBatch tiene los mismos
+// límites que SendMessageBatch (10 mensajes y 256 KB).
+func publishTopicBatch(ctx context.Context, client *sns.Client, topicARN string, batch []*publishRequest) []error {
+	entries := make([]types.PublishBatchRequestEntry, len(batch))
+	for i, request := range batch {
+		entries[i] = types.PublishBatchRequestEntry{
+			Id:      aws.String(strconv.Itoa(i)),
+			Message: aws.String(request.body),
+			MessageAttributes: map[string]types.MessageAttributeValue{
+				EventTypeAttribute: {
+					DataType:    aws.String("String"),
+					StringValue: aws.String(request.eventType),
+				},
+			},
+		}
+		// Un topic FIFO propaga grupo y deduplicación a las colas FIFO suscritas
+		if request.groupID != "" {
+			entries[i].MessageGroupId = aws.String(request.groupID)
+			entries[i].MessageDeduplicationId = aws.String(request.dedupID)
+		}
+	}
+
+	results := make([]error, len(batch))
+	output, err := client.PublishBatch(ctx, &sns.PublishBatchInput{
+		TopicArn:                   aws.String(topicARN),
+		PublishBatchRequestEntries: entries,
+	})
+	if err != nil {
+		for i := range results {
+			results[i] = err
+		}
+		return results
+	}
+
+	for _, entry := range output.Failed {
+		index, convErr := strconv.Atoi(aws.ToString(entry.Id))
+		if convErr != nil || index < 0 || index >= len(batch) {
+			continue
+		}
+		results[index] = fmt.Errorf("publish %s event: %s: %s", batch[index].eventType, aws.ToString(entry.Code), aws.ToString(entry.Message))
+	}
+	return results
+}
@@ -36,26 +36,317 @@ aws --endpoint-url=http://localhost:4566 sqs create-queue \
     --region us-east-1 \
     --no-cli-pager 2>/dev/null || echo "Cola employee-events-queue ya existe o error al crear"
 
+echo ""
+echo "Creando dead-letter queues y redrive policies..."
+# Los consumidores mueven a la DLQ los mensajes que fallan SQS_MAX_RECEIVE_COUNT veces
+# (con el motivo como atributos); la redrive policy es el respaldo si el consumidor
+# no llega a hacerlo (p.ej. se cae durante el procesamiento)
+for queue in employee-queue employee-events-queue; do
+    aws --endpoint-url=http://localhost:4566 sqs create-queue \
+        --queue-name "${queue}-dlq" \
+        --attributes MessageRetentionPeriod=1209600 \
+        --region us-east-1 \
+        --no-cli-pager 2>/dev/null || echo "Cola ${queue}-dlq ya existe o error al crear"
+
+    aws --endpoint-url=http://localhost:4566 sqs set-queue-attributes \
+        --queue-url "http://sqs.us-east-1.localhost.localstack.cloud:4566/000000000000/${queue}" \
+        --attributes "{\"RedrivePolicy\":\"{\\\"deadLetterTargetArn\\\":\\\"arn:aws:sqs:us-east-1:000000000000:${queue}-dlq\\\",\\\"maxReceiveCount\\\":\\\"5\\\"}\"}" \
+        --region us-east-1 \
+        --no-cli-pager 2>/dev/null || echo "Redrive policy de ${queue} ya configurada o error al configurar"
+done
+
+echo ""
+echo "Creando topic SNS de eventos de empleados..."
+aws --endpoint-url=http://localhost:4566 sns create-topic \
+    --name employee-events-topic \
+    --region us-east-1 \
+    --no-cli-pager 2>/dev/null || echo "Topic employee-events-topic ya existe o error al crear"
+
+# Las suscripciones de las colas al topic las declara employee-service al arrancar a partir de
+# EVENT_SUBSCRIPTIONS (nombre=ARN de la cola|tipos de evento), con entrega raw y filter policy por
+# event_type. En AWS real cada cola necesita además una policy que permita sqs:SendMessage desde el topic.
+
+echo ""
+echo "Creando variantes FIFO (orden de eventos por empleado)..."
+# Alternativa FIFO: MessageGroupId = ID del empleado y MessageDeduplicationId = ID del evento.
+# Para usarla apunta EVENT_TOPIC_ARN, EVENT_SUBSCRIPTIONS, SQS_QUEUE_URL, EMPLOYEE_EVENTS_QUEUE_URL,
+# LOG_QUEUE_URL y SQS_DLQ_URL a los recursos .fifo (ver README). La DLQ de una cola FIFO también es FIFO.
+for queue in employee-queue employee-events-queue; do
+    aws --endpoint-url=http://localhost:4566 sqs create-queue \
+        --queue-name "${queue}-dlq.fifo" \
+        --attributes FifoQueue=true,MessageRetentionPeriod=1209600 \
+        --region us-east-1 \
+        --no-cli-pager 2>/dev/null || echo "Cola ${queue}-dlq.fifo ya existe o error al crear"
+
+    aws --endpoint-url=http://localhost:4566 sqs create-queue \
+        --queue-name "${queue}.fifo" \
+        --attributes FifoQueue=true \
+        --region us-east-1 \
+        --no-cli-pager 2>/dev/null || echo "Cola ${queue}.fifo ya existe o error al crear"
+
+    aws --endpoint-url=http://localhost:4566 sqs set-queue-attributes \
+        --queue-url "http://sqs.us-east-1.localhost.localstack.cloud:4566/000000000000/${queue}.fifo" \
+        --attributes "{\"RedrivePolicy\":\"{\\\"deadLetterTargetArn\\\":\\\"arn:aws:sqs:us-east-1:000000000000:${queue}-dlq.fifo\\\",\\\"maxReceiveCount\\\":\\\"5\\\"}\"}" \
+        --region us-east-1 \
+        --no-cli-pager 2>/dev/null || echo "Redrive policy de ${queue}.fifo ya configurada o error al configurar"
+done
+
+aws --endpoint-url=http://localhost:4566 sns create-topic \
+    --name employee-events-topic.fifo \
+    --attributes FifoTopic=true \
+    --region us-east-1 \
+    --no-cli-pager 2>/dev/null || echo "Topic employee-events-topic.fifo ya existe o error al crear"
+
 echo ""
 echo "Creando tabla DynamoDB para empleados..."
 aws --endpoint-url=http://localhost:4566 dynamodb create-table \
     --table-name employees \
-    --attribute-definitions AttributeName=ID,AttributeType=S \
+    --attribute-definitions AttributeName=ID,AttributeType=S AttributeName=Email,AttributeType=S AttributeName=ManagerID,AttributeType=S AttributeName=DepartmentID,AttributeType=S \
     --key-schema AttributeName=ID,KeyType=HASH \
+    --global-secondary-indexes \
+        "IndexName=EmailIndex,KeySchema=[{AttributeName=Email,KeyType=HASH}],Projection={ProjectionType=KEYS_ONLY},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+        "IndexName=ManagerIndex,KeySchema=[{AttributeName=ManagerID,KeyType=HASH}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+        "IndexName=DepartmentIndex,KeySchema=[{AttributeName=DepartmentID,KeyType=HASH}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
     --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --stream-specification StreamEnabled=true,StreamViewType=NEW_AND_OLD_IMAGES \
     --region us-east-1 \
     --no-cli-pager 2>/dev/null || echo "Tabla employees ya existe o error al crear"
 
+# Habilitar el stream en tablas creadas antes de EVENT_PUBLISHING_MODE=stream
+aws --endpoint-url=http://localhost:4566 dynamodb update-table \
+    --table-name employees \
+    --stream-specification StreamEnabled=true,StreamViewType=NEW_AND_OLD_IMAGES \
+    --region us-east-1 \
+    --no-cli-pager 2>/dev/null || echo "Stream de employees ya habilitado o error al configurar"
+
+# Agregar el índice de emails a tablas creadas antes de la verificación de la importación
+aws --endpoint-url=http://localhost:4566 dynamodb update-table \
+    --table-name employees \
+    --attribute-definitions AttributeName=Email,AttributeType=S \
+    --global-secondary-index-updates '[{"Create":{"IndexName":"EmailIndex","KeySchema":[{"AttributeName":"Email","KeyType":"HASH"}],"Projection":{"ProjectionType":"KEYS_ONLY"},"ProvisionedThroughput":{"ReadCapacityUnits":5,"WriteCapacityUnits":5}}}]' \
+    --region us-east-1 \
+    --no-cli-pager 2>/dev/null || echo "Índice EmailIndex ya existe o error al crear"
+
+# Agregar los índices de reportes directos y de departamento a tablas creadas antes
+# de que dejaran de hacer Scan (uno por llamada). Son dispersos: los empleados sin
+# jefe o sin departamento no tienen el atributo y no ocupan el índice.
+for index in ManagerIndex:ManagerID DepartmentIndex:DepartmentID; do
+    aws --endpoint-url=http://localhost:4566 dynamodb update-table \
+        --table-name employees \
+        --attribute-definitions AttributeName=${index#*:},AttributeType=S \
+        --global-secondary-index-updates "[{\"Create\":{\"IndexName\":\"${index%%:*}\",\"KeySchema\":[{\"AttributeName\":\"${index#*:}\",\"KeyType\":\"HASH\"}],\"Projection\":{\"ProjectionType\":\"ALL\"},\"ProvisionedThroughput\":{\"ReadCapacityUnits\":5,\"WriteCapacityUnits\":5}}}]" \
+        --region us-east-1 \
+        --no-cli-pager 2>/dev/null || echo "Índice ${index%%:*} ya existe o error al crear"
+done
+
+echo ""
+echo "Creando tabla DynamoDB para departamentos..."
+aws --endpoint-url=http://localhost:4566 dynamodb create-table \
+    --table-name departments \
+    --attribute-definitions AttributeName=ID,AttributeType=S \
+    --key-schema AttributeName=ID,KeyType=HASH \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --region us-east-1 \
+    --no-cli-pager 2>/dev/null || echo "Tabla departments ya existe o error al crear"
+
+echo ""
+echo "Creando tabla DynamoDB para claves de idempotencia..."
+aws --endpoint-url=http://localhost:4566 dynamodb create-table \
+    --table-name idempotency-keys \
+    --attribute-definitions AttributeName=Key,AttributeType=S \
+    --key-schema AttributeName=Key,KeyType=HASH \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --region us-east-1 \
+    --no-cli-pager 2>/dev/null || echo "Tabla idempotency-keys ya existe o error al crear"
+
+aws --endpoint-url=http://localhost:4566 dynamodb update-time-to-live \
+    --table-name idempotency-keys \
+    --time-to-live-specification Enabled=true,AttributeName=ExpiresAt \
+    --region us-east-1 \
+    --no-cli-pager 2>/dev/null || echo "TTL de idempotency-keys ya configurado o error al configurar"
+
+echo ""
+echo "Creando tabla DynamoDB para el outbox de eventos de empleados..."
+aws --endpoint-url=http://localhost:4566 dynamodb create-table \
+    --table-name employee-outbox \
+    --attribute-definitions AttributeName=ID,AttributeType=S AttributeName=Pending,AttributeType=S AttributeName=NextAttemptAt,AttributeType=N AttributeName=PendingAggregateID,AttributeType=S AttributeName=Sequence,AttributeType=N \
+    --key-schema AttributeName=ID,KeyType=HASH \
+    --global-secondary-indexes \
+        "IndexName=PendingIndex,KeySchema=[{AttributeName=Pending,KeyType=HASH},{AttributeName=NextAttemptAt,KeyType=RANGE}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+        "IndexName=PendingAggregateIndex,KeySchema=[{AttributeName=PendingAggregateID,KeyType=HASH},{AttributeName=Sequence,KeyType=RANGE}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --region us-east-1 \
+    --no-cli-pager 2>/dev/null || echo "Tabla employee-outbox ya existe o error al crear"
+
+aws --endpoint-url=http://localhost:4566 dynamodb update-time-to-live \
+    --table-name employee-outbox \
+    --time-to-live-specification Enabled=true,AttributeName=ExpiresAt \
+    --region us-east-1 \
+    --no-cli-pager 2>/dev/null || echo "TTL de employee-outbox ya configurado o error al configurar"
+
+# Agregar el índice de pendientes a tablas creadas antes de que el relay dejara de hacer Scan
+aws --endpoint-url=http://localhost:4566 dynamodb update-table \
+    --table-name employee-outbox \
+    --attribute-definitions AttributeName=Pending,AttributeType=S AttributeName=NextAttemptAt,AttributeType=N \
+    --global-secondary-index-updates '[{"Create":{"IndexName":"PendingIndex","KeySchema":[{"AttributeName":"Pending","KeyType":"HASH"},{"AttributeName":"NextAttemptAt","KeyType":"RANGE"}],"Projection":{"ProjectionType":"ALL"},"ProvisionedThroughput":{"ReadCapacityUnits":5,"WriteCapacityUnits":5}}}]' \
+    --region us-east-1 \
+    --no-cli-pager 2>/dev/null || echo "Índice PendingIndex ya existe o error al crear"
+
+# Índice de pendientes por empleado: el relay publica las entradas de cada empleado en orden
+aws --endpoint-url=http://localhost:4566 dynamodb update-table \
+    --table-name employee-outbox \
+    --attribute-definitions AttributeName=PendingAggregateID,AttributeType=S AttributeName=Sequence,AttributeType=N \
+    --global-secondary-index-updates '[{"Create":{"IndexName":"PendingAggregateIndex","KeySchema":[{"AttributeName":"PendingAggregateID","KeyType":"HASH"},{"AttributeName":"Sequence","KeyType":"RANGE"}],"Projection":{"ProjectionType":"ALL"},"ProvisionedThroughput":{"ReadCapacityUnits":5,"WriteCapacityUnits":5}}}]' \
+    --region us-east-1 \
+    --no-cli-pager 2>/dev/null || echo "Índice PendingAggregateIndex ya existe o error al crear"
+
+echo ""
+echo "Creando tabla DynamoDB para checkpoints del stream de empleados..."
+aws --endpoint-url=http://localhost:4566 dynamodb create-table \
+    --table-name stream-checkpoints \
+    --attribute-definitions AttributeName=ShardID,AttributeType=S \
+    --key-schema AttributeName=ShardID,KeyType=HASH \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --region us-east-1 \
+    --no-cli-pager 2>/dev/null || echo "Tabla stream-checkpoints ya existe o error al crear"
+
+echo ""
+echo "Creando tabla DynamoDB de eventos procesados (deduplicación de consumidores)..."
+aws --endpoint-url=http://localhost:4566 dynamodb create-table \
+    --table-name processed-events \
+    --attribute-definitions AttributeName=Key,AttributeType=S \
+    --key-schema AttributeName=Key,KeyType=HASH \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --region us-east-1 \
+    --no-cli-pager 2>/dev/null || echo "Tabla processed-events ya existe o error al crear"
+
+aws --endpoint-url=http://localhost:4566 dynamodb update-time-to-live \
+    --table-name processed-events \
+    --time-to-live-specification Enabled=true,AttributeName=ExpiresAt \
+    --region us-east-1 \
+    --no-cli-pager 2>/dev/null || echo "TTL de processed-events ya configurado o error al configurar"
+
 echo ""
 echo "Creando tabla DynamoDB para logs..."
+# Índices de la API de consulta (GET /logs): historial por empleado, por tipo de
+# evento y por día, todos ordenados por SortKey (timestamp#id) para evitar Scans.
+# ChainIndex recorre la cadena de auditoría de cada empleado por secuencia.
+# ArchiveIndex (disperso) agrupa por día de vencimiento las entradas con
+# retención que aún no se archivaron.
 aws --endpoint-url=http://localhost:4566 dynamodb create-table \
     --table-name employee-logs \
-    --attribute-definitions AttributeName=ID,AttributeType=S \
+    --attribute-definitions \
+        AttributeName=ID,AttributeType=S \
+        AttributeName=EmployeeID,AttributeType=S \
+        AttributeName=EventType,AttributeType=S \
+        AttributeName=LogDate,AttributeType=S \
+        AttributeName=SortKey,AttributeType=S \
+        AttributeName=ChainID,AttributeType=S \
+        AttributeName=ChainSeq,AttributeType=N \
+        AttributeName=ArchiveDay,AttributeType=S \
     --key-schema AttributeName=ID,KeyType=HASH \
+    --global-secondary-indexes \
+        "IndexName=EmployeeIndex,KeySchema=[{AttributeName=EmployeeID,KeyType=HASH},{AttributeName=SortKey,KeyType=RANGE}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+        "IndexName=EventTypeIndex,KeySchema=[{AttributeName=EventType,KeyType=HASH},{AttributeName=SortKey,KeyType=RANGE}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+        "IndexName=LogDateIndex,KeySchema=[{AttributeName=LogDate,KeyType=HASH},{AttributeName=SortKey,KeyType=RANGE}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+        "IndexName=ChainIndex,KeySchema=[{AttributeName=ChainID,KeyType=HASH},{AttributeName=ChainSeq,KeyType=RANGE}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
+        "IndexName=ArchiveIndex,KeySchema=[{AttributeName=ArchiveDay,KeyType=HASH},{AttributeName=SortKey,KeyType=RANGE}],Projection={ProjectionType=ALL},ProvisionedThroughput={ReadCapacityUnits=5,WriteCapacityUnits=5}" \
     --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5 \
     --region us-east-1 \
     --no-cli-pager 2>/dev/null || echo "Tabla employee-logs ya existe o error al crear"
 
+# Agregar los índices a tablas creadas antes de la API de consulta (uno por llamada)
+for index in EmployeeIndex:EmployeeID EventTypeIndex:EventType LogDateIndex:LogDate; do
+    aws --endpoint-url=http://localhost:4566 dynamodb update-table \
+        --table-name employee-logs \
+        --attribute-definitions AttributeName=${index#*:},AttributeType=S AttributeName=SortKey,AttributeType=S \
+        --global-secondary-index-updates "[{\"Create\":{\"IndexName\":\"${index%%:*}\",\"KeySchema\":[{\"AttributeName\":\"${index#*:}\",\"KeyType\":\"HASH\"},{\"AttributeName\":\"SortKey\",\"KeyType\":\"RANGE\"}],\"Projection\":{\"ProjectionType\":\"ALL\"},\"ProvisionedThroughput\":{\"ReadCapacityUnits\":5,\"WriteCapacityUnits\":5}}}]" \
+        --region us-east-1 \
+        --no-cli-pager 2>/dev/null || echo "Índice ${index%%:*} ya existe o error al crear"
+done
+
+aws --endpoint-url=http://localhost:4566 dynamodb update-table \
+    --table-name employee-logs \
+    --attribute-definitions AttributeName=ChainID,AttributeType=S AttributeName=ChainSeq,AttributeType=N \
+    --global-secondary-index-updates '[{"Create":{"IndexName":"ChainIndex","KeySchema":[{"AttributeName":"ChainID","KeyType":"HASH"},{"AttributeName":"ChainSeq","KeyType":"RANGE"}],"Projection":{"ProjectionType":"ALL"},"ProvisionedThroughput":{"ReadCapacityUnits":5,"WriteCapacityUnits":5}}}]' \
+    --region us-east-1 \
+    --no-cli-pager 2>/dev/null || echo "Índice ChainIndex ya existe o error al crear"
+
+aws --endpoint-url=http://localhost:4566 dynamodb update-table \
+    --table-name employee-logs \
+    --attribute-definitions AttributeName=ArchiveDay,AttributeType=S AttributeName=SortKey,AttributeType=S \
+    --global-secondary-index-updates '[{"Create":{"IndexName":"ArchiveIndex","KeySchema":[{"AttributeName":"ArchiveDay","KeyType":"HASH"},{"AttributeName":"SortKey","KeyType":"RANGE"}],"Projection":{"ProjectionType":"ALL"},"ProvisionedThroughput":{"ReadCapacityUnits":5,"WriteCapacityUnits":5}}}]' \
+    --region us-east-1 \
+    --no-cli-pager 2>/dev/null || echo "Índice ArchiveIndex ya existe o error al crear"
+
+# El TTL solo se asigna a las entradas ya archivadas
+aws --endpoint-url=http://localhost:4566 dynamodb update-time-to-live \
+    --table-name employee-logs \
+    --time-to-live-specification Enabled=true,AttributeName=ExpiresAt \
+    --region us-east-1 \
+    --no-cli-pager 2>/dev/null || echo "TTL de employee-logs ya configurado o error al configurar"
+
+echo ""
+echo "Creando bucket S3 para los archivos de logs vencidos..."
+aws --endpoint-url=http://localhost:4566 s3 mb s3://employee-logs-archive \
+    --region us-east-1 \
+    --no-cli-pager 2>/dev/null || echo "Bucket employee-logs-archive ya existe o error al crear"
+
+echo ""
+echo "Creando tablas DynamoDB de la cadena de auditoría (cabezas y checkpoints firmados)..."
+aws --endpoint-url=http://localhost:4566 dynamodb create-table \
+    --table-name audit-chain-heads \
+    --attribute-definitions AttributeName=ChainID,AttributeType=S \
+    --key-schema AttributeName=ChainID,KeyType=HASH \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --region us-east-1 \
+    --no-cli-pager 2>/dev/null || echo "Tabla audit-chain-heads ya existe o error al crear"
+
+aws --endpoint-url=http://localhost:4566 dynamodb create-table \
+    --table-name audit-checkpoints \
+    --attribute-definitions AttributeName=ChainID,AttributeType=S AttributeName=Seq,AttributeType=N \
+    --key-schema AttributeName=ChainID,KeyType=HASH AttributeName=Seq,KeyType=RANGE \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --region us-east-1 \
+    --no-cli-pager 2>/dev/null || echo "Tabla audit-checkpoints ya existe o error al crear"
+
+echo ""
+echo "Creando tabla DynamoDB de estadísticas de actividad (contadores por hora y por día)..."
+aws --endpoint-url=http://localhost:4566 dynamodb create-table \
+    --table-name activity-stats \
+    --attribute-definitions AttributeName=Series,AttributeType=S AttributeName=Bucket,AttributeType=S \
+    --key-schema AttributeName=Series,KeyType=HASH AttributeName=Bucket,KeyType=RANGE \
+    --provisioned-throughput ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --region us-east-1 \
+    --no-cli-pager 2>/dev/null || echo "Tabla activity-stats ya existe o error al crear"
+
+# Los contadores por hora caducan (ExpiresAt); los diarios no tienen TTL
+aws --endpoint-url=http://localhost:4566 dynamodb update-time-to-live \
+    --table-name activity-stats \
+    --time-to-live-specification Enabled=true,AttributeName=ExpiresAt \
+    --region us-east-1 \
+    --no-cli-pager 2>/dev/null || echo "TTL de activity-stats ya configurado o error al configurar"
+
+echo ""
+echo "Creando clave KMS para cifrar los datos personales de los logs..."
+if aws --endpoint-url=http://localhost:4566 kms describe-key \
+    --key-id alias/logger-pii \
+    --region us-east-1 \
+    --no-cli-pager >/dev/null 2>&1; then
+    echo "Clave alias/logger-pii ya existe"
+else
+    PII_KEY_ID=$(aws --endpoint-url=http://localhost:4566 kms create-key \
+        --description "logger-service PII" \
+        --query KeyMetadata.KeyId --output text \
+        --region us-east-1 \
+        --no-cli-pager 2>/dev/null)
+    aws --endpoint-url=http://localhost:4566 kms create-alias \
+        --alias-name alias/logger-pii \
+        --target-key-id "$PII_KEY_ID" \
+        --region us-east-1 \
+        --no-cli-pager 2>/dev/null || echo "Error al crear la clave KMS alias/logger-pii"
+fi
+
 echo ""
 echo "Creando tabla DynamoDB para mensajes..."
 aws --endpoint-url=http://localhost:4566 dynamodb create-table \